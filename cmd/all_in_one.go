@@ -23,6 +23,9 @@ func allInOne(_ *cobra.Command, _ []string) {
 	// "embed" KMS
 	cfg.KMS.IsEmbedded = true
 	cfg.Providers.KmsClient.Host = "localhost:14000"
+	if cfg.Providers.KmsClient.AuthSecret == "" {
+		cfg.Providers.KmsClient.AuthSecret = cfg.KMS.AuthSecret
+	}
 
 	service := app.New(ctx, cfg)
 	kmsService := kms.NewApp(ctx, cfg)
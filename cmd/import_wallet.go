@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/oxygenpay/oxygen/internal/app"
+	kmswallet "github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/spf13/cobra"
+)
+
+var importWalletCommand = &cobra.Command{
+	Use:   "import-wallet",
+	Short: "Import externally generated wallet",
+	Long: "Imports wallet into KMS by its private key or mnemonic & derivation path and registers it with provided type. " +
+		"Secrets are read from env or stdin: private key from " + importWalletPrivateKeyEnv + ", mnemonic (if --derivation-path is set) from " +
+		importWalletMnemonicEnv + " and its optional passphrase from " + importWalletMnemonicPassphraseEnv + " env",
+	Run: importWallet,
+}
+
+const (
+	importWalletPrivateKeyEnv         = "IMPORT_WALLET_PRIVATE_KEY"
+	importWalletMnemonicEnv           = "IMPORT_WALLET_MNEMONIC"
+	importWalletMnemonicPassphraseEnv = "IMPORT_WALLET_MNEMONIC_PASSPHRASE"
+)
+
+var importWalletArgs = struct {
+	Blockchain     string
+	Address        string
+	Type           string
+	DerivationPath string
+}{}
+
+func importWallet(_ *cobra.Command, _ []string) {
+	var (
+		ctx           = context.Background()
+		cfg           = resolveConfig()
+		service       = app.New(ctx, cfg)
+		walletService = service.Locator().WalletService()
+		logger        = service.Logger()
+	)
+
+	params := kmswallet.ImportParams{
+		Blockchain:     kmswallet.Blockchain(importWalletArgs.Blockchain),
+		Address:        importWalletArgs.Address,
+		DerivationPath: importWalletArgs.DerivationPath,
+	}
+
+	var err error
+	if params.DerivationPath == "" {
+		params.PrivateKey, err = readSecret("private key", importWalletPrivateKeyEnv)
+	} else {
+		params.Mnemonic, err = readSecret("mnemonic", importWalletMnemonicEnv)
+		params.MnemonicPassphrase = os.Getenv(importWalletMnemonicPassphraseEnv)
+	}
+
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Unable to read wallet secret")
+	}
+
+	w, err := walletService.Import(ctx, params, wallet.Type(importWalletArgs.Type))
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Unable to import wallet")
+	}
+
+	logger.Info().
+		Int64("wallet.id", w.ID).
+		Str("wallet.uuid", w.UUID.String()).
+		Str("wallet.type", string(w.Type)).
+		Str("wallet.blockchain", w.Blockchain.String()).
+		Str("wallet.address", w.Address).
+		Msg("Wallet imported")
+}
+
+func importWalletSetup(cmd *cobra.Command) {
+	f := cmd.Flags()
+
+	f.StringVar(&importWalletArgs.Blockchain, "blockchain", "", "Blockchain: BTC, ETH, TRON, MATIC or BSC")
+	f.StringVar(&importWalletArgs.Address, "address", "", "Wallet address")
+	f.StringVar(&importWalletArgs.Type, "type", string(wallet.TypeOutbound), "Wallet type: inbound, outbound, gas or staking")
+	f.StringVar(&importWalletArgs.DerivationPath, "derivation-path", "", "Derivation path of the mnemonic's key, e.g. m/44'/60'/0'/0/0")
+
+	for _, name := range []string{"blockchain", "address"} {
+		if err := cmd.MarkFlagRequired(name); err != nil {
+			panic(name + ": " + err.Error())
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/oxygenpay/oxygen/internal/kms"
+	"github.com/spf13/cobra"
+)
+
+var kmsAuditExportCommand = &cobra.Command{
+	Use:   "kms-audit-export",
+	Short: "Export KMS audit log as JSON lines",
+	Long:  "Outputs KMS audit log entries to stdout. KMS should be stopped",
+	Run:   kmsAuditExport,
+}
+
+var kmsAuditVerifyCommand = &cobra.Command{
+	Use:   "kms-audit-verify",
+	Short: "Verify hash chain of KMS audit log",
+	Long:  "Checks that KMS audit log wasn't modified. Requires the same audit_key (KMS_AUDIT_KEY) the log was written with. Keep printed head hash outside of KMS and pass it with --head next time to detect truncation. KMS should be stopped",
+	Run:   kmsAuditVerify,
+}
+
+var (
+	kmsAuditFromSeq  uint64
+	kmsAuditHeadHash string
+)
+
+func kmsAuditExport(_ *cobra.Command, _ []string) {
+	service := kms.NewApp(context.Background(), resolveConfig())
+
+	count, err := service.ExportAuditLog(os.Stdout, kmsAuditFromSeq)
+	if err != nil {
+		log.Fatalf("Unable to export audit log: %s\n", err.Error())
+	}
+
+	log.Printf("Exported %d entries ✔\n", count)
+}
+
+func kmsAuditVerify(_ *cobra.Command, _ []string) {
+	service := kms.NewApp(context.Background(), resolveConfig())
+
+	result, err := service.VerifyAuditLog(kmsAuditHeadHash)
+	if err != nil {
+		log.Fatalf("Unable to verify audit log: %s\n", err.Error())
+	}
+
+	if !result.Valid {
+		log.Fatalf("Audit log is invalid: %s (entry #%d)\n", result.Reason, result.BrokenAt)
+	}
+
+	log.Printf("Audit log is valid: %d entries, head hash %s ✔\n", result.Count, result.HeadHash)
+}
+
+func kmsAuditSetup() {
+	kmsAuditExportCommand.PersistentFlags().Uint64Var(&kmsAuditFromSeq, "from", 0, "sequence number to start from")
+	kmsAuditVerifyCommand.PersistentFlags().StringVar(&kmsAuditHeadHash, "head", "", "previously exported head hash")
+}
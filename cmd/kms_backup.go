@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/oxygenpay/oxygen/internal/kms"
+	"github.com/spf13/cobra"
+)
+
+var kmsBackupCommand = &cobra.Command{
+	Use:   "kms-backup",
+	Short: "Export KMS wallets into encrypted archive",
+	Long:  "Exports all KMS wallets & HD seed into archive encrypted with a passphrase. Passphrase is read from " + kmsBackupPassphraseEnv + " env or stdin. KMS should be stopped",
+	Run:   kmsBackup,
+}
+
+var kmsRestoreCommand = &cobra.Command{
+	Use:   "kms-restore",
+	Short: "Import KMS wallets from encrypted archive",
+	Long:  "Verifies archive integrity and that every key matches its wallet address, then imports wallets into KMS store in a single transaction. Existing wallets are not overwritten unless --force is set. Passphrase is read from " + kmsBackupPassphraseEnv + " env or stdin. KMS should be stopped",
+	Run:   kmsRestore,
+}
+
+const kmsBackupPassphraseEnv = "KMS_BACKUP_PASSPHRASE"
+
+var (
+	kmsBackupFile   string
+	kmsRestoreForce bool
+)
+
+func kmsBackup(_ *cobra.Command, _ []string) {
+	passphrase := resolveBackupPassphrase()
+
+	// O_EXCL: never overwrite previous backups
+	file, err := os.OpenFile(kmsBackupFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		log.Fatalf("Unable to create backup file: %s\n", err.Error())
+	}
+
+	service := kms.NewApp(context.Background(), resolveConfig())
+
+	count, err := service.Backup(file, passphrase)
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(kmsBackupFile)
+		log.Fatalf("Unable to backup KMS: %s\n", err.Error())
+	}
+
+	if err := file.Close(); err != nil {
+		log.Fatalf("Unable to write backup file: %s\n", err.Error())
+	}
+
+	log.Printf("Exported %d wallet(s) to %s ✔\n", count, kmsBackupFile)
+}
+
+func kmsRestore(_ *cobra.Command, _ []string) {
+	passphrase := resolveBackupPassphrase()
+
+	file, err := os.Open(kmsBackupFile)
+	if err != nil {
+		log.Fatalf("Unable to open backup file: %s\n", err.Error())
+	}
+
+	defer file.Close()
+
+	service := kms.NewApp(context.Background(), resolveConfig())
+
+	count, err := service.Restore(file, passphrase, kmsRestoreForce)
+	if err != nil {
+		log.Fatalf("Unable to restore KMS: %s\n", err.Error())
+	}
+
+	log.Printf("Restored %d wallet(s) ✔\n", count)
+}
+
+func resolveBackupPassphrase() string {
+	if kmsBackupFile == "" {
+		log.Fatalln("Backup file should be provided")
+	}
+
+	passphrase, err := readSecret("archive passphrase", kmsBackupPassphraseEnv)
+	if err != nil {
+		log.Fatalf("Unable to read passphrase: %s\n", err.Error())
+	}
+
+	if passphrase == "" {
+		log.Fatalf("Passphrase should be provided with %s env or stdin\n", kmsBackupPassphraseEnv)
+	}
+
+	return passphrase
+}
+
+func kmsBackupSetup() {
+	for _, c := range []*cobra.Command{kmsBackupCommand, kmsRestoreCommand} {
+		c.PersistentFlags().StringVar(&kmsBackupFile, "file", "", "path to backup archive")
+	}
+
+	kmsRestoreCommand.PersistentFlags().BoolVar(&kmsRestoreForce, "force", false, "overwrite existing wallets")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/oxygenpay/oxygen/internal/kms"
+	"github.com/oxygenpay/oxygen/internal/kms/encryption"
+	"github.com/spf13/cobra"
+)
+
+var kmsGenerateKeyCommand = &cobra.Command{
+	Use:   "kms-generate-key",
+	Short: "Generate random KMS master key",
+	Run:   kmsGenerateKey,
+}
+
+var kmsEncryptStoreCommand = &cobra.Command{
+	Use:   "kms-encrypt-store",
+	Short: "Encrypt plaintext KMS wallets with configured master key",
+	Long:  "Encrypts existing KMS wallets with master key from kms.encryption config. KMS should be stopped. Bolt db is compacted afterwards; backups made before encryption still contain plaintext keys and should be destroyed",
+	Run:   kmsEncryptStore,
+}
+
+var kmsRotateKeyCommand = &cobra.Command{
+	Use:   "kms-rotate-key",
+	Short: "Re-encrypt KMS wallets with a new master key",
+	Long:  "Re-encrypts wallets' data keys with a new master key. Current key is taken from kms.encryption config. KMS should be stopped",
+	Run:   kmsRotateKey,
+}
+
+var kmsNextKey encryption.Config
+
+func kmsGenerateKey(_ *cobra.Command, _ []string) {
+	key, err := encryption.GenerateKey()
+	if err != nil {
+		log.Fatalf("Unable to generate master key: %s\n", err.Error())
+	}
+
+	fmt.Println(key)
+}
+
+func kmsEncryptStore(_ *cobra.Command, _ []string) {
+	service := kms.NewApp(context.Background(), resolveConfig())
+
+	count, err := service.EncryptStore()
+	if err != nil {
+		log.Fatalf("Unable to encrypt KMS store: %s\n", err.Error())
+	}
+
+	log.Printf("Encrypted %d wallet(s) ✔\n", count)
+}
+
+func kmsRotateKey(_ *cobra.Command, _ []string) {
+	if kmsNextKey.IsEmpty() {
+		log.Fatalln("New master key should be provided")
+	}
+
+	service := kms.NewApp(context.Background(), resolveConfig())
+
+	count, err := service.RotateMasterKey(kmsNextKey)
+	if err != nil {
+		log.Fatalf("Unable to rotate KMS master key: %s\n", err.Error())
+	}
+
+	log.Printf("Re-encrypted %d wallet(s) ✔. Update kms.encryption config with the new master key\n", count)
+}
+
+func kmsRotateKeySetup(c *cobra.Command) {
+	c.PersistentFlags().StringVar(&kmsNextKey.MasterKey, "new-master-key", "", "new base64-encoded master key")
+	c.PersistentFlags().StringVar(&kmsNextKey.MasterKeyFile, "new-master-key-file", "", "path to a file with new master key")
+	c.PersistentFlags().StringVar(&kmsNextKey.Passphrase, "new-passphrase", "", "new passphrase to derive master key from")
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/oxygenpay/oxygen/internal/kms"
+	"github.com/spf13/cobra"
+)
+
+var kmsExportSeedCommand = &cobra.Command{
+	Use:   "kms-export-seed",
+	Short: "Output BIP-39 mnemonic & hex-encoded seed of KMS HD wallets for backup",
+	Long: "Outputs mnemonic & seed that all KMS HD wallets are derived from. Write the mnemonic down and keep it offline. " +
+		"Seeds created before mnemonic support are exported only as hex. KMS should be stopped",
+	Run: kmsExportSeed,
+}
+
+var kmsImportSeedCommand = &cobra.Command{
+	Use:   "kms-import-seed",
+	Short: "Import seed of KMS HD wallets from BIP-39 mnemonic or hex",
+	Long: "Replaces KMS HD seed. Allowed only until the first HD wallet is created. Mnemonic is read from " + kmsMnemonicEnv +
+		" env or stdin, its optional passphrase from " + kmsMnemonicPassphraseEnv + " env. With --hex seed is read from " +
+		kmsSeedEnv + " env or stdin instead. KMS should be stopped",
+	Run: kmsImportSeed,
+}
+
+var kmsRecoverWalletsCommand = &cobra.Command{
+	Use:   "kms-recover-wallets",
+	Short: "Recover KMS HD wallets from the seed",
+	Long:  "Derives first N HD wallets of each blockchain from the seed and restores missing ones. KMS should be stopped",
+	Run:   kmsRecoverWallets,
+}
+
+const (
+	kmsSeedEnv               = "KMS_HD_SEED"
+	kmsMnemonicEnv           = "KMS_HD_MNEMONIC"
+	kmsMnemonicPassphraseEnv = "KMS_HD_MNEMONIC_PASSPHRASE"
+)
+
+var (
+	kmsSeedFromHex  bool
+	kmsRecoverCount uint32
+)
+
+func kmsExportSeed(_ *cobra.Command, _ []string) {
+	service := kms.NewApp(context.Background(), resolveConfig())
+
+	mnemonic, seed, err := service.ExportHDSeed()
+	if err != nil {
+		log.Fatalf("Unable to export HD seed: %s\n", err.Error())
+	}
+
+	if mnemonic == "" {
+		log.Println("HD seed was not generated from mnemonic, only hex-encoded seed is available")
+	} else {
+		fmt.Printf("mnemonic: %s\n", mnemonic)
+	}
+
+	fmt.Printf("seed: %s\n", seed)
+}
+
+func kmsImportSeed(_ *cobra.Command, _ []string) {
+	if kmsSeedFromHex {
+		importHexSeed()
+	} else {
+		importMnemonic()
+	}
+
+	log.Println("Imported HD seed ✔")
+}
+
+func importHexSeed() {
+	seedHex, err := readSecret("hex-encoded seed", kmsSeedEnv)
+	if err != nil {
+		log.Fatalf("Unable to read HD seed: %s\n", err.Error())
+	}
+
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil || len(seed) == 0 {
+		log.Fatalln("Unable to parse HD seed: hex-encoded seed is expected")
+	}
+
+	service := kms.NewApp(context.Background(), resolveConfig())
+	if err := service.ImportHDSeed(seed); err != nil {
+		log.Fatalf("Unable to import HD seed: %s\n", err.Error())
+	}
+}
+
+func importMnemonic() {
+	mnemonic, err := readSecret("BIP-39 mnemonic", kmsMnemonicEnv)
+	if err != nil {
+		log.Fatalf("Unable to read mnemonic: %s\n", err.Error())
+	}
+
+	service := kms.NewApp(context.Background(), resolveConfig())
+	if err := service.ImportHDMnemonic(mnemonic, os.Getenv(kmsMnemonicPassphraseEnv)); err != nil {
+		log.Fatalf("Unable to import HD seed: %s\n", err.Error())
+	}
+}
+
+func kmsRecoverWallets(_ *cobra.Command, _ []string) {
+	service := kms.NewApp(context.Background(), resolveConfig())
+
+	count, err := service.RecoverHDWallets(kmsRecoverCount)
+	if err != nil {
+		log.Fatalf("Unable to recover HD wallets: %s\n", err.Error())
+	}
+
+	log.Printf("Recovered %d wallet(s) ✔\n", count)
+}
+
+func kmsHDSetup() {
+	kmsImportSeedCommand.PersistentFlags().BoolVar(&kmsSeedFromHex, "hex", false, "import hex-encoded seed instead of mnemonic")
+
+	kmsRecoverWalletsCommand.PersistentFlags().Uint32Var(&kmsRecoverCount, "count", 100, "number of wallets to derive per blockchain")
+}
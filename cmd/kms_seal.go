@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/oxygenpay/oxygen/internal/config"
+	"github.com/oxygenpay/oxygen/internal/kms"
+	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/client"
+	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/client/system"
+	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var kmsSplitKeyCommand = &cobra.Command{
+	Use:   "kms-split-key",
+	Short: "Split KMS master key into shares for sealed mode",
+	Long: "Encrypts KMS store with master key from kms.encryption config and splits the key into shares. " +
+		"Hand out shares to different operators, remove the key from config and set kms.encryption.unseal_threshold. KMS should be stopped",
+	Run: kmsSplitKey,
+}
+
+var kmsUnsealCommand = &cobra.Command{
+	Use:   "kms-unseal",
+	Short: "Submit master key share to sealed KMS",
+	Long:  "Submits key share to running KMS. KMS is unsealed when the threshold of shares is reached. Should be run on KMS host",
+	Run:   kmsUnseal,
+}
+
+var kmsSealCommand = &cobra.Command{
+	Use:   "kms-seal",
+	Short: "Seal running KMS",
+	Long:  "Wipes master key from KMS memory. KMS refuses to access wallets until unsealed again. Should be run on KMS host",
+	Run:   kmsSeal,
+}
+
+var kmsSealStatusCommand = &cobra.Command{
+	Use:   "kms-seal-status",
+	Short: "Show seal status of running KMS",
+	Run:   kmsSealStatus,
+}
+
+const kmsUnsealShareEnv = "KMS_UNSEAL_SHARE"
+
+var (
+	kmsSplitShares    int
+	kmsSplitThreshold int
+	kmsUnsealShare    string
+)
+
+func kmsSplitKey(_ *cobra.Command, _ []string) {
+	service := kms.NewApp(context.Background(), resolveConfig())
+
+	shares, err := service.SplitMasterKey(kmsSplitShares, kmsSplitThreshold)
+	if err != nil {
+		log.Fatalf("Unable to split KMS master key: %s\n", err.Error())
+	}
+
+	for i, share := range shares {
+		fmt.Printf("Share #%d: %s\n", i+1, share)
+	}
+
+	log.Printf(
+		"Split master key into %d shares ✔. Any %d of them unseal KMS. Set kms.encryption.unseal_threshold: %d and remove the key from config\n",
+		len(shares), kmsSplitThreshold, kmsSplitThreshold,
+	)
+}
+
+func kmsUnseal(_ *cobra.Command, _ []string) {
+	share := kmsUnsealShare
+	if share == "" {
+		share = os.Getenv(kmsUnsealShareEnv)
+	}
+
+	if share == "" {
+		log.Fatalf("Key share should be provided with --share or %s\n", kmsUnsealShareEnv)
+	}
+
+	params := system.NewUnsealParamsWithContext(context.Background()).
+		WithData(&model.UnsealRequest{Share: share})
+
+	res, err := kmsLocalClient(resolveConfig()).System.Unseal(params)
+	if err != nil {
+		log.Fatalf("Unable to unseal KMS: %s\n", kmsSystemError(err))
+	}
+
+	printSealStatus(res.Payload)
+}
+
+func kmsSeal(_ *cobra.Command, _ []string) {
+	res, err := kmsLocalClient(resolveConfig()).System.Seal(system.NewSealParamsWithContext(context.Background()))
+	if err != nil {
+		log.Fatalf("Unable to seal KMS: %s\n", kmsSystemError(err))
+	}
+
+	printSealStatus(res.Payload)
+}
+
+func kmsSealStatus(_ *cobra.Command, _ []string) {
+	res, err := kmsLocalClient(resolveConfig()).System.GetSealStatus(system.NewGetSealStatusParamsWithContext(context.Background()))
+	if err != nil {
+		log.Fatalf("Unable to get KMS seal status: %s\n", kmsSystemError(err))
+	}
+
+	printSealStatus(res.Payload)
+}
+
+// kmsLocalClient returns client of KMS running on the same host.
+func kmsLocalClient(cfg *config.Config) *client.KMSInternalAPI {
+	transport := client.DefaultTransportConfig().WithHost("127.0.0.1:" + cfg.KMS.Server.Port)
+
+	return client.NewHTTPClientWithSecret(nil, transport, cfg.KMS.AuthSecret)
+}
+
+func kmsSystemError(err error) string {
+	var unsealErr *system.UnsealBadRequest
+	if errors.As(err, &unsealErr) && unsealErr.Payload != nil {
+		return errorResponseMessage(unsealErr.Payload)
+	}
+
+	var sealErr *system.SealBadRequest
+	if errors.As(err, &sealErr) && sealErr.Payload != nil {
+		return errorResponseMessage(sealErr.Payload)
+	}
+
+	return err.Error()
+}
+
+func errorResponseMessage(res *model.ErrorResponse) string {
+	if len(res.Errors) > 0 && res.Errors[0].Message != "" {
+		return res.Errors[0].Message
+	}
+
+	return res.Message
+}
+
+func printSealStatus(status *model.SealStatus) {
+	switch {
+	case status.Threshold == 0:
+		log.Println("KMS is not running in sealed mode")
+	case status.Sealed:
+		log.Printf("KMS is sealed: %d of %d key shares submitted\n", status.Progress, status.Threshold)
+	default:
+		log.Println("KMS is unsealed ✔")
+	}
+}
+
+func kmsSealSetup() {
+	kmsSplitKeyCommand.PersistentFlags().IntVar(&kmsSplitShares, "shares", 5, "number of key shares")
+	kmsSplitKeyCommand.PersistentFlags().IntVar(&kmsSplitThreshold, "threshold", 3, "number of key shares required to unseal KMS")
+
+	kmsUnsealCommand.PersistentFlags().StringVar(&kmsUnsealShare, "share", "", "hex-encoded key share (or "+kmsUnsealShareEnv+" env)")
+}
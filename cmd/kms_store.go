@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"context"
+	"log"
+
+	"github.com/oxygenpay/oxygen/internal/kms"
+	"github.com/oxygenpay/oxygen/internal/kms/storage"
+	"github.com/spf13/cobra"
+)
+
+var kmsMigrateStoreCommand = &cobra.Command{
+	Use:   "kms-migrate-store",
+	Short: "Copy KMS store to another storage backend",
+	Long:  "Copies all KMS records from the store in kms.store config to an empty target store. Records are copied as is, so wallets stay encrypted. KMS should be stopped",
+	Run:   kmsMigrateStore,
+}
+
+var kmsTargetStore storage.Config
+
+func kmsMigrateStore(_ *cobra.Command, _ []string) {
+	service := kms.NewApp(context.Background(), resolveConfig())
+
+	count, err := service.MigrateStore(kmsTargetStore)
+	if err != nil {
+		log.Fatalf("Unable to migrate KMS store: %s\n", err.Error())
+	}
+
+	log.Printf("Copied %d record(s) to %s store ✔. Update kms.store config with the new store\n", count, kmsTargetStore.Type)
+}
+
+func kmsMigrateStoreSetup(c *cobra.Command) {
+	c.PersistentFlags().StringVar((*string)(&kmsTargetStore.Type), "to-type", "", "target store type: bolt, postgres or file")
+	c.PersistentFlags().StringVar(&kmsTargetStore.Path, "to-path", "", "target bolt db or encrypted file path")
+	c.PersistentFlags().StringVar(&kmsTargetStore.Postgres.DataSource, "to-postgres-data-source", "", "target postgres connection string")
+	c.PersistentFlags().StringVar(&kmsTargetStore.File.Passphrase, "to-file-passphrase", "", "target encrypted file passphrase")
+}
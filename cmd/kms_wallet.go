@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/oxygenpay/oxygen/internal/kms"
+	"github.com/spf13/cobra"
+)
+
+var kmsApproveWalletCommand = &cobra.Command{
+	Use:   "kms-approve-wallet",
+	Short: "Approve imported KMS wallet as own wallet",
+	Long:  "Imported wallets are not treated as own wallets by signing policy (allow_own_wallets) until approved, as their keys are known outside of KMS. KMS should be stopped",
+	Run:   kmsApproveWallet,
+}
+
+var kmsApproveWalletID string
+
+func kmsApproveWallet(_ *cobra.Command, _ []string) {
+	id, err := uuid.Parse(kmsApproveWalletID)
+	if err != nil {
+		log.Fatalf("Invalid wallet id: %s\n", err.Error())
+	}
+
+	service := kms.NewApp(context.Background(), resolveConfig())
+	if err := service.ApproveWallet(id); err != nil {
+		log.Fatalf("Unable to approve wallet: %s\n", err.Error())
+	}
+
+	log.Println("Approved wallet ✔")
+}
+
+func kmsWalletSetup() {
+	kmsApproveWalletCommand.PersistentFlags().StringVar(&kmsApproveWalletID, "id", "", "KMS wallet UUID")
+}
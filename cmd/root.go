@@ -77,10 +77,45 @@ func init() {
 	createUserCommand.PersistentFlags().BoolVar(&overridePassword, "override-password", false, "overrides password if user already exists")
 
 	rootCmd.AddCommand(listWalletsCommand)
+
+	importWalletSetup(importWalletCommand)
+	rootCmd.AddCommand(importWalletCommand)
+
 	rootCmd.AddCommand(listBalancesCommand)
 
 	topupBalanceSetup(topupBalanceCommand)
 	rootCmd.AddCommand(topupBalanceCommand)
 
+	rootCmd.AddCommand(kmsGenerateKeyCommand)
+	rootCmd.AddCommand(kmsEncryptStoreCommand)
+
+	kmsRotateKeySetup(kmsRotateKeyCommand)
+	rootCmd.AddCommand(kmsRotateKeyCommand)
+
+	kmsHDSetup()
+	rootCmd.AddCommand(kmsExportSeedCommand)
+	rootCmd.AddCommand(kmsImportSeedCommand)
+	rootCmd.AddCommand(kmsRecoverWalletsCommand)
+
+	kmsWalletSetup()
+	rootCmd.AddCommand(kmsApproveWalletCommand)
+
+	kmsBackupSetup()
+	rootCmd.AddCommand(kmsBackupCommand)
+	rootCmd.AddCommand(kmsRestoreCommand)
+
+	kmsAuditSetup()
+	rootCmd.AddCommand(kmsAuditExportCommand)
+	rootCmd.AddCommand(kmsAuditVerifyCommand)
+
+	kmsSealSetup()
+	rootCmd.AddCommand(kmsSplitKeyCommand)
+	rootCmd.AddCommand(kmsUnsealCommand)
+	rootCmd.AddCommand(kmsSealCommand)
+	rootCmd.AddCommand(kmsSealStatusCommand)
+
+	kmsMigrateStoreSetup(kmsMigrateStoreCommand)
+	rootCmd.AddCommand(kmsMigrateStoreCommand)
+
 	rand.Seed(time.Now().Unix())
 }
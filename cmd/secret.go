@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+// readSecret returns secret from env or, if it's not set, reads it from stdin. Secrets are never accepted
+// as flags, so they don't end up in shell history and process list. Input is hidden when stdin is a terminal.
+func readSecret(name, env string) (string, error) {
+	if value := os.Getenv(env); value != "" {
+		return value, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprintf(os.Stderr, "Enter %s (or set %s env): ", name, env)
+
+		value, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+
+		return strings.TrimSpace(string(value)), err
+	}
+
+	value, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && value == "" {
+		return "", errors.Wrapf(err, "unable to read %s from stdin", name)
+	}
+
+	return strings.TrimSpace(value), nil
+}
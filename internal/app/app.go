@@ -71,6 +71,7 @@ func (app *App) RunServer() {
 		app.services.TokenManagerService(),
 		app.services.PaymentService(),
 		app.services.WalletService(),
+		app.services.ExchangeService(),
 		app.services.BlockchainService(),
 		app.services.EventBus(),
 		app.Logger(),
@@ -103,6 +104,8 @@ func (app *App) RunServer() {
 		app.services.WalletService(),
 		app.services.ProcessingService(),
 		app.services.TransactionService(),
+		app.services.Scanner(),
+		app.services.RPCPool(),
 		app.services.JobLogger(),
 	)
 
@@ -133,6 +136,7 @@ func (app *App) RunServer() {
 			internalapi.New(
 				app.services.WalletService(),
 				app.services.BlockchainService(),
+				app.services.ProcessingService(),
 				schedulerHandler,
 				app.logger,
 			),
@@ -197,18 +201,30 @@ func (app *App) RunScheduler() {
 		app.services.WalletService(),
 		app.services.ProcessingService(),
 		app.services.TransactionService(),
+		app.services.Scanner(),
+		app.services.RPCPool(),
 		app.services.JobLogger(),
 	)
 
 	app.registerEventHandlers()
 
+	if len(app.services.Scanner().Blockchains()) > 0 {
+		register("@every 15s", "scanIncomingTransactions", jobs.ScanIncomingTransactions, false)
+	}
+
 	register("@every 30s", "checkIncomingTransactionsProgress", jobs.CheckIncomingTransactionsProgress, false)
+	register("@every 1m", "checkReorganizations", jobs.CheckReorganizations, false)
+	register("@every 1m", "checkRPCHealth", jobs.CheckRPCHealth, false)
 
 	register("@every 10m", "performInternalWalletTransfer", jobs.PerformInternalWalletTransfer, true)
 	register("@every 2m", "checkInternalTransferProgress", jobs.CheckInternalTransferProgress, false)
 
 	register("@every 10m", "performWithdrawalsCreation", jobs.PerformWithdrawalsCreation, true)
 	register("@every 2m", "checkWithdrawalsProgress", jobs.CheckWithdrawalsProgress, false)
+	register("@every 5m", "bumpStuckTransactions", jobs.BumpStuckTransactions, false)
+	register("@every 10m", "reconcileNonces", jobs.ReconcileNonces, false)
+	register("@every 5m", "manageTronEnergy", jobs.ManageTronEnergy, false)
+	register("@every 30m", "performColdWalletSweep", jobs.PerformColdWalletSweep, true)
 
 	register("@every 2m", "cancelExpiredPayments", jobs.CancelExpiredPayments, false)
 }
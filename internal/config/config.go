@@ -9,12 +9,19 @@ import (
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/olekukonko/tablewriter"
 	"github.com/oxygenpay/oxygen/internal/auth"
-	"github.com/oxygenpay/oxygen/internal/db/connection/bolt"
 	"github.com/oxygenpay/oxygen/internal/db/connection/pg"
+	"github.com/oxygenpay/oxygen/internal/kms/encryption"
+	"github.com/oxygenpay/oxygen/internal/kms/storage"
+	"github.com/oxygenpay/oxygen/internal/kms/wallet"
 	"github.com/oxygenpay/oxygen/internal/log"
+	"github.com/oxygenpay/oxygen/internal/provider/notify"
+	"github.com/oxygenpay/oxygen/internal/provider/rates"
+	"github.com/oxygenpay/oxygen/internal/provider/rpcpool"
 	"github.com/oxygenpay/oxygen/internal/provider/tatum"
 	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
+	"github.com/oxygenpay/oxygen/internal/scanner"
 	"github.com/oxygenpay/oxygen/internal/server/http"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
 	"github.com/oxygenpay/oxygen/internal/service/processing"
 	"github.com/oxygenpay/oxygen/internal/util"
 	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/client"
@@ -44,6 +51,8 @@ type Oxygen struct {
 	Auth       auth.Config       `yaml:"auth"`
 	Postgres   pg.Config         `yaml:"postgres"`
 	Processing processing.Config `yaml:"processing"`
+	Scanner    scanner.Config    `yaml:"scanner"`
+	Blockchain blockchain.Config `yaml:"blockchain"`
 }
 
 type KMS struct {
@@ -52,14 +61,26 @@ type KMS struct {
 	// keep private keys secure.
 	IsEmbedded bool `yaml:"-"`
 
-	Server http.Config `yaml:"server"`
-	Bolt   bolt.Config `yaml:"store"`
+	Server     http.Config         `yaml:"server"`
+	Store      storage.Config      `yaml:"store"`
+	Encryption encryption.Config   `yaml:"encryption"`
+	Policy     wallet.PolicyConfig `yaml:"policy"`
+
+	// AuthSecret shared secret for HMAC-signed requests. KMS refuses to start without it unless AuthDisabled is set.
+	AuthSecret   string `yaml:"auth_secret" env:"KMS_AUTH_SECRET" env-description:"Shared secret for HMAC-signed requests to KMS API. Use random string with 32+ chars"`
+	AuthDisabled bool   `yaml:"auth_disabled" env:"KMS_AUTH_DISABLED" env-description:"Accept unsigned requests to KMS API. Use only when KMS is reachable exclusively by the web app"`
+
+	// AuditKey HMAC key of audit log chain. Should not be stored alongside the log.
+	AuditKey string `yaml:"audit_key" env:"KMS_AUDIT_KEY" env-description:"HMAC key of KMS audit log. Use random string with 32+ chars, keep it outside of KMS store"`
 }
 
 type Providers struct {
 	Tatum     tatum.Config    `yaml:"tatum"`
 	Trongrid  trongrid.Config `yaml:"trongrid"`
+	RPC       rpcpool.Config  `yaml:"rpc"`
+	Rates     rates.Config    `yaml:"rates"`
 	KmsClient client.Config   `yaml:"kms"`
+	Notify    notify.Config   `yaml:"notify"`
 }
 
 type Notifications struct {
@@ -16,8 +16,6 @@ type Connection struct {
 	logger *zerolog.Logger
 }
 
-const WalletsBucket = "wallets"
-
 const chmodReadWrite = 0660
 
 func Open(cfg Config, logger *zerolog.Logger) (*Connection, error) {
@@ -43,13 +41,6 @@ func Open(cfg Config, logger *zerolog.Logger) (*Connection, error) {
 	return connection, nil
 }
 
-func (c *Connection) LoadBuckets() error {
-	return c.db.Update(func(tx *bbolt.Tx) error {
-		_, err := tx.CreateBucketIfNotExists([]byte(WalletsBucket))
-		return err
-	})
-}
-
 func (c *Connection) DB() *bbolt.DB {
 	return c.db
 }
@@ -293,6 +293,41 @@ func (q *Queries) ListAllBalancesByType(ctx context.Context, arg ListAllBalances
 	return items, nil
 }
 
+const listBalanceAuditLogSince = `-- name: ListBalanceAuditLogSince :many
+select id, created_at, balance_id, comment, metadata from balance_audit_log where balance_id = $1 and created_at > $2 order by id
+`
+
+type ListBalanceAuditLogSinceParams struct {
+	BalanceID int64
+	CreatedAt time.Time
+}
+
+func (q *Queries) ListBalanceAuditLogSince(ctx context.Context, arg ListBalanceAuditLogSinceParams) ([]BalanceAuditLog, error) {
+	rows, err := q.db.Query(ctx, listBalanceAuditLogSince, arg.BalanceID, arg.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BalanceAuditLog
+	for rows.Next() {
+		var i BalanceAuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.BalanceID,
+			&i.Comment,
+			&i.Metadata,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listBalances = `-- name: ListBalances :many
 select id, created_at, updated_at, entity_id, entity_type, network, network_id, currency_type, currency, decimals, amount, uuid from balances
 where entity_type = $1 and entity_id = $2
@@ -55,6 +55,15 @@ type Customer struct {
 	MerchantID int64
 }
 
+type ExchangeRate struct {
+	ID           int64
+	CreatedAt    time.Time
+	FromCurrency string
+	ToCurrency   string
+	Rate         pgtype.Numeric
+	Source       string
+}
+
 type JobLog struct {
 	ID        int64
 	CreatedAt time.Time
@@ -127,6 +136,24 @@ type PaymentLink struct {
 	IsTest         bool
 }
 
+type Quote struct {
+	ID           int64
+	Uuid         uuid.UUID
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+	MerchantID   int64
+	Type         string
+	FromCurrency string
+	FromAmount   pgtype.Numeric
+	FromDecimals int32
+	ToCurrency   string
+	ToAmount     pgtype.Numeric
+	ToDecimals   int32
+	Rate         pgtype.Numeric
+	RateID       sql.NullInt64
+	PaymentID    sql.NullInt64
+}
+
 type Registry struct {
 	ID          int64
 	CreatedAt   time.Time
@@ -163,6 +190,7 @@ type Transaction struct {
 	IsTest            bool
 	NetworkDecimals   int32
 	SenderWalletID    sql.NullInt64
+	UsdRateID         sql.NullInt64
 }
 
 type User struct {
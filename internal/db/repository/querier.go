@@ -19,11 +19,13 @@ type Querier interface {
 	CreateAPIToken(ctx context.Context, arg CreateAPITokenParams) (ApiToken, error)
 	CreateBalance(ctx context.Context, arg CreateBalanceParams) (Balance, error)
 	CreateCustomer(ctx context.Context, arg CreateCustomerParams) (Customer, error)
+	CreateExchangeRate(ctx context.Context, arg CreateExchangeRateParams) (ExchangeRate, error)
 	CreateJobLog(ctx context.Context, arg CreateJobLogParams) error
 	CreateMerchant(ctx context.Context, arg CreateMerchantParams) (Merchant, error)
 	CreateMerchantAddress(ctx context.Context, arg CreateMerchantAddressParams) (MerchantAddress, error)
 	CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error)
 	CreatePaymentLink(ctx context.Context, arg CreatePaymentLinkParams) (PaymentLink, error)
+	CreateQuote(ctx context.Context, arg CreateQuoteParams) (Quote, error)
 	CreateRegistryItem(ctx context.Context, arg CreateRegistryItemParams) (Registry, error)
 	CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
@@ -48,6 +50,9 @@ type Querier interface {
 	GetCustomerByEmail(ctx context.Context, arg GetCustomerByEmailParams) (Customer, error)
 	GetCustomerByID(ctx context.Context, arg GetCustomerByIDParams) (Customer, error)
 	GetCustomerByUUID(ctx context.Context, arg GetCustomerByUUIDParams) (Customer, error)
+	GetExchangeRateAt(ctx context.Context, arg GetExchangeRateAtParams) (ExchangeRate, error)
+	GetExchangeRateByID(ctx context.Context, id int64) (ExchangeRate, error)
+	GetLatestQuoteByPaymentID(ctx context.Context, arg GetLatestQuoteByPaymentIDParams) (Quote, error)
 	GetLatestTransactionByPaymentID(ctx context.Context, entityID sql.NullInt64) (Transaction, error)
 	GetMerchantAddressByAddress(ctx context.Context, arg GetMerchantAddressByAddressParams) (MerchantAddress, error)
 	GetMerchantAddressByID(ctx context.Context, arg GetMerchantAddressByIDParams) (MerchantAddress, error)
@@ -63,6 +68,7 @@ type Querier interface {
 	GetPaymentLinkByPublicID(ctx context.Context, arg GetPaymentLinkByPublicIDParams) (PaymentLink, error)
 	GetPaymentLinkBySlug(ctx context.Context, slug string) (PaymentLink, error)
 	GetPaymentsByType(ctx context.Context, arg GetPaymentsByTypeParams) ([]Payment, error)
+	GetQuoteByUUID(ctx context.Context, arg GetQuoteByUUIDParams) (Quote, error)
 	GetRecentCustomerPayments(ctx context.Context, arg GetRecentCustomerPaymentsParams) ([]Payment, error)
 	GetRegistryItemByKey(ctx context.Context, arg GetRegistryItemByKeyParams) (Registry, error)
 	GetTransactionByHashAndNetworkID(ctx context.Context, arg GetTransactionByHashAndNetworkIDParams) (Transaction, error)
@@ -71,6 +77,7 @@ type Querier interface {
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByGoogleID(ctx context.Context, googleID sql.NullString) (User, error)
 	GetUserByID(ctx context.Context, id int64) (User, error)
+	GetWalletByAddress(ctx context.Context, arg GetWalletByAddressParams) (Wallet, error)
 	GetWalletByID(ctx context.Context, id int64) (Wallet, error)
 	GetWalletByUUID(ctx context.Context, uuid uuid.UUID) (Wallet, error)
 	GetWalletForUpdateByID(ctx context.Context, id int64) (Wallet, error)
@@ -78,11 +85,15 @@ type Querier interface {
 	InsertBalanceAuditLog(ctx context.Context, arg InsertBalanceAuditLogParams) error
 	ListAPITokensByEntity(ctx context.Context, arg ListAPITokensByEntityParams) ([]ApiToken, error)
 	ListAllBalancesByType(ctx context.Context, arg ListAllBalancesByTypeParams) ([]Balance, error)
+	ListBalanceAuditLogSince(ctx context.Context, arg ListBalanceAuditLogSinceParams) ([]BalanceAuditLog, error)
 	ListBalances(ctx context.Context, arg ListBalancesParams) ([]Balance, error)
+	ListExchangeRates(ctx context.Context, arg ListExchangeRatesParams) ([]ExchangeRate, error)
 	ListJobLogsByID(ctx context.Context, arg ListJobLogsByIDParams) ([]JobLog, error)
 	ListMerchantAddresses(ctx context.Context, merchantID int64) ([]MerchantAddress, error)
 	ListMerchantsByCreatorID(ctx context.Context, arg ListMerchantsByCreatorIDParams) ([]Merchant, error)
+	ListOrphanedTransactionsWithPaymentStatus(ctx context.Context, arg ListOrphanedTransactionsWithPaymentStatusParams) ([]Transaction, error)
 	ListPaymentLinks(ctx context.Context, arg ListPaymentLinksParams) ([]PaymentLink, error)
+	ListTransactionsForReorgCheck(ctx context.Context, arg ListTransactionsForReorgCheckParams) ([]Transaction, error)
 	ListUsers(ctx context.Context) ([]User, error)
 	PaginateCustomersAsc(ctx context.Context, arg PaginateCustomersAscParams) ([]Customer, error)
 	PaginateCustomersDesc(ctx context.Context, arg PaginateCustomersDescParams) ([]Customer, error)
@@ -99,8 +110,10 @@ type Querier interface {
 	UpdatePayment(ctx context.Context, arg UpdatePaymentParams) (Payment, error)
 	UpdatePaymentCustomerID(ctx context.Context, arg UpdatePaymentCustomerIDParams) error
 	UpdatePaymentWebhookInfo(ctx context.Context, arg UpdatePaymentWebhookInfoParams) error
+	UpdateQuotePaymentID(ctx context.Context, arg UpdateQuotePaymentIDParams) (Quote, error)
 	UpdateRegistryItem(ctx context.Context, arg UpdateRegistryItemParams) (Registry, error)
 	UpdateTransaction(ctx context.Context, arg UpdateTransactionParams) (Transaction, error)
+	UpdateTransactionMetadata(ctx context.Context, arg UpdateTransactionMetadataParams) error
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
 	UpdateWalletMainnetTransactionCounters(ctx context.Context, arg UpdateWalletMainnetTransactionCountersParams) error
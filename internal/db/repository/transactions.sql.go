@@ -53,14 +53,15 @@ insert into transactions (
       transaction_hash,
       blockchain, network_id, currency_type, currency, decimals, network_decimals,
       amount, fact_amount, network_fee, service_fee, usd_amount,
-      metadata, is_test
+      metadata, is_test, usd_rate_id
 )
 values (
       $1, $2, $3, $4, $5, $6,
       $7, $8, $9, $10, $11, $12,
       $13, $14, $15, $16, $17, $18,
-      $19, $20, $21, $22, $23, $24
-) returning id, created_at, updated_at, merchant_id, status, type, entity_id, recipient_wallet_id, sender_address, recipient_address, transaction_hash, blockchain, currency_type, currency, decimals, amount, fact_amount, network_fee, service_fee, usd_amount, metadata, network_id, is_test, network_decimals, sender_wallet_id
+      $19, $20, $21, $22, $23, $24,
+      $25
+) returning id, created_at, updated_at, merchant_id, status, type, entity_id, recipient_wallet_id, sender_address, recipient_address, transaction_hash, blockchain, currency_type, currency, decimals, amount, fact_amount, network_fee, service_fee, usd_amount, metadata, network_id, is_test, network_decimals, sender_wallet_id, usd_rate_id
 `
 
 type CreateTransactionParams struct {
@@ -88,6 +89,7 @@ type CreateTransactionParams struct {
 	UsdAmount         pgtype.Numeric
 	Metadata          pgtype.JSONB
 	IsTest            bool
+	UsdRateID         sql.NullInt64
 }
 
 func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
@@ -116,6 +118,7 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		arg.UsdAmount,
 		arg.Metadata,
 		arg.IsTest,
+		arg.UsdRateID,
 	)
 	var i Transaction
 	err := row.Scan(
@@ -144,12 +147,13 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		&i.IsTest,
 		&i.NetworkDecimals,
 		&i.SenderWalletID,
+		&i.UsdRateID,
 	)
 	return i, err
 }
 
 const eagerLoadTransactionsByPaymentID = `-- name: EagerLoadTransactionsByPaymentID :many
-select distinct on (entity_id) id, created_at, updated_at, merchant_id, status, type, entity_id, recipient_wallet_id, sender_address, recipient_address, transaction_hash, blockchain, currency_type, currency, decimals, amount, fact_amount, network_fee, service_fee, usd_amount, metadata, network_id, is_test, network_decimals, sender_wallet_id from transactions
+select distinct on (entity_id) id, created_at, updated_at, merchant_id, status, type, entity_id, recipient_wallet_id, sender_address, recipient_address, transaction_hash, blockchain, currency_type, currency, decimals, amount, fact_amount, network_fee, service_fee, usd_amount, metadata, network_id, is_test, network_decimals, sender_wallet_id, usd_rate_id from transactions
 where merchant_id = $1 and entity_id = any($2::int[])
 and entity_id = any($2::int[])
 and type = any($3::varchar[])
@@ -197,6 +201,7 @@ func (q *Queries) EagerLoadTransactionsByPaymentID(ctx context.Context, arg Eage
 			&i.IsTest,
 			&i.NetworkDecimals,
 			&i.SenderWalletID,
+			&i.UsdRateID,
 		); err != nil {
 			return nil, err
 		}
@@ -209,7 +214,7 @@ func (q *Queries) EagerLoadTransactionsByPaymentID(ctx context.Context, arg Eage
 }
 
 const getLatestTransactionByPaymentID = `-- name: GetLatestTransactionByPaymentID :one
-select id, created_at, updated_at, merchant_id, status, type, entity_id, recipient_wallet_id, sender_address, recipient_address, transaction_hash, blockchain, currency_type, currency, decimals, amount, fact_amount, network_fee, service_fee, usd_amount, metadata, network_id, is_test, network_decimals, sender_wallet_id from transactions where entity_id = $1 order by id desc limit 1
+select id, created_at, updated_at, merchant_id, status, type, entity_id, recipient_wallet_id, sender_address, recipient_address, transaction_hash, blockchain, currency_type, currency, decimals, amount, fact_amount, network_fee, service_fee, usd_amount, metadata, network_id, is_test, network_decimals, sender_wallet_id, usd_rate_id from transactions where entity_id = $1 order by id desc limit 1
 `
 
 func (q *Queries) GetLatestTransactionByPaymentID(ctx context.Context, entityID sql.NullInt64) (Transaction, error) {
@@ -241,12 +246,13 @@ func (q *Queries) GetLatestTransactionByPaymentID(ctx context.Context, entityID
 		&i.IsTest,
 		&i.NetworkDecimals,
 		&i.SenderWalletID,
+		&i.UsdRateID,
 	)
 	return i, err
 }
 
 const getTransactionByHashAndNetworkID = `-- name: GetTransactionByHashAndNetworkID :one
-select id, created_at, updated_at, merchant_id, status, type, entity_id, recipient_wallet_id, sender_address, recipient_address, transaction_hash, blockchain, currency_type, currency, decimals, amount, fact_amount, network_fee, service_fee, usd_amount, metadata, network_id, is_test, network_decimals, sender_wallet_id from transactions where transaction_hash = $1 and network_id = $2 limit 1
+select id, created_at, updated_at, merchant_id, status, type, entity_id, recipient_wallet_id, sender_address, recipient_address, transaction_hash, blockchain, currency_type, currency, decimals, amount, fact_amount, network_fee, service_fee, usd_amount, metadata, network_id, is_test, network_decimals, sender_wallet_id, usd_rate_id from transactions where transaction_hash = $1 and network_id = $2 limit 1
 `
 
 type GetTransactionByHashAndNetworkIDParams struct {
@@ -283,12 +289,13 @@ func (q *Queries) GetTransactionByHashAndNetworkID(ctx context.Context, arg GetT
 		&i.IsTest,
 		&i.NetworkDecimals,
 		&i.SenderWalletID,
+		&i.UsdRateID,
 	)
 	return i, err
 }
 
 const getTransactionByID = `-- name: GetTransactionByID :one
-select id, created_at, updated_at, merchant_id, status, type, entity_id, recipient_wallet_id, sender_address, recipient_address, transaction_hash, blockchain, currency_type, currency, decimals, amount, fact_amount, network_fee, service_fee, usd_amount, metadata, network_id, is_test, network_decimals, sender_wallet_id from transactions where id = $1
+select id, created_at, updated_at, merchant_id, status, type, entity_id, recipient_wallet_id, sender_address, recipient_address, transaction_hash, blockchain, currency_type, currency, decimals, amount, fact_amount, network_fee, service_fee, usd_amount, metadata, network_id, is_test, network_decimals, sender_wallet_id, usd_rate_id from transactions where id = $1
 and (CASE WHEN $3::boolean THEN merchant_id = $2 ELSE true END)
 `
 
@@ -327,18 +334,20 @@ func (q *Queries) GetTransactionByID(ctx context.Context, arg GetTransactionByID
 		&i.IsTest,
 		&i.NetworkDecimals,
 		&i.SenderWalletID,
+		&i.UsdRateID,
 	)
 	return i, err
 }
 
 const getTransactionsByFilter = `-- name: GetTransactionsByFilter :many
-select id, created_at, updated_at, merchant_id, status, type, entity_id, recipient_wallet_id, sender_address, recipient_address, transaction_hash, blockchain, currency_type, currency, decimals, amount, fact_amount, network_fee, service_fee, usd_amount, metadata, network_id, is_test, network_decimals, sender_wallet_id from transactions
+select id, created_at, updated_at, merchant_id, status, type, entity_id, recipient_wallet_id, sender_address, recipient_address, transaction_hash, blockchain, currency_type, currency, decimals, amount, fact_amount, network_fee, service_fee, usd_amount, metadata, network_id, is_test, network_decimals, sender_wallet_id, usd_rate_id from transactions
 where (CASE WHEN $5::boolean THEN recipient_wallet_id = $1 ELSE true END)
 and (CASE WHEN $6::boolean THEN network_id = $2 ELSE true END)
 and (CASE WHEN $7::boolean THEN currency = $3 ELSE true END)
 and (CASE WHEN $8::boolean THEN type = any($9::varchar[]) ELSE true END)
 and (CASE WHEN $10::boolean THEN status = any($11::varchar[]) ELSE true END)
 and (CASE WHEN $12::boolean THEN transaction_hash is null ELSE true END)
+and (CASE WHEN $13::boolean THEN sender_wallet_id = $14 ELSE true END)
 order by id desc
 limit $4
 `
@@ -356,6 +365,8 @@ type GetTransactionsByFilterParams struct {
 	FilterByStatuses          bool
 	Statuses                  []string
 	FilterEmptyHash           bool
+	FilterBySenderWalletID    bool
+	SenderWalletID            sql.NullInt64
 }
 
 func (q *Queries) GetTransactionsByFilter(ctx context.Context, arg GetTransactionsByFilterParams) ([]Transaction, error) {
@@ -372,6 +383,77 @@ func (q *Queries) GetTransactionsByFilter(ctx context.Context, arg GetTransactio
 		arg.FilterByStatuses,
 		arg.Statuses,
 		arg.FilterEmptyHash,
+		arg.FilterBySenderWalletID,
+		arg.SenderWalletID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.MerchantID,
+			&i.Status,
+			&i.Type,
+			&i.EntityID,
+			&i.RecipientWalletID,
+			&i.SenderAddress,
+			&i.RecipientAddress,
+			&i.TransactionHash,
+			&i.Blockchain,
+			&i.CurrencyType,
+			&i.Currency,
+			&i.Decimals,
+			&i.Amount,
+			&i.FactAmount,
+			&i.NetworkFee,
+			&i.ServiceFee,
+			&i.UsdAmount,
+			&i.Metadata,
+			&i.NetworkID,
+			&i.IsTest,
+			&i.NetworkDecimals,
+			&i.SenderWalletID,
+			&i.UsdRateID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrphanedTransactionsWithPaymentStatus = `-- name: ListOrphanedTransactionsWithPaymentStatus :many
+select transactions.id, transactions.created_at, transactions.updated_at, transactions.merchant_id, transactions.status, transactions.type, transactions.entity_id, transactions.recipient_wallet_id, transactions.sender_address, transactions.recipient_address, transactions.transaction_hash, transactions.blockchain, transactions.currency_type, transactions.currency, transactions.decimals, transactions.amount, transactions.fact_amount, transactions.network_fee, transactions.service_fee, transactions.usd_amount, transactions.metadata, transactions.network_id, transactions.is_test, transactions.network_decimals, transactions.sender_wallet_id, transactions.usd_rate_id from transactions
+join payments on payments.id = transactions.entity_id and payments.merchant_id = transactions.merchant_id
+where transactions.type = $1
+and transactions.status = $2
+and payments.status = $4
+order by transactions.id
+limit $3
+`
+
+type ListOrphanedTransactionsWithPaymentStatusParams struct {
+	Type          string
+	Status        string
+	Limit         int32
+	PaymentStatus string
+}
+
+func (q *Queries) ListOrphanedTransactionsWithPaymentStatus(ctx context.Context, arg ListOrphanedTransactionsWithPaymentStatusParams) ([]Transaction, error) {
+	rows, err := q.db.Query(ctx, listOrphanedTransactionsWithPaymentStatus,
+		arg.Type,
+		arg.Status,
+		arg.Limit,
+		arg.PaymentStatus,
 	)
 	if err != nil {
 		return nil, err
@@ -406,6 +488,77 @@ func (q *Queries) GetTransactionsByFilter(ctx context.Context, arg GetTransactio
 			&i.IsTest,
 			&i.NetworkDecimals,
 			&i.SenderWalletID,
+			&i.UsdRateID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransactionsForReorgCheck = `-- name: ListTransactionsForReorgCheck :many
+select id, created_at, updated_at, merchant_id, status, type, entity_id, recipient_wallet_id, sender_address, recipient_address, transaction_hash, blockchain, currency_type, currency, decimals, amount, fact_amount, network_fee, service_fee, usd_amount, metadata, network_id, is_test, network_decimals, sender_wallet_id, usd_rate_id from transactions
+where type = $1
+and status = any($3::varchar[])
+and metadata->>'blockNumber' is not null
+and metadata->>'reorgVerified' is null
+and id > $4
+order by id
+limit $2
+`
+
+type ListTransactionsForReorgCheckParams struct {
+	Type     string
+	Limit    int32
+	Statuses []string
+	AfterID  int64
+}
+
+func (q *Queries) ListTransactionsForReorgCheck(ctx context.Context, arg ListTransactionsForReorgCheckParams) ([]Transaction, error) {
+	rows, err := q.db.Query(ctx, listTransactionsForReorgCheck,
+		arg.Type,
+		arg.Limit,
+		arg.Statuses,
+		arg.AfterID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.MerchantID,
+			&i.Status,
+			&i.Type,
+			&i.EntityID,
+			&i.RecipientWalletID,
+			&i.SenderAddress,
+			&i.RecipientAddress,
+			&i.TransactionHash,
+			&i.Blockchain,
+			&i.CurrencyType,
+			&i.Currency,
+			&i.Decimals,
+			&i.Amount,
+			&i.FactAmount,
+			&i.NetworkFee,
+			&i.ServiceFee,
+			&i.UsdAmount,
+			&i.Metadata,
+			&i.NetworkID,
+			&i.IsTest,
+			&i.NetworkDecimals,
+			&i.SenderWalletID,
+			&i.UsdRateID,
 		); err != nil {
 			return nil, err
 		}
@@ -438,6 +591,27 @@ func (q *Queries) SetTransactionHash(ctx context.Context, arg SetTransactionHash
 	return err
 }
 
+const updateTransactionMetadata = `-- name: UpdateTransactionMetadata :exec
+update transactions set metadata = $1, updated_at = $2 where id = $3 and merchant_id = $4
+`
+
+type UpdateTransactionMetadataParams struct {
+	Metadata   pgtype.JSONB
+	UpdatedAt  time.Time
+	ID         int64
+	MerchantID int64
+}
+
+func (q *Queries) UpdateTransactionMetadata(ctx context.Context, arg UpdateTransactionMetadataParams) error {
+	_, err := q.db.Exec(ctx, updateTransactionMetadata,
+		arg.Metadata,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.MerchantID,
+	)
+	return err
+}
+
 const updateTransaction = `-- name: UpdateTransaction :one
 update transactions set
 status = $3,
@@ -449,7 +623,7 @@ network_fee = $8,
 service_fee = CASE WHEN $10::boolean THEN 0 ELSE transactions.service_fee END,
 metadata = $9
 where merchant_id = $1 and id = $2
-returning id, created_at, updated_at, merchant_id, status, type, entity_id, recipient_wallet_id, sender_address, recipient_address, transaction_hash, blockchain, currency_type, currency, decimals, amount, fact_amount, network_fee, service_fee, usd_amount, metadata, network_id, is_test, network_decimals, sender_wallet_id
+returning id, created_at, updated_at, merchant_id, status, type, entity_id, recipient_wallet_id, sender_address, recipient_address, transaction_hash, blockchain, currency_type, currency, decimals, amount, fact_amount, network_fee, service_fee, usd_amount, metadata, network_id, is_test, network_decimals, sender_wallet_id, usd_rate_id
 `
 
 type UpdateTransactionParams struct {
@@ -505,6 +679,7 @@ func (q *Queries) UpdateTransaction(ctx context.Context, arg UpdateTransactionPa
 		&i.IsTest,
 		&i.NetworkDecimals,
 		&i.SenderWalletID,
+		&i.UsdRateID,
 	)
 	return i, err
 }
@@ -170,6 +170,38 @@ func (q *Queries) GetAvailableWallet(ctx context.Context, arg GetAvailableWallet
 	return i, err
 }
 
+const getWalletByAddress = `-- name: GetWalletByAddress :one
+SELECT id, created_at, uuid, address, blockchain, tatum_mainnet_subscription_id, tatum_testnet_subscription_id, type, confirmed_mainnet_transactions, pending_mainnet_transactions, pending_testnet_transactions, confirmed_testnet_transactions
+FROM wallets
+WHERE blockchain = $1 AND lower(address) = lower($2::text)
+LIMIT 1
+`
+
+type GetWalletByAddressParams struct {
+	Blockchain string
+	Address    string
+}
+
+func (q *Queries) GetWalletByAddress(ctx context.Context, arg GetWalletByAddressParams) (Wallet, error) {
+	row := q.db.QueryRow(ctx, getWalletByAddress, arg.Blockchain, arg.Address)
+	var i Wallet
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.Uuid,
+		&i.Address,
+		&i.Blockchain,
+		&i.TatumMainnetSubscriptionID,
+		&i.TatumTestnetSubscriptionID,
+		&i.Type,
+		&i.ConfirmedMainnetTransactions,
+		&i.PendingMainnetTransactions,
+		&i.PendingTestnetTransactions,
+		&i.ConfirmedTestnetTransactions,
+	)
+	return i, err
+}
+
 const getWalletByID = `-- name: GetWalletByID :one
 SELECT id, created_at, uuid, address, blockchain, tatum_mainnet_subscription_id, tatum_testnet_subscription_id, type, confirmed_mainnet_transactions, pending_mainnet_transactions, pending_testnet_transactions, confirmed_testnet_transactions
 FROM wallets
@@ -2,9 +2,14 @@ package api
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/oxygenpay/oxygen/internal/kms/audit"
+	"github.com/oxygenpay/oxygen/internal/kms/seal"
 	"github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
 	httpServer "github.com/oxygenpay/oxygen/internal/server/http"
 	"github.com/oxygenpay/oxygen/internal/server/http/common"
 	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/model"
@@ -13,33 +18,65 @@ import (
 )
 
 type Handler struct {
-	wallets *wallet.Service
-	logger  *zerolog.Logger
+	wallets  *wallet.Service
+	auditLog *audit.Log
+	unsealer *seal.Unsealer
+	logger   *zerolog.Logger
 }
 
-const paramWalletID = "walletId"
+var errNotSealedMode = errors.New("KMS is not running in sealed mode")
 
-func SetupRoutes(handler *Handler) httpServer.Opt {
+const (
+	paramWalletID      = "walletId"
+	paramQueryFromSeq  = "fromSeq"
+	paramQueryHeadHash = "headHash"
+)
+
+// SensitivePaths routes which requests or responses carry private keys or master key shares.
+// Their bodies should never be logged.
+var SensitivePaths = []string{
+	"/api/kms/v1/wallet/import",
+	"/api/kms/v1/system/unseal",
+}
+
+func SetupRoutes(handler *Handler, middlewares ...echo.MiddlewareFunc) httpServer.Opt {
 	return func(s *httpServer.Server) {
-		kmsAPI := s.Echo().Group("/api/kms/v1")
+		kmsAPI := s.Echo().Group("/api/kms/v1", append(middlewares, auditCaller())...)
+
+		walletAPI := kmsAPI.Group("/wallet", requireUnsealed(handler.unsealer))
 
-		kmsAPI.POST("/wallet", handler.Create)
-		kmsAPI.GET("/wallet/:walletId", handler.Get)
-		kmsAPI.DELETE("/wallet/:walletId", handler.Delete)
+		walletAPI.POST("", handler.Create)
+		walletAPI.POST("/import", handler.Import)
+		walletAPI.GET("/:walletId", handler.Get)
+		walletAPI.DELETE("/:walletId", handler.Delete)
 
-		kmsAPI.POST("/wallet/:walletId/transaction/eth", handler.CreateEthereumTransaction)
-		kmsAPI.POST("/wallet/:walletId/transaction/matic", handler.CreateMaticTransaction)
-		kmsAPI.POST("/wallet/:walletId/transaction/bsc", handler.CreateBSCTransaction)
-		kmsAPI.POST("/wallet/:walletId/transaction/tron", handler.CreateTronTransaction)
+		walletAPI.POST("/:walletId/transaction/eth", handler.CreateEthereumTransaction)
+		walletAPI.POST("/:walletId/transaction/matic", handler.CreateMaticTransaction)
+		walletAPI.POST("/:walletId/transaction/bsc", handler.CreateBSCTransaction)
+		walletAPI.POST("/:walletId/transaction/tron", handler.CreateTronTransaction)
+		walletAPI.POST("/:walletId/transaction/tron/resource", handler.CreateTronResourceTransaction)
+		walletAPI.POST("/:walletId/message", handler.SignMessage)
+
+		kmsAPI.GET("/audit", handler.ExportAuditLog)
+		kmsAPI.GET("/audit/verify", handler.VerifyAuditLog)
+
+		systemAPI := kmsAPI.Group("/system", localOnly())
+
+		systemAPI.GET("/seal-status", handler.GetSealStatus)
+		systemAPI.POST("/unseal", handler.Unseal)
+		systemAPI.POST("/seal", handler.Seal)
 	}
 }
 
-func New(wallets *wallet.Service, logger *zerolog.Logger) *Handler {
+// New Handler constructor. Unsealer is nil if KMS is not running in sealed mode.
+func New(wallets *wallet.Service, auditLog *audit.Log, unsealer *seal.Unsealer, logger *zerolog.Logger) *Handler {
 	log := logger.With().Str("channel", "kms_handler").Logger()
 
 	return &Handler{
-		wallets: wallets,
-		logger:  &log,
+		wallets:  wallets,
+		auditLog: auditLog,
+		unsealer: unsealer,
+		logger:   &log,
 	}
 }
 
@@ -59,6 +96,41 @@ func (h *Handler) Create(c echo.Context) error {
 	return c.JSON(http.StatusCreated, walletToResponse(w))
 }
 
+func (h *Handler) Import(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req model.ImportWalletRequest
+	if valid := common.BindAndValidateRequest(c, &req); !valid {
+		return nil
+	}
+
+	w, err := h.wallets.ImportWallet(ctx, wallet.ImportParams{
+		Blockchain:         wallet.Blockchain(req.Blockchain),
+		Address:            req.Address,
+		PrivateKey:         req.PrivateKey,
+		Mnemonic:           req.Mnemonic,
+		MnemonicPassphrase: req.MnemonicPassphrase,
+		DerivationPath:     req.DerivationPath,
+	})
+
+	switch {
+	case errors.Is(err, wallet.ErrUnknownBlockchain),
+		errors.Is(err, wallet.ErrInvalidAddress),
+		errors.Is(err, wallet.ErrInvalidImportParams),
+		errors.Is(err, wallet.ErrInvalidPrivateKey),
+		errors.Is(err, wallet.ErrInvalidMnemonic),
+		errors.Is(err, wallet.ErrInvalidDerivationPath),
+		errors.Is(err, wallet.ErrAddressMismatch),
+		errors.Is(err, wallet.ErrWalletExists):
+		return common.ValidationErrorResponse(c, err)
+	case err != nil:
+		h.logger.Error().Err(err).Str("blockchain", string(req.Blockchain)).Msg("unable to import wallet")
+		return common.ErrorResponse(c, "unable to import wallet")
+	}
+
+	return c.JSON(http.StatusCreated, walletToResponse(w))
+}
+
 func (h *Handler) Get(c echo.Context) error {
 	ctx := c.Request().Context()
 
@@ -268,6 +340,193 @@ func (h *Handler) CreateTronTransaction(c echo.Context) error {
 	})
 }
 
+func (h *Handler) CreateTronResourceTransaction(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := common.UUID(c, paramWalletID)
+	if err != nil {
+		return err
+	}
+
+	w, err := h.wallets.GetWallet(ctx, id, false)
+
+	switch {
+	case errors.Is(err, wallet.ErrNotFound):
+		return common.NotFoundResponse(c, wallet.ErrNotFound.Error())
+	case err != nil:
+		return err
+	}
+
+	var req model.CreateTronResourceTransactionRequest
+	if valid := common.BindAndValidateRequest(c, &req); !valid {
+		return nil
+	}
+
+	tx, err := h.wallets.CreateTronResourceTransaction(ctx, w, wallet.TronResourceParams{
+		Operation: wallet.TronResourceOperation(req.Operation),
+		Resource:  trongrid.Resource(req.Resource),
+		Amount:    req.Amount,
+		Receiver:  req.Receiver,
+		IsTest:    req.IsTest,
+	})
+
+	if err != nil {
+		return transactionCreationFailed(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, &model.TronTransaction{
+		TxID:       tx.TxID,
+		Visible:    tx.Visible,
+		RawData:    tx.RawData,
+		RawDataHex: tx.RawDataHex,
+		Signature:  tx.Signature,
+	})
+}
+
+func (h *Handler) SignMessage(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := common.UUID(c, paramWalletID)
+	if err != nil {
+		return err
+	}
+
+	w, err := h.wallets.GetWallet(ctx, id, false)
+
+	switch {
+	case errors.Is(err, wallet.ErrNotFound):
+		return common.NotFoundResponse(c, wallet.ErrNotFound.Error())
+	case err != nil:
+		return err
+	}
+
+	var req model.SignMessageRequest
+	if valid := common.BindAndValidateRequest(c, &req); !valid {
+		return nil
+	}
+
+	params := wallet.SignMessageParams{
+		Type:      wallet.MessageType(req.Type),
+		Challenge: req.Challenge,
+	}
+
+	signed, err := h.wallets.SignMessage(ctx, w, params)
+
+	switch {
+	case errors.Is(err, wallet.ErrInvalidMessage), errors.Is(err, wallet.ErrUnsupportedMessageType):
+		return common.ValidationErrorResponse(c, err)
+	case errors.Is(err, wallet.ErrPolicyViolation):
+		return c.JSON(http.StatusForbidden, &model.ErrorResponse{
+			Message: err.Error(),
+			Status:  "policy_violation",
+		})
+	case err != nil:
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &model.SignedMessage{
+		Address:   signed.Address,
+		Message:   signed.Message,
+		Timestamp: signed.Timestamp.Unix(),
+		Hash:      signed.Hash,
+		Signature: signed.Signature,
+	})
+}
+
+func (h *Handler) ExportAuditLog(c echo.Context) error {
+	var fromSeq uint64
+	if raw := c.QueryParam(paramQueryFromSeq); raw != "" {
+		var err error
+		if fromSeq, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			return common.ValidationErrorItemResponse(c, paramQueryFromSeq, "invalid sequence number")
+		}
+	}
+
+	limit, err := common.QueryLimit(c)
+	if err != nil {
+		return common.ValidationErrorItemResponse(c, common.ParamQueryLimit, err.Error())
+	}
+
+	entries, err := h.auditLog.List(fromSeq, limit)
+	if err != nil {
+		return err
+	}
+
+	res := &model.AuditLog{Entries: make([]*model.AuditEntry, len(entries))}
+	for i := range entries {
+		res.Entries[i] = auditEntryToResponse(entries[i])
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) VerifyAuditLog(c echo.Context) error {
+	result, err := h.auditLog.Verify(c.QueryParam(paramQueryHeadHash))
+	if err != nil {
+		return err
+	}
+
+	if !result.Valid {
+		h.logger.Error().
+			Uint64("broken_at", result.BrokenAt).
+			Str("reason", result.Reason).
+			Msg("audit log verification failed")
+	}
+
+	return c.JSON(http.StatusOK, &model.AuditVerification{
+		Valid:        result.Valid,
+		EntriesCount: int64(result.Count),
+		HeadHash:     result.HeadHash,
+		BrokenAt:     int64(result.BrokenAt),
+		Reason:       result.Reason,
+	})
+}
+
+func (h *Handler) GetSealStatus(c echo.Context) error {
+	if h.unsealer == nil {
+		return c.JSON(http.StatusOK, &model.SealStatus{})
+	}
+
+	return c.JSON(http.StatusOK, sealStatusToResponse(h.unsealer.Status()))
+}
+
+func (h *Handler) Unseal(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req model.UnsealRequest
+	if valid := common.BindAndValidateRequest(c, &req); !valid {
+		return nil
+	}
+
+	if h.unsealer == nil {
+		return common.ValidationErrorResponse(c, errNotSealedMode)
+	}
+
+	status, err := h.unsealer.Submit(ctx, req.Share)
+
+	switch {
+	case errors.Is(err, seal.ErrInvalidShare),
+		errors.Is(err, seal.ErrDuplicateShare),
+		errors.Is(err, seal.ErrInvalidShares),
+		errors.Is(err, seal.ErrNotSealed):
+		return common.ValidationErrorResponse(c, err)
+	case err != nil:
+		return err
+	}
+
+	return c.JSON(http.StatusOK, sealStatusToResponse(status))
+}
+
+func (h *Handler) Seal(c echo.Context) error {
+	if h.unsealer == nil {
+		return common.ValidationErrorResponse(c, errNotSealedMode)
+	}
+
+	status := h.unsealer.Seal(c.Request().Context())
+
+	return c.JSON(http.StatusOK, sealStatusToResponse(status))
+}
+
 func transactionCreationFailed(c echo.Context, err error) error {
 	switch {
 	case errors.Is(err, wallet.ErrUnknownBlockchain):
@@ -288,11 +547,24 @@ func transactionCreationFailed(c echo.Context, err error) error {
 		return common.ValidationErrorResponse(c, wallet.ErrTronResponse)
 	case errors.Is(err, wallet.ErrInsufficientBalance):
 		return common.ValidationErrorResponse(c, wallet.ErrInsufficientBalance)
+	case errors.Is(err, wallet.ErrPolicyViolation):
+		return c.JSON(http.StatusForbidden, &model.ErrorResponse{
+			Message: err.Error(),
+			Status:  "policy_violation",
+		})
 	default:
 		return err
 	}
 }
 
+func sealStatusToResponse(s seal.Status) *model.SealStatus {
+	return &model.SealStatus{
+		Sealed:    s.Sealed,
+		Threshold: int64(s.Threshold),
+		Progress:  int64(s.Progress),
+	}
+}
+
 func walletToResponse(w *wallet.Wallet) *model.Wallet {
 	return &model.Wallet{
 		ID:            w.UUID.String(),
@@ -302,3 +574,24 @@ func walletToResponse(w *wallet.Wallet) *model.Wallet {
 		PublicKey:     w.PublicKey,
 	}
 }
+
+func auditEntryToResponse(e audit.Entry) *model.AuditEntry {
+	return &model.AuditEntry{
+		Seq:             int64(e.Seq),
+		CreatedAt:       e.CreatedAt.Format(time.RFC3339Nano),
+		Operation:       string(e.Operation),
+		WalletID:        e.WalletID,
+		Blockchain:      e.Blockchain,
+		Recipient:       e.Recipient,
+		Asset:           e.Asset,
+		Amount:          e.Amount,
+		Caller:          e.Caller,
+		KeyID:           e.KeyID,
+		Result:          string(e.Result),
+		Error:           e.Error,
+		TransactionHash: e.TransactionHash,
+		Message:         e.Message,
+		PrevHash:        e.PrevHash,
+		Hash:            e.Hash,
+	}
+}
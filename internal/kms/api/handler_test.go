@@ -26,6 +26,7 @@ func TestHandlerRoutes(t *testing.T) {
 		polygonTransactionRoute  = "/api/kms/v1/wallet/:walletId/transaction/matic"
 		bscTransactionRoute      = "/api/kms/v1/wallet/:walletId/transaction/bsc"
 		tronTransactionRoute     = "/api/kms/v1/wallet/:walletId/transaction/tron"
+		signMessageRoute         = "/api/kms/v1/wallet/:walletId/message"
 	)
 
 	tc := test.NewIntegrationTest(t)
@@ -430,4 +431,62 @@ func TestHandlerRoutes(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("SignMessage", func(t *testing.T) {
+		const challenge = "6f1c2b7e-3d4a-4c1e-9f7a-2b8d5e0c1a93"
+
+		// note that test kms has no policy, so message signing is denied for every wallet
+		for testCaseIndex, testCase := range []struct {
+			wallet *wallet.Wallet
+			req    model.SignMessageRequest
+			status int
+		}{
+			{
+				wallet: createWallet(wallet.ETH),
+				req:    model.SignMessageRequest{Type: "personal_sign", Challenge: challenge},
+				status: http.StatusForbidden,
+			},
+			{
+				wallet: createWallet(wallet.BSC),
+				req:    model.SignMessageRequest{Type: "typed_data", Challenge: challenge},
+				status: http.StatusForbidden,
+			},
+			{
+				wallet: createWallet(wallet.TRON),
+				req:    model.SignMessageRequest{Type: "tron", Challenge: challenge},
+				status: http.StatusForbidden,
+			},
+			{
+				// blockchain mismatch
+				wallet: createWallet(wallet.TRON),
+				req:    model.SignMessageRequest{Type: "personal_sign", Challenge: challenge},
+				status: http.StatusBadRequest,
+			},
+			{
+				// unknown type
+				wallet: createWallet(wallet.ETH),
+				req:    model.SignMessageRequest{Type: "eth_sign", Challenge: challenge},
+				status: http.StatusBadRequest,
+			},
+			{
+				// arbitrary text instead of challenge
+				wallet: createWallet(wallet.ETH),
+				req:    model.SignMessageRequest{Type: "personal_sign", Challenge: "I approve order #42"},
+				status: http.StatusBadRequest,
+			},
+		} {
+			t.Run(strconv.Itoa(testCaseIndex+1), func(t *testing.T) {
+				// ACT
+				res := tc.Client.
+					POST().
+					Path(signMessageRoute).
+					Param(paramWalletID, testCase.wallet.UUID.String()).
+					JSON(&testCase.req).
+					Do()
+
+				// ASSERT
+				assert.Equal(t, testCase.status, res.StatusCode(), res.String())
+			})
+		}
+	})
 }
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/oxygenpay/oxygen/internal/kms/audit"
+	"github.com/oxygenpay/oxygen/internal/kms/seal"
+	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/auth"
+	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/model"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// maxBodySize limits request body that is buffered before authentication.
+// KMS payloads (wallets, transactions to sign, messages) are far smaller.
+const maxBodySize = 256 << 10
+
+// RequireSignature rejects requests that are not signed with the shared secret (see auth package).
+// Request body is limited to maxBodySize as it's read before the signature is verified.
+func RequireSignature(verifier *auth.Verifier, logger *zerolog.Logger) echo.MiddlewareFunc {
+	log := logger.With().Str("channel", "kms_auth").Logger()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			body, err := io.ReadAll(http.MaxBytesReader(c.Response(), req.Body, maxBodySize))
+
+			var tooLarge *http.MaxBytesError
+			switch {
+			case errors.As(err, &tooLarge):
+				return c.JSON(http.StatusRequestEntityTooLarge, &model.ErrorResponse{
+					Message: "request body is too large",
+					Status:  "request_too_large",
+				})
+			case err != nil:
+				return err
+			}
+
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			if err := verifier.Verify(req, body); err != nil {
+				log.Warn().Err(err).
+					Str("method", req.Method).
+					Str("uri", req.URL.RequestURI()).
+					Str("remote_ip", c.RealIP()).
+					Msg("rejected unauthenticated request")
+
+				return c.JSON(http.StatusUnauthorized, &model.ErrorResponse{
+					Message: err.Error(),
+					Status:  "unauthenticated",
+				})
+			}
+
+			c.SetRequest(req.WithContext(audit.WithKeyID(req.Context(), verifier.KeyID())))
+
+			return next(c)
+		}
+	}
+}
+
+// auditCaller stores request's socket remote address in the context so KMS operations are attributed
+// in the audit log. Proxy headers are ignored on purpose as they're controlled by the client.
+func auditCaller() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			c.SetRequest(req.WithContext(audit.WithCaller(req.Context(), req.RemoteAddr)))
+
+			return next(c)
+		}
+	}
+}
+
+// requireUnsealed rejects requests while KMS is sealed.
+func requireUnsealed(unsealer *seal.Unsealer) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if unsealer.IsSealed() {
+				return c.JSON(http.StatusServiceUnavailable, &model.ErrorResponse{
+					Message: "KMS is sealed, submit key shares to unseal it",
+					Status:  "sealed",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// localOnly accepts requests only from loopback address. Proxy headers are ignored on purpose.
+func localOnly() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			host, _, err := net.SplitHostPort(c.Request().RemoteAddr)
+			if ip := net.ParseIP(host); err != nil || ip == nil || !ip.IsLoopback() {
+				return c.JSON(http.StatusForbidden, &model.ErrorResponse{
+					Message: "endpoint is available only from localhost",
+					Status:  "forbidden",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
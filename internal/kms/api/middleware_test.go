@@ -0,0 +1,292 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/oxygenpay/oxygen/internal/kms/api"
+	"github.com/oxygenpay/oxygen/internal/kms/audit"
+	"github.com/oxygenpay/oxygen/internal/kms/seal"
+	"github.com/oxygenpay/oxygen/internal/kms/storage"
+	kmswallet "github.com/oxygenpay/oxygen/internal/kms/wallet"
+	httpServer "github.com/oxygenpay/oxygen/internal/server/http"
+	"github.com/oxygenpay/oxygen/internal/server/http/middleware"
+	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/auth"
+	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/client"
+	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/client/wallet"
+	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/model"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireSignature(t *testing.T) {
+	const secret = "kms-test-secret"
+
+	logger := zerolog.Nop()
+
+	verifier := auth.NewVerifier(secret, time.Minute, nil)
+	t.Cleanup(verifier.Stop)
+
+	e := echo.New()
+	g := e.Group("/api/kms/v1", api.RequireSignature(verifier, &logger))
+
+	g.POST("/wallet", func(c echo.Context) error {
+		var req model.CreateWalletRequest
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, &model.Wallet{ID: "abc", Blockchain: req.Blockchain})
+	})
+
+	g.GET("/key", func(c echo.Context) error {
+		return c.String(http.StatusOK, audit.KeyID(c.Request().Context()))
+	})
+
+	srv := httptest.NewServer(e)
+	t.Cleanup(srv.Close)
+
+	serverURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	newClient := func(secret string) *client.KMSInternalAPI {
+		return client.NewHTTPClientWithSecret(strfmt.Default, &client.TransportConfig{
+			Host:     serverURL.Host,
+			BasePath: "/api/kms/v1",
+			Schemes:  []string{"http"},
+		}, secret)
+	}
+
+	createWallet := func(c *client.KMSInternalAPI) (*wallet.CreateWalletCreated, error) {
+		return c.Wallet.CreateWallet(&wallet.CreateWalletParams{
+			Context: context.Background(),
+			Data:    &model.CreateWalletRequest{Blockchain: "ETH"},
+		})
+	}
+
+	t.Run("Accepts signed request", func(t *testing.T) {
+		res, err := createWallet(newClient(secret))
+		require.NoError(t, err)
+		assert.Equal(t, "abc", res.Payload.ID)
+		assert.Equal(t, model.Blockchain("ETH"), res.Payload.Blockchain)
+	})
+
+	t.Run("Rejects unsigned request", func(t *testing.T) {
+		_, err := createWallet(newClient(""))
+		assert.Error(t, err)
+	})
+
+	t.Run("Rejects request signed with another secret", func(t *testing.T) {
+		_, err := createWallet(newClient("another-secret"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Rejects replayed request", func(t *testing.T) {
+		body := `{"blockchain":"ETH"}`
+		timestamp := time.Now().Unix()
+		signature := auth.Sign(secret, http.MethodPost, "/api/kms/v1/wallet", timestamp, "nonce-1", []byte(body))
+
+		send := func() int {
+			req, err := http.NewRequest(http.MethodPost, srv.URL+"/api/kms/v1/wallet", strings.NewReader(body))
+			require.NoError(t, err)
+
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(auth.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+			req.Header.Set(auth.HeaderNonce, "nonce-1")
+			req.Header.Set(auth.HeaderSignature, signature)
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			_ = res.Body.Close()
+
+			return res.StatusCode
+		}
+
+		assert.Equal(t, http.StatusCreated, send())
+		assert.Equal(t, http.StatusUnauthorized, send())
+	})
+
+	t.Run("Attributes request to the key", func(t *testing.T) {
+		timestamp := time.Now().Unix()
+
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/kms/v1/key", nil)
+		require.NoError(t, err)
+
+		req.Header.Set(auth.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(auth.HeaderNonce, "nonce-3")
+		req.Header.Set(auth.HeaderSignature, auth.Sign(secret, http.MethodGet, "/api/kms/v1/key", timestamp, "nonce-3", nil))
+
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		keyID, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, verifier.KeyID(), string(keyID))
+		assert.Len(t, verifier.KeyID(), 16)
+
+		another := auth.NewVerifier("another-secret", time.Minute, nil)
+		defer another.Stop()
+
+		assert.NotEqual(t, another.KeyID(), verifier.KeyID())
+	})
+
+	t.Run("Rejects too large body before authentication", func(t *testing.T) {
+		body := bytes.Repeat([]byte("a"), 1<<20)
+
+		res, err := http.Post(srv.URL+"/api/kms/v1/wallet", echo.MIMEApplicationJSON, bytes.NewReader(body))
+		require.NoError(t, err)
+		_ = res.Body.Close()
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+	})
+
+	t.Run("Rejects stale request", func(t *testing.T) {
+		timestamp := time.Now().Add(-time.Hour).Unix()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/kms/v1/wallet/abc", nil)
+		req.Header.Set(auth.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(auth.HeaderNonce, "nonce-2")
+		req.Header.Set(auth.HeaderSignature, auth.Sign(secret, http.MethodGet, "/api/kms/v1/wallet/abc", timestamp, "nonce-2", nil))
+
+		assert.ErrorIs(t, verifier.Verify(req, nil), auth.ErrExpired)
+	})
+}
+
+func TestNonceStore(t *testing.T) {
+	const secret = "kms-test-secret"
+
+	logger := zerolog.Nop()
+
+	store, err := storage.Open(storage.Config{Type: storage.Bolt, Path: t.TempDir() + "/kms.test.db"}, &logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	// two KMS replicas that share the same store
+	replicaA := auth.NewVerifier(secret, time.Minute, api.NewNonceStore(store, time.Minute))
+	replicaB := auth.NewVerifier(secret, time.Minute, api.NewNonceStore(store, time.Minute))
+
+	newRequest := func(timestamp int64, nonce string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/kms/v1/wallet/abc", nil)
+		req.Header.Set(auth.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(auth.HeaderNonce, nonce)
+		req.Header.Set(auth.HeaderSignature, auth.Sign(secret, http.MethodGet, "/api/kms/v1/wallet/abc", timestamp, nonce, nil))
+
+		return req
+	}
+
+	now := time.Now().Unix()
+
+	assert.NoError(t, replicaA.Verify(newRequest(now, "nonce-1"), nil))
+	assert.ErrorIs(t, replicaB.Verify(newRequest(now, "nonce-1"), nil), auth.ErrReplay)
+	assert.ErrorIs(t, replicaA.Verify(newRequest(now, "nonce-1"), nil), auth.ErrReplay)
+	assert.NoError(t, replicaB.Verify(newRequest(now, "nonce-2"), nil))
+
+	// nonces of stale requests are pruned
+	staleKey := binary.BigEndian.AppendUint64(nil, uint64(now-3600))
+	require.NoError(t, store.Update(func(tx storage.Tx) error {
+		return tx.Bucket(storage.NoncesBucket).Put(append(staleKey, "nonce-0"...), []byte{1})
+	}))
+	assert.NoError(t, replicaA.Verify(newRequest(now, "nonce-3"), nil))
+
+	var count int
+	require.NoError(t, store.View(func(tx storage.Tx) error {
+		return tx.Bucket(storage.NoncesBucket).ForEach(func(_, _ []byte) error {
+			count++
+			return nil
+		})
+	}))
+	assert.Equal(t, 3, count)
+}
+
+func TestBodyDump(t *testing.T) {
+	var logs bytes.Buffer
+
+	e := echo.New()
+	e.Logger.SetOutput(&logs)
+	e.Logger.SetLevel(log.INFO)
+	e.Use(middleware.BodyDump(api.SensitivePaths...))
+
+	echoBody := func(c echo.Context) error {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+
+		return c.String(http.StatusOK, string(body))
+	}
+
+	e.POST("/api/kms/v1/wallet", echoBody)
+	e.POST("/api/kms/v1/wallet/import", echoBody)
+	e.POST("/api/kms/v1/system/unseal", echoBody)
+
+	send := func(path, body string) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	send("/api/kms/v1/wallet", "blockchain-eth")
+	send("/api/kms/v1/wallet/import", "private-key-secret")
+	send("/api/kms/v1/system/unseal", "key-share-secret")
+
+	assert.Contains(t, logs.String(), "blockchain-eth")
+	assert.NotContains(t, logs.String(), "private-key-secret")
+	assert.NotContains(t, logs.String(), "key-share-secret")
+}
+
+func TestSealedRoutes(t *testing.T) {
+	logger := zerolog.Nop()
+
+	store, err := storage.Open(storage.Config{Type: storage.Bolt, Path: t.TempDir() + "/kms.test.db"}, &logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	service := kmswallet.New(kmswallet.NewRepository(store, nil), kmswallet.NewGenerator(), nil, nil, nil, &logger)
+	service.Seal()
+
+	unsealer := seal.New(2, service.Unseal, service.Seal, nil, &logger)
+
+	srv := httpServer.New(httpServer.Config{}, false, api.SetupRoutes(api.New(service, nil, unsealer, &logger)))
+
+	send := func(method, path, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = remoteAddr
+
+		res := httptest.NewRecorder()
+		srv.Echo().ServeHTTP(res, req)
+
+		return res
+	}
+
+	t.Run("Rejects wallet requests while sealed", func(t *testing.T) {
+		res := send(http.MethodGet, "/api/kms/v1/wallet/"+uuid.New().String(), "10.0.0.1:4000")
+		assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+		assert.Contains(t, res.Body.String(), `"sealed"`)
+	})
+
+	t.Run("Returns seal status to localhost", func(t *testing.T) {
+		res := send(http.MethodGet, "/api/kms/v1/system/seal-status", "127.0.0.1:4000")
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.JSONEq(t, `{"sealed":true,"threshold":2,"progress":0}`, res.Body.String())
+	})
+
+	t.Run("Rejects system requests from remote address", func(t *testing.T) {
+		res := send(http.MethodPost, "/api/kms/v1/system/seal", "10.0.0.1:4000")
+		assert.Equal(t, http.StatusForbidden, res.Code)
+	})
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+
+	"github.com/oxygenpay/oxygen/internal/kms/storage"
+	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/auth"
+)
+
+// NonceStore keeps used request nonces in KMS store, so a signed request can't be replayed
+// against another KMS replica that shares the same store (e.g. postgres).
+type NonceStore struct {
+	store   storage.Store
+	maxSkew time.Duration
+	now     func() time.Time
+}
+
+var _ auth.NonceStore = (*NonceStore)(nil)
+
+func NewNonceStore(store storage.Store, maxSkew time.Duration) *NonceStore {
+	return &NonceStore{store: store, maxSkew: maxSkew, now: time.Now}
+}
+
+// Remember stores nonce under request's timestamp. Nonces of requests outside allowed
+// time window are pruned as such requests are rejected by timestamp anyway.
+func (s *NonceStore) Remember(timestamp int64, nonce string) error {
+	key := nonceKey(timestamp, nonce)
+
+	return s.store.Update(func(tx storage.Tx) error {
+		b := tx.Bucket(storage.NoncesBucket)
+
+		if err := pruneNonces(b, s.now().Add(-s.maxSkew).Unix()); err != nil {
+			return err
+		}
+
+		if b.Get(key) != nil {
+			return auth.ErrReplay
+		}
+
+		return b.Put(key, []byte{1})
+	})
+}
+
+func nonceKey(timestamp int64, nonce string) []byte {
+	key := make([]byte, 8, 8+len(nonce))
+	binary.BigEndian.PutUint64(key, uint64(timestamp))
+
+	return append(key, nonce...)
+}
+
+// pruneNonces removes nonces of requests older than provided timestamp.
+func pruneNonces(b storage.Bucket, before int64) error {
+	var stale [][]byte
+
+	prefix := make([]byte, 8)
+	binary.BigEndian.PutUint64(prefix, uint64(before))
+
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil && bytes.Compare(k[:8], prefix) < 0; k, _ = c.Next() {
+		stale = append(stale, append([]byte(nil), k...))
+	}
+
+	for _, k := range stale {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
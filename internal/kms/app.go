@@ -4,26 +4,41 @@ package kms
 import (
 	"context"
 	cryptorand "crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
 	"net/http"
 	"os"
 
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
 	"github.com/oxygenpay/oxygen/internal/config"
-	"github.com/oxygenpay/oxygen/internal/db/connection/bolt"
 	"github.com/oxygenpay/oxygen/internal/kms/api"
+	"github.com/oxygenpay/oxygen/internal/kms/audit"
+	"github.com/oxygenpay/oxygen/internal/kms/backup"
+	"github.com/oxygenpay/oxygen/internal/kms/encryption"
+	"github.com/oxygenpay/oxygen/internal/kms/seal"
+	"github.com/oxygenpay/oxygen/internal/kms/storage"
 	"github.com/oxygenpay/oxygen/internal/kms/wallet"
 	"github.com/oxygenpay/oxygen/internal/log"
 	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
 	httpServer "github.com/oxygenpay/oxygen/internal/server/http"
+	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/auth"
 	"github.com/oxygenpay/oxygen/pkg/graceful"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
-	"go.etcd.io/bbolt"
 )
 
 type App struct {
 	ctx    context.Context
 	config *config.Config
 	logger *zerolog.Logger
-	db     *bbolt.DB
+	db     storage.Store
+
+	walletRepo *wallet.Repository
+	keychain   *wallet.HDKeychain
+	policy     *wallet.Policy
+	auditLog   *audit.Log
 }
 
 func NewApp(ctx context.Context, cfg *config.Config) *App {
@@ -39,24 +54,492 @@ func NewApp(ctx context.Context, cfg *config.Config) *App {
 
 func (app *App) Run() {
 	app.connectToDB()
+
+	if app.config.KMS.Encryption.IsSealed() {
+		app.loadSealedWalletRepository()
+	} else {
+		app.loadWalletRepository()
+		app.loadHDKeychain()
+
+		if err := app.indexAddresses(); err != nil {
+			app.logger.Fatal().Err(err).Msg("unable to index wallet addresses")
+		}
+	}
+
+	app.loadPolicy()
+	app.auditLog = app.newAuditLog()
 	app.runWebServer(app.ctx)
 }
 
+// ExportHDSeed returns BIP-39 mnemonic & hex-encoded seed of HD wallets. Mnemonic is empty
+// if seed was not generated or imported from mnemonic.
+func (app *App) ExportHDSeed() (mnemonic, seed string, err error) {
+	app.connectToDB()
+	defer app.closeDB()
+
+	app.loadWalletRepository()
+
+	rawSeed, err := app.walletRepo.LoadHDSeed()
+	if err != nil {
+		return "", "", err
+	}
+
+	mnemonic, err = app.walletRepo.LoadHDMnemonic()
+	if err != nil && !errors.Is(err, wallet.ErrNoMnemonic) {
+		return "", "", err
+	}
+
+	return mnemonic, hex.EncodeToString(rawSeed), nil
+}
+
+// ImportHDMnemonic replaces HD seed with the one derived from BIP-39 mnemonic. Mnemonic is persisted
+// for export only if it's not protected with passphrase. Allowed only until the first HD wallet is derived.
+func (app *App) ImportHDMnemonic(mnemonic, passphrase string) error {
+	seed, err := wallet.SeedFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if passphrase != "" {
+		return app.ImportHDSeed(seed)
+	}
+
+	app.connectToDB()
+	defer app.closeDB()
+
+	app.loadWalletRepository()
+
+	return app.walletRepo.SetHDMnemonic(mnemonic)
+}
+
+// ImportHDSeed replaces HD seed. Allowed only until the first HD wallet is derived.
+func (app *App) ImportHDSeed(seed []byte) error {
+	app.connectToDB()
+	defer app.closeDB()
+
+	if _, err := wallet.NewHDKeychain(seed); err != nil {
+		return err
+	}
+
+	app.loadWalletRepository()
+
+	return app.walletRepo.SetHDSeed(seed)
+}
+
+// RecoverHDWallets restores first count HD wallets of each blockchain from the seed.
+// Returns number of recovered wallets.
+func (app *App) RecoverHDWallets(count uint32) (int, error) {
+	app.connectToDB()
+	defer app.closeDB()
+
+	app.loadWalletRepository()
+	app.loadHDKeychain()
+
+	service := wallet.New(app.walletRepo, wallet.NewGenerator(), app.keychain, nil, app.newAuditLog(), app.logger)
+
+	return service.RecoverHDWallets(audit.WithCaller(app.ctx, audit.CallerCLI), count)
+}
+
+// ApproveWallet allows signing policy to treat imported wallet as own wallet. See wallet.Service.ApproveWallet.
+func (app *App) ApproveWallet(id uuid.UUID) error {
+	app.connectToDB()
+	defer app.closeDB()
+
+	app.loadWalletRepository()
+
+	service := wallet.New(app.walletRepo, wallet.NewGenerator(), nil, nil, app.newAuditLog(), app.logger)
+
+	return service.ApproveWallet(audit.WithCaller(app.ctx, audit.CallerCLI), id)
+}
+
+// Backup writes encrypted archive of all wallets & HD seed. Returns number of exported wallets.
+func (app *App) Backup(w io.Writer, passphrase string) (int, error) {
+	app.connectToDB()
+	defer app.closeDB()
+
+	app.loadWalletRepository()
+
+	payload, err := backup.Collect(app.walletRepo)
+	if err != nil {
+		return 0, err
+	}
+
+	raw, err := backup.Seal(payload, passphrase)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := w.Write(raw); err != nil {
+		return 0, errors.Wrap(err, "unable to write backup")
+	}
+
+	return len(payload.Wallets), nil
+}
+
+// Restore imports wallets from encrypted archive. Existing wallets are overwritten only if overwrite is true.
+// Returns number of restored wallets.
+func (app *App) Restore(r io.Reader, passphrase string, overwrite bool) (int, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to read backup")
+	}
+
+	payload, err := backup.Open(raw, passphrase)
+	if err != nil {
+		return 0, err
+	}
+
+	app.connectToDB()
+	defer app.closeDB()
+
+	app.loadWalletRepository()
+
+	ctx := audit.WithCaller(app.ctx, audit.CallerCLI)
+
+	return backup.Restore(ctx, app.walletRepo, app.newAuditLog(), payload, overwrite)
+}
+
+// ExportAuditLog writes audit log entries starting from fromSeq as JSON lines.
+// Returns number of exported entries.
+func (app *App) ExportAuditLog(w io.Writer, fromSeq uint64) (int, error) {
+	app.connectToDB()
+	defer app.closeDB()
+
+	entries, err := app.newAuditLog().List(fromSeq, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	encoder := json.NewEncoder(w)
+	for i := range entries {
+		if err := encoder.Encode(entries[i]); err != nil {
+			return i, errors.Wrap(err, "unable to write entry")
+		}
+	}
+
+	return len(entries), nil
+}
+
+// VerifyAuditLog checks audit log's hash chain. See audit.Log.Verify.
+func (app *App) VerifyAuditLog(headHash string) (audit.Verification, error) {
+	app.connectToDB()
+	defer app.closeDB()
+
+	return app.newAuditLog().Verify(headHash)
+}
+
+// EncryptStore encrypts plaintext wallets with configured master key.
+// Returns number of encrypted wallets.
+func (app *App) EncryptStore() (int, error) {
+	app.connectToDB()
+	defer app.closeDB()
+
+	if app.config.KMS.Encryption.IsEmpty() {
+		return 0, encryption.ErrNoMasterKey
+	}
+
+	app.loadWalletRepository()
+
+	return app.walletRepo.EncryptAll()
+}
+
+// SplitMasterKey splits configured master key into hex-encoded shares so that any threshold
+// of them can unseal KMS. Wallets & HD seed are encrypted with the key beforehand.
+func (app *App) SplitMasterKey(shares, threshold int) ([]string, error) {
+	app.connectToDB()
+	defer app.closeDB()
+
+	if app.config.KMS.Encryption.IsEmpty() {
+		return nil, encryption.ErrNoMasterKey
+	}
+
+	app.loadWalletRepository()
+
+	if _, err := app.walletRepo.EncryptAll(); err != nil {
+		return nil, errors.Wrap(err, "unable to encrypt wallets")
+	}
+
+	app.loadHDKeychain()
+
+	key, err := app.resolveMasterKey(app.config.KMS.Encryption)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := seal.Split(key, shares, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := make([]string, len(parts))
+	for i := range parts {
+		encoded[i] = seal.EncodeShare(parts[i])
+	}
+
+	return encoded, nil
+}
+
+// RotateMasterKey re-encrypts wallets' data keys with the next master key.
+// Returns number of rewritten wallets.
+func (app *App) RotateMasterKey(next encryption.Config) (int, error) {
+	app.connectToDB()
+	defer app.closeDB()
+
+	if app.config.KMS.Encryption.IsEmpty() {
+		return 0, encryption.ErrNoMasterKey
+	}
+
+	app.loadWalletRepository()
+
+	salt, err := encryption.NewSalt()
+	if err != nil {
+		return 0, err
+	}
+
+	nextKeyring, err := encryption.NewKeyringFromConfig(next, salt)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to resolve next master key")
+	}
+
+	return app.walletRepo.RotateKey(nextKeyring, salt)
+}
+
+// MigrateStore copies all records of configured store to an empty target store.
+// Returns number of copied records.
+func (app *App) MigrateStore(target storage.Config) (int, error) {
+	source := app.config.KMS.Store
+
+	switch {
+	case target.Type == "":
+		return 0, errors.New("target store type is not set")
+	case target.SameAs(source):
+		return 0, errors.New("target store should differ from the current one")
+	}
+
+	app.connectToDB()
+	defer app.closeDB()
+
+	dst, err := storage.Open(target, app.logger)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to open target store")
+	}
+
+	defer func() {
+		if err := dst.Close(); err != nil {
+			app.logger.Error().Err(err).Msg("unable to close target kms db")
+		}
+	}()
+
+	return storage.Copy(dst, app.db)
+}
+
 func (app *App) Logger() *zerolog.Logger {
 	return app.logger
 }
 
 func (app *App) connectToDB() {
-	conn, err := bolt.Open(app.config.KMS.Bolt, app.logger)
+	db, err := storage.Open(app.config.KMS.Store, app.logger)
 	if err != nil {
 		app.logger.Fatal().Err(err).Msg("unable to run kms without db")
 	}
 
-	if err := conn.LoadBuckets(); err != nil {
-		app.logger.Fatal().Err(err).Msg("unable to load kms bolt db buckets")
+	app.db = db
+}
+
+func (app *App) closeDB() {
+	if err := app.db.Close(); err != nil {
+		app.logger.Error().Err(err).Msg("unable to close kms db")
+	}
+}
+
+func (app *App) loadWalletRepository() {
+	keyring, err := app.resolveKeyring(app.config.KMS.Encryption)
+	if err != nil {
+		app.logger.Fatal().Err(err).Msg("unable to resolve kms master key")
+	}
+
+	if keyring == nil {
+		app.logger.Warn().Msg("master key is not configured, private keys are stored unencrypted")
+	}
+
+	repo := wallet.NewRepository(app.db, keyring)
+	if err := repo.VerifyKeyring(); err != nil {
+		app.logger.Fatal().Err(err).Msg("unable to verify kms master key")
+	}
+
+	app.walletRepo = repo
+}
+
+// loadHDKeychain loads HD seed or generates a new one from random BIP-39 mnemonic on the first run.
+// Mnemonic is never logged, operator should write it down using kms-export-seed command.
+func (app *App) loadHDKeychain() {
+	seed, err := app.walletRepo.LoadHDSeed()
+
+	switch {
+	case errors.Is(err, wallet.ErrHDSeedNotFound):
+		mnemonic, err := wallet.GenerateMnemonic(cryptorand.Reader)
+		if err != nil {
+			app.logger.Fatal().Err(err).Msg("unable to generate HD seed")
+		}
+
+		if err := app.walletRepo.SetHDMnemonic(mnemonic); err != nil {
+			app.logger.Fatal().Err(err).Msg("unable to persist HD seed")
+		}
+
+		if seed, err = app.walletRepo.LoadHDSeed(); err != nil {
+			app.logger.Fatal().Err(err).Msg("unable to load HD seed")
+		}
+
+		app.logger.Warn().Msg("generated new HD seed, write down its mnemonic using kms-export-seed command")
+	case err != nil:
+		app.logger.Fatal().Err(err).Msg("unable to load HD seed")
+	}
+
+	keychain, err := wallet.NewHDKeychain(seed)
+	if err != nil {
+		app.logger.Fatal().Err(err).Msg("unable to create HD keychain")
+	}
+
+	app.keychain = keychain
+}
+
+// loadSealedWalletRepository loads repository without master key. The store should be
+// already encrypted, so the key restored from shares can be verified.
+func (app *App) loadSealedWalletRepository() {
+	cfg := app.config.KMS.Encryption
+
+	switch {
+	case !cfg.IsEmpty():
+		app.logger.Fatal().Msg("master key should not be configured when KMS runs in sealed mode")
+	case cfg.UnsealThreshold < 2:
+		app.logger.Fatal().Msg("unseal threshold should be at least 2")
+	}
+
+	repo := wallet.NewRepository(app.db, nil)
+
+	encrypted, err := repo.IsEncrypted()
+	switch {
+	case err != nil:
+		app.logger.Fatal().Err(err).Msg("unable to load kms store")
+	case !encrypted:
+		app.logger.Fatal().Msg("kms store is not encrypted, run kms-split-key before enabling sealed mode")
+	}
+
+	repo.Seal()
+
+	app.walletRepo = repo
+}
+
+// indexAddresses builds address index that is required to recognize transfers between KMS wallets.
+func (app *App) indexAddresses() error {
+	indexed, err := app.walletRepo.IndexAddresses()
+	if err != nil {
+		return err
+	}
+
+	if indexed > 0 {
+		app.logger.Info().Int("wallets_count", indexed).Msg("indexed wallet addresses")
+	}
+
+	return nil
+}
+
+func (app *App) newAuditLog() *audit.Log {
+	if app.config.KMS.AuditKey == "" {
+		app.logger.Fatal().Msg("audit log key is not configured, set KMS_AUDIT_KEY")
+	}
+
+	return audit.New(app.db, []byte(app.config.KMS.AuditKey))
+}
+
+// loadPolicy loads signing policy.
+func (app *App) loadPolicy() {
+	if !app.config.KMS.Policy.Enabled {
+		app.logger.Warn().Msg("signing policy is disabled, KMS signs any transaction")
+	}
+
+	app.policy = wallet.NewPolicy(app.config.KMS.Policy, app.walletRepo, app.logger)
+}
+
+func (app *App) authMiddlewares() []echo.MiddlewareFunc {
+	if app.config.KMS.AuthSecret == "" {
+		if !app.config.KMS.AuthDisabled {
+			app.logger.Fatal().Msg("auth secret is not configured, set KMS_AUTH_SECRET or disable request signing explicitly")
+		}
+
+		app.logger.Warn().Msg("request signing is disabled, KMS API accepts unauthenticated requests")
+
+		return nil
+	}
+
+	// bolt & file stores are used by a single KMS process, so in-memory nonces are enough
+	var nonces auth.NonceStore
+	if app.config.KMS.Store.Type == storage.Postgres {
+		nonces = api.NewNonceStore(app.db, auth.DefaultMaxSkew)
 	}
 
-	app.db = conn.DB()
+	verifier := auth.NewVerifier(app.config.KMS.AuthSecret, auth.DefaultMaxSkew, nonces)
+
+	graceful.AddCallback(func() error {
+		verifier.Stop()
+		return nil
+	})
+
+	return []echo.MiddlewareFunc{api.RequireSignature(verifier, app.logger)}
+}
+
+// resolveKeyring returns nil if encryption is not configured.
+func (app *App) resolveKeyring(cfg encryption.Config) (*encryption.Keyring, error) {
+	if cfg.IsEmpty() {
+		return nil, nil
+	}
+
+	key, err := app.resolveMasterKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryption.NewKeyring(key)
+}
+
+func (app *App) resolveMasterKey(cfg encryption.Config) ([]byte, error) {
+	var salt []byte
+	if cfg.Passphrase != "" {
+		var err error
+		if salt, err = wallet.LoadKeySalt(app.db); err != nil {
+			return nil, errors.Wrap(err, "unable to load master key salt")
+		}
+	}
+
+	return encryption.ResolveMasterKey(cfg, salt)
+}
+
+// newUnsealer seals wallet service and returns Unsealer that restores master key from shares.
+// Returns nil if KMS is not running in sealed mode.
+func (app *App) newUnsealer(service *wallet.Service) *seal.Unsealer {
+	threshold := app.config.KMS.Encryption.UnsealThreshold
+	if threshold == 0 {
+		return nil
+	}
+
+	service.Seal()
+
+	unseal := func(keyring *encryption.Keyring) error {
+		if err := service.Unseal(keyring); err != nil {
+			return err
+		}
+
+		if err := app.indexAddresses(); err != nil {
+			service.Seal()
+			return errors.Wrap(err, "unable to index wallet addresses")
+		}
+
+		return nil
+	}
+
+	app.logger.Warn().Int("threshold", threshold).Msg("KMS is sealed, submit key shares using kms-unseal command")
+
+	return seal.New(threshold, unseal, service.Seal, app.auditLog, app.logger)
 }
 
 func (app *App) runWebServer(ctx context.Context) {
@@ -71,8 +554,8 @@ func (app *App) runWebServer(ctx context.Context) {
 			CryptoReader: cryptorand.Reader,
 		})
 
-	walletRepo := wallet.NewRepository(app.db)
-	kmsService := wallet.New(walletRepo, walletGenerator, app.logger)
+	kmsService := wallet.New(app.walletRepo, walletGenerator, app.keychain, app.policy, app.auditLog, app.logger)
+	unsealer := app.newUnsealer(kmsService)
 
 	if app.config.KMS.IsEmbedded {
 		app.config.KMS.Server.Port = "14000"
@@ -83,8 +566,8 @@ func (app *App) runWebServer(ctx context.Context) {
 		app.config.Debug,
 		httpServer.WithRecover(),
 		httpServer.WithLogger(app.logger),
-		httpServer.WithBodyDump(),
-		api.SetupRoutes(api.New(kmsService, app.logger)),
+		httpServer.WithBodyDump(api.SensitivePaths...),
+		api.SetupRoutes(api.New(kmsService, app.auditLog, unsealer, app.logger), app.authMiddlewares()...),
 	)
 
 	go func() {
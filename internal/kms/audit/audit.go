@@ -0,0 +1,311 @@
+// Package audit implements append-only log of KMS operations. Each entry contains hash of the previous
+// one, so modification, removal or reordering of entries breaks the chain. Hashes are HMACs with a key
+// that is not stored alongside the log, so the chain can't be rebuilt by someone who can write to the store.
+// Head hash should be kept outside of KMS to detect truncation of the log's tail.
+package audit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/oxygenpay/oxygen/internal/kms/storage"
+	"github.com/pkg/errors"
+)
+
+type Operation string
+
+const (
+	CreateWallet          Operation = "create_wallet"
+	RecoverWallet         Operation = "recover_wallet"
+	RestoreWallet         Operation = "restore_wallet"
+	ImportWallet          Operation = "import_wallet"
+	ApproveWallet         Operation = "approve_wallet"
+	DeleteWallet          Operation = "delete_wallet"
+	SignTransaction       Operation = "sign_transaction"
+	SignResourceOperation Operation = "sign_resource_transaction"
+	SignMessage           Operation = "sign_message"
+	Unseal                Operation = "unseal"
+	Seal                  Operation = "seal"
+)
+
+type Result string
+
+const (
+	Success Result = "success"
+	Denied  Result = "denied"
+	Failed  Result = "failed"
+)
+
+// Entry represents single KMS operation. Amount is in the smallest units (wei, sun, ...).
+// Message is the signed proof of ownership (or its challenge if the proof wasn't built).
+type Entry struct {
+	Seq             uint64    `json:"seq"`
+	CreatedAt       time.Time `json:"created_at"`
+	Operation       Operation `json:"operation"`
+	WalletID        string    `json:"wallet_id"`
+	Blockchain      string    `json:"blockchain"`
+	Recipient       string    `json:"recipient,omitempty"`
+	Asset           string    `json:"asset,omitempty"`
+	Amount          string    `json:"amount,omitempty"`
+	Caller          string    `json:"caller"`
+	KeyID           string    `json:"key_id,omitempty"`
+	Result          Result    `json:"result"`
+	Error           string    `json:"error,omitempty"`
+	TransactionHash string    `json:"transaction_hash,omitempty"`
+	Message         string    `json:"message,omitempty"`
+	PrevHash        string    `json:"prev_hash"`
+	Hash            string    `json:"hash"`
+}
+
+// Verification result of the chain check. BrokenAt is the seq of the first invalid entry.
+type Verification struct {
+	Valid    bool
+	Count    uint64
+	HeadHash string
+	BrokenAt uint64
+	Reason   string
+}
+
+type Log struct {
+	store storage.Store
+	key   []byte
+	now   func() time.Time
+}
+
+var (
+	ErrInvalidChain = errors.New("audit log chain is broken")
+	ErrNoKey        = errors.New("audit log key is not set")
+)
+
+const (
+	// genesisHash prev hash of the first entry
+	genesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+	// CallerCLI caller of operations performed with KMS commands
+	CallerCLI = "cli"
+
+	callerUnknown = "unknown"
+)
+
+// New creates Log. key is used to compute entries' HMACs and should be kept outside of the store.
+func New(store storage.Store, key []byte) *Log {
+	return &Log{store: store, key: key, now: time.Now}
+}
+
+// Append adds entry to the log filling its seq, time and hashes. Nil log is a no-op.
+func (l *Log) Append(ctx context.Context, entry Entry) (Entry, error) {
+	if l == nil {
+		return entry, nil
+	}
+
+	err := l.store.Update(func(tx storage.Tx) (err error) {
+		entry, err = l.AppendTx(ctx, tx, entry)
+		return err
+	})
+
+	if err != nil {
+		return Entry{}, err
+	}
+
+	return entry, nil
+}
+
+// AppendTx appends entry within provided transaction of log's store, so the entry is committed
+// (or rolled back) together with the audited change.
+func (l *Log) AppendTx(ctx context.Context, tx storage.Tx, entry Entry) (Entry, error) {
+	if l == nil {
+		return entry, nil
+	}
+
+	if len(l.key) == 0 {
+		return Entry{}, ErrNoKey
+	}
+
+	entry.Caller = Caller(ctx)
+	entry.KeyID = KeyID(ctx)
+	entry.CreatedAt = l.now().UTC()
+
+	if err := l.put(tx, &entry); err != nil {
+		return Entry{}, errors.Wrap(err, "unable to append audit log entry")
+	}
+
+	return entry, nil
+}
+
+// put chains entry to the last one and persists it.
+func (l *Log) put(tx storage.Tx, entry *Entry) error {
+	b := tx.Bucket(storage.AuditBucket)
+
+	entry.Seq = 1
+	entry.PrevHash = genesisHash
+
+	if k, v := b.Cursor().Last(); k != nil {
+		var last Entry
+		if err := json.Unmarshal(v, &last); err != nil {
+			return errors.Wrap(err, "unable to decode last entry")
+		}
+
+		entry.Seq = last.Seq + 1
+		entry.PrevHash = last.Hash
+	}
+
+	hash, err := entry.hash(l.key)
+	if err != nil {
+		return err
+	}
+
+	entry.Hash = hash
+
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(seqToKey(entry.Seq), value)
+}
+
+// List returns up to limit entries starting from seq. Zero limit means all entries.
+func (l *Log) List(fromSeq uint64, limit int) ([]Entry, error) {
+	var entries []Entry
+
+	err := l.store.View(func(tx storage.Tx) error {
+		c := tx.Bucket(storage.AuditBucket).Cursor()
+
+		for k, v := c.Seek(seqToKey(fromSeq)); k != nil; k, v = c.Next() {
+			if limit > 0 && len(entries) >= limit {
+				return nil
+			}
+
+			var e Entry
+			if err := json.Unmarshal(v, &e); err != nil {
+				return errors.Wrapf(err, "unable to decode entry %d", binary.BigEndian.Uint64(k))
+			}
+
+			entries = append(entries, e)
+		}
+
+		return nil
+	})
+
+	return entries, err
+}
+
+// Verify walks through the whole log and checks that each entry matches its hash
+// and references the previous one. If headHash is provided, the log should contain it:
+// this detects truncation of entries appended after the hash was exported.
+func (l *Log) Verify(headHash string) (Verification, error) {
+	if len(l.key) == 0 {
+		return Verification{}, ErrNoKey
+	}
+
+	result := Verification{Valid: true, HeadHash: genesisHash}
+	headFound := headHash == "" || headHash == genesisHash
+
+	err := l.store.View(func(tx storage.Tx) error {
+		return tx.Bucket(storage.AuditBucket).ForEach(func(k, v []byte) error {
+			seq := binary.BigEndian.Uint64(k)
+
+			fail := func(reason string) error {
+				result.Valid = false
+				result.BrokenAt = seq
+				result.Reason = reason
+
+				return ErrInvalidChain
+			}
+
+			var e Entry
+			if err := json.Unmarshal(v, &e); err != nil {
+				return fail("unable to decode entry")
+			}
+
+			switch {
+			case e.Seq != seq || e.Seq != result.Count+1:
+				return fail("unexpected sequence number")
+			case e.PrevHash != result.HeadHash:
+				return fail("previous hash mismatch")
+			}
+
+			hash, err := e.hash(l.key)
+			if err != nil {
+				return err
+			}
+
+			if !hmac.Equal([]byte(hash), []byte(e.Hash)) {
+				return fail("entry hash mismatch")
+			}
+
+			result.Count++
+			result.HeadHash = e.Hash
+			headFound = headFound || e.Hash == headHash
+
+			return nil
+		})
+	})
+
+	switch {
+	case errors.Is(err, ErrInvalidChain):
+		return result, nil
+	case err != nil:
+		return result, err
+	case !headFound:
+		result.Valid = false
+		result.Reason = "head hash not found, log might be truncated"
+	}
+
+	return result, nil
+}
+
+// hash returns hex-encoded HMAC-SHA256 of entry's JSON without the hash itself.
+func (e Entry) hash(key []byte) (string, error) {
+	e.Hash = ""
+
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to marshal entry")
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(raw)
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+type (
+	callerKey struct{}
+	keyIDKey  struct{}
+)
+
+// WithCaller stores operation's initiator (e.g. remote address) in the context.
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+func Caller(ctx context.Context) string {
+	if caller, ok := ctx.Value(callerKey{}).(string); ok && caller != "" {
+		return caller
+	}
+
+	return callerUnknown
+}
+
+// WithKeyID stores ID of the key that authenticated operation's request in the context.
+func WithKeyID(ctx context.Context, keyID string) context.Context {
+	return context.WithValue(ctx, keyIDKey{}, keyID)
+}
+
+func KeyID(ctx context.Context) string {
+	keyID, _ := ctx.Value(keyIDKey{}).(string)
+	return keyID
+}
+
+func seqToKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+
+	return key
+}
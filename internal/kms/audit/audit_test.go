@@ -0,0 +1,189 @@
+package audit_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/oxygenpay/oxygen/internal/kms/audit"
+	"github.com/oxygenpay/oxygen/internal/kms/storage"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = []byte("audit-test-key")
+
+func TestLog(t *testing.T) {
+	ctx := audit.WithKeyID(audit.WithCaller(context.Background(), "10.0.0.1:43210"), "5b1f0e2a9c7d3e48")
+
+	setup := func(t *testing.T) (storage.Store, *audit.Log, []audit.Entry) {
+		db := openStore(t)
+		log := audit.New(db, testKey)
+
+		var entries []audit.Entry
+		for _, amount := range []string{"100", "200", "300"} {
+			e, err := log.Append(ctx, audit.Entry{
+				Operation:  audit.SignTransaction,
+				WalletID:   "3c2b1d7f-6e8a-4a2f-a9f0-7b1a7c1e5d9b",
+				Blockchain: "ETH",
+				Recipient:  "0x5e41bc5922370522800103f826c3bb9cd5d83f1a",
+				Asset:      "coin",
+				Amount:     amount,
+				Result:     audit.Success,
+			})
+			require.NoError(t, err)
+
+			entries = append(entries, e)
+		}
+
+		return db, log, entries
+	}
+
+	t.Run("Chains entries", func(t *testing.T) {
+		_, log, entries := setup(t)
+
+		assert.Equal(t, uint64(1), entries[0].Seq)
+		assert.Equal(t, "10.0.0.1:43210", entries[0].Caller)
+		assert.Equal(t, "5b1f0e2a9c7d3e48", entries[0].KeyID)
+		assert.Equal(t, entries[0].Hash, entries[1].PrevHash)
+		assert.Equal(t, entries[1].Hash, entries[2].PrevHash)
+
+		actual, err := log.List(2, 1)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, entries[1], actual[0])
+
+		result, err := log.Verify(entries[1].Hash)
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, uint64(3), result.Count)
+		assert.Equal(t, entries[2].Hash, result.HeadHash)
+	})
+
+	t.Run("Detects modified entry", func(t *testing.T) {
+		db, log, entries := setup(t)
+
+		tampered := entries[1]
+		tampered.Amount = "1"
+		putEntry(t, db, tampered)
+
+		result, err := log.Verify("")
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, uint64(2), result.BrokenAt)
+	})
+
+	t.Run("Detects chain rebuilt without the key", func(t *testing.T) {
+		db, log, entries := setup(t)
+
+		// attacker with write access to the store rewrites the tail with unkeyed hashes
+		prevHash := entries[0].Hash
+		for _, e := range entries[1:] {
+			e.Amount = "1"
+			e.PrevHash = prevHash
+			e.Hash = ""
+
+			raw, err := json.Marshal(e)
+			require.NoError(t, err)
+
+			sum := sha256.Sum256(raw)
+			e.Hash = hex.EncodeToString(sum[:])
+			prevHash = e.Hash
+
+			putEntry(t, db, e)
+		}
+
+		result, err := log.Verify("")
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, uint64(2), result.BrokenAt)
+
+		result, err = audit.New(db, []byte("another-key")).Verify("")
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, uint64(1), result.BrokenAt)
+	})
+
+	t.Run("Requires key", func(t *testing.T) {
+		db, _, _ := setup(t)
+
+		_, err := audit.New(db, nil).Append(ctx, audit.Entry{Operation: audit.CreateWallet})
+		assert.ErrorIs(t, err, audit.ErrNoKey)
+
+		_, err = audit.New(db, nil).Verify("")
+		assert.ErrorIs(t, err, audit.ErrNoKey)
+	})
+
+	t.Run("Detects removed entry", func(t *testing.T) {
+		db, log, _ := setup(t)
+
+		require.NoError(t, db.Update(func(tx storage.Tx) error {
+			return tx.Bucket(storage.AuditBucket).Delete(seqToKey(2))
+		}))
+
+		result, err := log.Verify("")
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, uint64(3), result.BrokenAt)
+	})
+
+	t.Run("Detects truncated tail", func(t *testing.T) {
+		db, log, entries := setup(t)
+
+		require.NoError(t, db.Update(func(tx storage.Tx) error {
+			return tx.Bucket(storage.AuditBucket).Delete(seqToKey(3))
+		}))
+
+		result, err := log.Verify("")
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+
+		result, err = log.Verify(entries[2].Hash)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+	})
+
+	t.Run("Nil log is a no-op", func(t *testing.T) {
+		var log *audit.Log
+
+		_, err := log.Append(ctx, audit.Entry{Operation: audit.CreateWallet})
+		assert.NoError(t, err)
+	})
+}
+
+func TestCaller(t *testing.T) {
+	assert.Equal(t, "unknown", audit.Caller(context.Background()))
+	assert.Equal(t, audit.CallerCLI, audit.Caller(audit.WithCaller(context.Background(), audit.CallerCLI)))
+	assert.Empty(t, audit.KeyID(context.Background()))
+}
+
+func putEntry(t *testing.T, db storage.Store, e audit.Entry) {
+	raw, err := json.Marshal(e)
+	require.NoError(t, err)
+
+	require.NoError(t, db.Update(func(tx storage.Tx) error {
+		return tx.Bucket(storage.AuditBucket).Put(seqToKey(e.Seq), raw)
+	}))
+}
+
+func seqToKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+
+	return key
+}
+
+func openStore(t *testing.T) storage.Store {
+	logger := zerolog.Nop()
+
+	store, err := storage.Open(storage.Config{Type: storage.Bolt, Path: t.TempDir() + "/kms.test.db"}, &logger)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = store.Close() })
+
+	return store
+}
@@ -0,0 +1,175 @@
+// Package backup implements encrypted archive of KMS wallets. Archive is encrypted with
+// a key derived from the passphrase (scrypt), so it can be restored to a KMS with any master key.
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/oxygenpay/oxygen/internal/kms/encryption"
+	"github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/pkg/errors"
+)
+
+// Payload decrypted archive contents.
+type Payload struct {
+	CreatedAt         time.Time                    `json:"created_at"`
+	Wallets           []*wallet.Wallet             `json:"wallets"`
+	HDSeed            []byte                       `json:"hd_seed,omitempty"`
+	HDMnemonic        string                       `json:"hd_mnemonic,omitempty"`
+	DerivationIndexes map[wallet.Blockchain]uint32 `json:"derivation_indexes,omitempty"`
+}
+
+// archive is the on-disk format. Checksum of the payload is stored inside encrypted data.
+type archive struct {
+	Format   string               `json:"format"`
+	Version  int                  `json:"version"`
+	KDF      string               `json:"kdf"`
+	Salt     []byte               `json:"salt"`
+	Envelope *encryption.Envelope `json:"envelope"`
+}
+
+type sealedPayload struct {
+	Payload  json.RawMessage `json:"payload"`
+	Checksum string          `json:"checksum"`
+}
+
+const (
+	format  = "oxygen-kms-backup"
+	version = 1
+	kdf     = "scrypt"
+)
+
+var (
+	ErrEmptyPassphrase    = errors.New("backup passphrase is empty")
+	ErrInvalidArchive     = errors.New("invalid backup archive")
+	ErrUnsupportedVersion = errors.New("unsupported backup version")
+	ErrInvalidPassphrase  = errors.New("invalid backup passphrase")
+	ErrIntegrityViolation = errors.New("backup checksum mismatch")
+	ErrIncompletePayload  = errors.New("backup is missing wallets' keys")
+)
+
+// Seal encrypts payload with passphrase-derived key.
+func Seal(payload *Payload, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to marshal payload")
+	}
+
+	plaintext, err := json.Marshal(sealedPayload{Payload: rawPayload, Checksum: checksum(rawPayload)})
+	if err != nil {
+		return nil, err
+	}
+
+	salt, err := encryption.NewSalt()
+	if err != nil {
+		return nil, err
+	}
+
+	keyring, err := newKeyring(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, err := keyring.Seal(plaintext, []byte(format))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to encrypt payload")
+	}
+
+	return json.MarshalIndent(archive{
+		Format:   format,
+		Version:  version,
+		KDF:      kdf,
+		Salt:     salt,
+		Envelope: envelope,
+	}, "", "  ")
+}
+
+// Open decrypts the archive and verifies its integrity.
+func Open(raw []byte, passphrase string) (*Payload, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+
+	var a archive
+	if err := json.Unmarshal(raw, &a); err != nil {
+		return nil, errors.Wrap(ErrInvalidArchive, err.Error())
+	}
+
+	switch {
+	case a.Format != format:
+		return nil, errors.Wrapf(ErrInvalidArchive, "unexpected format %q", a.Format)
+	case a.Version != version:
+		return nil, errors.Wrapf(ErrUnsupportedVersion, "version %d", a.Version)
+	case a.KDF != kdf || len(a.Salt) == 0 || a.Envelope == nil:
+		return nil, ErrInvalidArchive
+	}
+
+	keyring, err := newKeyring(passphrase, a.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := keyring.Open(a.Envelope, []byte(format))
+	switch {
+	case errors.Is(err, encryption.ErrKeyMismatch):
+		return nil, ErrInvalidPassphrase
+	case err != nil:
+		return nil, errors.Wrap(ErrInvalidArchive, err.Error())
+	}
+
+	var sealed sealedPayload
+	if err := json.Unmarshal(plaintext, &sealed); err != nil {
+		return nil, errors.Wrap(ErrInvalidArchive, err.Error())
+	}
+
+	if checksum(sealed.Payload) != sealed.Checksum {
+		return nil, ErrIntegrityViolation
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(sealed.Payload, &payload); err != nil {
+		return nil, errors.Wrap(ErrInvalidArchive, err.Error())
+	}
+
+	if err := payload.validate(); err != nil {
+		return nil, err
+	}
+
+	return &payload, nil
+}
+
+// validate ensures that each wallet can be restored: legacy wallets require private keys,
+// HD wallets require the seed.
+func (p *Payload) validate() error {
+	for _, w := range p.Wallets {
+		switch {
+		case w.IsHD() && len(p.HDSeed) == 0:
+			return errors.Wrapf(ErrIncompletePayload, "HD seed is missing for wallet %s", w.UUID)
+		case !w.IsHD() && w.PrivateKey == "":
+			return errors.Wrapf(ErrIncompletePayload, "private key is missing for wallet %s", w.UUID)
+		}
+	}
+
+	return nil
+}
+
+func newKeyring(passphrase string, salt []byte) (*encryption.Keyring, error) {
+	key, err := encryption.DeriveKey(passphrase, salt)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to derive key from passphrase")
+	}
+
+	return encryption.NewKeyring(key)
+}
+
+func checksum(raw []byte) string {
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,293 @@
+package backup_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/oxygenpay/oxygen/internal/kms/audit"
+	"github.com/oxygenpay/oxygen/internal/kms/backup"
+	"github.com/oxygenpay/oxygen/internal/kms/encryption"
+	"github.com/oxygenpay/oxygen/internal/kms/storage"
+	"github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const passphrase = "correct horse battery staple"
+
+func TestSealOpen(t *testing.T) {
+	payload := &backup.Payload{
+		Wallets: []*wallet.Wallet{
+			(&wallet.EthProvider{Blockchain: wallet.ETH, CryptoReader: strings.NewReader(strings.Repeat("a", 128))}).Generate(),
+		},
+	}
+
+	raw, err := backup.Seal(payload, passphrase)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), payload.Wallets[0].PrivateKey)
+
+	t.Run("Opens archive", func(t *testing.T) {
+		actual, err := backup.Open(raw, passphrase)
+		require.NoError(t, err)
+		require.Len(t, actual.Wallets, 1)
+		assert.Equal(t, payload.Wallets[0].PrivateKey, actual.Wallets[0].PrivateKey)
+	})
+
+	t.Run("Rejects invalid passphrase", func(t *testing.T) {
+		_, err := backup.Open(raw, "wrong")
+		assert.ErrorIs(t, err, backup.ErrInvalidPassphrase)
+
+		_, err = backup.Seal(payload, "")
+		assert.ErrorIs(t, err, backup.ErrEmptyPassphrase)
+	})
+
+	t.Run("Rejects modified archive", func(t *testing.T) {
+		var archive map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(raw, &archive))
+
+		var envelope encryption.Envelope
+		require.NoError(t, json.Unmarshal(archive["envelope"], &envelope))
+		envelope.Data[len(envelope.Data)-1] ^= 0xff
+
+		archive["envelope"], err = json.Marshal(envelope)
+		require.NoError(t, err)
+
+		modified, err := json.Marshal(archive)
+		require.NoError(t, err)
+
+		_, err = backup.Open(modified, passphrase)
+		assert.ErrorIs(t, err, backup.ErrInvalidArchive)
+	})
+
+	t.Run("Rejects unknown version", func(t *testing.T) {
+		modified := strings.Replace(string(raw), `"version": 1`, `"version": 2`, 1)
+
+		_, err := backup.Open([]byte(modified), passphrase)
+		assert.ErrorIs(t, err, backup.ErrUnsupportedVersion)
+
+		_, err = backup.Open([]byte("kms.db"), passphrase)
+		assert.ErrorIs(t, err, backup.ErrInvalidArchive)
+	})
+}
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	seed, err := wallet.GenerateHDSeed(strings.NewReader(strings.Repeat("s", 64)))
+	require.NoError(t, err)
+
+	keychain, err := wallet.NewHDKeychain(seed)
+	require.NoError(t, err)
+
+	// Given source KMS with legacy and HD wallets
+	source := wallet.NewRepository(openStore(t), nil)
+	require.NoError(t, source.SetHDSeed(seed))
+
+	sourceService := wallet.New(source, wallet.NewGenerator(), keychain, nil, nil, &logger)
+
+	legacy := (&wallet.EthProvider{Blockchain: wallet.ETH, CryptoReader: strings.NewReader(strings.Repeat("a", 128))}).Generate()
+	require.NoError(t, source.Set(legacy))
+
+	hd, err := sourceService.CreateWallet(ctx, wallet.ETH)
+	require.NoError(t, err)
+
+	payload, err := backup.Collect(source)
+	require.NoError(t, err)
+
+	raw, err := backup.Seal(payload, passphrase)
+	require.NoError(t, err)
+
+	payload, err = backup.Open(raw, passphrase)
+	require.NoError(t, err)
+
+	t.Run("Restores to encrypted store", func(t *testing.T) {
+		db := openStore(t)
+		target := wallet.NewRepository(db, newKeyring(t))
+		auditLog := audit.New(db, []byte("audit-test-key"))
+
+		// ACT
+		count, err := backup.Restore(ctx, target, auditLog, payload, false)
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		targetSeed, err := target.LoadHDSeed()
+		require.NoError(t, err)
+		targetKeychain, err := wallet.NewHDKeychain(targetSeed)
+		require.NoError(t, err)
+
+		targetService := wallet.New(target, wallet.NewGenerator(), targetKeychain, nil, nil, &logger)
+
+		for _, expected := range []*wallet.Wallet{legacy, hd} {
+			actual, err := targetService.GetWallet(ctx, expected.UUID, false)
+			require.NoError(t, err)
+			assert.Equal(t, expected.Address, actual.Address)
+			assert.Equal(t, expected.PrivateKey, actual.PrivateKey)
+		}
+
+		// Check that derivation index was restored
+		next, err := targetService.CreateWallet(ctx, wallet.ETH)
+		require.NoError(t, err)
+		assert.Equal(t, "m/44'/60'/0'/0/1", next.DerivationPath)
+
+		entries, err := auditLog.List(0, 0)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, audit.RestoreWallet, entries[0].Operation)
+	})
+
+	t.Run("Writes audit log in the same transaction", func(t *testing.T) {
+		db := openStore(t)
+		target := wallet.NewRepository(db, nil)
+
+		// Given audit log that fails to append entries
+		auditLog := audit.New(db, nil)
+
+		// ACT
+		_, err := backup.Restore(ctx, target, auditLog, payload, false)
+
+		// ASSERT
+		assert.ErrorIs(t, err, audit.ErrNoKey)
+
+		wallets, err := target.List()
+		require.NoError(t, err)
+		assert.Empty(t, wallets)
+
+		_, err = target.LoadHDSeed()
+		assert.ErrorIs(t, err, wallet.ErrHDSeedNotFound)
+	})
+
+	t.Run("Refuses to overwrite wallets unless forced", func(t *testing.T) {
+		target := wallet.NewRepository(openStore(t), nil)
+
+		_, err := backup.Restore(ctx, target, nil, payload, false)
+		require.NoError(t, err)
+
+		_, err = backup.Restore(ctx, target, nil, payload, false)
+		assert.ErrorIs(t, err, wallet.ErrWalletExists)
+
+		count, err := backup.Restore(ctx, target, nil, payload, true)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("Rejects wallet with mismatching key before writing anything", func(t *testing.T) {
+		target := wallet.NewRepository(openStore(t), nil)
+
+		tampered := *legacy
+		tampered.Address = hd.Address
+
+		invalid := *payload
+		invalid.Wallets = []*wallet.Wallet{hd, &tampered}
+
+		_, err := backup.Restore(ctx, target, nil, &invalid, false)
+		assert.ErrorIs(t, err, backup.ErrInvalidPayload)
+		assert.ErrorContains(t, err, legacy.UUID.String())
+
+		_, err = target.LoadHDSeed()
+		assert.ErrorIs(t, err, wallet.ErrHDSeedNotFound)
+
+		wallets, err := target.List()
+		require.NoError(t, err)
+		assert.Empty(t, wallets)
+	})
+
+	t.Run("Rejects HD wallet of another seed", func(t *testing.T) {
+		target := wallet.NewRepository(openStore(t), nil)
+
+		otherSeed, err := wallet.GenerateHDSeed(strings.NewReader(strings.Repeat("o", 64)))
+		require.NoError(t, err)
+
+		invalid := *payload
+		invalid.HDSeed = otherSeed
+
+		_, err = backup.Restore(ctx, target, nil, &invalid, false)
+		assert.ErrorIs(t, err, backup.ErrInvalidPayload)
+
+		_, err = target.LoadHDSeed()
+		assert.ErrorIs(t, err, wallet.ErrHDSeedNotFound)
+	})
+
+	t.Run("Leaves store intact if wallets can't be imported", func(t *testing.T) {
+		target := wallet.NewRepository(openStore(t), nil)
+		require.NoError(t, target.Set(legacy))
+
+		_, err := backup.Restore(ctx, target, nil, payload, false)
+		assert.ErrorIs(t, err, wallet.ErrWalletExists)
+
+		_, err = target.LoadHDSeed()
+		assert.ErrorIs(t, err, wallet.ErrHDSeedNotFound)
+
+		indexes, err := target.DerivationIndexes()
+		require.NoError(t, err)
+		assert.Empty(t, indexes)
+	})
+
+	t.Run("Restores HD mnemonic", func(t *testing.T) {
+		// Given source KMS with seed generated from mnemonic
+		mnemonic, err := wallet.GenerateMnemonic(strings.NewReader(strings.Repeat("m", 32)))
+		require.NoError(t, err)
+
+		mnemonicSource := wallet.NewRepository(openStore(t), nil)
+		require.NoError(t, mnemonicSource.SetHDMnemonic(mnemonic))
+
+		mnemonicPayload, err := backup.Collect(mnemonicSource)
+		require.NoError(t, err)
+		assert.Equal(t, mnemonic, mnemonicPayload.HDMnemonic)
+
+		target := wallet.NewRepository(openStore(t), nil)
+
+		// ACT
+		_, err = backup.Restore(ctx, target, nil, mnemonicPayload, false)
+
+		// ASSERT
+		require.NoError(t, err)
+
+		actual, err := target.LoadHDMnemonic()
+		require.NoError(t, err)
+		assert.Equal(t, mnemonic, actual)
+
+		// Check that mnemonic of another seed is rejected
+		invalid := *mnemonicPayload
+		invalid.HDSeed = seed
+
+		_, err = backup.Restore(ctx, wallet.NewRepository(openStore(t), nil), nil, &invalid, false)
+		assert.ErrorIs(t, err, backup.ErrInvalidPayload)
+	})
+
+	t.Run("Refuses to replace HD seed in use", func(t *testing.T) {
+		target := wallet.NewRepository(openStore(t), nil)
+
+		otherSeed, err := wallet.GenerateHDSeed(strings.NewReader(strings.Repeat("o", 64)))
+		require.NoError(t, err)
+		require.NoError(t, target.SetHDSeed(otherSeed))
+		_, err = target.NextDerivationIndex(wallet.ETH)
+		require.NoError(t, err)
+
+		_, err = backup.Restore(ctx, target, nil, payload, true)
+		assert.ErrorIs(t, err, backup.ErrSeedMismatch)
+	})
+}
+
+func newKeyring(t *testing.T) *encryption.Keyring {
+	keyring, err := encryption.NewKeyring([]byte(strings.Repeat("k", 32)))
+	require.NoError(t, err)
+
+	return keyring
+}
+
+func openStore(t *testing.T) storage.Store {
+	logger := zerolog.Nop()
+
+	store, err := storage.Open(storage.Config{Type: storage.Bolt, Path: t.TempDir() + "/kms.test.db"}, &logger)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = store.Close() })
+
+	return store
+}
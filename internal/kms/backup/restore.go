@@ -0,0 +1,134 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oxygenpay/oxygen/internal/kms/audit"
+	"github.com/oxygenpay/oxygen/internal/kms/storage"
+	"github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrSeedMismatch   = errors.New("KMS store already uses another HD seed")
+	ErrInvalidPayload = errors.New("backup contains invalid wallet")
+)
+
+// Collect gathers all wallets & HD seed from the repository.
+func Collect(repo *wallet.Repository) (*Payload, error) {
+	wallets, err := repo.List()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list wallets")
+	}
+
+	seed, err := repo.LoadHDSeed()
+	if err != nil && !errors.Is(err, wallet.ErrHDSeedNotFound) {
+		return nil, errors.Wrap(err, "unable to load HD seed")
+	}
+
+	mnemonic, err := repo.LoadHDMnemonic()
+	if err != nil && !errors.Is(err, wallet.ErrHDSeedNotFound) && !errors.Is(err, wallet.ErrNoMnemonic) {
+		return nil, errors.Wrap(err, "unable to load HD mnemonic")
+	}
+
+	indexes, err := repo.DerivationIndexes()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load derivation indexes")
+	}
+
+	return &Payload{
+		CreatedAt:         time.Now().UTC(),
+		Wallets:           wallets,
+		HDSeed:            seed,
+		HDMnemonic:        mnemonic,
+		DerivationIndexes: indexes,
+	}, nil
+}
+
+// Restore imports payload into the repository. Existing wallets are overwritten only if overwrite is true.
+// HD seed can be restored only to a store that has no HD wallets derived from another seed.
+// The whole payload is checked before anything is written and then applied in a single transaction
+// together with audit log entries, so auditLog should use the same store as repo.
+// Returns number of restored wallets.
+func Restore(ctx context.Context, repo *wallet.Repository, auditLog *audit.Log, p *Payload, overwrite bool) (int, error) {
+	if err := validate(repo, p); err != nil {
+		return 0, err
+	}
+
+	appendAudit := func(tx storage.Tx) error {
+		for _, w := range p.Wallets {
+			_, err := auditLog.AppendTx(ctx, tx, audit.Entry{
+				Operation:  audit.RestoreWallet,
+				WalletID:   w.UUID.String(),
+				Blockchain: w.Blockchain.String(),
+				Result:     audit.Success,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	err := repo.Restore(p.HDSeed, p.HDMnemonic, p.Wallets, p.DerivationIndexes, overwrite, appendAudit)
+	switch {
+	case errors.Is(err, wallet.ErrHDSeedInUse):
+		return 0, ErrSeedMismatch
+	case err != nil:
+		return 0, errors.Wrap(err, "unable to restore wallets")
+	}
+
+	return len(p.Wallets), nil
+}
+
+// validate ensures that each wallet is unique and its key (or HD seed) derives wallet's address.
+// HD wallets are checked against payload's seed or, if payload has none, against store's seed.
+// Payload's mnemonic should derive payload's seed.
+func validate(repo *wallet.Repository, p *Payload) error {
+	if p.HDMnemonic != "" {
+		seed, err := wallet.SeedFromMnemonic(p.HDMnemonic, "")
+		if err != nil || !bytes.Equal(seed, p.HDSeed) {
+			return errors.Wrap(ErrInvalidPayload, "HD mnemonic doesn't match HD seed")
+		}
+	}
+
+	seed := p.HDSeed
+	if len(seed) == 0 {
+		current, err := repo.LoadHDSeed()
+		if err != nil && !errors.Is(err, wallet.ErrHDSeedNotFound) {
+			return errors.Wrap(err, "unable to load HD seed")
+		}
+
+		seed = current
+	}
+
+	var keychain *wallet.HDKeychain
+	if len(seed) > 0 {
+		var err error
+		if keychain, err = wallet.NewHDKeychain(seed); err != nil {
+			return errors.Wrap(ErrInvalidPayload, err.Error())
+		}
+
+		defer keychain.Wipe()
+	}
+
+	ids := make(map[uuid.UUID]struct{}, len(p.Wallets))
+
+	for _, w := range p.Wallets {
+		if _, exists := ids[w.UUID]; exists {
+			return errors.Wrapf(ErrInvalidPayload, "duplicate wallet %s", w.UUID)
+		}
+
+		ids[w.UUID] = struct{}{}
+
+		if err := wallet.VerifyKeys(w, keychain); err != nil {
+			return errors.Wrapf(ErrInvalidPayload, "wallet %s: %s", w.UUID, err.Error())
+		}
+	}
+
+	return nil
+}
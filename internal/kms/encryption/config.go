@@ -0,0 +1,127 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Config master key source. Exactly one option should be set unless KMS runs in sealed mode:
+// then master key is not configured at all and is restored from key shares at runtime.
+type Config struct {
+	MasterKey     string `yaml:"master_key" env:"KMS_MASTER_KEY" env-description:"Base64-encoded 32-byte master key that encrypts KMS private keys"`
+	MasterKeyFile string `yaml:"master_key_file" env:"KMS_MASTER_KEY_FILE" env-description:"Path to a file with base64-encoded or raw 32-byte master key"`
+	Passphrase    string `yaml:"passphrase" env:"KMS_MASTER_PASSPHRASE" env-description:"Passphrase to derive master key from (scrypt)"`
+
+	UnsealThreshold int `yaml:"unseal_threshold" env:"KMS_UNSEAL_THRESHOLD" env-description:"Starts KMS sealed until this number of master key shares is submitted (see kms-split-key)"`
+}
+
+// scrypt params as recommended for interactive logins in 2017+.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+
+	saltSize = 16
+)
+
+var ErrNoMasterKey = errors.New("master key is not configured")
+
+func (c Config) IsEmpty() bool {
+	return c.MasterKey == "" && c.MasterKeyFile == "" && c.Passphrase == ""
+}
+
+// IsSealed checks whether KMS should start in sealed mode.
+func (c Config) IsSealed() bool {
+	return c.UnsealThreshold > 0
+}
+
+// ResolveMasterKey returns master key from configured source. Salt is used only for passphrase-derived keys
+// and should be persisted alongside encrypted data.
+func ResolveMasterKey(cfg Config, salt []byte) ([]byte, error) {
+	set := 0
+	for _, v := range []string{cfg.MasterKey, cfg.MasterKeyFile, cfg.Passphrase} {
+		if v != "" {
+			set++
+		}
+	}
+
+	switch {
+	case set == 0:
+		return nil, ErrNoMasterKey
+	case set > 1:
+		return nil, errors.New("only one of master key, master key file, or passphrase should be set")
+	case cfg.MasterKey != "":
+		return decodeKey([]byte(cfg.MasterKey))
+	case cfg.MasterKeyFile != "":
+		raw, err := os.ReadFile(cfg.MasterKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read master key file")
+		}
+
+		if len(raw) == keySize {
+			return raw, nil
+		}
+
+		return decodeKey(raw)
+	default:
+		return DeriveKey(cfg.Passphrase, salt)
+	}
+}
+
+// NewKeyringFromConfig resolves master key and creates a keyring.
+func NewKeyringFromConfig(cfg Config, salt []byte) (*Keyring, error) {
+	key, err := ResolveMasterKey(cfg, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyring(key)
+}
+
+// DeriveKey derives master key from passphrase using scrypt.
+func DeriveKey(passphrase string, salt []byte) ([]byte, error) {
+	if len(salt) < saltSize {
+		return nil, errors.New("salt is too short")
+	}
+
+	return scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+}
+
+// NewSalt generates random salt for DeriveKey.
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, errors.Wrap(err, "unable to generate salt")
+	}
+
+	return salt, nil
+}
+
+// GenerateKey returns random base64-encoded master key.
+func GenerateKey() (string, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", errors.Wrap(err, "unable to generate master key")
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func decodeKey(raw []byte) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return nil, errors.Wrap(err, "master key should be base64-encoded")
+	}
+
+	if len(key) != keySize {
+		return nil, ErrInvalidKey
+	}
+
+	return key, nil
+}
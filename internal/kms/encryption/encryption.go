@@ -0,0 +1,233 @@
+// Package encryption provides envelope encryption of KMS data at rest. Each record is encrypted
+// with its own random data key that is in turn encrypted ("wrapped") with a master key.
+// Master key rotation only re-wraps data keys without touching records' payload.
+//
+// Payload is bound to its record with additional authenticated data (e.g. bucket & record key),
+// so envelopes can't be swapped between records. Legacy (version 1) envelopes are not bound
+// and are still readable.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Keyring encrypts & decrypts envelopes using master key.
+type Keyring struct {
+	masterKey []byte
+	id        string
+}
+
+// Envelope represents encrypted record.
+type Envelope struct {
+	Version int `json:"envelope_version"`
+
+	// KeyID id of a master key that was used to wrap DataKey.
+	KeyID string `json:"key_id"`
+
+	// DataKey encrypted data key.
+	DataKey []byte `json:"data_key"`
+
+	// Data encrypted payload.
+	Data []byte `json:"data"`
+}
+
+const (
+	legacyEnvelopeVersion = 1
+	envelopeVersion       = 2
+
+	keySize   = 32
+	keyIDSize = 8
+	keyIDSalt = "oxygen-kms-master-key-id"
+)
+
+var (
+	ErrInvalidKey       = errors.New("master key should be 32 bytes long")
+	ErrKeyMismatch      = errors.New("record is encrypted with different master key")
+	ErrMalformedPayload = errors.New("malformed encrypted payload")
+	ErrUnknownVersion   = errors.New("unknown envelope version")
+)
+
+func NewKeyring(masterKey []byte) (*Keyring, error) {
+	if len(masterKey) != keySize {
+		return nil, ErrInvalidKey
+	}
+
+	mac := hmac.New(sha256.New, masterKey)
+	mac.Write([]byte(keyIDSalt))
+
+	return &Keyring{
+		masterKey: masterKey,
+		id:        hex.EncodeToString(mac.Sum(nil)[:keyIDSize]),
+	}, nil
+}
+
+// ID returns master key fingerprint that is safe to be stored alongside encrypted data.
+func (k *Keyring) ID() string {
+	return k.id
+}
+
+// Wipe zeroes master key in memory. Keyring can't be used afterwards.
+func (k *Keyring) Wipe() {
+	for i := range k.masterKey {
+		k.masterKey[i] = 0
+	}
+}
+
+// Seal encrypts plaintext with new random data key. Additional data (e.g. record key) is authenticated
+// but not encrypted: the same aad should be provided to Open.
+func (k *Keyring) Seal(plaintext, aad []byte) (*Envelope, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, errors.Wrap(err, "unable to generate data key")
+	}
+
+	data, err := encrypt(dataKey, plaintext, aad)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to encrypt data")
+	}
+
+	wrappedKey, err := encrypt(k.masterKey, dataKey, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to wrap data key")
+	}
+
+	return &Envelope{
+		Version: envelopeVersion,
+		KeyID:   k.id,
+		DataKey: wrappedKey,
+		Data:    data,
+	}, nil
+}
+
+// Open decrypts envelope's payload. aad is ignored for legacy envelopes.
+func (k *Keyring) Open(e *Envelope, aad []byte) ([]byte, error) {
+	switch e.Version {
+	case legacyEnvelopeVersion:
+		aad = nil
+	case envelopeVersion:
+	default:
+		return nil, errors.Wrapf(ErrUnknownVersion, "version %d", e.Version)
+	}
+
+	dataKey, err := k.unwrap(e)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := decrypt(dataKey, e.Data, aad)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decrypt data")
+	}
+
+	return plaintext, nil
+}
+
+// Rewrap re-encrypts envelope's data key with next keyring leaving payload as is.
+func (k *Keyring) Rewrap(e *Envelope, next *Keyring) (*Envelope, error) {
+	dataKey, err := k.unwrap(e)
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedKey, err := encrypt(next.masterKey, dataKey, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to wrap data key")
+	}
+
+	return &Envelope{
+		Version: e.Version,
+		KeyID:   next.id,
+		DataKey: wrappedKey,
+		Data:    e.Data,
+	}, nil
+}
+
+func (k *Keyring) unwrap(e *Envelope) ([]byte, error) {
+	if e.KeyID != k.id {
+		return nil, errors.Wrapf(ErrKeyMismatch, "expected key %q, got %q", k.id, e.KeyID)
+	}
+
+	dataKey, err := decrypt(k.masterKey, e.DataKey, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unwrap data key")
+	}
+
+	return dataKey, nil
+}
+
+// IsLegacy checks whether envelope's payload is not bound to its record.
+func (e *Envelope) IsLegacy() bool {
+	return e.Version == legacyEnvelopeVersion
+}
+
+// Marshal encodes envelope to JSON.
+func (e *Envelope) Marshal() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// UnmarshalEnvelope decodes envelope from raw value. Returns false if value is not an envelope
+// (e.g. a legacy plaintext record).
+func UnmarshalEnvelope(raw []byte) (*Envelope, bool, error) {
+	e := &Envelope{}
+	if err := json.Unmarshal(raw, e); err != nil {
+		return nil, false, err
+	}
+
+	if e.Version == 0 {
+		return nil, false, nil
+	}
+
+	if e.KeyID == "" || len(e.DataKey) == 0 || len(e.Data) == 0 {
+		return nil, false, ErrMalformedPayload
+	}
+
+	return e, true, nil
+}
+
+// encrypt encrypts plaintext using AES-256-GCM. Returns nonce + ciphertext.
+func encrypt(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func decrypt(key, payload, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payload) < gcm.NonceSize() {
+		return nil, ErrMalformedPayload
+	}
+
+	nonce, ciphertext := payload[:gcm.NonceSize()], payload[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, ciphertext, aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
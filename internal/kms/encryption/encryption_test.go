@@ -0,0 +1,162 @@
+package encryption_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"testing"
+
+	"github.com/oxygenpay/oxygen/internal/kms/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyring(t *testing.T) {
+	keyring := newKeyring(t, 1)
+	other := newKeyring(t, 2)
+
+	plaintext := []byte(`{"private_key":"0xabc"}`)
+	aad := []byte("wallets/a")
+
+	t.Run("Seals and opens payload", func(t *testing.T) {
+		env, err := keyring.Seal(plaintext, aad)
+		require.NoError(t, err)
+
+		assert.Equal(t, keyring.ID(), env.KeyID)
+		assert.NotContains(t, string(env.Data), "0xabc")
+
+		raw, err := env.Marshal()
+		require.NoError(t, err)
+
+		decoded, ok, err := encryption.UnmarshalEnvelope(raw)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		actual, err := keyring.Open(decoded, aad)
+		require.NoError(t, err)
+		assert.Equal(t, plaintext, actual)
+	})
+
+	t.Run("Fails to open with different key", func(t *testing.T) {
+		env, err := keyring.Seal(plaintext, aad)
+		require.NoError(t, err)
+
+		_, err = other.Open(env, aad)
+		assert.ErrorIs(t, err, encryption.ErrKeyMismatch)
+	})
+
+	t.Run("Detects tampered payload", func(t *testing.T) {
+		env, err := keyring.Seal(plaintext, aad)
+		require.NoError(t, err)
+
+		env.Data[len(env.Data)-1] ^= 0xFF
+
+		_, err = keyring.Open(env, aad)
+		assert.Error(t, err)
+	})
+
+	t.Run("Fails to open with different aad", func(t *testing.T) {
+		env, err := keyring.Seal(plaintext, aad)
+		require.NoError(t, err)
+
+		_, err = keyring.Open(env, []byte("wallets/b"))
+		assert.Error(t, err)
+
+		_, err = keyring.Open(env, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("Opens legacy envelope", func(t *testing.T) {
+		env, err := keyring.Seal(plaintext, nil)
+		require.NoError(t, err)
+
+		env.Version = 1
+		assert.True(t, env.IsLegacy())
+
+		actual, err := keyring.Open(env, aad)
+		require.NoError(t, err)
+		assert.Equal(t, plaintext, actual)
+	})
+
+	t.Run("Fails on unknown version", func(t *testing.T) {
+		env, err := keyring.Seal(plaintext, aad)
+		require.NoError(t, err)
+
+		env.Version = 3
+
+		_, err = keyring.Open(env, aad)
+		assert.ErrorIs(t, err, encryption.ErrUnknownVersion)
+	})
+
+	t.Run("Rewraps data key", func(t *testing.T) {
+		env, err := keyring.Seal(plaintext, aad)
+		require.NoError(t, err)
+
+		rewrapped, err := keyring.Rewrap(env, other)
+		require.NoError(t, err)
+
+		assert.Equal(t, other.ID(), rewrapped.KeyID)
+		assert.Equal(t, env.Data, rewrapped.Data)
+
+		actual, err := other.Open(rewrapped, aad)
+		require.NoError(t, err)
+		assert.Equal(t, plaintext, actual)
+	})
+
+	t.Run("Skips plaintext records", func(t *testing.T) {
+		_, ok, err := encryption.UnmarshalEnvelope([]byte(`{"uuid":"123","private_key":"0xabc"}`))
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestResolveMasterKey(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	encoded := base64.StdEncoding.EncodeToString(key)
+
+	keyFile := t.TempDir() + "/master.key"
+	require.NoError(t, os.WriteFile(keyFile, []byte(encoded+"\n"), 0600))
+
+	salt, err := encryption.NewSalt()
+	require.NoError(t, err)
+
+	t.Run("From env", func(t *testing.T) {
+		actual, err := encryption.ResolveMasterKey(encryption.Config{MasterKey: encoded}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, key, actual)
+	})
+
+	t.Run("From file", func(t *testing.T) {
+		actual, err := encryption.ResolveMasterKey(encryption.Config{MasterKeyFile: keyFile}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, key, actual)
+	})
+
+	t.Run("From passphrase", func(t *testing.T) {
+		a, err := encryption.ResolveMasterKey(encryption.Config{Passphrase: "correct horse"}, salt)
+		require.NoError(t, err)
+		assert.Len(t, a, 32)
+
+		b, err := encryption.ResolveMasterKey(encryption.Config{Passphrase: "correct horse"}, salt)
+		require.NoError(t, err)
+		assert.Equal(t, a, b)
+	})
+
+	t.Run("Fails on invalid config", func(t *testing.T) {
+		_, err := encryption.ResolveMasterKey(encryption.Config{}, nil)
+		assert.ErrorIs(t, err, encryption.ErrNoMasterKey)
+
+		_, err = encryption.ResolveMasterKey(encryption.Config{MasterKey: encoded, Passphrase: "abc"}, salt)
+		assert.Error(t, err)
+
+		_, err = encryption.ResolveMasterKey(encryption.Config{MasterKey: "c2hvcnQ="}, nil)
+		assert.ErrorIs(t, err, encryption.ErrInvalidKey)
+	})
+}
+
+func newKeyring(t *testing.T, seed byte) *encryption.Keyring {
+	keyring, err := encryption.NewKeyring(bytes.Repeat([]byte{seed}, 32))
+	require.NoError(t, err)
+
+	return keyring
+}
@@ -0,0 +1,218 @@
+// Package seal implements sealed startup mode of KMS. Master key is never configured directly:
+// it's split into N key shares using Shamir's secret sharing, and KMS refuses to access wallets
+// until M of them are submitted. Sealing wipes the key from memory.
+package seal
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/oxygenpay/oxygen/internal/kms/audit"
+	"github.com/oxygenpay/oxygen/internal/kms/encryption"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// UnsealFunc should verify the keyring and make it available to KMS.
+type UnsealFunc func(keyring *encryption.Keyring) error
+
+// SealFunc should drop keys from memory.
+type SealFunc func()
+
+// Unsealer collects key shares and unseals KMS once threshold is reached.
+type Unsealer struct {
+	mu        sync.Mutex
+	threshold int
+	sealed    bool
+	shares    map[byte][]byte
+	unseal    UnsealFunc
+	seal      SealFunc
+	auditLog  *audit.Log
+	logger    *zerolog.Logger
+}
+
+// Status of the unsealing process.
+type Status struct {
+	Sealed    bool
+	Threshold int
+	Progress  int
+}
+
+var (
+	ErrNotSealed     = errors.New("KMS is already unsealed")
+	ErrInvalidShares = errors.New("key shares don't match KMS master key")
+)
+
+// New Unsealer constructor. KMS should be already sealed. If audit log is nil, events are not recorded.
+func New(threshold int, unseal UnsealFunc, seal SealFunc, auditLog *audit.Log, logger *zerolog.Logger) *Unsealer {
+	log := logger.With().Str("channel", "kms_seal").Logger()
+
+	return &Unsealer{
+		threshold: threshold,
+		sealed:    true,
+		shares:    make(map[byte][]byte),
+		unseal:    unseal,
+		seal:      seal,
+		auditLog:  auditLog,
+		logger:    &log,
+	}
+}
+
+// EncodeShare returns hex representation of the share.
+func EncodeShare(share []byte) string {
+	return hex.EncodeToString(share)
+}
+
+// DecodeShare parses hex representation of the share.
+func DecodeShare(raw string) ([]byte, error) {
+	share, err := hex.DecodeString(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidShare, err.Error())
+	}
+
+	return share, nil
+}
+
+func (u *Unsealer) Status() Status {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.status()
+}
+
+// IsSealed nil-safe check. KMS without Unsealer is never sealed.
+func (u *Unsealer) IsSealed() bool {
+	if u == nil {
+		return false
+	}
+
+	return u.Status().Sealed
+}
+
+// Submit adds hex-encoded key share. When threshold is reached, shares are combined into
+// the master key and KMS is unsealed. If the key is wrong, submitted shares are discarded.
+func (u *Unsealer) Submit(ctx context.Context, rawShare string) (Status, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if !u.sealed {
+		return u.status(), ErrNotSealed
+	}
+
+	share, err := DecodeShare(rawShare)
+	if err != nil {
+		return u.status(), err
+	}
+
+	if len(share) < 2 {
+		return u.status(), ErrInvalidShare
+	}
+
+	x := share[len(share)-1]
+	if _, ok := u.shares[x]; ok {
+		wipe(share)
+		return u.status(), ErrDuplicateShare
+	}
+
+	u.shares[x] = share
+
+	if len(u.shares) < u.threshold {
+		u.logger.Info().Int("progress", len(u.shares)).Int("threshold", u.threshold).Msg("received key share")
+		return u.status(), nil
+	}
+
+	err = u.combine()
+	u.resetShares()
+
+	if err != nil {
+		u.logger.Error().Err(err).Msg("unable to unseal KMS")
+		u.record(ctx, audit.Unseal, err)
+
+		return u.status(), err
+	}
+
+	u.sealed = false
+	u.logger.Info().Msg("KMS is unsealed")
+	u.record(ctx, audit.Unseal, nil)
+
+	return u.status(), nil
+}
+
+// Seal wipes master key from memory and discards submitted shares.
+func (u *Unsealer) Seal(ctx context.Context) Status {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.resetShares()
+
+	if u.sealed {
+		return u.status()
+	}
+
+	u.seal()
+	u.sealed = true
+
+	u.logger.Warn().Msg("KMS is sealed")
+	u.record(ctx, audit.Seal, nil)
+
+	return u.status()
+}
+
+func (u *Unsealer) combine() error {
+	shares := make([][]byte, 0, len(u.shares))
+	for _, share := range u.shares {
+		shares = append(shares, share)
+	}
+
+	key, err := Combine(shares)
+	if err != nil {
+		return err
+	}
+
+	keyring, err := encryption.NewKeyring(key)
+	if err != nil {
+		wipe(key)
+		return errors.Wrap(ErrInvalidShares, err.Error())
+	}
+
+	if err := u.unseal(keyring); err != nil {
+		keyring.Wipe()
+
+		if errors.Is(err, encryption.ErrKeyMismatch) {
+			return ErrInvalidShares
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (u *Unsealer) resetShares() {
+	for x, share := range u.shares {
+		wipe(share)
+		delete(u.shares, x)
+	}
+}
+
+func (u *Unsealer) status() Status {
+	return Status{
+		Sealed:    u.sealed,
+		Threshold: u.threshold,
+		Progress:  len(u.shares),
+	}
+}
+
+func (u *Unsealer) record(ctx context.Context, op audit.Operation, err error) {
+	entry := audit.Entry{Operation: op, Result: audit.Success}
+	if err != nil {
+		entry.Result = audit.Failed
+		entry.Error = err.Error()
+	}
+
+	if _, errAppend := u.auditLog.Append(ctx, entry); errAppend != nil {
+		u.logger.Error().Err(errAppend).Str("operation", string(op)).Msg("unable to append audit log entry")
+	}
+}
@@ -0,0 +1,184 @@
+package seal_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/oxygenpay/oxygen/internal/kms/audit"
+	"github.com/oxygenpay/oxygen/internal/kms/encryption"
+	"github.com/oxygenpay/oxygen/internal/kms/seal"
+	"github.com/oxygenpay/oxygen/internal/kms/storage"
+	"github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte(strings.Repeat("k", 32))
+
+	shares, err := seal.Split(secret, 5, 3)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+
+	for _, share := range shares {
+		assert.Len(t, share, len(secret)+1)
+		assert.False(t, bytes.Contains(share, secret))
+	}
+
+	t.Run("Any threshold of shares restores secret", func(t *testing.T) {
+		for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+			var selected [][]byte
+			for _, i := range subset {
+				selected = append(selected, shares[i])
+			}
+
+			actual, err := seal.Combine(selected)
+			require.NoError(t, err)
+			assert.Equal(t, secret, actual)
+		}
+	})
+
+	t.Run("Less than threshold shares don't restore secret", func(t *testing.T) {
+		actual, err := seal.Combine(shares[:2])
+		require.NoError(t, err)
+		assert.NotEqual(t, secret, actual)
+	})
+
+	t.Run("Rejects invalid shares", func(t *testing.T) {
+		_, err := seal.Combine([][]byte{shares[0], shares[0]})
+		assert.ErrorIs(t, err, seal.ErrDuplicateShare)
+
+		_, err = seal.Combine([][]byte{shares[0], shares[1][:10]})
+		assert.ErrorIs(t, err, seal.ErrInvalidShare)
+
+		_, err = seal.Combine(shares[:1])
+		assert.ErrorIs(t, err, seal.ErrInvalidShare)
+	})
+
+	t.Run("Rejects invalid params", func(t *testing.T) {
+		for _, tc := range [][2]int{{5, 1}, {3, 4}, {256, 3}} {
+			_, err := seal.Split(secret, tc[0], tc[1])
+			assert.ErrorIs(t, err, seal.ErrInvalidSharesParams)
+		}
+	})
+}
+
+func TestUnsealer(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	masterKey := []byte(strings.Repeat("k", 32))
+
+	shares, err := seal.Split(masterKey, 3, 2)
+	require.NoError(t, err)
+
+	// Given encrypted store with HD wallet
+	db := openStore(t)
+	auditLog := audit.New(db, []byte("audit-test-key"))
+	repo := wallet.NewRepository(db, newKeyring(t, masterKey))
+	require.NoError(t, repo.VerifyKeyring())
+
+	seed, err := wallet.GenerateHDSeed(strings.NewReader(strings.Repeat("s", 64)))
+	require.NoError(t, err)
+	require.NoError(t, repo.SetHDSeed(seed))
+
+	keychain, err := wallet.NewHDKeychain(seed)
+	require.NoError(t, err)
+
+	service := wallet.New(repo, wallet.NewGenerator(), keychain, nil, nil, &logger)
+
+	w, err := service.CreateWallet(ctx, wallet.ETH)
+	require.NoError(t, err)
+
+	// And sealed service
+	service.Seal()
+	unsealer := seal.New(2, service.Unseal, service.Seal, auditLog, &logger)
+
+	_, err = service.GetWallet(ctx, w.UUID, false)
+	require.ErrorIs(t, err, wallet.ErrSealed)
+
+	_, err = service.CreateWallet(ctx, wallet.ETH)
+	require.ErrorIs(t, err, wallet.ErrSealed)
+
+	t.Run("Rejects shares of another key", func(t *testing.T) {
+		otherShares, err := seal.Split([]byte(strings.Repeat("o", 32)), 3, 2)
+		require.NoError(t, err)
+
+		status, err := unsealer.Submit(ctx, seal.EncodeShare(otherShares[0]))
+		require.NoError(t, err)
+		assert.Equal(t, seal.Status{Sealed: true, Threshold: 2, Progress: 1}, status)
+
+		_, err = unsealer.Submit(ctx, seal.EncodeShare(otherShares[0]))
+		assert.ErrorIs(t, err, seal.ErrDuplicateShare)
+
+		status, err = unsealer.Submit(ctx, seal.EncodeShare(otherShares[1]))
+		assert.ErrorIs(t, err, seal.ErrInvalidShares)
+		assert.Equal(t, seal.Status{Sealed: true, Threshold: 2, Progress: 0}, status)
+
+		_, err = unsealer.Submit(ctx, "not-a-share")
+		assert.ErrorIs(t, err, seal.ErrInvalidShare)
+	})
+
+	t.Run("Unseals with threshold of shares", func(t *testing.T) {
+		_, err := unsealer.Submit(ctx, seal.EncodeShare(shares[2]))
+		require.NoError(t, err)
+
+		status, err := unsealer.Submit(ctx, seal.EncodeShare(shares[0]))
+		require.NoError(t, err)
+		assert.False(t, status.Sealed)
+
+		actual, err := service.GetWallet(ctx, w.UUID, false)
+		require.NoError(t, err)
+		assert.Equal(t, w.PrivateKey, actual.PrivateKey)
+
+		_, err = unsealer.Submit(ctx, seal.EncodeShare(shares[1]))
+		assert.ErrorIs(t, err, seal.ErrNotSealed)
+	})
+
+	t.Run("Seal wipes keys", func(t *testing.T) {
+		status := unsealer.Seal(ctx)
+		assert.True(t, status.Sealed)
+		assert.True(t, unsealer.IsSealed())
+
+		_, err := service.GetWallet(ctx, w.UUID, false)
+		assert.ErrorIs(t, err, wallet.ErrSealed)
+	})
+
+	t.Run("Records audit entries", func(t *testing.T) {
+		entries, err := auditLog.List(0, 0)
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+
+		assert.Equal(t, audit.Unseal, entries[0].Operation)
+		assert.Equal(t, audit.Failed, entries[0].Result)
+		assert.Equal(t, audit.Unseal, entries[1].Operation)
+		assert.Equal(t, audit.Success, entries[1].Result)
+		assert.Equal(t, audit.Seal, entries[2].Operation)
+	})
+
+	t.Run("Nil unsealer is never sealed", func(t *testing.T) {
+		var nilUnsealer *seal.Unsealer
+		assert.False(t, nilUnsealer.IsSealed())
+	})
+}
+
+func newKeyring(t *testing.T, key []byte) *encryption.Keyring {
+	keyring, err := encryption.NewKeyring(append([]byte(nil), key...))
+	require.NoError(t, err)
+
+	return keyring
+}
+
+func openStore(t *testing.T) storage.Store {
+	logger := zerolog.Nop()
+
+	store, err := storage.Open(storage.Config{Type: storage.Bolt, Path: t.TempDir() + "/kms.test.db"}, &logger)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = store.Close() })
+
+	return store
+}
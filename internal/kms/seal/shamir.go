@@ -0,0 +1,149 @@
+package seal
+
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Shamir's secret sharing over GF(2^8). Each byte of the secret is the constant term of its own
+// random polynomial of degree threshold-1. Share is polynomial values followed by the x coordinate.
+
+const maxShares = 255
+
+var (
+	ErrInvalidSharesParams = errors.New("threshold should be in [2, shares] and shares should be <= 255")
+	ErrInvalidShare        = errors.New("invalid key share")
+	ErrDuplicateShare      = errors.New("duplicate key share")
+)
+
+// Split splits secret into n shares so that any threshold of them can restore it.
+func Split(secret []byte, n, threshold int) ([][]byte, error) {
+	switch {
+	case len(secret) == 0:
+		return nil, errors.New("secret is empty")
+	case threshold < 2 || threshold > n || n > maxShares:
+		return nil, ErrInvalidSharesParams
+	}
+
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][len(secret)] = byte(i + 1)
+	}
+
+	coefficients := make([]byte, threshold)
+	defer wipe(coefficients)
+
+	for i, b := range secret {
+		if _, err := io.ReadFull(rand.Reader, coefficients[1:]); err != nil {
+			return nil, errors.Wrap(err, "unable to generate polynomial")
+		}
+
+		coefficients[0] = b
+
+		for _, share := range shares {
+			share[i] = evaluate(coefficients, share[len(secret)])
+		}
+	}
+
+	return shares, nil
+}
+
+// Combine restores secret from shares. Note that combining less than threshold shares
+// doesn't fail but produces a wrong secret, so the result should be verified by the caller.
+func Combine(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, errors.Wrap(ErrInvalidShare, "at least 2 shares are required")
+	}
+
+	size := len(shares[0])
+	if size < 2 {
+		return nil, ErrInvalidShare
+	}
+
+	xs := make([]byte, len(shares))
+	seen := make(map[byte]struct{}, len(shares))
+
+	for i, share := range shares {
+		if len(share) != size {
+			return nil, errors.Wrap(ErrInvalidShare, "shares have different length")
+		}
+
+		x := share[size-1]
+		if x == 0 {
+			return nil, ErrInvalidShare
+		}
+
+		if _, ok := seen[x]; ok {
+			return nil, ErrDuplicateShare
+		}
+
+		seen[x] = struct{}{}
+		xs[i] = x
+	}
+
+	secret := make([]byte, size-1)
+
+	// Lagrange interpolation at x = 0. Addition & subtraction in GF(2^8) is XOR.
+	for i := range shares {
+		basis := byte(1)
+		for j := range shares {
+			if i != j {
+				basis = mul(basis, div(xs[j], xs[i]^xs[j]))
+			}
+		}
+
+		for k := range secret {
+			secret[k] ^= mul(shares[i][k], basis)
+		}
+	}
+
+	return secret, nil
+}
+
+// evaluate computes polynomial value at x using Horner's method.
+func evaluate(coefficients []byte, x byte) byte {
+	result := byte(0)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result = mul(result, x) ^ coefficients[i]
+	}
+
+	return result
+}
+
+// mul multiplies a and b in GF(2^8) with AES polynomial x^8 + x^4 + x^3 + x + 1.
+// Runs in constant time to avoid leaking key material.
+func mul(a, b byte) byte {
+	var result byte
+	for i := 0; i < 8; i++ {
+		result ^= -(b & 1) & a
+		carry := -(a >> 7)
+		a = (a << 1) ^ (0x1b & carry)
+		b >>= 1
+	}
+
+	return result
+}
+
+// inverse returns a^254 = a^-1. Inverse of 0 is 0.
+func inverse(a byte) byte {
+	result := byte(1)
+	for i := 0; i < 7; i++ {
+		a = mul(a, a)
+		result = mul(result, a)
+	}
+
+	return result
+}
+
+func div(a, b byte) byte {
+	return mul(a, inverse(b))
+}
+
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
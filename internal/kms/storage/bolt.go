@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.etcd.io/bbolt"
+)
+
+// boltStore stores data in a local bolt db file. Only one process can open the file.
+type boltStore struct {
+	mu sync.RWMutex
+	db *bbolt.DB
+}
+
+type boltTx struct {
+	tx *bbolt.Tx
+}
+
+type boltBucket struct {
+	*bbolt.Bucket
+}
+
+// NewBolt wraps bolt db and creates missing buckets.
+func NewBolt(db *bbolt.DB) (Store, error) {
+	err := db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range Buckets {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &boltStore{db: db}, nil
+}
+
+func (s *boltStore) View(fn func(tx Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.db.View(func(tx *bbolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+func (s *boltStore) Update(fn func(tx Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+func (s *boltStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.db.Close()
+}
+
+// Compact rewrites db into a new file that contains only live pages, fsyncs it and atomically
+// renames it over the original file. Bolt reuses freed pages without wiping them, so after
+// in-place rewrite of records (e.g. encryption) their previous versions stay in the file until compaction.
+func (s *boltStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.db.Path()
+	tmpPath := path + ".compact"
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.Wrap(err, "unable to stat db file")
+	}
+
+	if err := os.Remove(tmpPath); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "unable to remove stale compaction file")
+	}
+
+	dst, err := bbolt.Open(tmpPath, info.Mode().Perm(), nil)
+	if err != nil {
+		return errors.Wrap(err, "unable to create compaction file")
+	}
+
+	if err := bbolt.Compact(dst, s.db, 0); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmpPath)
+
+		return errors.Wrap(err, "unable to compact db")
+	}
+
+	if err := dst.Sync(); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmpPath)
+
+		return errors.Wrap(err, "unable to sync compacted db")
+	}
+
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return errors.Wrap(err, "unable to close compacted db")
+	}
+
+	if err := s.db.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return errors.Wrap(err, "unable to close db")
+	}
+
+	// old file is unlinked by rename
+	renameErr := os.Rename(tmpPath, path)
+	if renameErr == nil {
+		renameErr = syncDir(filepath.Dir(path))
+	}
+
+	db, err := bbolt.Open(path, info.Mode().Perm(), nil)
+	if err != nil {
+		return errors.Wrap(err, "unable to reopen db")
+	}
+
+	s.db = db
+
+	if renameErr != nil {
+		_ = os.Remove(tmpPath)
+		return errors.Wrap(renameErr, "unable to replace db with compacted one")
+	}
+
+	return nil
+}
+
+// syncDir persists directory entries (e.g. after rename).
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	if err := dir.Sync(); err != nil {
+		_ = dir.Close()
+		return err
+	}
+
+	return dir.Close()
+}
+
+func (t *boltTx) Bucket(name string) Bucket {
+	b := t.tx.Bucket([]byte(name))
+	if b == nil {
+		panic("unable to resolve bucket " + name)
+	}
+
+	return &boltBucket{Bucket: b}
+}
+
+func (b *boltBucket) Cursor() Cursor {
+	return b.Bucket.Cursor()
+}
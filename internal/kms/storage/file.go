@@ -0,0 +1,412 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/oxygenpay/oxygen/internal/kms/encryption"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type FileConfig struct {
+	Passphrase string `yaml:"passphrase" env:"KMS_STORE_FILE_PASSPHRASE" env-description:"Passphrase to encrypt KMS store file with (scrypt)"`
+}
+
+// fileStore keeps all data in memory and rewrites the whole encrypted file on each update,
+// so it's suitable only for small single-instance deployments.
+type fileStore struct {
+	mu      sync.RWMutex
+	path    string
+	salt    []byte
+	keyring *encryption.Keyring
+	buckets map[string]fileBucketData
+}
+
+type fileBucketData map[string][]byte
+
+type fileTx struct {
+	store    *fileStore
+	writable bool
+
+	// buckets copies of buckets modified within the transaction.
+	buckets map[string]fileBucketData
+}
+
+type fileBucket struct {
+	tx   *fileTx
+	name string
+}
+
+type fileCursor struct {
+	data  fileBucketData
+	keys  []string
+	index int
+}
+
+// fileContents is the on-disk format.
+type fileContents struct {
+	Format   string               `json:"format"`
+	Version  int                  `json:"version"`
+	KDF      string               `json:"kdf"`
+	Salt     []byte               `json:"salt"`
+	Envelope *encryption.Envelope `json:"envelope"`
+}
+
+type fileRecord struct {
+	Bucket string `json:"bucket"`
+	Key    []byte `json:"key"`
+	Value  []byte `json:"value"`
+}
+
+const (
+	fileFormat  = "oxygen-kms-store"
+	fileVersion = 1
+	fileKDF     = "scrypt"
+	fileMode    = 0600
+)
+
+var ErrInvalidFile = errors.New("invalid KMS store file")
+
+// OpenFile decrypts store file or creates a new one.
+func OpenFile(path string, cfg FileConfig, logger *zerolog.Logger) (Store, error) {
+	log := logger.With().Str("channel", "kms_file_store").Logger()
+
+	switch {
+	case path == "":
+		return nil, errors.New("store file path is not configured")
+	case cfg.Passphrase == "":
+		return nil, errors.New("store file passphrase is not configured")
+	}
+
+	store := &fileStore{path: path, buckets: make(map[string]fileBucketData)}
+	for _, name := range Buckets {
+		store.buckets[name] = make(fileBucketData)
+	}
+
+	raw, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		if store.salt, err = encryption.NewSalt(); err != nil {
+			return nil, err
+		}
+
+		if err := store.useKey(cfg.Passphrase); err != nil {
+			return nil, err
+		}
+
+		if err := store.write(store.buckets); err != nil {
+			return nil, err
+		}
+
+		log.Info().Str("path", path).Msg("created KMS store file")
+
+		return store, nil
+	case err != nil:
+		return nil, errors.Wrap(err, "unable to read store file")
+	}
+
+	if err := store.load(raw, cfg.Passphrase); err != nil {
+		return nil, err
+	}
+
+	log.Info().Str("path", path).Msg("loaded KMS store file")
+
+	return store, nil
+}
+
+func (s *fileStore) View(fn func(tx Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return fn(&fileTx{store: s})
+}
+
+func (s *fileStore) Update(fn func(tx Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := &fileTx{store: s, writable: true, buckets: make(map[string]fileBucketData)}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if len(tx.buckets) == 0 {
+		return nil
+	}
+
+	next := make(map[string]fileBucketData, len(s.buckets))
+	for name, data := range s.buckets {
+		next[name] = data
+	}
+
+	for name, data := range tx.buckets {
+		next[name] = data
+	}
+
+	if err := s.write(next); err != nil {
+		return err
+	}
+
+	s.buckets = next
+
+	return nil
+}
+
+func (s *fileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keyring.Wipe()
+	s.buckets = nil
+
+	return nil
+}
+
+func (s *fileStore) useKey(passphrase string) error {
+	key, err := encryption.DeriveKey(passphrase, s.salt)
+	if err != nil {
+		return errors.Wrap(err, "unable to derive store file key")
+	}
+
+	s.keyring, err = encryption.NewKeyring(key)
+
+	return err
+}
+
+func (s *fileStore) load(raw []byte, passphrase string) error {
+	var contents fileContents
+	if err := json.Unmarshal(raw, &contents); err != nil {
+		return errors.Wrap(ErrInvalidFile, err.Error())
+	}
+
+	switch {
+	case contents.Format != fileFormat || contents.KDF != fileKDF:
+		return errors.Wrapf(ErrInvalidFile, "unexpected format %q", contents.Format)
+	case contents.Version != fileVersion:
+		return errors.Wrapf(ErrInvalidFile, "unsupported version %d", contents.Version)
+	case len(contents.Salt) == 0 || contents.Envelope == nil:
+		return ErrInvalidFile
+	}
+
+	s.salt = contents.Salt
+	if err := s.useKey(passphrase); err != nil {
+		return err
+	}
+
+	plaintext, err := s.keyring.Open(contents.Envelope, []byte(fileFormat))
+	switch {
+	case errors.Is(err, encryption.ErrKeyMismatch):
+		return errors.New("invalid store file passphrase")
+	case err != nil:
+		return errors.Wrap(ErrInvalidFile, err.Error())
+	}
+
+	var records []fileRecord
+	if err := json.Unmarshal(plaintext, &records); err != nil {
+		return errors.Wrap(ErrInvalidFile, err.Error())
+	}
+
+	for _, r := range records {
+		data, ok := s.buckets[r.Bucket]
+		if !ok {
+			return errors.Wrapf(ErrInvalidFile, "unknown bucket %q", r.Bucket)
+		}
+
+		data[string(r.Key)] = r.Value
+	}
+
+	return nil
+}
+
+// write atomically replaces the file: data is written to a temporary file that is renamed afterwards.
+func (s *fileStore) write(buckets map[string]fileBucketData) error {
+	var records []fileRecord
+	for _, name := range Buckets {
+		for k, v := range buckets[name] {
+			records = append(records, fileRecord{Bucket: name, Key: []byte(k), Value: v})
+		}
+	}
+
+	plaintext, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+
+	envelope, err := s.keyring.Seal(plaintext, []byte(fileFormat))
+	if err != nil {
+		return errors.Wrap(err, "unable to encrypt store file")
+	}
+
+	raw, err := json.Marshal(fileContents{
+		Format:   fileFormat,
+		Version:  fileVersion,
+		KDF:      fileKDF,
+		Salt:     s.salt,
+		Envelope: envelope,
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "unable to create temporary store file")
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if err := writeAndSync(tmp, raw); err != nil {
+		return errors.Wrap(err, "unable to write store file")
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return errors.Wrap(err, "unable to replace store file")
+	}
+
+	return nil
+}
+
+func writeAndSync(f *os.File, raw []byte) error {
+	if err := f.Chmod(fileMode); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if _, err := f.Write(raw); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func (t *fileTx) Bucket(name string) Bucket {
+	if !isKnownBucket(name) {
+		panic("unable to resolve bucket " + name)
+	}
+
+	return &fileBucket{tx: t, name: name}
+}
+
+// data returns bucket's data visible to the transaction.
+func (b *fileBucket) data() fileBucketData {
+	if data, ok := b.tx.buckets[b.name]; ok {
+		return data
+	}
+
+	return b.tx.store.buckets[b.name]
+}
+
+// mutableData copies bucket on first modification.
+func (b *fileBucket) mutableData() (fileBucketData, error) {
+	if !b.tx.writable {
+		return nil, ErrReadOnly
+	}
+
+	if data, ok := b.tx.buckets[b.name]; ok {
+		return data, nil
+	}
+
+	original := b.tx.store.buckets[b.name]
+
+	data := make(fileBucketData, len(original))
+	for k, v := range original {
+		data[k] = v
+	}
+
+	b.tx.buckets[b.name] = data
+
+	return data, nil
+}
+
+func (b *fileBucket) Get(key []byte) []byte {
+	return b.data()[string(key)]
+}
+
+func (b *fileBucket) Put(key, value []byte) error {
+	data, err := b.mutableData()
+	if err != nil {
+		return err
+	}
+
+	data[string(key)] = append([]byte{}, value...)
+
+	return nil
+}
+
+func (b *fileBucket) Delete(key []byte) error {
+	data, err := b.mutableData()
+	if err != nil {
+		return err
+	}
+
+	delete(data, string(key))
+
+	return nil
+}
+
+func (b *fileBucket) ForEach(fn func(k, v []byte) error) error {
+	c := b.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Cursor iterates over keys sorted at the moment of cursor creation.
+func (b *fileBucket) Cursor() Cursor {
+	data := b.data()
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return &fileCursor{data: data, keys: keys}
+}
+
+func (c *fileCursor) First() ([]byte, []byte) {
+	return c.at(0)
+}
+
+func (c *fileCursor) Last() ([]byte, []byte) {
+	return c.at(len(c.keys) - 1)
+}
+
+func (c *fileCursor) Seek(seek []byte) ([]byte, []byte) {
+	return c.at(sort.Search(len(c.keys), func(i int) bool {
+		return bytes.Compare([]byte(c.keys[i]), seek) >= 0
+	}))
+}
+
+func (c *fileCursor) Next() ([]byte, []byte) {
+	return c.at(c.index + 1)
+}
+
+func (c *fileCursor) at(index int) ([]byte, []byte) {
+	c.index = index
+	if index < 0 || index >= len(c.keys) {
+		c.index = len(c.keys)
+		return nil, nil
+	}
+
+	k := c.keys[index]
+
+	return []byte(k), c.data[k]
+}
@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/oxygenpay/oxygen/internal/db/connection/pg"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type PostgresConfig struct {
+	DataSource string `yaml:"data_source" env:"KMS_STORE_POSTGRES_DATA_SOURCE" env-description:"Connection string of a dedicated KMS Postgres database. Do not use the app's database"`
+}
+
+// postgresStore stores data in a single table of a dedicated database. Write transactions
+// of all KMS replicas are serialized with an advisory lock, so replicas can share the database.
+type postgresStore struct {
+	pool *pgxpool.Pool
+}
+
+type postgresTx struct {
+	ctx      context.Context
+	tx       pgx.Tx
+	writable bool
+
+	// err first query error. Bucket methods can't return errors in some cases (e.g. Get),
+	// so the error is returned by the transaction instead.
+	err error
+}
+
+type postgresBucket struct {
+	tx   *postgresTx
+	name string
+}
+
+type postgresCursor struct {
+	bucket *postgresBucket
+	key    []byte
+}
+
+const postgresSchema = `create table if not exists kms_records (
+	bucket text not null,
+	key bytea not null,
+	value bytea not null,
+	primary key (bucket, key)
+)`
+
+// postgresLockID advisory lock id of write transactions ("kms" in ASCII).
+const postgresLockID = 0x6b6d73
+
+// OpenPostgres connects to KMS database and creates the table if it's missing.
+func OpenPostgres(cfg PostgresConfig, logger *zerolog.Logger) (Store, error) {
+	if cfg.DataSource == "" {
+		return nil, errors.New("postgres data source is not configured")
+	}
+
+	conn, err := pg.Open(context.Background(), pg.Config{DataSource: cfg.DataSource}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := NewPostgres(conn.Pool)
+	if err != nil {
+		_ = conn.Shutdown()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// NewPostgres creates store using provided pool. Store takes ownership of the pool.
+func NewPostgres(pool *pgxpool.Pool) (Store, error) {
+	if _, err := pool.Exec(context.Background(), postgresSchema); err != nil {
+		return nil, errors.Wrap(err, "unable to create kms_records table")
+	}
+
+	return &postgresStore{pool: pool}, nil
+}
+
+func (s *postgresStore) View(fn func(tx Tx) error) error {
+	return s.run(pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}, false, fn)
+}
+
+func (s *postgresStore) Update(fn func(tx Tx) error) error {
+	return s.run(pgx.TxOptions{IsoLevel: pgx.ReadCommitted}, true, fn)
+}
+
+func (s *postgresStore) Close() error {
+	s.pool.Close()
+	return nil
+}
+
+func (s *postgresStore) run(opts pgx.TxOptions, writable bool, fn func(tx Tx) error) error {
+	ctx := context.Background()
+
+	tx, err := s.pool.BeginTx(ctx, opts)
+	if err != nil {
+		return errors.Wrap(err, "unable to begin transaction")
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if writable {
+		if _, err := tx.Exec(ctx, "select pg_advisory_xact_lock($1)", postgresLockID); err != nil {
+			return errors.Wrap(err, "unable to acquire lock")
+		}
+	}
+
+	ptx := &postgresTx{ctx: ctx, tx: tx, writable: writable}
+
+	err = fn(ptx)
+	if ptx.err != nil {
+		err = ptx.err
+	}
+
+	if err != nil || !writable {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (t *postgresTx) Bucket(name string) Bucket {
+	if !isKnownBucket(name) {
+		panic("unable to resolve bucket " + name)
+	}
+
+	return &postgresBucket{tx: t, name: name}
+}
+
+func (t *postgresTx) fail(err error) {
+	if t.err == nil {
+		t.err = err
+	}
+}
+
+func (b *postgresBucket) Get(key []byte) []byte {
+	return b.queryRow("select key, value from kms_records where bucket = $1 and key = $2", key).value
+}
+
+func (b *postgresBucket) Put(key, value []byte) error {
+	if !b.tx.writable {
+		return ErrReadOnly
+	}
+
+	if value == nil {
+		value = []byte{}
+	}
+
+	_, err := b.tx.tx.Exec(
+		b.tx.ctx,
+		`insert into kms_records (bucket, key, value) values ($1, $2, $3)
+		on conflict (bucket, key) do update set value = excluded.value`,
+		b.name, key, value,
+	)
+
+	return err
+}
+
+func (b *postgresBucket) Delete(key []byte) error {
+	if !b.tx.writable {
+		return ErrReadOnly
+	}
+
+	_, err := b.tx.tx.Exec(b.tx.ctx, "delete from kms_records where bucket = $1 and key = $2", b.name, key)
+
+	return err
+}
+
+// ForEach loads all bucket's records first as pgx doesn't allow other queries while rows are open.
+func (b *postgresBucket) ForEach(fn func(k, v []byte) error) error {
+	rows, err := b.tx.tx.Query(b.tx.ctx, "select key, value from kms_records where bucket = $1 order by key", b.name)
+	if err != nil {
+		return err
+	}
+
+	var records []postgresRecord
+	for rows.Next() {
+		var r postgresRecord
+		if err := rows.Scan(&r.key, &r.value); err != nil {
+			rows.Close()
+			return err
+		}
+
+		records = append(records, r)
+	}
+
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		if err := fn(r.key, r.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *postgresBucket) Cursor() Cursor {
+	return &postgresCursor{bucket: b}
+}
+
+type postgresRecord struct {
+	key   []byte
+	value []byte
+}
+
+// queryRow returns empty record if nothing is found.
+func (b *postgresBucket) queryRow(query string, args ...any) postgresRecord {
+	var r postgresRecord
+
+	err := b.tx.tx.QueryRow(b.tx.ctx, query, append([]any{b.name}, args...)...).Scan(&r.key, &r.value)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return postgresRecord{}
+	case err != nil:
+		b.tx.fail(err)
+		return postgresRecord{}
+	}
+
+	return r
+}
+
+func (c *postgresCursor) First() ([]byte, []byte) {
+	return c.move("select key, value from kms_records where bucket = $1 order by key limit 1")
+}
+
+func (c *postgresCursor) Last() ([]byte, []byte) {
+	return c.move("select key, value from kms_records where bucket = $1 order by key desc limit 1")
+}
+
+func (c *postgresCursor) Seek(seek []byte) ([]byte, []byte) {
+	return c.move("select key, value from kms_records where bucket = $1 and key >= $2 order by key limit 1", seek)
+}
+
+func (c *postgresCursor) Next() ([]byte, []byte) {
+	if c.key == nil {
+		return nil, nil
+	}
+
+	return c.move("select key, value from kms_records where bucket = $1 and key > $2 order by key limit 1", c.key)
+}
+
+func (c *postgresCursor) move(query string, args ...any) ([]byte, []byte) {
+	r := c.bucket.queryRow(query, args...)
+	c.key = r.key
+
+	return r.key, r.value
+}
@@ -0,0 +1,192 @@
+// Package storage provides transactional key-value storage of KMS data. Data is organized in buckets;
+// keys within a bucket are sorted byte-wise. Values are stored as is: wallets are encrypted
+// by the wallet repository, so records can be copied between backends without the master key.
+package storage
+
+import (
+	"path/filepath"
+
+	"github.com/oxygenpay/oxygen/internal/db/connection/bolt"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// Store backend. Update transactions are serialized, View transactions see a consistent snapshot.
+type Store interface {
+	View(fn func(tx Tx) error) error
+	Update(fn func(tx Tx) error) error
+	Close() error
+}
+
+// Compactor is implemented by stores that keep previous versions of overwritten records
+// (e.g. freed bolt pages). Compact drops them so rewritten data can't be recovered from the store.
+type Compactor interface {
+	Compact() error
+}
+
+type Tx interface {
+	// Bucket returns bucket by name. Panics if bucket is unknown.
+	Bucket(name string) Bucket
+}
+
+// Bucket sorted key-value collection. Returned values are valid only during the transaction.
+type Bucket interface {
+	Get(key []byte) []byte
+	Put(key, value []byte) error
+	Delete(key []byte) error
+	ForEach(fn func(k, v []byte) error) error
+	Cursor() Cursor
+}
+
+// Cursor iterates over bucket's keys in sorted order. Nil key means there are no more items.
+type Cursor interface {
+	First() (key, value []byte)
+	Last() (key, value []byte)
+	Seek(seek []byte) (key, value []byte)
+	Next() (key, value []byte)
+}
+
+type Type string
+
+const (
+	Bolt     Type = "bolt"
+	Postgres Type = "postgres"
+	File     Type = "file"
+)
+
+const (
+	WalletsBucket   = "wallets"
+	MetaBucket      = "meta"
+	AddressesBucket = "addresses"
+	SpendingsBucket = "spendings"
+	AuditBucket     = "audit"
+	NoncesBucket    = "nonces"
+)
+
+// Buckets lists all KMS buckets.
+var Buckets = []string{WalletsBucket, MetaBucket, AddressesBucket, SpendingsBucket, AuditBucket, NoncesBucket}
+
+type Config struct {
+	Type     Type           `yaml:"type" env:"KMS_STORE_TYPE" env-default:"bolt" env-description:"KMS store type: bolt, postgres (allows multiple KMS replicas) or file (encrypted file)"`
+	Path     string         `yaml:"path" env:"KMS_DB_DATA_SOURCE" env-description:"Path to bolt db or encrypted file. Example: '/opt/oxygen/kms.db'"`
+	Postgres PostgresConfig `yaml:"postgres"`
+	File     FileConfig     `yaml:"file"`
+}
+
+var (
+	ErrUnknownType = errors.New("unknown KMS store type")
+	ErrReadOnly    = errors.New("transaction is read-only")
+	ErrNotEmpty    = errors.New("target store is not empty")
+)
+
+// Open opens store of configured type.
+func Open(cfg Config, logger *zerolog.Logger) (Store, error) {
+	switch cfg.resolveType() {
+	case Bolt:
+		conn, err := bolt.Open(bolt.Config{DataSource: cfg.Path}, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewBolt(conn.DB())
+	case Postgres:
+		return OpenPostgres(cfg.Postgres, logger)
+	case File:
+		return OpenFile(cfg.Path, cfg.File, logger)
+	default:
+		return nil, errors.Wrapf(ErrUnknownType, "%q", cfg.Type)
+	}
+}
+
+// SameAs checks whether both configs point to the same store.
+func (c Config) SameAs(other Config) bool {
+	a, b := c.resolveType(), other.resolveType()
+
+	switch {
+	case a != b:
+		return false
+	case a == Postgres:
+		return c.Postgres.DataSource == other.Postgres.DataSource
+	default:
+		return filepath.Clean(c.Path) == filepath.Clean(other.Path)
+	}
+}
+
+func (c Config) resolveType() Type {
+	if c.Type == "" {
+		return Bolt
+	}
+
+	return c.Type
+}
+
+// Copy copies all records from src to dst within a single dst transaction.
+// Fails with ErrNotEmpty if dst already has any data. Returns number of copied records.
+func Copy(dst, src Store) (int, error) {
+	type record struct {
+		key   []byte
+		value []byte
+	}
+
+	records := make(map[string][]record, len(Buckets))
+
+	err := src.View(func(tx Tx) error {
+		for _, name := range Buckets {
+			err := tx.Bucket(name).ForEach(func(k, v []byte) error {
+				records[name] = append(records[name], record{
+					key:   append([]byte(nil), k...),
+					value: append([]byte(nil), v...),
+				})
+
+				return nil
+			})
+			if err != nil {
+				return errors.Wrapf(err, "unable to read bucket %s", name)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+
+	err = dst.Update(func(tx Tx) error {
+		for _, name := range Buckets {
+			b := tx.Bucket(name)
+
+			if k, _ := b.Cursor().First(); k != nil {
+				return errors.Wrapf(ErrNotEmpty, "bucket %s", name)
+			}
+
+			for _, r := range records[name] {
+				if err := b.Put(r.key, r.value); err != nil {
+					return errors.Wrapf(err, "unable to write bucket %s", name)
+				}
+
+				count++
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func isKnownBucket(name string) bool {
+	for _, b := range Buckets {
+		if b == name {
+			return true
+		}
+	}
+
+	return false
+}
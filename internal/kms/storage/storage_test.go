@@ -0,0 +1,227 @@
+package storage_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/oxygenpay/oxygen/internal/kms/storage"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	for _, storeType := range []storage.Type{storage.Bolt, storage.File} {
+		t.Run(string(storeType), func(t *testing.T) {
+			store := openStore(t, storeType, t.TempDir()+"/kms.db")
+
+			t.Run("Puts and gets records", func(t *testing.T) {
+				require.NoError(t, store.Update(func(tx storage.Tx) error {
+					b := tx.Bucket(storage.WalletsBucket)
+					for _, k := range []string{"c", "a", "b", "d"} {
+						if err := b.Put([]byte(k), []byte("value-"+k)); err != nil {
+							return err
+						}
+					}
+
+					return b.Delete([]byte("d"))
+				}))
+
+				require.NoError(t, store.View(func(tx storage.Tx) error {
+					b := tx.Bucket(storage.WalletsBucket)
+
+					assert.Equal(t, []byte("value-a"), b.Get([]byte("a")))
+					assert.Nil(t, b.Get([]byte("d")))
+					assert.Nil(t, tx.Bucket(storage.MetaBucket).Get([]byte("a")))
+
+					return nil
+				}))
+			})
+
+			t.Run("Iterates in sorted order", func(t *testing.T) {
+				require.NoError(t, store.View(func(tx storage.Tx) error {
+					b := tx.Bucket(storage.WalletsBucket)
+
+					var keys []string
+					require.NoError(t, b.ForEach(func(k, v []byte) error {
+						keys = append(keys, string(k))
+						return nil
+					}))
+					assert.Equal(t, []string{"a", "b", "c"}, keys)
+
+					c := b.Cursor()
+
+					k, v := c.Seek([]byte("aa"))
+					assert.Equal(t, "b", string(k))
+					assert.Equal(t, "value-b", string(v))
+
+					k, _ = c.Next()
+					assert.Equal(t, "c", string(k))
+
+					k, _ = c.Next()
+					assert.Nil(t, k)
+
+					k, _ = c.Last()
+					assert.Equal(t, "c", string(k))
+
+					k, _ = c.First()
+					assert.Equal(t, "a", string(k))
+
+					return nil
+				}))
+			})
+
+			t.Run("Rolls back failed update", func(t *testing.T) {
+				err := store.Update(func(tx storage.Tx) error {
+					if err := tx.Bucket(storage.WalletsBucket).Put([]byte("a"), []byte("changed")); err != nil {
+						return err
+					}
+
+					return assert.AnError
+				})
+				assert.ErrorIs(t, err, assert.AnError)
+
+				require.NoError(t, store.View(func(tx storage.Tx) error {
+					assert.Equal(t, []byte("value-a"), tx.Bucket(storage.WalletsBucket).Get([]byte("a")))
+					return nil
+				}))
+			})
+
+			t.Run("Panics on unknown bucket", func(t *testing.T) {
+				assert.Panics(t, func() {
+					_ = store.View(func(tx storage.Tx) error {
+						tx.Bucket("unknown")
+						return nil
+					})
+				})
+			})
+		})
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	path := t.TempDir() + "/kms.db"
+	logger := zerolog.Nop()
+
+	store, err := storage.OpenFile(path, storage.FileConfig{Passphrase: "passphrase"}, &logger)
+	require.NoError(t, err)
+
+	secret := strings.Repeat("s", 32)
+
+	require.NoError(t, store.Update(func(tx storage.Tx) error {
+		return tx.Bucket(storage.MetaBucket).Put([]byte("key"), []byte(secret))
+	}))
+	require.NoError(t, store.Close())
+
+	t.Run("Encrypts file", func(t *testing.T) {
+		raw, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(raw), secret)
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("Rejects put within view", func(t *testing.T) {
+		store, err := storage.OpenFile(path, storage.FileConfig{Passphrase: "passphrase"}, &logger)
+		require.NoError(t, err)
+
+		err = store.View(func(tx storage.Tx) error {
+			return tx.Bucket(storage.MetaBucket).Put([]byte("key"), []byte("value"))
+		})
+		assert.ErrorIs(t, err, storage.ErrReadOnly)
+	})
+
+	t.Run("Reopens with passphrase", func(t *testing.T) {
+		store, err := storage.OpenFile(path, storage.FileConfig{Passphrase: "passphrase"}, &logger)
+		require.NoError(t, err)
+
+		require.NoError(t, store.View(func(tx storage.Tx) error {
+			assert.Equal(t, []byte(secret), tx.Bucket(storage.MetaBucket).Get([]byte("key")))
+			return nil
+		}))
+	})
+
+	t.Run("Rejects wrong passphrase", func(t *testing.T) {
+		_, err := storage.OpenFile(path, storage.FileConfig{Passphrase: "wrong"}, &logger)
+		assert.ErrorContains(t, err, "invalid store file passphrase")
+	})
+
+	t.Run("Rejects malformed file", func(t *testing.T) {
+		malformed := t.TempDir() + "/malformed.db"
+		require.NoError(t, os.WriteFile(malformed, []byte(`{"format":"other"}`), 0600))
+
+		_, err := storage.OpenFile(malformed, storage.FileConfig{Passphrase: "passphrase"}, &logger)
+		assert.ErrorIs(t, err, storage.ErrInvalidFile)
+	})
+}
+
+func TestCopy(t *testing.T) {
+	src := openStore(t, storage.Bolt, t.TempDir()+"/kms.db")
+
+	require.NoError(t, src.Update(func(tx storage.Tx) error {
+		for _, name := range storage.Buckets {
+			if err := tx.Bucket(name).Put([]byte("key"), []byte(name)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}))
+
+	dst := openStore(t, storage.File, t.TempDir()+"/kms.db")
+
+	count, err := storage.Copy(dst, src)
+	require.NoError(t, err)
+	assert.Equal(t, len(storage.Buckets), count)
+
+	require.NoError(t, dst.View(func(tx storage.Tx) error {
+		for _, name := range storage.Buckets {
+			assert.Equal(t, []byte(name), tx.Bucket(name).Get([]byte("key")))
+		}
+
+		return nil
+	}))
+
+	t.Run("Rejects non-empty target", func(t *testing.T) {
+		_, err := storage.Copy(dst, src)
+		assert.ErrorIs(t, err, storage.ErrNotEmpty)
+	})
+
+	t.Run("Rejects unknown type", func(t *testing.T) {
+		logger := zerolog.Nop()
+
+		_, err := storage.Open(storage.Config{Type: "redis"}, &logger)
+		assert.ErrorIs(t, err, storage.ErrUnknownType)
+	})
+}
+
+func TestConfigSameAs(t *testing.T) {
+	bolt := storage.Config{Path: "/opt/oxygen/kms.db"}
+
+	assert.True(t, bolt.SameAs(storage.Config{Type: storage.Bolt, Path: "/opt/oxygen/../oxygen/kms.db"}))
+	assert.False(t, bolt.SameAs(storage.Config{Type: storage.File, Path: "/opt/oxygen/kms.db"}))
+	assert.False(t, bolt.SameAs(storage.Config{Type: storage.Bolt, Path: "/opt/oxygen/kms2.db"}))
+
+	pg := storage.Config{Type: storage.Postgres, Postgres: storage.PostgresConfig{DataSource: "dbname=kms"}}
+
+	assert.True(t, pg.SameAs(storage.Config{Type: storage.Postgres, Path: "other", Postgres: pg.Postgres}))
+	assert.False(t, pg.SameAs(storage.Config{Type: storage.Postgres, Postgres: storage.PostgresConfig{DataSource: "dbname=kms2"}}))
+}
+
+func openStore(t *testing.T, storeType storage.Type, path string) storage.Store {
+	logger := zerolog.Nop()
+
+	store, err := storage.Open(storage.Config{
+		Type: storeType,
+		Path: path,
+		File: storage.FileConfig{Passphrase: "passphrase"},
+	}, &logger)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = store.Close() })
+
+	return store
+}
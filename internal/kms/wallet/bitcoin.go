@@ -1,12 +1,18 @@
 package wallet
 
 import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
 	"io"
 	"regexp"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/google/uuid"
-	"github.com/wemeetagain/go-hdwallet"
+	"github.com/pkg/errors"
 )
 
 type BitcoinProvider struct {
@@ -22,9 +28,17 @@ func (p *BitcoinProvider) Generate() *Wallet {
 		return &Wallet{}
 	}
 
-	privateKey := hdwallet.MasterKey(seed)
-	publicKey := privateKey.Pub()
-	address := publicKey.Address()
+	privateKey := bitcoinMasterKey(seed)
+
+	publicKey, err := privateKey.Neuter()
+	if err != nil {
+		return &Wallet{}
+	}
+
+	address, err := bitcoinUncompressedAddress(privateKey)
+	if err != nil {
+		return &Wallet{}
+	}
 
 	return &Wallet{
 		UUID:       uuid.New(),
@@ -36,6 +50,93 @@ func (p *BitcoinProvider) Generate() *Wallet {
 	}
 }
 
+// bitcoinUncompressedAddress returns P2PKH address of key's uncompressed public key.
+// Generated wallets historically use such addresses.
+func bitcoinUncompressedAddress(key *hdkeychain.ExtendedKey) (string, error) {
+	pubKey, err := key.ECPubKey()
+	if err != nil {
+		return "", errors.Wrap(err, "unable to get public key")
+	}
+
+	address, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(pubKey.SerializeUncompressed()), &chaincfg.MainNetParams)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to get address")
+	}
+
+	return address.EncodeAddress(), nil
+}
+
+// bitcoinMasterKey derives BIP-32 master key from seed of arbitrary length.
+// hdkeychain.NewMaster limits seed to 64 bytes, so HMAC is computed here.
+func bitcoinMasterKey(seed []byte) *hdkeychain.ExtendedKey {
+	h := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	_, _ = h.Write(seed)
+	sum := h.Sum(nil)
+
+	return hdkeychain.NewExtendedKey(
+		chaincfg.MainNetParams.HDPrivateKeyID[:],
+		sum[:32],
+		sum[32:],
+		[]byte{0, 0, 0, 0},
+		0,
+		0,
+		true,
+	)
+}
+
+// bitcoinWalletFromKey represents derived extended key as P2PKH wallet.
+func bitcoinWalletFromKey(key *hdkeychain.ExtendedKey) (*Wallet, error) {
+	publicKey, err := key.Neuter()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get public key")
+	}
+
+	pubKey, err := key.ECPubKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get public key")
+	}
+
+	address, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(pubKey.SerializeCompressed()), &chaincfg.MainNetParams)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get address")
+	}
+
+	return &Wallet{
+		Address:    address.EncodeAddress(),
+		PublicKey:  publicKey.String(),
+		PrivateKey: key.String(),
+	}, nil
+}
+
+// bitcoinWalletFromPrivateKey represents extended private key (xprv) or WIF as P2PKH wallet.
+func bitcoinWalletFromPrivateKey(raw string) (*Wallet, error) {
+	if key, err := hdkeychain.NewKeyFromString(raw); err == nil {
+		if !key.IsPrivate() || !key.IsForNet(&chaincfg.MainNetParams) {
+			return nil, ErrInvalidPrivateKey
+		}
+
+		return bitcoinWalletFromKey(key)
+	}
+
+	wif, err := btcutil.DecodeWIF(raw)
+	if err != nil || !wif.IsForNet(&chaincfg.MainNetParams) {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	publicKey := wif.SerializePubKey()
+
+	address, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(publicKey), &chaincfg.MainNetParams)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get address")
+	}
+
+	return &Wallet{
+		Address:    address.EncodeAddress(),
+		PublicKey:  hex.EncodeToString(publicKey),
+		PrivateKey: wif.String(),
+	}, nil
+}
+
 func (p *BitcoinProvider) GetBlockchain() Blockchain {
 	return p.Blockchain
 }
@@ -4,10 +4,12 @@ import (
 	cryptorand "crypto/rand"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/oxygenpay/oxygen/internal/kms/wallet"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"github.com/wemeetagain/go-hdwallet"
 )
 
 func TestBitcoinProvider_Generate(t *testing.T) {
@@ -33,20 +35,14 @@ func TestBitcoinProvider_Generate(t *testing.T) {
 	t.Run("Mock_PrivateKeyAsStringToPublicKey", func(t *testing.T) {
 		w := p.Generate()
 
-		key, err := hdwallet.StringWallet(w.PrivateKey)
-		require.NoError(t, err)
-
-		publicKey := key.Pub().String()
+		publicKey, _ := parseBitcoinKey(t, w.PrivateKey)
 		assert.Equal(t, publicKey, w.PublicKey)
 	})
 
 	t.Run("Mock_PrivateKeyAsStringToAddress", func(t *testing.T) {
 		w := p.Generate()
 
-		key, err := hdwallet.StringWallet(w.PrivateKey)
-		require.NoError(t, err)
-
-		address := key.Pub().Address()
+		_, address := parseBitcoinKey(t, w.PrivateKey)
 		assert.Equal(t, address, w.Address)
 	})
 
@@ -58,11 +54,7 @@ func TestBitcoinProvider_Generate(t *testing.T) {
 
 		w := p.Generate()
 
-		key, err := hdwallet.StringWallet(w.PrivateKey)
-		require.NoError(t, err)
-
-		publicKey := key.Pub().String()
-		address := key.Pub().Address()
+		publicKey, address := parseBitcoinKey(t, w.PrivateKey)
 
 		assert.Equal(t, publicKey, w.PublicKey)
 		assert.Equal(t, address, w.Address)
@@ -92,3 +84,20 @@ func TestBitcoinProvider_ValidateAddress(t *testing.T) {
 		})
 	}
 }
+
+// parseBitcoinKey returns xpub and P2PKH address (uncompressed public key) of provided xprv.
+func parseBitcoinKey(t *testing.T, raw string) (string, string) {
+	key, err := hdkeychain.NewKeyFromString(raw)
+	require.NoError(t, err)
+
+	publicKey, err := key.Neuter()
+	require.NoError(t, err)
+
+	pubKey, err := key.ECPubKey()
+	require.NoError(t, err)
+
+	address, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(pubKey.SerializeUncompressed()), &chaincfg.MainNetParams)
+	require.NoError(t, err)
+
+	return publicKey.String(), address.EncodeAddress()
+}
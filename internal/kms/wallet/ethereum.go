@@ -33,17 +33,19 @@ func (p *EthProvider) Generate() *Wallet {
 		return &Wallet{}
 	}
 
-	privateKey := hexutil.Encode(crypto.FromECDSA(key))
-	publicKey := hexutil.Encode(crypto.FromECDSAPub(&key.PublicKey))
-	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	w := ethWalletFromKey(key)
+	w.UUID = uuid.New()
+	w.CreatedAt = time.Now()
+	w.Blockchain = p.Blockchain
 
+	return w
+}
+
+func ethWalletFromKey(key *ecdsa.PrivateKey) *Wallet {
 	return &Wallet{
-		UUID:       uuid.New(),
-		CreatedAt:  time.Now(),
-		Blockchain: p.Blockchain,
-		Address:    address,
-		PublicKey:  publicKey,
-		PrivateKey: privateKey,
+		Address:    crypto.PubkeyToAddress(key.PublicKey).Hex(),
+		PublicKey:  hexutil.Encode(crypto.FromECDSAPub(&key.PublicKey)),
+		PrivateKey: hexutil.Encode(crypto.FromECDSA(key)),
 	}
 }
 
@@ -0,0 +1,217 @@
+package wallet
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// HDKeychain derives wallets from a single BIP-32 seed using BIP-44 paths:
+// m / 44' / coin_type' / 0' / 0 / index. Only derivation path of such wallet is persisted.
+type HDKeychain struct {
+	master *hdkeychain.ExtendedKey
+}
+
+// coinTypes see https://github.com/satoshilabs/slips/blob/master/slip-0044.md
+var coinTypes = map[Blockchain]uint32{
+	BTC:   0,
+	ETH:   60,
+	TRON:  195,
+	MATIC: 966,
+	BSC:   9006,
+}
+
+var (
+	ErrInvalidSeed           = errors.New("invalid HD seed")
+	ErrInvalidMnemonic       = errors.New("invalid mnemonic")
+	ErrInvalidDerivationPath = errors.New("invalid derivation path")
+	ErrSeedMismatch          = errors.New("wallet address doesn't match HD seed")
+	ErrHDKeychainRequired    = errors.New("HD keychain is required to access wallet")
+)
+
+// hdNamespace is used to derive deterministic wallet UUIDs.
+var hdNamespace = uuid.MustParse("0b6a4a3e-52c4-4d8b-9a2c-1c0f8e1f0a44")
+
+const (
+	HDSeedSize = 64
+
+	mnemonicEntropySize = 32
+	mnemonicIterations  = 2048
+)
+
+func NewHDKeychain(seed []byte) (*HDKeychain, error) {
+	master, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidSeed, err.Error())
+	}
+
+	return &HDKeychain{master: master}, nil
+}
+
+// GenerateHDSeed returns random seed.
+func GenerateHDSeed(reader io.Reader) ([]byte, error) {
+	seed := make([]byte, HDSeedSize)
+	if _, err := io.ReadFull(reader, seed); err != nil {
+		return nil, errors.Wrap(err, "unable to generate HD seed")
+	}
+
+	return seed, nil
+}
+
+// GenerateMnemonic returns random 24 words BIP-39 mnemonic. Seed of such mnemonic is derived without passphrase.
+func GenerateMnemonic(reader io.Reader) (string, error) {
+	entropy := make([]byte, mnemonicEntropySize)
+	if _, err := io.ReadFull(reader, entropy); err != nil {
+		return "", errors.Wrap(err, "unable to generate mnemonic")
+	}
+
+	return bip39.NewMnemonic(entropy)
+}
+
+// SeedFromMnemonic converts BIP-39 mnemonic to seed. Mnemonic should consist of english wordlist words
+// and pass the checksum, so a mistyped phrase doesn't silently recover an empty keychain.
+func SeedFromMnemonic(mnemonic, passphrase string) ([]byte, error) {
+	phrase := normalizeMnemonic(mnemonic)
+
+	switch count := len(strings.Fields(phrase)); count {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, errors.Wrapf(ErrInvalidMnemonic, "unexpected words count %d", count)
+	}
+
+	if _, err := bip39.MnemonicToByteArray(phrase); err != nil {
+		return nil, errors.Wrap(ErrInvalidMnemonic, err.Error())
+	}
+
+	password := []byte(phrase)
+	salt := []byte("mnemonic" + norm.NFKD.String(passphrase))
+
+	return pbkdf2.Key(password, salt, mnemonicIterations, HDSeedSize, sha512.New), nil
+}
+
+// normalizeMnemonic returns NFKD-normalized mnemonic with words separated by single space.
+func normalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+}
+
+// DerivationPath returns BIP-44 path of blockchain's wallet with provided index.
+func DerivationPath(blockchain Blockchain, index uint32) (string, error) {
+	coinType, ok := coinTypes[blockchain]
+	if !ok {
+		return "", ErrUnknownBlockchain
+	}
+
+	return fmt.Sprintf("m/44'/%d'/0'/0/%d", coinType, index), nil
+}
+
+// Derive derives wallet by path. Wallet UUID is deterministic, so the same wallet is recovered from the same seed.
+func (k *HDKeychain) Derive(blockchain Blockchain, path string) (*Wallet, error) {
+	key, err := k.deriveKey(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var w *Wallet
+
+	switch blockchain {
+	case ETH, MATIC, BSC, TRON:
+		privateKey, err := key.ECPrivKey()
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get private key")
+		}
+
+		if blockchain == TRON {
+			w = tronWalletFromKey(privateKey.ToECDSA())
+		} else {
+			w = ethWalletFromKey(privateKey.ToECDSA())
+		}
+	case BTC:
+		if w, err = bitcoinWalletFromKey(key); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrUnknownBlockchain
+	}
+
+	w.UUID = uuid.NewSHA1(hdNamespace, []byte(blockchain.String()+":"+w.Address))
+	w.CreatedAt = time.Now()
+	w.Blockchain = blockchain
+	w.DerivationPath = path
+
+	return w, nil
+}
+
+// Wipe zeroes master extended key in memory. Keychain can't be used afterwards.
+func (k *HDKeychain) Wipe() {
+	k.master.Zero()
+}
+
+// hydrate fills HD wallet's keys.
+func (k *HDKeychain) hydrate(w *Wallet) error {
+	derived, err := k.Derive(w.Blockchain, w.DerivationPath)
+	if err != nil {
+		return err
+	}
+
+	if derived.Address != w.Address {
+		return ErrSeedMismatch
+	}
+
+	w.PublicKey = derived.PublicKey
+	w.PrivateKey = derived.PrivateKey
+
+	return nil
+}
+
+func (k *HDKeychain) deriveKey(path string) (*hdkeychain.ExtendedKey, error) {
+	indexes, err := parseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := k.master
+	for _, i := range indexes {
+		if key, err = key.Derive(i); err != nil {
+			return nil, errors.Wrapf(err, "unable to derive %q", path)
+		}
+	}
+
+	return key, nil
+}
+
+func parseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || parts[0] != "m" {
+		return nil, errors.Wrapf(ErrInvalidDerivationPath, "%q", path)
+	}
+
+	indexes := make([]uint32, 0, len(parts)-1)
+
+	for _, part := range parts[1:] {
+		offset := uint32(0)
+		if strings.HasSuffix(part, "'") {
+			offset = hdkeychain.HardenedKeyStart
+			part = strings.TrimSuffix(part, "'")
+		}
+
+		i, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || uint32(i) >= hdkeychain.HardenedKeyStart {
+			return nil, errors.Wrapf(ErrInvalidDerivationPath, "%q", path)
+		}
+
+		indexes = append(indexes, uint32(i)+offset)
+	}
+
+	return indexes, nil
+}
@@ -0,0 +1,266 @@
+package wallet_test
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestSeedFromMnemonic(t *testing.T) {
+	// https://github.com/trezor/python-mnemonic/blob/master/vectors.json
+	seed, err := wallet.SeedFromMnemonic(testMnemonic, "TREZOR")
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		hex.EncodeToString(seed),
+	)
+
+	_, err = wallet.SeedFromMnemonic("abandon about", "")
+	assert.ErrorIs(t, err, wallet.ErrInvalidMnemonic)
+
+	// bad checksum
+	_, err = wallet.SeedFromMnemonic(strings.Repeat("abandon ", 12), "")
+	assert.ErrorIs(t, err, wallet.ErrInvalidMnemonic)
+
+	// unknown word
+	_, err = wallet.SeedFromMnemonic(strings.Replace(testMnemonic, "about", "aboot", 1), "")
+	assert.ErrorIs(t, err, wallet.ErrInvalidMnemonic)
+}
+
+func TestGenerateMnemonic(t *testing.T) {
+	mnemonic, err := wallet.GenerateMnemonic(strings.NewReader(strings.Repeat("m", 32)))
+	require.NoError(t, err)
+	assert.Len(t, strings.Fields(mnemonic), 24)
+
+	seed, err := wallet.SeedFromMnemonic(mnemonic, "")
+	require.NoError(t, err)
+	assert.Len(t, seed, wallet.HDSeedSize)
+
+	_, err = wallet.GenerateMnemonic(strings.NewReader("short"))
+	assert.Error(t, err)
+}
+
+func TestRepository_HDMnemonic(t *testing.T) {
+	repo := wallet.NewRepository(openStore(t), newKeyring(t, 1))
+
+	t.Run("Seed without mnemonic", func(t *testing.T) {
+		seed, err := wallet.SeedFromMnemonic(testMnemonic, "")
+		require.NoError(t, err)
+		require.NoError(t, repo.SetHDSeed(seed))
+
+		_, err = repo.LoadHDMnemonic()
+		assert.ErrorIs(t, err, wallet.ErrNoMnemonic)
+	})
+
+	t.Run("Persists mnemonic alongside seed", func(t *testing.T) {
+		require.NoError(t, repo.SetHDMnemonic("  "+strings.ReplaceAll(testMnemonic, " ", "  ")))
+
+		mnemonic, err := repo.LoadHDMnemonic()
+		require.NoError(t, err)
+		assert.Equal(t, testMnemonic, mnemonic)
+
+		expected, err := wallet.SeedFromMnemonic(testMnemonic, "")
+		require.NoError(t, err)
+
+		seed, err := repo.LoadHDSeed()
+		require.NoError(t, err)
+		assert.Equal(t, expected, seed)
+	})
+
+	t.Run("Rejects invalid mnemonic", func(t *testing.T) {
+		assert.ErrorIs(t, repo.SetHDMnemonic("abandon about"), wallet.ErrInvalidMnemonic)
+	})
+}
+
+func TestHDKeychain_Derive(t *testing.T) {
+	seed, err := wallet.SeedFromMnemonic(testMnemonic, "")
+	require.NoError(t, err)
+
+	keychain, err := wallet.NewHDKeychain(seed)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		blockchain wallet.Blockchain
+		path       string
+		address    string
+	}{
+		{blockchain: wallet.ETH, path: "m/44'/60'/0'/0/0", address: "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"},
+		{blockchain: wallet.BTC, path: "m/44'/0'/0'/0/0", address: "1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA"},
+	} {
+		t.Run(tt.blockchain.String(), func(t *testing.T) {
+			path, err := wallet.DerivationPath(tt.blockchain, 0)
+			require.NoError(t, err)
+			assert.Equal(t, tt.path, path)
+
+			w, err := keychain.Derive(tt.blockchain, path)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.address, w.Address)
+			assert.Equal(t, tt.path, w.DerivationPath)
+			assert.NotEmpty(t, w.PrivateKey)
+
+			// Check that derivation is deterministic
+			again, err := keychain.Derive(tt.blockchain, path)
+			require.NoError(t, err)
+			assert.Equal(t, w.UUID, again.UUID)
+			assert.Equal(t, w.PrivateKey, again.PrivateKey)
+		})
+	}
+
+	t.Run("Uses different keys for EVM blockchains", func(t *testing.T) {
+		addresses := map[string]struct{}{}
+
+		for _, bc := range []wallet.Blockchain{wallet.ETH, wallet.MATIC, wallet.BSC} {
+			path, err := wallet.DerivationPath(bc, 0)
+			require.NoError(t, err)
+
+			w, err := keychain.Derive(bc, path)
+			require.NoError(t, err)
+			assert.NoError(t, wallet.ValidateAddress(bc, w.Address))
+
+			addresses[w.Address] = struct{}{}
+		}
+
+		assert.Len(t, addresses, 3)
+	})
+
+	t.Run("Retains leading zeros of private key", func(t *testing.T) {
+		// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vector-3
+		seed, err := hex.DecodeString(
+			"4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be",
+		)
+		require.NoError(t, err)
+
+		keychain, err := wallet.NewHDKeychain(seed)
+		require.NoError(t, err)
+
+		w, err := keychain.Derive(wallet.BTC, "m/0'")
+		require.NoError(t, err)
+
+		assert.Equal(
+			t,
+			"xprv9uPDJpEQgRQfDcW7BkF7eTya6RPxXeJCqCJGHuCJ4GiRVLzkTXBAJMu2qaMWPrS7AANYqdq6vcBcBUdJCVVFceUvJFjaPdGZ2y9WACViL4L",
+			w.PrivateKey,
+		)
+		assert.Equal(
+			t,
+			"xpub68NZiKmJWnxxS6aaHmn81bvJeTESw724CRDs6HbuccFQN9Ku14VQrADWgqbhhTHBaohPX4CjNLf9fq9MYo6oDaPPLPxSb7gwQN3ih19Zm4Y",
+			w.PublicKey,
+		)
+
+		// m/0' of this seed has a public key with a leading zero, see btcsuite/btcutil#172
+		seed, err = hex.DecodeString("000000000000000000000000000000000000000000000000000000000000018f")
+		require.NoError(t, err)
+
+		keychain, err = wallet.NewHDKeychain(seed)
+		require.NoError(t, err)
+
+		w, err = keychain.Derive(wallet.BTC, "m/0'/0'")
+		require.NoError(t, err)
+
+		assert.Equal(
+			t,
+			"xprv9wJ9uMGDdSBf16cY63wPCGEt3Gj2zxZmQWeoN1TDVDfmyt21rp6HLvNwzcKsmUimxknXLGYzfRavTMajhCPiPRKfGscDM8vNbTdjBWWHKGb",
+			w.PrivateKey,
+		)
+	})
+
+	t.Run("Validates path", func(t *testing.T) {
+		for _, path := range []string{"", "m", "44'/0'", "m/abc", "m/2147483648"} {
+			_, err := keychain.Derive(wallet.ETH, path)
+			assert.ErrorIs(t, err, wallet.ErrInvalidDerivationPath, path)
+		}
+	})
+}
+
+func TestService_HDWallets(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+	db := openStore(t)
+
+	seed, err := wallet.SeedFromMnemonic(testMnemonic, "")
+	require.NoError(t, err)
+
+	keychain, err := wallet.NewHDKeychain(seed)
+	require.NoError(t, err)
+
+	repo := wallet.NewRepository(db, nil)
+	require.NoError(t, repo.SetHDSeed(seed))
+
+	service := wallet.New(repo, wallet.NewGenerator(), keychain, nil, nil, &logger)
+
+	// Given legacy wallet
+	legacy := (&wallet.EthProvider{Blockchain: wallet.ETH, CryptoReader: strings.NewReader(strings.Repeat("a", 128))}).Generate()
+	require.NoError(t, repo.Set(legacy))
+
+	// And two HD wallets
+	w1, err := service.CreateWallet(ctx, wallet.ETH)
+	require.NoError(t, err)
+
+	w2, err := service.CreateWallet(ctx, wallet.ETH)
+	require.NoError(t, err)
+
+	assert.Equal(t, "m/44'/60'/0'/0/0", w1.DerivationPath)
+	assert.Equal(t, "m/44'/60'/0'/0/1", w2.DerivationPath)
+
+	t.Run("Stores only derivation path", func(t *testing.T) {
+		raw := string(rawWallet(t, db, w1.UUID))
+
+		assert.Contains(t, raw, w1.DerivationPath)
+		assert.NotContains(t, raw, w1.PrivateKey)
+	})
+
+	t.Run("Returns HD & legacy wallets with keys", func(t *testing.T) {
+		actual, err := service.GetWallet(ctx, w2.UUID, false)
+		require.NoError(t, err)
+		assert.Equal(t, w2.PrivateKey, actual.PrivateKey)
+		assert.Equal(t, w2.Address, actual.Address)
+
+		actual, err = service.GetWallet(ctx, legacy.UUID, false)
+		require.NoError(t, err)
+		assert.Equal(t, legacy.PrivateKey, actual.PrivateKey)
+	})
+
+	t.Run("Seed can't be replaced", func(t *testing.T) {
+		otherSeed, err := wallet.SeedFromMnemonic(testMnemonic, "other")
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, repo.SetHDSeed(otherSeed), wallet.ErrHDSeedInUse)
+	})
+
+	t.Run("Recovers wallets from seed", func(t *testing.T) {
+		// Given empty store with the same seed
+		recoveredRepo := wallet.NewRepository(openStore(t), nil)
+		require.NoError(t, recoveredRepo.SetHDSeed(seed))
+
+		recoveredService := wallet.New(recoveredRepo, wallet.NewGenerator(), keychain, nil, nil, &logger)
+
+		// ACT
+		count, err := recoveredService.RecoverHDWallets(ctx, 2)
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Equal(t, 2*len(wallet.ListBlockchains()), count)
+
+		for _, expected := range []*wallet.Wallet{w1, w2} {
+			actual, err := recoveredService.GetWallet(ctx, expected.UUID, false)
+			require.NoError(t, err)
+			assert.Equal(t, expected.Address, actual.Address)
+			assert.Equal(t, expected.PrivateKey, actual.PrivateKey)
+		}
+
+		// Check that new wallet doesn't reuse recovered index
+		w3, err := recoveredService.CreateWallet(ctx, wallet.ETH)
+		require.NoError(t, err)
+		assert.Equal(t, "m/44'/60'/0'/0/2", w3.DerivationPath)
+	})
+}
@@ -0,0 +1,173 @@
+package wallet
+
+import (
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ImportParams externally generated wallet. Either PrivateKey or Mnemonic with DerivationPath should be set.
+type ImportParams struct {
+	Blockchain Blockchain
+	Address    string
+
+	// PrivateKey hex-encoded key for EVM chains & TRON; WIF or extended private key (xprv) for BTC.
+	PrivateKey string
+
+	Mnemonic           string
+	MnemonicPassphrase string
+	DerivationPath     string
+}
+
+var (
+	ErrInvalidImportParams = errors.New("either private key or mnemonic with derivation path should be provided")
+	ErrInvalidPrivateKey   = errors.New("invalid private key")
+	ErrAddressMismatch     = errors.New("private key doesn't match wallet address")
+)
+
+func (p ImportParams) validate() error {
+	if !p.Blockchain.IsValid() {
+		return ErrUnknownBlockchain
+	}
+
+	if err := ValidateAddress(p.Blockchain, p.Address); err != nil {
+		return err
+	}
+
+	hasKey := p.PrivateKey != ""
+	hasMnemonic := p.Mnemonic != "" || p.DerivationPath != ""
+
+	switch {
+	case hasKey == hasMnemonic:
+		return ErrInvalidImportParams
+	case hasMnemonic && (p.Mnemonic == "" || p.DerivationPath == ""):
+		return ErrInvalidImportParams
+	}
+
+	return nil
+}
+
+// walletFromImport restores wallet from provided key and ensures that it matches claimed address.
+// Imported wallet keeps its keys even if it was derived from mnemonic, so it doesn't depend on KMS HD seed.
+// The key is known outside of KMS, so the wallet is pending operator approval.
+func walletFromImport(p ImportParams) (*Wallet, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		w   *Wallet
+		err error
+	)
+
+	if p.PrivateKey != "" {
+		w, err = walletFromPrivateKey(p.Blockchain, p.PrivateKey)
+	} else {
+		w, err = walletFromMnemonic(p.Blockchain, p.Mnemonic, p.MnemonicPassphrase, p.DerivationPath)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if normalizeAddress(p.Blockchain, w.Address) != normalizeAddress(p.Blockchain, p.Address) {
+		return nil, ErrAddressMismatch
+	}
+
+	w.UUID = uuid.New()
+	w.CreatedAt = time.Now()
+	w.Blockchain = p.Blockchain
+	w.DerivationPath = ""
+	w.PendingApproval = true
+
+	return w, nil
+}
+
+// VerifyKeys checks that wallet's private key (or derivation path for HD wallets) produces wallet's address.
+// keychain is required only for HD wallets.
+func VerifyKeys(w *Wallet, keychain *HDKeychain) error {
+	if !w.Blockchain.IsValid() {
+		return ErrUnknownBlockchain
+	}
+
+	if w.IsHD() {
+		if keychain == nil {
+			return ErrHDKeychainRequired
+		}
+
+		derived, err := keychain.Derive(w.Blockchain, w.DerivationPath)
+		if err != nil {
+			return err
+		}
+
+		if derived.Address != w.Address {
+			return ErrSeedMismatch
+		}
+
+		return nil
+	}
+
+	restored, err := walletFromPrivateKey(w.Blockchain, w.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	if normalizeAddress(w.Blockchain, restored.Address) == normalizeAddress(w.Blockchain, w.Address) {
+		return nil
+	}
+
+	// generated BTC wallets use uncompressed public key for the address
+	if key, err := hdkeychain.NewKeyFromString(strings.TrimSpace(w.PrivateKey)); err == nil && w.Blockchain == BTC {
+		if address, err := bitcoinUncompressedAddress(key); err == nil && address == w.Address {
+			return nil
+		}
+	}
+
+	return ErrAddressMismatch
+}
+
+func walletFromPrivateKey(blockchain Blockchain, raw string) (*Wallet, error) {
+	switch blockchain {
+	case ETH, MATIC, BSC, TRON:
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(raw), "0x"))
+		if err != nil {
+			return nil, ErrInvalidPrivateKey
+		}
+
+		if blockchain == TRON {
+			return tronWalletFromKey(key), nil
+		}
+
+		return ethWalletFromKey(key), nil
+	case BTC:
+		return bitcoinWalletFromPrivateKey(strings.TrimSpace(raw))
+	default:
+		return nil, ErrUnknownBlockchain
+	}
+}
+
+func walletFromMnemonic(blockchain Blockchain, mnemonic, passphrase, path string) (*Wallet, error) {
+	seed, err := SeedFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		for i := range seed {
+			seed[i] = 0
+		}
+	}()
+
+	keychain, err := NewHDKeychain(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	defer keychain.Wipe()
+
+	return keychain.Derive(blockchain, path)
+}
@@ -0,0 +1,234 @@
+package wallet_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oxygenpay/oxygen/internal/kms/audit"
+	"github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_ImportWallet(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+	db := openStore(t)
+
+	repo := wallet.NewRepository(db, newKeyring(t, 1))
+	require.NoError(t, repo.VerifyKeyring())
+
+	auditLog := audit.New(db, []byte("audit-test-key"))
+	service := wallet.New(repo, wallet.NewGenerator(), nil, nil, auditLog, &logger)
+
+	seed, err := wallet.SeedFromMnemonic(testMnemonic, "")
+	require.NoError(t, err)
+
+	keychain, err := wallet.NewHDKeychain(seed)
+	require.NoError(t, err)
+
+	tronWallet, err := keychain.Derive(wallet.TRON, "m/44'/195'/0'/0/0")
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name   string
+		params wallet.ImportParams
+	}{
+		{
+			name: "ETH private key",
+			params: wallet.ImportParams{
+				Blockchain: wallet.ETH,
+				Address:    "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23",
+				PrivateKey: "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+			},
+		},
+		{
+			name: "ETH mnemonic",
+			params: wallet.ImportParams{
+				Blockchain:     wallet.ETH,
+				Address:        "0x9858EfFD232B4033E47d90003D41EC34EcaEda94",
+				Mnemonic:       testMnemonic,
+				DerivationPath: "m/44'/60'/0'/0/0",
+			},
+		},
+		{
+			name: "TRON private key",
+			params: wallet.ImportParams{
+				Blockchain: wallet.TRON,
+				Address:    tronWallet.Address,
+				PrivateKey: strings.TrimPrefix(tronWallet.PrivateKey, "0x"),
+			},
+		},
+		{
+			name: "BTC WIF",
+			params: wallet.ImportParams{
+				Blockchain: wallet.BTC,
+				Address:    "1GAehh7TsJAHuUAeKZcXf5CnwuGuGgyX2S",
+				PrivateKey: "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ",
+			},
+		},
+		{
+			name: "BTC mnemonic",
+			params: wallet.ImportParams{
+				Blockchain:     wallet.BTC,
+				Address:        "1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA",
+				Mnemonic:       testMnemonic,
+				DerivationPath: "m/44'/0'/0'/0/0",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := service.ImportWallet(ctx, tt.params)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.params.Blockchain, w.Blockchain)
+			assert.False(t, w.IsHD())
+
+			// Keys are persisted as imported wallet doesn't depend on KMS HD seed
+			actual, err := service.GetWallet(ctx, w.UUID, false)
+			require.NoError(t, err)
+			assert.Equal(t, w.PrivateKey, actual.PrivateKey)
+
+			byAddress, err := repo.GetByAddress(tt.params.Blockchain, tt.params.Address)
+			require.NoError(t, err)
+			assert.Equal(t, w.UUID, byAddress.UUID)
+		})
+	}
+
+	t.Run("Rejects key of another address", func(t *testing.T) {
+		_, err := service.ImportWallet(ctx, wallet.ImportParams{
+			Blockchain: wallet.ETH,
+			Address:    "0x9858EfFD232B4033E47d90003D41EC34EcaEda94",
+			PrivateKey: "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+		})
+		assert.ErrorIs(t, err, wallet.ErrAddressMismatch)
+	})
+
+	t.Run("Rejects existing wallet", func(t *testing.T) {
+		_, err := service.ImportWallet(ctx, wallet.ImportParams{
+			Blockchain: wallet.ETH,
+			Address:    "0x2C7536E3605D9C16A7A3D7B1898E529396A65C23",
+			PrivateKey: "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+		})
+		assert.ErrorIs(t, err, wallet.ErrWalletExists)
+	})
+
+	t.Run("Validates params", func(t *testing.T) {
+		for _, params := range []wallet.ImportParams{
+			{Blockchain: wallet.ETH, Address: "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"},
+			{Blockchain: wallet.ETH, Address: "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", Mnemonic: testMnemonic},
+			{Blockchain: wallet.ETH, Address: "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", PrivateKey: "abc", Mnemonic: testMnemonic},
+		} {
+			_, err := service.ImportWallet(ctx, params)
+			assert.ErrorIs(t, err, wallet.ErrInvalidImportParams)
+		}
+
+		_, err := service.ImportWallet(ctx, wallet.ImportParams{Blockchain: wallet.ETH, Address: "0xABC", PrivateKey: "abc"})
+		assert.ErrorIs(t, err, wallet.ErrInvalidAddress)
+
+		_, err = service.ImportWallet(ctx, wallet.ImportParams{
+			Blockchain: wallet.ETH,
+			Address:    "0x9858EfFD232B4033E47d90003D41EC34EcaEda94",
+			PrivateKey: "not-a-key",
+		})
+		assert.ErrorIs(t, err, wallet.ErrInvalidPrivateKey)
+	})
+
+	t.Run("Records audit entries", func(t *testing.T) {
+		entries, err := auditLog.List(0, 0)
+		require.NoError(t, err)
+		require.Len(t, entries, 12)
+
+		for _, e := range entries {
+			assert.Equal(t, audit.ImportWallet, e.Operation)
+		}
+
+		assert.Equal(t, audit.Success, entries[0].Result)
+		assert.Equal(t, audit.Failed, entries[len(entries)-1].Result)
+		assert.NotContains(t, entries[len(entries)-1].Error, "not-a-key")
+	})
+}
+
+func TestService_ApproveWallet(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+	db := openStore(t)
+
+	repo := wallet.NewRepository(db, nil)
+	auditLog := audit.New(db, []byte("audit-test-key"))
+
+	policy := wallet.NewPolicy(wallet.PolicyConfig{Enabled: true, AllowOwnWallets: true}, repo, &logger)
+	service := wallet.New(repo, wallet.NewGenerator(), nil, policy, auditLog, &logger)
+
+	sender := (&wallet.EthProvider{Blockchain: wallet.ETH, CryptoReader: strings.NewReader(strings.Repeat("a", 128))}).Generate()
+	require.NoError(t, repo.Set(sender))
+
+	// Given a wallet imported via KMS API
+	imported, err := service.ImportWallet(ctx, wallet.ImportParams{
+		Blockchain: wallet.ETH,
+		Address:    "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23",
+		PrivateKey: "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+	})
+	require.NoError(t, err)
+	assert.True(t, imported.PendingApproval)
+
+	// ACT 1: it's not an own wallet for signing policy
+	err = policy.AuthorizeRecipient(sender, imported.Address)
+	assert.ErrorIs(t, err, wallet.ErrPolicyViolation)
+
+	// ACT 2: operator approves it
+	require.NoError(t, service.ApproveWallet(audit.WithCaller(ctx, audit.CallerCLI), imported.UUID))
+
+	assert.NoError(t, policy.AuthorizeRecipient(sender, imported.Address))
+
+	actual, err := service.GetWallet(ctx, imported.UUID, false)
+	require.NoError(t, err)
+	assert.False(t, actual.PendingApproval)
+	assert.Equal(t, imported.PrivateKey, actual.PrivateKey)
+
+	assert.ErrorIs(t, service.ApproveWallet(ctx, uuid.New()), wallet.ErrNotFound)
+
+	entries, err := auditLog.List(0, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, audit.ApproveWallet, entries[1].Operation)
+	assert.Equal(t, audit.CallerCLI, entries[1].Caller)
+	assert.Equal(t, audit.Failed, entries[2].Result)
+}
+
+func TestVerifyKeys(t *testing.T) {
+	eth := (&wallet.EthProvider{Blockchain: wallet.ETH, CryptoReader: strings.NewReader(strings.Repeat("a", 128))}).Generate()
+	btc := (&wallet.BitcoinProvider{Blockchain: wallet.BTC, CryptoReader: strings.NewReader(strings.Repeat("b", 256))}).Generate()
+
+	seed, err := wallet.SeedFromMnemonic(testMnemonic, "")
+	require.NoError(t, err)
+
+	keychain, err := wallet.NewHDKeychain(seed)
+	require.NoError(t, err)
+
+	hd, err := keychain.Derive(wallet.TRON, "m/44'/195'/0'/0/0")
+	require.NoError(t, err)
+
+	hd.PrivateKey = ""
+
+	assert.NoError(t, wallet.VerifyKeys(eth, nil))
+	assert.NoError(t, wallet.VerifyKeys(btc, nil))
+	assert.NoError(t, wallet.VerifyKeys(hd, keychain))
+
+	assert.ErrorIs(t, wallet.VerifyKeys(hd, nil), wallet.ErrHDKeychainRequired)
+
+	tampered := *eth
+	tampered.Address = "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"
+	assert.ErrorIs(t, wallet.VerifyKeys(&tampered, nil), wallet.ErrAddressMismatch)
+
+	tampered = *btc
+	tampered.Address = "1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA"
+	assert.ErrorIs(t, wallet.VerifyKeys(&tampered, nil), wallet.ErrAddressMismatch)
+
+	tampered = *hd
+	tampered.DerivationPath = "m/44'/195'/0'/0/1"
+	assert.ErrorIs(t, wallet.VerifyKeys(&tampered, keychain), wallet.ErrSeedMismatch)
+}
@@ -0,0 +1,173 @@
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+)
+
+// MessageType signing scheme of off-chain message.
+type MessageType string
+
+const (
+	// PersonalSign EIP-191 "\x19Ethereum Signed Message:\n" + len(message) + message.
+	PersonalSign MessageType = "personal_sign"
+
+	// TypedData EIP-712 typed structured data (eth_signTypedData_v4).
+	TypedData MessageType = "typed_data"
+
+	// TronMessage "\x19TRON Signed Message:\n" + len(message) + message (TronWeb signMessageV2).
+	TronMessage MessageType = "tron"
+)
+
+const tronMessagePrefix = "\x19TRON Signed Message:\n"
+
+// ownershipProofTitle heading of ownership proof text & name of its EIP-712 domain.
+const ownershipProofTitle = "Proof of address ownership"
+
+// SignMessageParams ownership proof request. KMS never signs arbitrary text or typed data: it builds
+// the message itself from wallet's address, Challenge & current time (see ownershipProof).
+type SignMessageParams struct {
+	Type MessageType
+
+	// Challenge nonce issued by the party that verifies the ownership (exchange, compliance partner, ...).
+	Challenge string
+}
+
+// SignedMessage signature in 65-byte [R || S || V] format where V is 27 or 28.
+// Message is the exact text (or EIP-712 JSON for typed_data) that was signed, Hash is its digest.
+type SignedMessage struct {
+	Address   string
+	Message   string
+	Timestamp time.Time
+	Hash      string
+	Signature string
+}
+
+var (
+	ErrInvalidMessage         = errors.New("invalid message")
+	ErrUnsupportedMessageType = errors.New("message type is not supported by wallet's blockchain")
+)
+
+// challengeRegexp limits challenge to a single token (nonce, UUID, base64, ...), so it can't
+// turn proof of ownership into a free-form statement.
+var challengeRegexp = regexp.MustCompile(`^[A-Za-z0-9_.:/+=-]{8,128}$`)
+
+// ownershipProofTypes EIP-712 types of ownership proof. Domain is not bound to a chain or a contract.
+var ownershipProofTypes = apitypes.Types{
+	"EIP712Domain": {
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+	},
+	"ProofOfOwnership": {
+		{Name: "address", Type: "address"},
+		{Name: "challenge", Type: "string"},
+		{Name: "timestamp", Type: "uint256"},
+	},
+}
+
+func (p SignMessageParams) validate(blockchain Blockchain) error {
+	switch p.Type {
+	case PersonalSign, TypedData:
+		if blockchain != ETH && blockchain != MATIC && blockchain != BSC {
+			return ErrUnsupportedMessageType
+		}
+	case TronMessage:
+		if blockchain != TRON {
+			return ErrUnsupportedMessageType
+		}
+	default:
+		return errors.Wrapf(ErrInvalidMessage, "unknown message type %q", p.Type)
+	}
+
+	if !challengeRegexp.MatchString(p.Challenge) {
+		return errors.Wrap(ErrInvalidMessage, "challenge should be 8-128 characters of [A-Za-z0-9_.:/+=-]")
+	}
+
+	return nil
+}
+
+// ownershipProof builds the message that proves ownership of wallet's address and returns it along with its digest.
+//
+//	Proof of address ownership
+//	Address: 0x...
+//	Challenge: <challenge>
+//	Timestamp: 2023-04-01T12:00:00Z
+//
+// For typed_data the same fields form EIP-712 ProofOfOwnership struct (timestamp is unix seconds).
+func ownershipProof(w *Wallet, p SignMessageParams, timestamp time.Time) (string, []byte, error) {
+	if err := p.validate(w.Blockchain); err != nil {
+		return "", nil, err
+	}
+
+	if p.Type == TypedData {
+		return ownershipProofTypedData(w, p.Challenge, timestamp)
+	}
+
+	message := fmt.Sprintf(
+		"%s\nAddress: %s\nChallenge: %s\nTimestamp: %s",
+		ownershipProofTitle,
+		w.Address,
+		p.Challenge,
+		timestamp.UTC().Format(time.RFC3339),
+	)
+
+	if p.Type == TronMessage {
+		prefixed := tronMessagePrefix + strconv.Itoa(len(message)) + message
+		return message, crypto.Keccak256([]byte(prefixed)), nil
+	}
+
+	return message, accounts.TextHash([]byte(message)), nil
+}
+
+func ownershipProofTypedData(w *Wallet, challenge string, timestamp time.Time) (string, []byte, error) {
+	typedData := apitypes.TypedData{
+		Types:       ownershipProofTypes,
+		PrimaryType: "ProofOfOwnership",
+		Domain:      apitypes.TypedDataDomain{Name: ownershipProofTitle, Version: "1"},
+		Message: apitypes.TypedDataMessage{
+			"address":   w.Address,
+			"challenge": challenge,
+			"timestamp": strconv.FormatInt(timestamp.Unix(), 10),
+		},
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return "", nil, errors.Wrap(ErrInvalidMessage, err.Error())
+	}
+
+	raw, err := json.Marshal(typedData)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return string(raw), hash, nil
+}
+
+// signHash signs message digest with wallet's key. Signature can be verified with ecrecover-based tools
+// (ethers.verifyMessage, eth_signTypedData_v4 verifiers, TronWeb trx.verifyMessageV2).
+func signHash(w *Wallet, hash []byte) (string, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(w.PrivateKey, "0x"))
+	if err != nil {
+		return "", errors.Wrap(err, "unable to decode private key")
+	}
+
+	signature, err := crypto.Sign(hash, key)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to sign message")
+	}
+
+	signature[crypto.RecoveryIDOffset] += 27
+
+	return hexutil.Encode(signature), nil
+}
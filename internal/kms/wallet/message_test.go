@@ -0,0 +1,188 @@
+package wallet_test
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/oxygenpay/oxygen/internal/kms/audit"
+	"github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Key of EIP-712 "Mail" example, see https://eips.ethereum.org/EIPS/eip-712
+const (
+	mailPrivateKey = "0xc85ef7d79691fe79573b1a7064c19c1a9819ebdbd1faaab1a8ec92344438aaf4"
+	mailAddress    = "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"
+)
+
+func TestService_SignMessage(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+	db := openStore(t)
+
+	repo := wallet.NewRepository(db, nil)
+	auditLog := audit.New(db, []byte("audit-test-key"))
+	importer := wallet.New(repo, wallet.NewGenerator(), nil, nil, auditLog, &logger)
+
+	importWallet := func(blockchain wallet.Blockchain, address string) *wallet.Wallet {
+		w, err := importer.ImportWallet(ctx, wallet.ImportParams{
+			Blockchain: blockchain,
+			Address:    address,
+			PrivateKey: mailPrivateKey,
+		})
+		require.NoError(t, err)
+
+		return w
+	}
+
+	ethWallet := importWallet(wallet.ETH, mailAddress)
+	tronWallet := importWallet(wallet.TRON, "TUg28KYvCXWW81EqMUeZvCZmZw2BChk1HQ")
+	bscWallet := importWallet(wallet.BSC, mailAddress)
+
+	// Given policy that allows message signing only for ETH & TRON wallets
+	policy := wallet.NewPolicy(wallet.PolicyConfig{
+		Enabled:               true,
+		MessageSigningWallets: []string{ethWallet.UUID.String(), tronWallet.UUID.String()},
+	}, repo, &logger)
+
+	service := wallet.New(repo, wallet.NewGenerator(), nil, policy, auditLog, &logger)
+
+	const challenge = "6f1c2b7e-3d4a-4c1e-9f7a-2b8d5e0c1a93"
+
+	recoverAddress := func(t *testing.T, hash, signature string) string {
+		sig, err := hexutil.Decode(signature)
+		require.NoError(t, err)
+		require.Len(t, sig, 65)
+
+		sig[crypto.RecoveryIDOffset] -= 27
+
+		pubKey, err := crypto.SigToPub(hexutil.MustDecode(hash), sig)
+		require.NoError(t, err)
+
+		return crypto.PubkeyToAddress(*pubKey).Hex()
+	}
+
+	expectedMessage := func(address string, timestamp time.Time) string {
+		return "Proof of address ownership\n" +
+			"Address: " + address + "\n" +
+			"Challenge: " + challenge + "\n" +
+			"Timestamp: " + timestamp.UTC().Format(time.RFC3339)
+	}
+
+	t.Run("EIP-191 personal_sign", func(t *testing.T) {
+		signed, err := service.SignMessage(ctx, ethWallet, wallet.SignMessageParams{
+			Type:      wallet.PersonalSign,
+			Challenge: challenge,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, mailAddress, signed.Address)
+		assert.WithinDuration(t, time.Now(), signed.Timestamp, time.Minute)
+		assert.Equal(t, expectedMessage(mailAddress, signed.Timestamp), signed.Message)
+		assert.Equal(t, hexutil.Encode(accounts.TextHash([]byte(signed.Message))), signed.Hash)
+		assert.Equal(t, mailAddress, recoverAddress(t, signed.Hash, signed.Signature))
+	})
+
+	t.Run("EIP-712 typed data", func(t *testing.T) {
+		signed, err := service.SignMessage(ctx, ethWallet, wallet.SignMessageParams{
+			Type:      wallet.TypedData,
+			Challenge: challenge,
+		})
+		require.NoError(t, err)
+
+		var typedData apitypes.TypedData
+		require.NoError(t, json.Unmarshal([]byte(signed.Message), &typedData))
+
+		assert.Equal(t, "ProofOfOwnership", typedData.PrimaryType)
+		assert.Equal(t, "Proof of address ownership", typedData.Domain.Name)
+		assert.Empty(t, typedData.Domain.VerifyingContract)
+		assert.Equal(t, mailAddress, typedData.Message["address"])
+		assert.Equal(t, challenge, typedData.Message["challenge"])
+		assert.Equal(t, strconv.FormatInt(signed.Timestamp.Unix(), 10), typedData.Message["timestamp"])
+
+		expectedHash, _, err := apitypes.TypedDataAndHash(typedData)
+		require.NoError(t, err)
+
+		assert.Equal(t, hexutil.Encode(expectedHash), signed.Hash)
+		assert.Equal(t, mailAddress, recoverAddress(t, signed.Hash, signed.Signature))
+	})
+
+	t.Run("TRON message", func(t *testing.T) {
+		signed, err := service.SignMessage(ctx, tronWallet, wallet.SignMessageParams{
+			Type:      wallet.TronMessage,
+			Challenge: challenge,
+		})
+		require.NoError(t, err)
+
+		message := expectedMessage(tronWallet.Address, signed.Timestamp)
+		expectedHash := crypto.Keccak256([]byte("\x19TRON Signed Message:\n" + strconv.Itoa(len(message)) + message))
+
+		assert.Equal(t, message, signed.Message)
+		assert.Equal(t, hexutil.Encode(expectedHash), signed.Hash)
+		assert.Equal(t, mailAddress, recoverAddress(t, signed.Hash, signed.Signature))
+	})
+
+	t.Run("Denies wallet that is not allowed to sign messages", func(t *testing.T) {
+		params := wallet.SignMessageParams{Type: wallet.PersonalSign, Challenge: challenge}
+
+		_, err := service.SignMessage(ctx, bscWallet, params)
+		assert.ErrorIs(t, err, wallet.ErrPolicyViolation)
+
+		// message signing is denied without policy as well
+		_, err = importer.SignMessage(ctx, ethWallet, params)
+		assert.ErrorIs(t, err, wallet.ErrPolicyViolation)
+	})
+
+	t.Run("Validates params", func(t *testing.T) {
+		for _, tt := range []struct {
+			wallet *wallet.Wallet
+			params wallet.SignMessageParams
+			err    error
+		}{
+			{ethWallet, wallet.SignMessageParams{Type: wallet.TronMessage, Challenge: challenge}, wallet.ErrUnsupportedMessageType},
+			{tronWallet, wallet.SignMessageParams{Type: wallet.PersonalSign, Challenge: challenge}, wallet.ErrUnsupportedMessageType},
+			{ethWallet, wallet.SignMessageParams{Type: "eth_sign", Challenge: challenge}, wallet.ErrInvalidMessage},
+			{ethWallet, wallet.SignMessageParams{Type: wallet.PersonalSign}, wallet.ErrInvalidMessage},
+			{ethWallet, wallet.SignMessageParams{Type: wallet.PersonalSign, Challenge: "short"}, wallet.ErrInvalidMessage},
+			{ethWallet, wallet.SignMessageParams{Type: wallet.PersonalSign, Challenge: "I approve order #42"}, wallet.ErrInvalidMessage},
+			{ethWallet, wallet.SignMessageParams{Type: wallet.PersonalSign, Challenge: "abcdefgh\nTimestamp: 0"}, wallet.ErrInvalidMessage},
+		} {
+			_, err := service.SignMessage(ctx, tt.wallet, tt.params)
+			assert.ErrorIs(t, err, tt.err)
+		}
+	})
+
+	t.Run("Records audit entries", func(t *testing.T) {
+		entries, err := auditLog.List(0, 0)
+		require.NoError(t, err)
+
+		// 3 imports, 3 signatures, 2 denials, 7 failures
+		require.Len(t, entries, 15)
+
+		signature := entries[3]
+		assert.Equal(t, audit.SignMessage, signature.Operation)
+		assert.Equal(t, audit.Success, signature.Result)
+		assert.Equal(t, ethWallet.UUID.String(), signature.WalletID)
+		assert.Equal(t, string(wallet.PersonalSign), signature.Asset)
+		assert.Contains(t, signature.Message, "Challenge: "+challenge)
+		assert.NotEmpty(t, signature.TransactionHash)
+
+		for _, entry := range entries[6:8] {
+			assert.Equal(t, audit.Denied, entry.Result)
+			assert.Contains(t, entry.Message, "Challenge: "+challenge)
+		}
+
+		failure := entries[8]
+		assert.Equal(t, audit.Failed, failure.Result)
+		assert.Equal(t, challenge, failure.Message)
+	})
+}
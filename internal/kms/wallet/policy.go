@@ -0,0 +1,484 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/oxygenpay/oxygen/internal/kms/storage"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// PolicyConfig restricts transactions that KMS signs, so a compromised web server can't drain wallets.
+// Changes require KMS restart on purpose: the policy can be altered only by the KMS operator.
+type PolicyConfig struct {
+	// Enabled turns on deny-by-default mode: a transaction is signed only if its recipient
+	// is an allowed destination and value limits are not exceeded.
+	Enabled bool `yaml:"enabled" env:"KMS_POLICY_ENABLED" env-description:"Enables KMS signing policy (deny-by-default)"`
+
+	// AllowOwnWallets allows transfers to wallets managed by this KMS (internal transfers, TRON delegation).
+	// Imported wallets are not included until approved by the operator as their keys are known outside of KMS.
+	AllowOwnWallets bool `yaml:"allow_own_wallets" env:"KMS_POLICY_ALLOW_OWN_WALLETS" env-description:"Allows transfers to KMS wallets"`
+
+	// AllowedRecipients external addresses per blockchain, e.g. merchants' withdrawal addresses & cold wallets.
+	// The list is maintained by the KMS operator and is not synced with merchants' withdrawal addresses
+	// on purpose: those are stored in the web app's database, so a compromised web server could add its own.
+	AllowedRecipients map[string][]string `yaml:"allowed_recipients"`
+
+	Limits []PolicyLimit `yaml:"limits"`
+	Fees   []PolicyFee   `yaml:"fees"`
+
+	// MessageSigningWallets UUIDs of KMS wallets that are allowed to sign proofs of address ownership.
+	// Message signing is denied for other wallets regardless of Enabled flag.
+	MessageSigningWallets []string `yaml:"message_signing_wallets"`
+}
+
+// PolicyLimit caps value that can be sent within a sliding time window. Amounts are
+// in the smallest units (wei, sun, ...). Empty amount means no limit.
+type PolicyLimit struct {
+	Blockchain string `yaml:"blockchain"`
+
+	// Asset "coin" or token contract address.
+	Asset string `yaml:"asset"`
+
+	Window    time.Duration `yaml:"window"`
+	PerWallet string        `yaml:"per_wallet"`
+	Global    string        `yaml:"global"`
+}
+
+// PolicyFee caps fees of a transaction, so funds can't be burned on gas. Prices are in the smallest
+// units (wei, sun). Empty (or zero gas limit) means no ceiling.
+type PolicyFee struct {
+	Blockchain string `yaml:"blockchain"`
+
+	// MaxGasPrice caps EVM max fee & max priority fee per gas.
+	MaxGasPrice string `yaml:"max_gas_price"`
+	MaxGasLimit uint64 `yaml:"max_gas_limit"`
+
+	// MaxFeeCap caps max fee of the whole transaction: gas limit * max fee per gas for EVM, fee limit for TRON.
+	MaxFeeCap string `yaml:"max_fee_cap"`
+}
+
+// TransferIntent represents a transfer that KMS is asked to sign.
+type TransferIntent struct {
+	Wallet    *Wallet
+	Recipient string
+	Asset     string
+	Amount    string
+
+	// GasPrice, GasLimit & FeeCap describe max fee that transaction can spend. Empty values are not checked.
+	GasPrice string
+	GasLimit uint64
+	FeeCap   string
+
+	// DedupKey identifies transfers that replace each other (e.g. EVM transaction with the same nonce)
+	// so that they are counted against limits only once.
+	DedupKey string
+}
+
+// Policy authorizes transfers according to PolicyConfig. Spent amounts are persisted,
+// so limits are not reset on KMS restart.
+type Policy struct {
+	config PolicyConfig
+	repo   *Repository
+	logger *zerolog.Logger
+}
+
+type spending struct {
+	WalletID   string     `json:"wallet_id"`
+	Blockchain Blockchain `json:"blockchain"`
+	Asset      string     `json:"asset"`
+	Amount     string     `json:"amount"`
+	DedupKey   string     `json:"dedup_key,omitempty"`
+}
+
+var ErrPolicyViolation = errors.New("transaction violates KMS signing policy")
+
+func NewPolicy(config PolicyConfig, repo *Repository, logger *zerolog.Logger) *Policy {
+	log := logger.With().Str("channel", "kms_policy").Logger()
+
+	return &Policy{
+		config: config,
+		repo:   repo,
+		logger: &log,
+	}
+}
+
+// Reserve authorizes the transfer and counts its amount against limits. The returned func
+// releases the reservation and should be called if the transaction wasn't signed.
+// Nil policy allows everything.
+func (p *Policy) Reserve(intent TransferIntent) (func(), error) {
+	noop := func() {}
+
+	if p == nil || !p.config.Enabled {
+		return noop, nil
+	}
+
+	if err := p.authorizeRecipient(intent.Wallet.Blockchain, intent.Recipient); err != nil {
+		return noop, p.deny(intent, err)
+	}
+
+	if err := p.checkFees(intent); err != nil {
+		return noop, p.deny(intent, err)
+	}
+
+	amount, ok := new(big.Int).SetString(intent.Amount, 10)
+	if !ok {
+		return noop, ErrInvalidAmount
+	}
+
+	limits := p.matchLimits(intent.Wallet.Blockchain, intent.Asset)
+
+	var key []byte
+
+	err := p.repo.store.Update(func(tx storage.Tx) error {
+		b := tx.Bucket(storage.SpendingsBucket)
+
+		if err := pruneSpendings(b, time.Now().Add(-p.maxWindow())); err != nil {
+			return err
+		}
+
+		for _, limit := range limits {
+			if err := checkLimit(b, limit, intent, amount); err != nil {
+				return err
+			}
+		}
+
+		if len(limits) == 0 {
+			return nil
+		}
+
+		value, err := json.Marshal(spending{
+			WalletID:   intent.Wallet.UUID.String(),
+			Blockchain: intent.Wallet.Blockchain,
+			Asset:      normalizeAsset(intent.Wallet.Blockchain, intent.Asset),
+			Amount:     amount.String(),
+			DedupKey:   intent.DedupKey,
+		})
+		if err != nil {
+			return err
+		}
+
+		if key, err = spendingKey(time.Now()); err != nil {
+			return err
+		}
+
+		return b.Put(key, value)
+	})
+
+	if err != nil {
+		if errors.Is(err, ErrPolicyViolation) {
+			return noop, p.deny(intent, err)
+		}
+
+		return noop, errors.Wrap(err, "unable to check policy limits")
+	}
+
+	release := func() {
+		if key == nil {
+			return
+		}
+
+		errRelease := p.repo.store.Update(func(tx storage.Tx) error {
+			return tx.Bucket(storage.SpendingsBucket).Delete(key)
+		})
+
+		if errRelease != nil {
+			p.logger.Error().Err(errRelease).Msg("unable to release policy reservation")
+		}
+	}
+
+	return release, nil
+}
+
+// AuthorizeRecipient checks that recipient is an allowed destination. Nil policy allows everything.
+func (p *Policy) AuthorizeRecipient(w *Wallet, recipient string) error {
+	if p == nil || !p.config.Enabled {
+		return nil
+	}
+
+	if err := p.authorizeRecipient(w.Blockchain, recipient); err != nil {
+		return p.deny(TransferIntent{Wallet: w, Recipient: recipient}, err)
+	}
+
+	return nil
+}
+
+// AuthorizeMessage checks that wallet is allowed to sign proofs of address ownership.
+// Unlike transfers, nil policy denies message signing.
+func (p *Policy) AuthorizeMessage(w *Wallet) error {
+	if p != nil {
+		for _, id := range p.config.MessageSigningWallets {
+			if strings.EqualFold(strings.TrimSpace(id), w.UUID.String()) {
+				return nil
+			}
+		}
+	}
+
+	err := errors.Wrapf(ErrPolicyViolation, "message signing is not enabled for wallet %s", w.UUID)
+
+	if p != nil {
+		p.logger.Error().Err(err).
+			Str("wallet_id", w.UUID.String()).
+			Str("blockchain", w.Blockchain.String()).
+			Msg("denied message signing")
+	}
+
+	return err
+}
+
+func (p *Policy) authorizeRecipient(blockchain Blockchain, recipient string) error {
+	normalized := normalizeAddress(blockchain, recipient)
+
+	for bc, addresses := range p.config.AllowedRecipients {
+		if !strings.EqualFold(bc, blockchain.String()) {
+			continue
+		}
+
+		for _, address := range addresses {
+			if normalizeAddress(blockchain, address) == normalized {
+				return nil
+			}
+		}
+	}
+
+	if p.config.AllowOwnWallets {
+		w, err := p.repo.GetByAddress(blockchain, recipient)
+		switch {
+		case err == nil && w.PendingApproval:
+			return errors.Wrapf(ErrPolicyViolation, "recipient %s is imported wallet pending operator approval", recipient)
+		case err == nil:
+			return nil
+		case !errors.Is(err, ErrNotFound):
+			return err
+		}
+	}
+
+	return errors.Wrapf(ErrPolicyViolation, "recipient %s is not allowed", recipient)
+}
+
+func (p *Policy) checkFees(intent TransferIntent) error {
+	for _, fee := range p.config.Fees {
+		if !strings.EqualFold(fee.Blockchain, intent.Wallet.Blockchain.String()) {
+			continue
+		}
+
+		if exceedsCeiling(intent.GasPrice, fee.MaxGasPrice) {
+			return errors.Wrapf(ErrPolicyViolation, "gas price %s exceeds %s", intent.GasPrice, fee.MaxGasPrice)
+		}
+
+		if fee.MaxGasLimit > 0 && intent.GasLimit > fee.MaxGasLimit {
+			return errors.Wrapf(ErrPolicyViolation, "gas limit %d exceeds %d", intent.GasLimit, fee.MaxGasLimit)
+		}
+
+		if exceedsCeiling(intent.FeeCap, fee.MaxFeeCap) {
+			return errors.Wrapf(ErrPolicyViolation, "fee cap %s exceeds %s", intent.FeeCap, fee.MaxFeeCap)
+		}
+	}
+
+	return nil
+}
+
+func (p *Policy) matchLimits(blockchain Blockchain, asset string) []PolicyLimit {
+	var limits []PolicyLimit
+
+	for _, limit := range p.config.Limits {
+		if !strings.EqualFold(limit.Blockchain, blockchain.String()) {
+			continue
+		}
+
+		if normalizeAsset(blockchain, limit.Asset) != normalizeAsset(blockchain, asset) {
+			continue
+		}
+
+		limits = append(limits, limit)
+	}
+
+	return limits
+}
+
+func (p *Policy) maxWindow() time.Duration {
+	var window time.Duration
+
+	for _, limit := range p.config.Limits {
+		if limit.Window > window {
+			window = limit.Window
+		}
+	}
+
+	return window
+}
+
+func (p *Policy) deny(intent TransferIntent, err error) error {
+	if !errors.Is(err, ErrPolicyViolation) {
+		return err
+	}
+
+	p.logger.Error().Err(err).
+		Str("wallet_id", intent.Wallet.UUID.String()).
+		Str("blockchain", intent.Wallet.Blockchain.String()).
+		Str("recipient", intent.Recipient).
+		Str("asset", intent.Asset).
+		Str("amount", intent.Amount).
+		Msg("denied transaction signing")
+
+	return err
+}
+
+// checkLimit sums spendings within limit's window. Spendings with the same dedup key
+// are counted once using the max amount.
+func checkLimit(b storage.Bucket, limit PolicyLimit, intent TransferIntent, amount *big.Int) error {
+	since, err := spendingKey(time.Now().Add(-limit.Window))
+	if err != nil {
+		return err
+	}
+
+	asset := normalizeAsset(intent.Wallet.Blockchain, intent.Asset)
+	walletID := intent.Wallet.UUID.String()
+
+	global := make(map[string]*big.Int)
+	perWallet := make(map[string]*big.Int)
+
+	add := func(m map[string]*big.Int, key string, value *big.Int) {
+		if current, ok := m[key]; !ok || current.Cmp(value) < 0 {
+			m[key] = value
+		}
+	}
+
+	c := b.Cursor()
+	for k, v := c.Seek(since[:8]); k != nil; k, v = c.Next() {
+		var s spending
+		if err := json.Unmarshal(v, &s); err != nil {
+			return err
+		}
+
+		if s.Blockchain != intent.Wallet.Blockchain || s.Asset != asset {
+			continue
+		}
+
+		spent, ok := new(big.Int).SetString(s.Amount, 10)
+		if !ok {
+			continue
+		}
+
+		key := s.DedupKey
+		if key == "" {
+			key = string(k)
+		}
+
+		add(global, key, spent)
+		if s.WalletID == walletID {
+			add(perWallet, key, spent)
+		}
+	}
+
+	key := intent.DedupKey
+	if key == "" {
+		key = "new"
+	}
+
+	add(global, key, amount)
+	add(perWallet, key, amount)
+
+	if exceeds(global, limit.Global) {
+		return errors.Wrapf(ErrPolicyViolation, "global %s limit of %s per %s is exceeded", asset, limit.Global, limit.Window)
+	}
+
+	if exceeds(perWallet, limit.PerWallet) {
+		return errors.Wrapf(ErrPolicyViolation, "wallet %s limit of %s per %s is exceeded", asset, limit.PerWallet, limit.Window)
+	}
+
+	return nil
+}
+
+// pruneSpendings removes spendings that are outside any limit window.
+func pruneSpendings(b storage.Bucket, before time.Time) error {
+	var stale [][]byte
+
+	prefix := make([]byte, 8)
+	binary.BigEndian.PutUint64(prefix, uint64(before.UnixNano()))
+
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil && bytes.Compare(k[:8], prefix) < 0; k, _ = c.Next() {
+		stale = append(stale, append([]byte(nil), k...))
+	}
+
+	for _, k := range stale {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exceedsCeiling checks that value is above the ceiling. Empty value is not checked.
+func exceedsCeiling(rawValue, rawCeiling string) bool {
+	if rawCeiling == "" || rawValue == "" {
+		return false
+	}
+
+	ceiling, ok := new(big.Int).SetString(rawCeiling, 10)
+	if !ok {
+		// misconfigured ceiling denies everything
+		return true
+	}
+
+	value, ok := new(big.Int).SetString(rawValue, 10)
+
+	return !ok || value.Cmp(ceiling) > 0
+}
+
+func exceeds(amounts map[string]*big.Int, rawLimit string) bool {
+	if rawLimit == "" {
+		return false
+	}
+
+	limit, ok := new(big.Int).SetString(rawLimit, 10)
+	if !ok {
+		// misconfigured limit denies everything
+		return true
+	}
+
+	total := new(big.Int)
+	for _, a := range amounts {
+		total.Add(total, a)
+	}
+
+	return total.Cmp(limit) > 0
+}
+
+// spendingKey returns time-ordered key: 8 bytes of unix nano + 8 random bytes.
+func spendingKey(t time.Time) ([]byte, error) {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+
+	if _, err := rand.Read(key[8:]); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func normalizeAsset(blockchain Blockchain, asset string) string {
+	if asset == "" || strings.EqualFold(asset, string(Coin)) {
+		return string(Coin)
+	}
+
+	return normalizeAddress(blockchain, asset)
+}
+
+// normalizeAddress EVM addresses are case-insensitive.
+func normalizeAddress(blockchain Blockchain, address string) string {
+	switch blockchain {
+	case ETH, MATIC, BSC:
+		return strings.ToLower(address)
+	default:
+		return address
+	}
+}
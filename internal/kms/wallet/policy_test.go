@@ -0,0 +1,215 @@
+package wallet_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const merchantAddress = "0x95222290DD7278Aa3Ddd389Cc1E1d165CC4BAfe5"
+
+func TestPolicy_Recipients(t *testing.T) {
+	logger := zerolog.Nop()
+	repo := wallet.NewRepository(openStore(t), nil)
+
+	sender := newEthWallet(t, repo, "a")
+	own := newEthWallet(t, repo, "b")
+
+	config := wallet.PolicyConfig{
+		Enabled:           true,
+		AllowOwnWallets:   true,
+		AllowedRecipients: map[string][]string{"ETH": {merchantAddress}},
+	}
+
+	reserve := func(policy *wallet.Policy, recipient string) error {
+		_, err := policy.Reserve(wallet.TransferIntent{
+			Wallet:    sender,
+			Recipient: recipient,
+			Asset:     "coin",
+			Amount:    "1000",
+		})
+
+		return err
+	}
+
+	t.Run("Allows everything when disabled", func(t *testing.T) {
+		var nilPolicy *wallet.Policy
+		assert.NoError(t, reserve(nilPolicy, "0x0000000000000000000000000000000000000001"))
+
+		disabled := wallet.NewPolicy(wallet.PolicyConfig{}, repo, &logger)
+		assert.NoError(t, reserve(disabled, "0x0000000000000000000000000000000000000001"))
+	})
+
+	t.Run("Allows own wallets and whitelisted recipients", func(t *testing.T) {
+		policy := wallet.NewPolicy(config, repo, &logger)
+
+		assert.NoError(t, reserve(policy, own.Address))
+		assert.NoError(t, reserve(policy, strings.ToLower(merchantAddress)))
+	})
+
+	t.Run("Denies unknown recipient", func(t *testing.T) {
+		policy := wallet.NewPolicy(config, repo, &logger)
+
+		assert.ErrorIs(t, reserve(policy, "0x0000000000000000000000000000000000000001"), wallet.ErrPolicyViolation)
+	})
+
+	t.Run("Denies own wallets if not allowed", func(t *testing.T) {
+		cfg := config
+		cfg.AllowOwnWallets = false
+		policy := wallet.NewPolicy(cfg, repo, &logger)
+
+		assert.ErrorIs(t, reserve(policy, own.Address), wallet.ErrPolicyViolation)
+	})
+
+	t.Run("Denies deleted own wallet", func(t *testing.T) {
+		deleted := newEthWallet(t, repo, "c")
+		require.NoError(t, repo.SoftDelete(deleted))
+
+		policy := wallet.NewPolicy(config, repo, &logger)
+
+		assert.ErrorIs(t, reserve(policy, deleted.Address), wallet.ErrPolicyViolation)
+	})
+}
+
+func TestPolicy_Limits(t *testing.T) {
+	logger := zerolog.Nop()
+	repo := wallet.NewRepository(openStore(t), nil)
+
+	w1 := newEthWallet(t, repo, "a")
+	w2 := newEthWallet(t, repo, "b")
+
+	policy := wallet.NewPolicy(wallet.PolicyConfig{
+		Enabled:           true,
+		AllowedRecipients: map[string][]string{"ETH": {merchantAddress}},
+		Limits: []wallet.PolicyLimit{
+			{Blockchain: "ETH", Asset: "coin", Window: time.Hour, PerWallet: "100", Global: "150"},
+		},
+	}, repo, &logger)
+
+	reserve := func(w *wallet.Wallet, asset, amount, dedupKey string) (func(), error) {
+		return policy.Reserve(wallet.TransferIntent{
+			Wallet:    w,
+			Recipient: merchantAddress,
+			Asset:     asset,
+			Amount:    amount,
+			DedupKey:  dedupKey,
+		})
+	}
+
+	// Replaced transaction (same nonce) is counted once
+	_, err := reserve(w1, "coin", "60", "nonce-1")
+	require.NoError(t, err)
+
+	_, err = reserve(w1, "coin", "70", "nonce-1")
+	require.NoError(t, err)
+
+	// Per wallet limit: 70 + 40 > 100
+	_, err = reserve(w1, "coin", "40", "nonce-2")
+	assert.ErrorIs(t, err, wallet.ErrPolicyViolation)
+
+	// Global limit: 70 + 90 > 150
+	_, err = reserve(w2, "coin", "90", "")
+	assert.ErrorIs(t, err, wallet.ErrPolicyViolation)
+
+	// Released reservation is not counted
+	release, err := reserve(w2, "coin", "80", "")
+	require.NoError(t, err)
+	release()
+
+	_, err = reserve(w2, "coin", "80", "")
+	assert.NoError(t, err)
+
+	// Tokens are not affected by coin limits
+	_, err = reserve(w1, "0xdAC17F958D2ee523a2206206994597C13D831ec7", "1000000", "")
+	assert.NoError(t, err)
+
+	_, err = reserve(w1, "coin", "abc", "")
+	assert.ErrorIs(t, err, wallet.ErrInvalidAmount)
+}
+
+func TestPolicy_Fees(t *testing.T) {
+	logger := zerolog.Nop()
+	repo := wallet.NewRepository(openStore(t), nil)
+
+	w := newEthWallet(t, repo, "a")
+
+	policy := wallet.NewPolicy(wallet.PolicyConfig{
+		Enabled:           true,
+		AllowedRecipients: map[string][]string{"ETH": {merchantAddress}},
+		Fees: []wallet.PolicyFee{
+			{Blockchain: "ETH", MaxGasPrice: "100", MaxGasLimit: 21000, MaxFeeCap: "1500000"},
+		},
+	}, repo, &logger)
+
+	for _, tt := range []struct {
+		name      string
+		gasPrice  string
+		gasLimit  uint64
+		feeCap    string
+		expectErr bool
+	}{
+		{name: "within ceilings", gasPrice: "70", gasLimit: 21000, feeCap: "1470000"},
+		{name: "gas price", gasPrice: "101", gasLimit: 21000, feeCap: "1470000", expectErr: true},
+		{name: "gas limit", gasPrice: "70", gasLimit: 21001, feeCap: "1470000", expectErr: true},
+		{name: "fee cap", gasPrice: "100", gasLimit: 21000, feeCap: "2100000", expectErr: true},
+		{name: "malformed gas price", gasPrice: "abc", gasLimit: 21000, feeCap: "1470000", expectErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := policy.Reserve(wallet.TransferIntent{
+				Wallet:    w,
+				Recipient: merchantAddress,
+				Asset:     "coin",
+				Amount:    "1",
+				GasPrice:  tt.gasPrice,
+				GasLimit:  tt.gasLimit,
+				FeeCap:    tt.feeCap,
+			})
+
+			if tt.expectErr {
+				assert.ErrorIs(t, err, wallet.ErrPolicyViolation)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRepository_IndexAddresses(t *testing.T) {
+	db := openStore(t)
+	repo := wallet.NewRepository(db, nil)
+
+	w := newEthWallet(t, repo, "a")
+
+	actual, err := repo.GetByAddress(wallet.ETH, strings.ToUpper(w.Address[2:]))
+	assert.ErrorIs(t, err, wallet.ErrNotFound)
+	assert.Nil(t, actual)
+
+	actual, err = repo.GetByAddress(wallet.ETH, strings.ToLower(w.Address))
+	require.NoError(t, err)
+	assert.Equal(t, w.UUID, actual.UUID)
+
+	_, err = repo.GetByAddress(wallet.MATIC, w.Address)
+	assert.ErrorIs(t, err, wallet.ErrNotFound)
+
+	// Index is built only once
+	count, err := repo.IndexAddresses()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = repo.IndexAddresses()
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func newEthWallet(t *testing.T, repo *wallet.Repository, seed string) *wallet.Wallet {
+	w := (&wallet.EthProvider{Blockchain: wallet.ETH, CryptoReader: strings.NewReader(strings.Repeat(seed, 128))}).Generate()
+	require.NoError(t, repo.Set(w))
+
+	return w
+}
@@ -1,38 +1,171 @@
 package wallet
 
 import (
+	"bytes"
+	"encoding/binary"
 	"encoding/json"
-	"errors"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/oxygenpay/oxygen/internal/db/connection/bolt"
-	"go.etcd.io/bbolt"
+	"github.com/oxygenpay/oxygen/internal/kms/encryption"
+	"github.com/oxygenpay/oxygen/internal/kms/storage"
+	"github.com/pkg/errors"
 )
 
+// Repository stores wallets in KMS store. When keyring is provided, wallets are envelope-encrypted.
+// Plaintext records created before encryption was enabled are still readable.
+// Sealed repository has no keyring and refuses to read or write wallets.
 type Repository struct {
-	db *bbolt.DB
+	store storage.Store
+
+	mu      sync.RWMutex
+	keyring *encryption.Keyring
+	sealed  bool
+}
+
+var (
+	ErrNotFound        = errors.New("wallet not found")
+	ErrStoreEncrypted  = errors.New("wallets store is encrypted, master key is required")
+	ErrKeyringRequired = errors.New("master key is not configured")
+	ErrHDSeedNotFound  = errors.New("HD seed not found")
+	ErrNoMnemonic      = errors.New("HD seed was not generated from mnemonic")
+	ErrHDSeedInUse     = errors.New("HD seed is already used to derive wallets")
+	ErrWalletExists    = errors.New("wallet already exists")
+	ErrSealed          = errors.New("KMS is sealed")
+)
+
+const (
+	metaMasterKeyID   = "master_key_id"
+	metaMasterKeySalt = "master_key_salt"
+	metaHDSeed        = "hd_seed"
+	metaHDIndexPrefix = "hd_index_"
+	metaAddressIndex  = "address_index"
+)
+
+type hdSeed struct {
+	Seed []byte `json:"seed"`
+
+	// Mnemonic BIP-39 mnemonic (without passphrase) that Seed is derived from. Empty for seeds
+	// that were generated or imported as raw bytes.
+	Mnemonic string `json:"mnemonic,omitempty"`
+}
+
+func NewRepository(store storage.Store, keyring *encryption.Keyring) *Repository {
+	return &Repository{store: store, keyring: keyring}
+}
+
+// LoadKeySalt returns salt for passphrase-derived master key. Creates one if missing.
+func LoadKeySalt(store storage.Store) ([]byte, error) {
+	var salt []byte
+
+	err := store.Update(func(tx storage.Tx) error {
+		b := tx.Bucket(storage.MetaBucket)
+
+		if raw := b.Get([]byte(metaMasterKeySalt)); len(raw) > 0 {
+			salt = append([]byte(nil), raw...)
+			return nil
+		}
+
+		newSalt, err := encryption.NewSalt()
+		if err != nil {
+			return err
+		}
+
+		salt = newSalt
+
+		return b.Put([]byte(metaMasterKeySalt), salt)
+	})
+
+	return salt, err
+}
+
+// IsEncrypted checks whether wallets store was encrypted with a master key.
+func (r *Repository) IsEncrypted() (bool, error) {
+	keyID, err := r.masterKeyID()
+
+	return keyID != "", err
+}
+
+// VerifyKeyring ensures that configured master key matches the one the store is encrypted with.
+// Remembers master key id on first run.
+func (r *Repository) VerifyKeyring() error {
+	keyID, err := r.masterKeyID()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case r.keyring == nil && keyID != "":
+		return ErrStoreEncrypted
+	case r.keyring == nil:
+		return nil
+	case keyID == "":
+		return r.store.Update(func(tx storage.Tx) error {
+			return tx.Bucket(storage.MetaBucket).Put([]byte(metaMasterKeyID), []byte(r.keyring.ID()))
+		})
+	case keyID != r.keyring.ID():
+		return encryption.ErrKeyMismatch
+	}
+
+	return nil
+}
+
+// Seal wipes master key from memory. Wallets are not accessible until Unseal is called.
+func (r *Repository) Seal() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.keyring != nil {
+		r.keyring.Wipe()
+	}
+
+	r.keyring = nil
+	r.sealed = true
 }
 
-var ErrNotFound = errors.New("wallet not found")
+// Unseal sets keyring of sealed repository. Keyring should match the one the store is encrypted with.
+func (r *Repository) Unseal(keyring *encryption.Keyring) error {
+	keyID, err := r.masterKeyID()
 
-func NewRepository(db *bbolt.DB) *Repository {
-	return &Repository{db: db}
+	switch {
+	case err != nil:
+		return err
+	case keyID == "":
+		return ErrKeyringRequired
+	case keyring == nil || keyID != keyring.ID():
+		return encryption.ErrKeyMismatch
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keyring = keyring
+	r.sealed = false
+
+	return nil
+}
+
+func (r *Repository) IsSealed() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.sealed
 }
 
 func (r *Repository) Get(id uuid.UUID, withTrashed bool) (*Wallet, error) {
 	found := false
 	w := &Wallet{}
 
-	err := r.db.View(func(tx *bbolt.Tx) error {
-		b := mustResolveBucket(tx, bolt.WalletsBucket)
+	err := r.store.View(func(tx storage.Tx) error {
+		b := tx.Bucket(storage.WalletsBucket)
 
 		rawValue := b.Get(uuidToKey(id))
 		if len(rawValue) == 0 {
 			return nil
 		}
 
-		if err := json.Unmarshal(rawValue, w); err != nil {
+		if err := r.decode(uuidToKey(id), rawValue, w); err != nil {
 			return err
 		}
 
@@ -41,6 +174,10 @@ func (r *Repository) Get(id uuid.UUID, withTrashed bool) (*Wallet, error) {
 		return nil
 	})
 
+	if err != nil {
+		return nil, err
+	}
+
 	if !found {
 		return nil, ErrNotFound
 	}
@@ -52,16 +189,148 @@ func (r *Repository) Get(id uuid.UUID, withTrashed bool) (*Wallet, error) {
 	return w, err
 }
 
+// Set persists the wallet. Keys of HD wallet are not persisted.
 func (r *Repository) Set(w *Wallet) error {
-	return r.db.Update(func(tx *bbolt.Tx) error {
-		rawValue, err := json.Marshal(w)
+	return r.store.Update(func(tx storage.Tx) error {
+		return r.put(tx, w)
+	})
+}
+
+// List returns all wallets including soft-deleted ones. Keys of HD wallets are not included.
+func (r *Repository) List() ([]*Wallet, error) {
+	var wallets []*Wallet
+
+	err := r.store.View(func(tx storage.Tx) error {
+		return tx.Bucket(storage.WalletsBucket).ForEach(func(k, rawValue []byte) error {
+			w := &Wallet{}
+			if err := r.decode(k, rawValue, w); err != nil {
+				return errors.Wrapf(err, "unable to decode wallet %s", k)
+			}
+
+			wallets = append(wallets, w)
+
+			return nil
+		})
+	})
+
+	return wallets, err
+}
+
+// Import persists wallets in a single transaction. Fails with ErrWalletExists if any wallet
+// is already present, unless overwrite is true.
+func (r *Repository) Import(wallets []*Wallet, overwrite bool) error {
+	return r.store.Update(func(tx storage.Tx) error {
+		return r.importWallets(tx, wallets, overwrite)
+	})
+}
+
+func (r *Repository) importWallets(tx storage.Tx, wallets []*Wallet, overwrite bool) error {
+	b := tx.Bucket(storage.WalletsBucket)
+
+	for _, w := range wallets {
+		if !overwrite && b.Get(uuidToKey(w.UUID)) != nil {
+			return errors.Wrapf(ErrWalletExists, "wallet %s", w.UUID)
+		}
+
+		if err := r.put(tx, w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Insert persists new wallet. Fails with ErrWalletExists if another non-deleted wallet has the same address.
+func (r *Repository) Insert(w *Wallet) error {
+	return r.store.Update(func(tx storage.Tx) error {
+		id := tx.Bucket(storage.AddressesBucket).Get(addressToKey(w.Blockchain, w.Address))
+		if id == nil {
+			return r.put(tx, w)
+		}
+
+		if rawValue := tx.Bucket(storage.WalletsBucket).Get(id); len(rawValue) > 0 {
+			existing := &Wallet{}
+			if err := r.decode(id, rawValue, existing); err != nil {
+				return err
+			}
+
+			if existing.DeletedAt == nil {
+				return errors.Wrapf(ErrWalletExists, "wallet %s", existing.UUID)
+			}
+		}
+
+		return r.put(tx, w)
+	})
+}
+
+// GetByAddress returns wallet by its blockchain address. Soft-deleted wallets are not returned.
+func (r *Repository) GetByAddress(blockchain Blockchain, address string) (*Wallet, error) {
+	var id []byte
+
+	err := r.store.View(func(tx storage.Tx) error {
+		id = tx.Bucket(storage.AddressesBucket).Get(addressToKey(blockchain, address))
+		if id != nil {
+			id = append([]byte(nil), id...)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if id == nil {
+		return nil, ErrNotFound
+	}
+
+	walletID, err := uuid.ParseBytes(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid address index entry")
+	}
+
+	w, err := r.Get(walletID, false)
+	switch {
+	case err != nil:
+		return nil, err
+	case w == nil:
+		return nil, ErrNotFound
+	}
+
+	return w, nil
+}
+
+// IndexAddresses builds address index for wallets created before the index existed.
+// Runs only once per store. Returns number of indexed wallets.
+func (r *Repository) IndexAddresses() (int, error) {
+	count := 0
+
+	err := r.store.Update(func(tx storage.Tx) error {
+		meta := tx.Bucket(storage.MetaBucket)
+		if meta.Get([]byte(metaAddressIndex)) != nil {
+			return nil
+		}
+
+		addresses := tx.Bucket(storage.AddressesBucket)
+
+		err := tx.Bucket(storage.WalletsBucket).ForEach(func(k, rawValue []byte) error {
+			w := &Wallet{}
+			if err := r.decode(k, rawValue, w); err != nil {
+				return errors.Wrapf(err, "unable to decode wallet %s", k)
+			}
+
+			count++
+
+			return addresses.Put(addressToKey(w.Blockchain, w.Address), uuidToKey(w.UUID))
+		})
 		if err != nil {
 			return err
 		}
 
-		b := mustResolveBucket(tx, bolt.WalletsBucket)
-		return b.Put(uuidToKey(w.UUID), rawValue)
+		return meta.Put([]byte(metaAddressIndex), []byte{1})
 	})
+
+	return count, err
 }
 
 func (r *Repository) SoftDelete(w *Wallet) error {
@@ -73,15 +342,510 @@ func (r *Repository) SoftDelete(w *Wallet) error {
 	return r.Set(w)
 }
 
-func mustResolveBucket(tx *bbolt.Tx, name string) *bbolt.Bucket {
-	bucket := tx.Bucket([]byte(name))
-	if bucket == nil {
-		panic("unable to resolve bucket " + name)
+// LoadHDSeed returns seed of HD wallets.
+func (r *Repository) LoadHDSeed() ([]byte, error) {
+	var seed []byte
+
+	err := r.store.View(func(tx storage.Tx) (err error) {
+		seed, err = r.getHDSeed(tx)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return seed, nil
+}
+
+// LoadHDMnemonic returns BIP-39 mnemonic of HD wallets' seed.
+// Returns ErrNoMnemonic if seed was not generated or imported from mnemonic.
+func (r *Repository) LoadHDMnemonic() (string, error) {
+	var record *hdSeed
+
+	err := r.store.View(func(tx storage.Tx) (err error) {
+		record, err = r.getHDSeedRecord(tx)
+		return err
+	})
+
+	switch {
+	case err != nil:
+		return "", err
+	case record.Mnemonic == "":
+		return "", ErrNoMnemonic
+	}
+
+	return record.Mnemonic, nil
+}
+
+// SetHDSeed persists seed of HD wallets. Seed can't be replaced once any HD wallet was derived.
+func (r *Repository) SetHDSeed(seed []byte) error {
+	return r.store.Update(func(tx storage.Tx) error {
+		return r.putHDSeed(tx, seed, "")
+	})
+}
+
+// SetHDMnemonic persists seed of HD wallets derived from BIP-39 mnemonic (without passphrase)
+// alongside the mnemonic itself, so it can be exported for backup. See SetHDSeed.
+func (r *Repository) SetHDMnemonic(mnemonic string) error {
+	seed, err := SeedFromMnemonic(mnemonic, "")
+	if err != nil {
+		return err
+	}
+
+	return r.store.Update(func(tx storage.Tx) error {
+		return r.putHDSeed(tx, seed, normalizeMnemonic(mnemonic))
+	})
+}
+
+// Restore persists HD seed (with its optional mnemonic), wallets & derivation indexes in a single transaction,
+// so a failed restore doesn't leave the store partially modified. Empty seed or the one that is already set
+// is skipped. See Import for overwrite behavior. Optional onWrite is called within the same transaction
+// after everything is written (e.g. to append audit log entries).
+func (r *Repository) Restore(
+	seed []byte,
+	mnemonic string,
+	wallets []*Wallet,
+	indexes map[Blockchain]uint32,
+	overwrite bool,
+	onWrite func(tx storage.Tx) error,
+) error {
+	return r.store.Update(func(tx storage.Tx) error {
+		meta := tx.Bucket(storage.MetaBucket)
+
+		if len(seed) > 0 {
+			current, err := r.getHDSeedRecord(tx)
+			switch {
+			case errors.Is(err, ErrHDSeedNotFound):
+				current = &hdSeed{}
+			case err != nil:
+				return err
+			}
+
+			if !bytes.Equal(current.Seed, seed) || (current.Mnemonic == "" && mnemonic != "") {
+				if err := r.putHDSeed(tx, seed, mnemonic); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := r.importWallets(tx, wallets, overwrite); err != nil {
+			return err
+		}
+
+		for blockchain, index := range indexes {
+			if derivationIndex(meta, blockchain) >= index {
+				continue
+			}
+
+			if err := putDerivationIndex(meta, blockchain, index); err != nil {
+				return err
+			}
+		}
+
+		if onWrite != nil {
+			return onWrite(tx)
+		}
+
+		return nil
+	})
+}
+
+func (r *Repository) getHDSeed(tx storage.Tx) ([]byte, error) {
+	record, err := r.getHDSeedRecord(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return record.Seed, nil
+}
+
+func (r *Repository) getHDSeedRecord(tx storage.Tx) (*hdSeed, error) {
+	rawValue := tx.Bucket(storage.MetaBucket).Get([]byte(metaHDSeed))
+	if len(rawValue) == 0 {
+		return nil, ErrHDSeedNotFound
+	}
+
+	plaintext, err := r.open(rawValue, hdSeedAAD())
+	if err != nil {
+		return nil, err
+	}
+
+	var record hdSeed
+	if err := json.Unmarshal(plaintext, &record); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (r *Repository) putHDSeed(tx storage.Tx, seed []byte, mnemonic string) error {
+	meta := tx.Bucket(storage.MetaBucket)
+
+	if len(meta.Get([]byte(metaHDSeed))) > 0 {
+		for _, blockchain := range ListBlockchains() {
+			if derivationIndex(meta, blockchain) > 0 {
+				return ErrHDSeedInUse
+			}
+		}
+	}
+
+	plaintext, err := json.Marshal(hdSeed{Seed: seed, Mnemonic: mnemonic})
+	if err != nil {
+		return err
+	}
+
+	var rawValue []byte
+	err = r.withKeyring(func(keyring *encryption.Keyring) error {
+		rawValue, err = r.seal(plaintext, keyring, hdSeedAAD())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return meta.Put([]byte(metaHDSeed), rawValue)
+}
+
+// NextDerivationIndex returns index for the next blockchain's HD wallet and increments it.
+func (r *Repository) NextDerivationIndex(blockchain Blockchain) (uint32, error) {
+	var index uint32
+
+	err := r.store.Update(func(tx storage.Tx) error {
+		meta := tx.Bucket(storage.MetaBucket)
+		index = derivationIndex(meta, blockchain)
+
+		return putDerivationIndex(meta, blockchain, index+1)
+	})
+
+	return index, err
+}
+
+// EnsureDerivationIndex ensures that next blockchain's HD wallet index is not less than provided one.
+func (r *Repository) EnsureDerivationIndex(blockchain Blockchain, index uint32) error {
+	return r.store.Update(func(tx storage.Tx) error {
+		meta := tx.Bucket(storage.MetaBucket)
+		if derivationIndex(meta, blockchain) >= index {
+			return nil
+		}
+
+		return putDerivationIndex(meta, blockchain, index)
+	})
+}
+
+// DerivationIndexes returns next HD wallet index of each blockchain.
+func (r *Repository) DerivationIndexes() (map[Blockchain]uint32, error) {
+	indexes := make(map[Blockchain]uint32)
+
+	err := r.store.View(func(tx storage.Tx) error {
+		meta := tx.Bucket(storage.MetaBucket)
+		for _, blockchain := range ListBlockchains() {
+			if index := derivationIndex(meta, blockchain); index > 0 {
+				indexes[blockchain] = index
+			}
+		}
+
+		return nil
+	})
+
+	return indexes, err
+}
+
+// EncryptAll encrypts all plaintext wallets & HD seed with repository's keyring. Legacy envelopes that are
+// not bound to their records are re-encrypted as well. Returns number of encrypted wallets.
+// Plaintext leftovers are purged from bolt store by compaction, while postgres keeps old row versions
+// until VACUUM. Backups and copies of the store made before encryption still hold plaintext keys
+// and should be destroyed separately.
+func (r *Repository) EncryptAll() (int, error) {
+	if r.keyring == nil {
+		return 0, ErrKeyringRequired
+	}
+
+	return r.rewriteAll(r.keyring, nil, func(aad, raw []byte, env *encryption.Envelope) ([]byte, bool, error) {
+		switch {
+		case env == nil:
+		case env.KeyID != r.keyring.ID():
+			return nil, false, encryption.ErrKeyMismatch
+		case !env.IsLegacy():
+			return nil, false, nil
+		default:
+			plaintext, err := r.keyring.Open(env, aad)
+			if err != nil {
+				return nil, false, err
+			}
+
+			raw = plaintext
+		}
+
+		encoded, err := r.seal(raw, r.keyring, aad)
+
+		return encoded, true, err
+	})
+}
+
+// RotateKey re-wraps data keys of wallets & HD seed with next keyring; plaintext records and legacy envelopes
+// (not bound to their records) are re-encrypted with next keyring.
+// Salt of passphrase-derived next key should be provided. All changes are applied atomically.
+// Returns number of rewritten wallets.
+func (r *Repository) RotateKey(next *encryption.Keyring, nextSalt []byte) (int, error) {
+	if r.keyring == nil || next == nil {
+		return 0, ErrKeyringRequired
 	}
 
-	return bucket
+	return r.rewriteAll(next, nextSalt, func(aad, raw []byte, env *encryption.Envelope) ([]byte, bool, error) {
+		if env != nil && env.IsLegacy() {
+			plaintext, err := r.keyring.Open(env, aad)
+			if err != nil {
+				return nil, false, err
+			}
+
+			raw, env = plaintext, nil
+		}
+
+		if env == nil {
+			encoded, err := r.seal(raw, next, aad)
+
+			return encoded, true, err
+		}
+
+		rewrapped, err := r.keyring.Rewrap(env, next)
+		if err != nil {
+			return nil, false, err
+		}
+
+		encoded, err := rewrapped.Marshal()
+
+		return encoded, true, err
+	})
+}
+
+// rewriteFunc returns new value of a record. aad binds value to the record.
+type rewriteFunc func(aad, raw []byte, env *encryption.Envelope) ([]byte, bool, error)
+
+// rewriteAll applies fn to every wallet & HD seed within a single transaction
+// and marks store as encrypted with keyring. Store is compacted afterwards (see storage.Compactor),
+// so previous versions of rewritten records don't stay on disk.
+func (r *Repository) rewriteAll(keyring *encryption.Keyring, salt []byte, fn rewriteFunc) (int, error) {
+	count := 0
+
+	apply := func(aad, raw []byte) ([]byte, bool, error) {
+		env, _, err := encryption.UnmarshalEnvelope(raw)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return fn(aad, raw, env)
+	}
+
+	err := r.store.Update(func(tx storage.Tx) error {
+		b := tx.Bucket(storage.WalletsBucket)
+
+		updates := make(map[string][]byte)
+
+		err := b.ForEach(func(k, v []byte) error {
+			value, changed, err := apply(walletAAD(k), v)
+			if err != nil {
+				return errors.Wrapf(err, "wallet %s", k)
+			}
+
+			if changed {
+				updates[string(k)] = value
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// buckets can't be modified during iteration
+		for k, v := range updates {
+			if err := b.Put([]byte(k), v); err != nil {
+				return err
+			}
+		}
+
+		meta := tx.Bucket(storage.MetaBucket)
+
+		if raw := meta.Get([]byte(metaHDSeed)); len(raw) > 0 {
+			value, changed, err := apply(hdSeedAAD(), raw)
+			if err != nil {
+				return errors.Wrap(err, "HD seed")
+			}
+
+			if changed {
+				if err := meta.Put([]byte(metaHDSeed), value); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := meta.Put([]byte(metaMasterKeyID), []byte(keyring.ID())); err != nil {
+			return err
+		}
+
+		if len(salt) > 0 {
+			if err := meta.Put([]byte(metaMasterKeySalt), salt); err != nil {
+				return err
+			}
+		}
+
+		count = len(updates)
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	r.keyring = keyring
+	r.mu.Unlock()
+
+	if compactor, ok := r.store.(storage.Compactor); ok {
+		if err := compactor.Compact(); err != nil {
+			return count, errors.Wrap(err, "unable to compact store")
+		}
+	}
+
+	return count, nil
+}
+
+// put persists wallet and indexes its address.
+func (r *Repository) put(tx storage.Tx, w *Wallet) error {
+	if w.IsHD() {
+		stripped := *w
+		stripped.PublicKey = ""
+		stripped.PrivateKey = ""
+		w = &stripped
+	}
+
+	var rawValue []byte
+	err := r.withKeyring(func(keyring *encryption.Keyring) (err error) {
+		rawValue, err = r.encode(w, keyring)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Bucket(storage.WalletsBucket).Put(uuidToKey(w.UUID), rawValue); err != nil {
+		return err
+	}
+
+	return tx.Bucket(storage.AddressesBucket).Put(addressToKey(w.Blockchain, w.Address), uuidToKey(w.UUID))
+}
+
+func (r *Repository) encode(w *Wallet, keyring *encryption.Keyring) ([]byte, error) {
+	rawValue, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.seal(rawValue, keyring, walletAAD(uuidToKey(w.UUID)))
+}
+
+func (r *Repository) decode(key, rawValue []byte, w *Wallet) error {
+	plaintext, err := r.open(rawValue, walletAAD(key))
+	if err != nil {
+		return errors.Wrap(err, "unable to decrypt wallet")
+	}
+
+	return json.Unmarshal(plaintext, w)
+}
+
+// seal encrypts JSON value with keyring binding it to the record (aad). Returns value as is if keyring is nil.
+func (r *Repository) seal(plaintext []byte, keyring *encryption.Keyring, aad []byte) ([]byte, error) {
+	if keyring == nil {
+		return plaintext, nil
+	}
+
+	env, err := keyring.Seal(plaintext, aad)
+	if err != nil {
+		return nil, err
+	}
+
+	return env.Marshal()
+}
+
+// open decrypts value if it's encrypted. aad should match the one value was sealed with.
+func (r *Repository) open(rawValue, aad []byte) ([]byte, error) {
+	env, isEncrypted, err := encryption.UnmarshalEnvelope(rawValue)
+	if err != nil {
+		return nil, err
+	}
+
+	var plaintext []byte
+	err = r.withKeyring(func(keyring *encryption.Keyring) error {
+		switch {
+		case !isEncrypted:
+			plaintext = rawValue
+		case keyring == nil:
+			return ErrStoreEncrypted
+		default:
+			plaintext, err = keyring.Open(env, aad)
+		}
+
+		return err
+	})
+
+	return plaintext, err
+}
+
+// withKeyring prevents keyring from being wiped while fn is running.
+func (r *Repository) withKeyring(fn func(keyring *encryption.Keyring) error) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.sealed {
+		return ErrSealed
+	}
+
+	return fn(r.keyring)
+}
+
+func (r *Repository) masterKeyID() (string, error) {
+	var keyID string
+
+	err := r.store.View(func(tx storage.Tx) error {
+		keyID = string(tx.Bucket(storage.MetaBucket).Get([]byte(metaMasterKeyID)))
+		return nil
+	})
+
+	return keyID, err
+}
+
+func derivationIndex(meta storage.Bucket, blockchain Blockchain) uint32 {
+	raw := meta.Get([]byte(metaHDIndexPrefix + blockchain.String()))
+	if len(raw) != 4 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint32(raw)
+}
+
+func putDerivationIndex(meta storage.Bucket, blockchain Blockchain, index uint32) error {
+	raw := make([]byte, 4)
+	binary.BigEndian.PutUint32(raw, index)
+
+	return meta.Put([]byte(metaHDIndexPrefix+blockchain.String()), raw)
+}
+
+func addressToKey(blockchain Blockchain, address string) []byte {
+	return []byte(blockchain.String() + ":" + normalizeAddress(blockchain, address))
 }
 
 func uuidToKey(id uuid.UUID) []byte {
 	return []byte(id.String())
 }
+
+// walletAAD binds encrypted wallet to its key in wallets bucket.
+func walletAAD(key []byte) []byte {
+	return []byte(storage.WalletsBucket + "/" + string(key))
+}
+
+// hdSeedAAD binds encrypted HD seed to its key in meta bucket.
+func hdSeedAAD() []byte {
+	return []byte(storage.MetaBucket + "/" + metaHDSeed)
+}
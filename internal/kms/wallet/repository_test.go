@@ -0,0 +1,219 @@
+package wallet_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oxygenpay/oxygen/internal/kms/encryption"
+	"github.com/oxygenpay/oxygen/internal/kms/storage"
+	"github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_Encryption(t *testing.T) {
+	logger := zerolog.Nop()
+	path := t.TempDir() + "/kms.test.db"
+
+	db, err := storage.Open(storage.Config{Type: storage.Bolt, Path: path}, &logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	w := &wallet.Wallet{
+		UUID:       uuid.New(),
+		Address:    "0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5",
+		PublicKey:  "0x04abc",
+		PrivateKey: "0xdeadbeef",
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+		Blockchain: wallet.ETH,
+	}
+
+	// Given plaintext wallet stored before encryption was enabled
+	plainRepo := wallet.NewRepository(db, nil)
+	require.NoError(t, plainRepo.VerifyKeyring())
+	require.NoError(t, plainRepo.Set(w))
+	assert.Contains(t, string(rawWallet(t, db, w.UUID)), w.PrivateKey)
+
+	keyring := newKeyring(t, 1)
+	repo := wallet.NewRepository(db, keyring)
+	require.NoError(t, repo.VerifyKeyring())
+
+	t.Run("Reads plaintext wallet", func(t *testing.T) {
+		actual, err := repo.Get(w.UUID, false)
+		require.NoError(t, err)
+		assert.Equal(t, w.PrivateKey, actual.PrivateKey)
+	})
+
+	t.Run("Encrypts existing wallets", func(t *testing.T) {
+		count, err := repo.EncryptAll()
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		assert.NotContains(t, string(rawWallet(t, db, w.UUID)), w.PrivateKey)
+
+		// plaintext doesn't stay in freed pages of db file
+		file, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(file), w.PrivateKey)
+
+		actual, err := repo.Get(w.UUID, false)
+		require.NoError(t, err)
+		assert.Equal(t, w.PrivateKey, actual.PrivateKey)
+
+		// second run is no-op
+		count, err = repo.EncryptAll()
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("Rejects records swapped between wallets", func(t *testing.T) {
+		// Given another encrypted wallet
+		other := *w
+		other.UUID = uuid.New()
+		other.PrivateKey = "0x0badf00d"
+		require.NoError(t, repo.Set(&other))
+
+		rawA, rawB := rawWallet(t, db, w.UUID), rawWallet(t, db, other.UUID)
+
+		// ACT
+		putRawWallet(t, db, w.UUID, rawB)
+		_, err := repo.Get(w.UUID, false)
+
+		// ASSERT
+		assert.Error(t, err)
+
+		putRawWallet(t, db, w.UUID, rawA)
+	})
+
+	t.Run("Upgrades legacy envelopes", func(t *testing.T) {
+		// Given wallet encrypted with envelope that is not bound to its record
+		legacy := *w
+		legacy.UUID = uuid.New()
+		legacy.PrivateKey = "0x1e9ac1"
+
+		plaintext, err := json.Marshal(&legacy)
+		require.NoError(t, err)
+
+		env, err := keyring.Seal(plaintext, nil)
+		require.NoError(t, err)
+		env.Version = 1
+
+		raw, err := env.Marshal()
+		require.NoError(t, err)
+		putRawWallet(t, db, legacy.UUID, raw)
+
+		actual, err := repo.Get(legacy.UUID, false)
+		require.NoError(t, err)
+		assert.Equal(t, legacy.PrivateKey, actual.PrivateKey)
+
+		// ACT
+		count, err := repo.EncryptAll()
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		upgraded, ok, err := encryption.UnmarshalEnvelope(rawWallet(t, db, legacy.UUID))
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.False(t, upgraded.IsLegacy())
+
+		actual, err = repo.Get(legacy.UUID, false)
+		require.NoError(t, err)
+		assert.Equal(t, legacy.PrivateKey, actual.PrivateKey)
+	})
+
+	t.Run("Requires master key for encrypted store", func(t *testing.T) {
+		assert.ErrorIs(t, wallet.NewRepository(db, nil).VerifyKeyring(), wallet.ErrStoreEncrypted)
+		assert.ErrorIs(t, wallet.NewRepository(db, newKeyring(t, 2)).VerifyKeyring(), encryption.ErrKeyMismatch)
+
+		_, err := wallet.NewRepository(db, nil).Get(w.UUID, false)
+		assert.ErrorIs(t, err, wallet.ErrStoreEncrypted)
+	})
+
+	t.Run("Rotates master key", func(t *testing.T) {
+		// Given another encrypted wallet
+		w2 := *w
+		w2.UUID = uuid.New()
+		w2.PrivateKey = "0xcafebabe"
+		require.NoError(t, repo.Set(&w2))
+
+		next := newKeyring(t, 2)
+
+		// ACT
+		count, err := repo.RotateKey(next, nil)
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Equal(t, 4, count)
+
+		assert.ErrorIs(t, wallet.NewRepository(db, keyring).VerifyKeyring(), encryption.ErrKeyMismatch)
+
+		nextRepo := wallet.NewRepository(db, next)
+		require.NoError(t, nextRepo.VerifyKeyring())
+
+		for _, expected := range []*wallet.Wallet{w, &w2} {
+			actual, err := nextRepo.Get(expected.UUID, false)
+			require.NoError(t, err)
+			assert.Equal(t, expected.PrivateKey, actual.PrivateKey)
+		}
+
+		_, err = wallet.NewRepository(db, keyring).Get(w.UUID, false)
+		assert.ErrorIs(t, err, encryption.ErrKeyMismatch)
+	})
+}
+
+func TestLoadKeySalt(t *testing.T) {
+	db := openStore(t)
+
+	salt, err := wallet.LoadKeySalt(db)
+	require.NoError(t, err)
+	assert.NotEmpty(t, salt)
+
+	again, err := wallet.LoadKeySalt(db)
+	require.NoError(t, err)
+	assert.Equal(t, salt, again)
+}
+
+func openStore(t *testing.T) storage.Store {
+	logger := zerolog.Nop()
+
+	store, err := storage.Open(storage.Config{Type: storage.Bolt, Path: t.TempDir() + "/kms.test.db"}, &logger)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = store.Close() })
+
+	return store
+}
+
+func rawWallet(t *testing.T, db storage.Store, id uuid.UUID) []byte {
+	var raw []byte
+
+	err := db.View(func(tx storage.Tx) error {
+		raw = append(raw, tx.Bucket(storage.WalletsBucket).Get([]byte(id.String()))...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, json.Valid(raw))
+
+	return raw
+}
+
+func putRawWallet(t *testing.T, db storage.Store, id uuid.UUID, raw []byte) {
+	err := db.Update(func(tx storage.Tx) error {
+		return tx.Bucket(storage.WalletsBucket).Put([]byte(id.String()), raw)
+	})
+	require.NoError(t, err)
+}
+
+func newKeyring(t *testing.T, seed byte) *encryption.Keyring {
+	keyring, err := encryption.NewKeyring(bytes.Repeat([]byte{seed}, 32))
+	require.NoError(t, err)
+
+	return keyring
+}
@@ -2,8 +2,17 @@ package wallet
 
 import (
 	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/google/uuid"
+	"github.com/oxygenpay/oxygen/internal/kms/audit"
+	"github.com/oxygenpay/oxygen/internal/kms/encryption"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
@@ -11,7 +20,13 @@ import (
 type Service struct {
 	repo      *Repository
 	generator *Generator
+	policy    *Policy
+	audit     *audit.Log
 	logger    *zerolog.Logger
+
+	mu       sync.RWMutex
+	keychain *HDKeychain
+	sealed   bool
 }
 
 type CreateTransactionParams struct {
@@ -34,34 +49,166 @@ var (
 	ErrUnknownBlockchain      = errors.New("unknown blockchain")
 )
 
-func New(repo *Repository, generator *Generator, logger *zerolog.Logger) *Service {
+// New Service constructor. If keychain is provided, new wallets are derived from HD seed,
+// otherwise they are generated from random keys. If policy is nil, all transactions are signed.
+// If audit log is nil, operations are not recorded.
+func New(
+	repo *Repository,
+	generator *Generator,
+	keychain *HDKeychain,
+	policy *Policy,
+	auditLog *audit.Log,
+	logger *zerolog.Logger,
+) *Service {
 	log := logger.With().Str("channel", "kms_service").Logger()
 
 	return &Service{
 		repo:      repo,
 		generator: generator,
+		keychain:  keychain,
+		policy:    policy,
+		audit:     auditLog,
 		logger:    &log,
 	}
 }
 
-func (s *Service) CreateWallet(_ context.Context, blockchain Blockchain) (*Wallet, error) {
-	wallet, err := s.generator.CreateWallet(blockchain)
+func (s *Service) CreateWallet(ctx context.Context, blockchain Blockchain) (*Wallet, error) {
+	entry := audit.Entry{Operation: audit.CreateWallet, Blockchain: blockchain.String()}
+
+	wallet, err := s.generateWallet(blockchain)
 	if err != nil {
-		return nil, err
+		return nil, s.record(ctx, entry, err)
 	}
 
+	entry.WalletID = wallet.UUID.String()
+
 	if err := s.repo.Set(wallet); err != nil {
 		msg := "unable to persist wallet"
 		s.logger.Error().Err(err).Msg(msg)
 
-		return nil, errors.Wrap(err, msg)
+		return nil, s.record(ctx, entry, errors.Wrap(err, msg))
+	}
+
+	if err := s.record(ctx, entry, nil); err != nil {
+		return nil, err
 	}
 
 	return wallet, nil
 }
 
+// ImportWallet persists externally generated wallet after checking that its key matches the address.
+func (s *Service) ImportWallet(ctx context.Context, params ImportParams) (*Wallet, error) {
+	entry := audit.Entry{Operation: audit.ImportWallet, Blockchain: params.Blockchain.String()}
+
+	wallet, err := walletFromImport(params)
+	if err != nil {
+		return nil, s.record(ctx, entry, err)
+	}
+
+	entry.WalletID = wallet.UUID.String()
+
+	if err := s.repo.Insert(wallet); err != nil {
+		return nil, s.record(ctx, entry, err)
+	}
+
+	if err := s.record(ctx, entry, nil); err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// ApproveWallet allows signing policy to treat imported wallet as own wallet.
+func (s *Service) ApproveWallet(ctx context.Context, id uuid.UUID) error {
+	entry := audit.Entry{Operation: audit.ApproveWallet, WalletID: id.String()}
+
+	wallet, err := s.repo.Get(id, false)
+	if err == nil && wallet == nil {
+		// soft-deleted wallet
+		err = ErrNotFound
+	}
+
+	if err != nil {
+		return s.record(ctx, entry, err)
+	}
+
+	entry.Blockchain = wallet.Blockchain.String()
+
+	if !wallet.PendingApproval {
+		return nil
+	}
+
+	wallet.PendingApproval = false
+
+	return s.record(ctx, entry, s.repo.Set(wallet))
+}
+
+// Seal wipes HD keychain & master key from memory. Service refuses to access wallets until unsealed.
+func (s *Service) Seal() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.keychain != nil {
+		s.keychain.Wipe()
+	}
+
+	s.keychain = nil
+	s.sealed = true
+	s.repo.Seal()
+}
+
+// Unseal unseals repository with provided keyring and loads HD keychain.
+func (s *Service) Unseal(keyring *encryption.Keyring) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.repo.Unseal(keyring); err != nil {
+		return err
+	}
+
+	seed, err := s.repo.LoadHDSeed()
+	if err != nil {
+		s.repo.Seal()
+		return errors.Wrap(err, "unable to load HD seed")
+	}
+
+	defer func() {
+		for i := range seed {
+			seed[i] = 0
+		}
+	}()
+
+	keychain, err := NewHDKeychain(seed)
+	if err != nil {
+		s.repo.Seal()
+		return err
+	}
+
+	s.keychain = keychain
+	s.sealed = false
+
+	return nil
+}
+
 func (s *Service) GetWallet(_ context.Context, id uuid.UUID, withTrashed bool) (*Wallet, error) {
-	return s.repo.Get(id, withTrashed)
+	wallet, err := s.repo.Get(id, withTrashed)
+	if err != nil || !wallet.IsHD() {
+		return wallet, err
+	}
+
+	err = s.withKeychain(func(keychain *HDKeychain) error {
+		if keychain == nil {
+			return ErrHDKeychainRequired
+		}
+
+		return keychain.hydrate(wallet)
+	})
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to derive wallet %s", id)
+	}
+
+	return wallet, nil
 }
 
 func (s *Service) DeleteWallet(ctx context.Context, id uuid.UUID) error {
@@ -70,11 +217,127 @@ func (s *Service) DeleteWallet(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
-	return s.repo.SoftDelete(wallet)
+	return s.record(ctx, audit.Entry{
+		Operation:  audit.DeleteWallet,
+		WalletID:   wallet.UUID.String(),
+		Blockchain: wallet.Blockchain.String(),
+	}, s.repo.SoftDelete(wallet))
+}
+
+// RecoverHDWallets derives first count HD wallets of each blockchain from the seed
+// and persists the missing ones. Returns number of recovered wallets.
+func (s *Service) RecoverHDWallets(ctx context.Context, count uint32) (int, error) {
+	recovered := 0
+
+	err := s.withKeychain(func(keychain *HDKeychain) error {
+		if keychain == nil {
+			return ErrHDKeychainRequired
+		}
+
+		var err error
+		recovered, err = s.recoverHDWallets(ctx, keychain, count)
+
+		return err
+	})
+
+	return recovered, err
+}
+
+func (s *Service) recoverHDWallets(ctx context.Context, keychain *HDKeychain, count uint32) (int, error) {
+	recovered := 0
+
+	for _, blockchain := range ListBlockchains() {
+		for i := uint32(0); i < count; i++ {
+			path, err := DerivationPath(blockchain, i)
+			if err != nil {
+				return recovered, err
+			}
+
+			wallet, err := keychain.Derive(blockchain, path)
+			if err != nil {
+				return recovered, err
+			}
+
+			_, err = s.repo.Get(wallet.UUID, true)
+			switch {
+			case err == nil:
+				continue
+			case !errors.Is(err, ErrNotFound):
+				return recovered, err
+			}
+
+			if err := s.repo.Set(wallet); err != nil {
+				return recovered, errors.Wrap(err, "unable to persist wallet")
+			}
+
+			err = s.record(ctx, audit.Entry{
+				Operation:  audit.RecoverWallet,
+				WalletID:   wallet.UUID.String(),
+				Blockchain: blockchain.String(),
+			}, nil)
+			if err != nil {
+				return recovered, err
+			}
+
+			recovered++
+		}
+
+		if err := s.repo.EnsureDerivationIndex(blockchain, count); err != nil {
+			return recovered, errors.Wrap(err, "unable to update derivation index")
+		}
+	}
+
+	return recovered, nil
+}
+
+func (s *Service) generateWallet(blockchain Blockchain) (*Wallet, error) {
+	var wallet *Wallet
+
+	err := s.withKeychain(func(keychain *HDKeychain) error {
+		var err error
+
+		if keychain == nil {
+			wallet, err = s.generator.CreateWallet(blockchain)
+			return err
+		}
+
+		if !blockchain.IsValid() {
+			return ErrUnknownBlockchain
+		}
+
+		index, err := s.repo.NextDerivationIndex(blockchain)
+		if err != nil {
+			return errors.Wrap(err, "unable to get derivation index")
+		}
+
+		path, err := DerivationPath(blockchain, index)
+		if err != nil {
+			return err
+		}
+
+		wallet, err = keychain.Derive(blockchain, path)
+
+		return err
+	})
+
+	return wallet, err
+}
+
+// withKeychain prevents keychain from being wiped while fn is running. Keychain is nil
+// if HD wallets are not enabled.
+func (s *Service) withKeychain(fn func(keychain *HDKeychain) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.sealed {
+		return ErrSealed
+	}
+
+	return fn(s.keychain)
 }
 
 // CreateEthereumTransaction creates and sings new raw Ethereum transaction based on provided input.
-func (s *Service) CreateEthereumTransaction(_ context.Context, wt *Wallet, params EthTransactionParams) (string, error) {
+func (s *Service) CreateEthereumTransaction(ctx context.Context, wt *Wallet, params EthTransactionParams) (string, error) {
 	if _, ok := s.generator.providers[ETH]; !ok {
 		return "", errors.New("ETH provider not found")
 	}
@@ -84,10 +347,10 @@ func (s *Service) CreateEthereumTransaction(_ context.Context, wt *Wallet, param
 		return "", errors.New("ETH provider is invalid")
 	}
 
-	return eth.NewTransaction(wt, params)
+	return s.signEVMTransaction(ctx, eth, wt, params)
 }
 
-func (s *Service) CreateMaticTransaction(_ context.Context, wt *Wallet, params EthTransactionParams) (string, error) {
+func (s *Service) CreateMaticTransaction(ctx context.Context, wt *Wallet, params EthTransactionParams) (string, error) {
 	if _, ok := s.generator.providers[MATIC]; !ok {
 		return "", errors.New("MATIC provider not found")
 	}
@@ -97,10 +360,10 @@ func (s *Service) CreateMaticTransaction(_ context.Context, wt *Wallet, params E
 		return "", errors.New("MATIC provider is invalid")
 	}
 
-	return matic.NewTransaction(wt, params)
+	return s.signEVMTransaction(ctx, matic, wt, params)
 }
 
-func (s *Service) CreateBSCTransaction(_ context.Context, wt *Wallet, params EthTransactionParams) (string, error) {
+func (s *Service) CreateBSCTransaction(ctx context.Context, wt *Wallet, params EthTransactionParams) (string, error) {
 	if _, ok := s.generator.providers[BSC]; !ok {
 		return "", errors.New("BSC provider not found")
 	}
@@ -110,7 +373,7 @@ func (s *Service) CreateBSCTransaction(_ context.Context, wt *Wallet, params Eth
 		return "", errors.New("BSC provider is invalid")
 	}
 
-	return bsc.NewTransaction(wt, params)
+	return s.signEVMTransaction(ctx, bsc, wt, params)
 }
 
 func (s *Service) CreateTronTransaction(
@@ -125,5 +388,230 @@ func (s *Service) CreateTronTransaction(
 		return TronTransaction{}, errors.New("TRON provider is invalid")
 	}
 
-	return tron.NewTransaction(ctx, wallet, params)
+	intent := TransferIntent{
+		Wallet:    wallet,
+		Recipient: params.Recipient,
+		Asset:     transferAsset(params.Type, params.ContractAddress),
+		Amount:    params.Amount,
+	}
+
+	if params.FeeLimit > 0 {
+		intent.FeeCap = strconv.FormatUint(params.FeeLimit, 10)
+	}
+
+	release, err := s.policy.Reserve(intent)
+	if err != nil {
+		return TronTransaction{}, s.recordTransfer(ctx, intent, "", err)
+	}
+
+	tx, err := tron.NewTransaction(ctx, wallet, params)
+	if err := s.recordTransfer(ctx, intent, tx.TxID, err); err != nil {
+		release()
+		return TronTransaction{}, err
+	}
+
+	return tx, nil
+}
+
+func (s *Service) CreateTronResourceTransaction(
+	ctx context.Context, wallet *Wallet, params TronResourceParams,
+) (TronTransaction, error) {
+	if _, ok := s.generator.providers[TRON]; !ok {
+		return TronTransaction{}, errors.New("TRON provider not found")
+	}
+
+	tron, ok := s.generator.providers[TRON].(*TronProvider)
+	if !ok {
+		return TronTransaction{}, errors.New("TRON provider is invalid")
+	}
+
+	entry := audit.Entry{
+		Operation:  audit.SignResourceOperation,
+		WalletID:   wallet.UUID.String(),
+		Blockchain: wallet.Blockchain.String(),
+		Recipient:  params.Receiver,
+		Asset:      string(params.Operation) + ":" + string(params.Resource),
+		Amount:     strconv.FormatInt(params.Amount, 10),
+	}
+
+	// Freezing doesn't move funds out of the wallet, delegation does (temporarily)
+	if params.Operation == TronDelegate || params.Operation == TronUndelegate {
+		if err := s.policy.AuthorizeRecipient(wallet, params.Receiver); err != nil {
+			return TronTransaction{}, s.record(ctx, entry, err)
+		}
+	}
+
+	tx, err := tron.NewResourceTransaction(ctx, wallet, params)
+	entry.TransactionHash = tx.TxID
+
+	if err := s.record(ctx, entry, err); err != nil {
+		return TronTransaction{}, err
+	}
+
+	return tx, nil
+}
+
+// SignMessage signs proof of address ownership built by KMS (see ownershipProof) with wallet's key.
+// Wallet should be allowed to sign messages by signing policy. Audit log keeps the message & its digest.
+func (s *Service) SignMessage(ctx context.Context, wallet *Wallet, params SignMessageParams) (SignedMessage, error) {
+	entry := audit.Entry{
+		Operation:  audit.SignMessage,
+		WalletID:   wallet.UUID.String(),
+		Blockchain: wallet.Blockchain.String(),
+		Asset:      string(params.Type),
+		Message:    params.Challenge,
+	}
+
+	signed, err := s.signMessage(wallet, params)
+	if signed.Message != "" {
+		entry.Message = signed.Message
+	}
+
+	entry.TransactionHash = signed.Hash
+
+	if err := s.record(ctx, entry, err); err != nil {
+		return SignedMessage{}, err
+	}
+
+	return signed, nil
+}
+
+// signMessage signs proof of address ownership if signing policy allows it for the wallet.
+// Built message is returned even if signing is denied, so it is recorded in the audit log.
+func (s *Service) signMessage(wallet *Wallet, params SignMessageParams) (SignedMessage, error) {
+	timestamp := time.Now().UTC().Truncate(time.Second)
+
+	message, hash, err := ownershipProof(wallet, params, timestamp)
+	if err != nil {
+		return SignedMessage{}, err
+	}
+
+	signed := SignedMessage{
+		Address:   wallet.Address,
+		Message:   message,
+		Timestamp: timestamp,
+		Hash:      hexutil.Encode(hash),
+	}
+
+	if err := s.policy.AuthorizeMessage(wallet); err != nil {
+		return signed, err
+	}
+
+	if signed.Signature, err = signHash(wallet, hash); err != nil {
+		return signed, err
+	}
+
+	return signed, nil
+}
+
+// signEVMTransaction signs ETH-like transaction if it's allowed by the policy. Transactions with the same
+// nonce replace each other (e.g. fee bump), so they're counted against limits once.
+func (s *Service) signEVMTransaction(
+	ctx context.Context, provider *EthProvider, wt *Wallet, params EthTransactionParams,
+) (string, error) {
+	intent := TransferIntent{
+		Wallet:    wt,
+		Recipient: params.Recipient,
+		Asset:     transferAsset(params.Type, params.ContractAddress),
+		Amount:    params.Amount,
+		DedupKey:  fmt.Sprintf("%s:%d:%s:%d", wt.Blockchain, params.NetworkID, wt.UUID, params.Nonce),
+	}
+
+	if gasPrice, feeCap, ok := evmFees(params); ok {
+		intent.GasPrice = gasPrice.String()
+		intent.GasLimit = uint64(params.Gas)
+		intent.FeeCap = feeCap.String()
+	}
+
+	release, err := s.policy.Reserve(intent)
+	if err != nil {
+		return "", s.recordTransfer(ctx, intent, "", err)
+	}
+
+	raw, err := provider.NewTransaction(wt, params)
+	if err := s.recordTransfer(ctx, intent, evmTransactionHash(raw), err); err != nil {
+		release()
+		return "", err
+	}
+
+	return raw, nil
+}
+
+// record appends operation to the audit log and returns operation's error. Signed transaction
+// is not returned to the caller if it can't be recorded.
+func (s *Service) record(ctx context.Context, entry audit.Entry, err error) error {
+	switch {
+	case err == nil:
+		entry.Result = audit.Success
+	case errors.Is(err, ErrPolicyViolation):
+		entry.Result = audit.Denied
+		entry.Error = err.Error()
+	default:
+		entry.Result = audit.Failed
+		entry.Error = err.Error()
+	}
+
+	if _, errAudit := s.audit.Append(ctx, entry); errAudit != nil {
+		s.logger.Error().Err(errAudit).
+			Str("operation", string(entry.Operation)).
+			Str("wallet_id", entry.WalletID).
+			Msg("unable to record audit log entry")
+
+		if err == nil {
+			return errAudit
+		}
+	}
+
+	return err
+}
+
+func (s *Service) recordTransfer(ctx context.Context, intent TransferIntent, txHash string, err error) error {
+	return s.record(ctx, audit.Entry{
+		Operation:       audit.SignTransaction,
+		WalletID:        intent.Wallet.UUID.String(),
+		Blockchain:      intent.Wallet.Blockchain.String(),
+		Recipient:       intent.Recipient,
+		Asset:           intent.Asset,
+		Amount:          intent.Amount,
+		TransactionHash: txHash,
+	}, err)
+}
+
+// evmFees returns the highest of max fee & max priority fee per gas and max fee of the whole transaction.
+func evmFees(params EthTransactionParams) (*big.Int, *big.Int, bool) {
+	maxFee, ok := new(big.Int).SetString(params.MaxFeePerGas, 10)
+	if !ok {
+		return nil, nil, false
+	}
+
+	maxPriorityFee, ok := new(big.Int).SetString(params.MaxPriorityFeePerGas, 10)
+	if !ok {
+		return nil, nil, false
+	}
+
+	gasPrice := maxFee
+	if maxPriorityFee.Cmp(maxFee) > 0 {
+		gasPrice = maxPriorityFee
+	}
+
+	feeCap := new(big.Int).Mul(maxFee, big.NewInt(params.Gas))
+
+	return gasPrice, feeCap, true
+}
+
+func evmTransactionHash(raw string) string {
+	bytes, err := hexutil.Decode(raw)
+	if err != nil {
+		return ""
+	}
+
+	return crypto.Keccak256Hash(bytes).Hex()
+}
+
+func transferAsset(assetType AssetType, contractAddress string) string {
+	if assetType == Token {
+		return contractAddress
+	}
+
+	return string(Coin)
 }
@@ -0,0 +1,93 @@
+package wallet_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/oxygenpay/oxygen/internal/kms/audit"
+	"github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_AuditLog(t *testing.T) {
+	ctx := audit.WithCaller(context.Background(), "10.0.0.1")
+	logger := zerolog.Nop()
+	db := openStore(t)
+
+	repo := wallet.NewRepository(db, nil)
+	auditLog := audit.New(db, []byte("audit-test-key"))
+
+	generator := wallet.NewGenerator().
+		AddProvider(&wallet.EthProvider{Blockchain: wallet.ETH, CryptoReader: strings.NewReader(strings.Repeat("a", 128))})
+
+	policy := wallet.NewPolicy(wallet.PolicyConfig{
+		Enabled:           true,
+		AllowedRecipients: map[string][]string{"ETH": {merchantAddress}},
+		Fees:              []wallet.PolicyFee{{Blockchain: "ETH", MaxGasPrice: "100000000000"}},
+	}, repo, &logger)
+
+	service := wallet.New(repo, generator, nil, policy, auditLog, &logger)
+
+	w, err := service.CreateWallet(ctx, wallet.ETH)
+	require.NoError(t, err)
+
+	params := wallet.EthTransactionParams{
+		Type:                 wallet.Coin,
+		Recipient:            merchantAddress,
+		Amount:               "1000",
+		NetworkID:            1,
+		MaxPriorityFeePerGas: "1000000000",
+		MaxFeePerGas:         "24000000000",
+		Gas:                  21000,
+	}
+
+	raw, err := service.CreateEthereumTransaction(ctx, w, params)
+	require.NoError(t, err)
+
+	params.Recipient = "0x0000000000000000000000000000000000000001"
+	_, err = service.CreateEthereumTransaction(ctx, w, params)
+	require.ErrorIs(t, err, wallet.ErrPolicyViolation)
+
+	params.Recipient = merchantAddress
+	params.MaxFeePerGas = "500000000000"
+	_, err = service.CreateEthereumTransaction(ctx, w, params)
+	require.ErrorIs(t, err, wallet.ErrPolicyViolation)
+
+	require.NoError(t, service.DeleteWallet(ctx, w.UUID))
+
+	// ASSERT
+	entries, err := auditLog.List(0, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 5)
+
+	assert.Equal(t, audit.CreateWallet, entries[0].Operation)
+	assert.Equal(t, w.UUID.String(), entries[0].WalletID)
+	assert.Equal(t, "10.0.0.1", entries[0].Caller)
+
+	rawBytes, err := hexutil.Decode(raw)
+	require.NoError(t, err)
+
+	assert.Equal(t, audit.SignTransaction, entries[1].Operation)
+	assert.Equal(t, audit.Success, entries[1].Result)
+	assert.Equal(t, merchantAddress, entries[1].Recipient)
+	assert.Equal(t, "1000", entries[1].Amount)
+	assert.Equal(t, crypto.Keccak256Hash(rawBytes).Hex(), entries[1].TransactionHash)
+
+	assert.Equal(t, audit.Denied, entries[2].Result)
+	assert.Empty(t, entries[2].TransactionHash)
+	assert.NotEmpty(t, entries[2].Error)
+
+	assert.Equal(t, audit.Denied, entries[3].Result)
+	assert.Contains(t, entries[3].Error, "gas price")
+
+	assert.Equal(t, audit.DeleteWallet, entries[4].Operation)
+
+	result, err := auditLog.Verify("")
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+}
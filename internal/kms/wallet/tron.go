@@ -10,7 +10,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/btcsuite/btcutil/base58"
+	"github.com/btcsuite/btcd/btcutil/base58"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -37,6 +37,25 @@ type TronTransactionParams struct {
 	IsTest          bool
 }
 
+// TronResourceOperation staking operation that manages energy & bandwidth of TRON accounts.
+type TronResourceOperation string
+
+const (
+	TronFreeze     TronResourceOperation = "freeze"
+	TronDelegate   TronResourceOperation = "delegate"
+	TronUndelegate TronResourceOperation = "undelegate"
+)
+
+// TronResourceParams params of staking transaction. Amount is in SUN. Receiver
+// is required only for delegate & undelegate operations.
+type TronResourceParams struct {
+	Operation TronResourceOperation
+	Resource  trongrid.Resource
+	Amount    int64
+	Receiver  string
+	IsTest    bool
+}
+
 var tronAddressRegex = regexp.MustCompile("^T[a-zA-HJ-NP-Z0-9]{33}$")
 
 func (p *TronProvider) Generate() *Wallet {
@@ -45,6 +64,15 @@ func (p *TronProvider) Generate() *Wallet {
 		return &Wallet{}
 	}
 
+	w := tronWalletFromKey(key)
+	w.UUID = uuid.New()
+	w.CreatedAt = time.Now()
+	w.Blockchain = p.Blockchain
+
+	return w
+}
+
+func tronWalletFromKey(key *ecdsa.PrivateKey) *Wallet {
 	// https://developers.tron.network/docs/account#account-address-format
 	// This part is the same as ETH address generation.
 	privateKey := hexutil.Encode(crypto.FromECDSA(key))
@@ -55,9 +83,6 @@ func (p *TronProvider) Generate() *Wallet {
 	addressBase58String := util.TronHexToBase58(addressHexString)
 
 	return &Wallet{
-		UUID:       uuid.New(),
-		CreatedAt:  time.Now(),
-		Blockchain: p.Blockchain,
 		Address:    addressBase58String,
 		PublicKey:  publicKey,
 		PrivateKey: privateKey,
@@ -111,6 +136,28 @@ func (p TronTransactionParams) validate() error {
 	return nil
 }
 
+func (p TronResourceParams) validate() error {
+	switch p.Operation {
+	case TronFreeze:
+	case TronDelegate, TronUndelegate:
+		if !validateTronAddress(p.Receiver) {
+			return errors.Wrap(ErrInvalidAddress, "receiver is invalid")
+		}
+	default:
+		return errors.New("operation is invalid")
+	}
+
+	if p.Resource != trongrid.ResourceEnergy && p.Resource != trongrid.ResourceBandwidth {
+		return errors.New("resource is invalid")
+	}
+
+	if p.Amount <= 0 {
+		return ErrInvalidAmount
+	}
+
+	return nil
+}
+
 // NewTransaction create new trx / trc20 transaction.
 // see https://developers.tron.network/docs/tron-protocol-transaction.
 func (p *TronProvider) NewTransaction(
@@ -141,6 +188,74 @@ func (p *TronProvider) NewTransaction(
 	}
 }
 
+// NewResourceTransaction creates staking transaction: freezes TRX for resources or
+// delegates (reclaims) resources obtained by staking to (from) another account.
+// see https://developers.tron.network/docs/stake-2-0-introduction
+func (p *TronProvider) NewResourceTransaction(
+	ctx context.Context,
+	wallet *Wallet,
+	params TronResourceParams,
+) (TronTransaction, error) {
+	if wallet.Blockchain != p.Blockchain {
+		return TronTransaction{}, errors.Wrapf(
+			ErrUnknownBlockchain,
+			"This wallet (%s) doesn't support transactions for %s",
+			wallet.Blockchain,
+			p.Blockchain,
+		)
+	}
+
+	if err := params.validate(); err != nil {
+		return TronTransaction{}, err
+	}
+
+	var (
+		tx  TronTransaction
+		err error
+	)
+
+	switch params.Operation {
+	case TronFreeze:
+		tx, err = p.Trongrid.FreezeBalance(ctx, trongrid.FreezeBalanceRequest{
+			OwnerAddress:  wallet.Address,
+			FrozenBalance: params.Amount,
+			Resource:      params.Resource,
+			Visible:       true,
+		}, params.IsTest)
+	case TronDelegate, TronUndelegate:
+		req := trongrid.DelegateResourceRequest{
+			OwnerAddress:    wallet.Address,
+			ReceiverAddress: params.Receiver,
+			Balance:         params.Amount,
+			Resource:        params.Resource,
+			Visible:         true,
+		}
+
+		if params.Operation == TronDelegate {
+			tx, err = p.Trongrid.DelegateResource(ctx, req, params.IsTest)
+		} else {
+			tx, err = p.Trongrid.UnDelegateResource(ctx, req, params.IsTest)
+		}
+	}
+
+	switch {
+	case errors.Is(err, trongrid.ErrResponse):
+		if strings.Contains(err.Error(), "balance") {
+			return TronTransaction{}, ErrInsufficientBalance
+		}
+
+		return TronTransaction{}, errors.Wrap(ErrTronResponse, err.Error())
+	case err != nil:
+		return TronTransaction{}, err
+	}
+
+	if err := p.sign(&tx, wallet); err != nil {
+		return TronTransaction{}, errors.Wrap(err, "unable to sign tx")
+	}
+
+	return tx, nil
+}
+
 func (p *TronProvider) newCoinTransaction(
 	ctx context.Context,
 	wallet *Wallet,
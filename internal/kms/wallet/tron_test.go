@@ -276,3 +276,134 @@ func TestTronProvider_NewTransaction(t *testing.T) {
 		})
 	}
 }
+
+func TestTronProvider_NewResourceTransaction(t *testing.T) {
+	// ARRANGE
+	// Given some constants
+	const (
+		addressReceiver = "TTYxentT3sf8XHbtHGyWX2uDgdadE9uYSL"
+		rawDataHex      = "a9059cbb"
+	)
+
+	// And mocked trongrid provider
+	provider, trongridMock := fakes.NewTrongrid(util.Ptr(zerolog.Nop()))
+
+	// And TronProvider
+	p := &wallet.TronProvider{
+		Blockchain:   wallet.TRON,
+		CryptoReader: &fakeReader{},
+		Trongrid:     provider,
+	}
+
+	// And generated wallet
+	w := p.Generate()
+
+	mockTX := trongrid.Transaction{TxID: "abc123", RawDataHex: rawDataHex, Visible: true}
+
+	for testCaseIndex, testCase := range []struct {
+		req   wallet.TronResourceParams
+		error error
+		setup func(tg *fakes.Trongrid, req wallet.TronResourceParams)
+	}{
+		// Success freeze
+		{
+			req: wallet.TronResourceParams{
+				Operation: wallet.TronFreeze,
+				Resource:  trongrid.ResourceEnergy,
+				Amount:    100_000_000,
+			},
+			setup: func(tg *fakes.Trongrid, req wallet.TronResourceParams) {
+				tg.SetupFreezeBalance(trongrid.FreezeBalanceRequest{
+					OwnerAddress:  w.Address,
+					FrozenBalance: req.Amount,
+					Resource:      trongrid.ResourceEnergy,
+					Visible:       true,
+				}, mockTX)
+			},
+		},
+		// Success delegate
+		{
+			req: wallet.TronResourceParams{
+				Operation: wallet.TronDelegate,
+				Resource:  trongrid.ResourceEnergy,
+				Amount:    50_000_000,
+				Receiver:  addressReceiver,
+			},
+			setup: func(tg *fakes.Trongrid, req wallet.TronResourceParams) {
+				tg.SetupDelegateResource(trongrid.DelegateResourceRequest{
+					OwnerAddress:    w.Address,
+					ReceiverAddress: req.Receiver,
+					Balance:         req.Amount,
+					Resource:        trongrid.ResourceEnergy,
+					Visible:         true,
+				}, false, mockTX)
+			},
+		},
+		// Success undelegate
+		{
+			req: wallet.TronResourceParams{
+				Operation: wallet.TronUndelegate,
+				Resource:  trongrid.ResourceEnergy,
+				Amount:    50_000_000,
+				Receiver:  addressReceiver,
+			},
+			setup: func(tg *fakes.Trongrid, req wallet.TronResourceParams) {
+				tg.SetupDelegateResource(trongrid.DelegateResourceRequest{
+					OwnerAddress:    w.Address,
+					ReceiverAddress: req.Receiver,
+					Balance:         req.Amount,
+					Resource:        trongrid.ResourceEnergy,
+					Visible:         true,
+				}, true, mockTX)
+			},
+		},
+		// Trongrid error
+		{
+			req: wallet.TronResourceParams{
+				Operation: wallet.TronFreeze,
+				Resource:  trongrid.ResourceBandwidth,
+				Amount:    123,
+			},
+			error: wallet.ErrTronResponse,
+		},
+		// Validation errors
+		{
+			req: wallet.TronResourceParams{
+				Operation: wallet.TronDelegate,
+				Resource:  trongrid.ResourceEnergy,
+				Amount:    50_000_000,
+				Receiver:  "abc",
+			},
+			error: wallet.ErrInvalidAddress,
+		},
+		{
+			req: wallet.TronResourceParams{
+				Operation: wallet.TronFreeze,
+				Resource:  trongrid.ResourceEnergy,
+				Amount:    0,
+			},
+			error: wallet.ErrInvalidAmount,
+		},
+	} {
+		t.Run(strconv.Itoa(testCaseIndex), func(t *testing.T) {
+			// ARRANGE
+			if testCase.setup != nil {
+				testCase.setup(trongridMock, testCase.req)
+			}
+
+			// ACT
+			tx, err := p.NewResourceTransaction(context.Background(), w, testCase.req)
+
+			// ASSERT
+			if testCase.error != nil {
+				assert.ErrorIs(t, err, testCase.error)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, mockTX.TxID, tx.TxID)
+			assert.Len(t, tx.Signature, 1)
+			assert.NotEmpty(t, tx.Signature[0])
+		})
+	}
+}
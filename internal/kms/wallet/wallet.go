@@ -36,6 +36,18 @@ type Wallet struct {
 	CreatedAt  time.Time  `json:"created_at"`
 	DeletedAt  *time.Time `json:"deleted_at"`
 	Blockchain Blockchain `json:"blockchain"`
+
+	// DerivationPath BIP-44 path of HD wallet. Keys of such wallet are not persisted
+	// but derived from the seed. Empty for legacy wallets generated from random keys.
+	DerivationPath string `json:"derivation_path,omitempty"`
+
+	// PendingApproval imported wallet that signing policy doesn't treat as own wallet
+	// until the KMS operator approves it (see kms-approve-wallet).
+	PendingApproval bool `json:"pending_approval,omitempty"`
+}
+
+func (w *Wallet) IsHD() bool {
+	return w.DerivationPath != ""
 }
 
 func (b Blockchain) IsValid() bool {
@@ -13,9 +13,14 @@ import (
 	"github.com/oxygenpay/oxygen/internal/db/repository"
 	"github.com/oxygenpay/oxygen/internal/lock"
 	"github.com/oxygenpay/oxygen/internal/log"
+	"github.com/oxygenpay/oxygen/internal/provider/notify"
+	"github.com/oxygenpay/oxygen/internal/provider/rates"
+	"github.com/oxygenpay/oxygen/internal/provider/rpcpool"
 	"github.com/oxygenpay/oxygen/internal/provider/tatum"
 	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
+	"github.com/oxygenpay/oxygen/internal/scanner"
 	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/exchange"
 	"github.com/oxygenpay/oxygen/internal/service/merchant"
 	"github.com/oxygenpay/oxygen/internal/service/payment"
 	"github.com/oxygenpay/oxygen/internal/service/processing"
@@ -46,6 +51,9 @@ type Locator struct {
 	// Provides
 	tatumProvider    *tatum.Provider
 	trongridProvider *trongrid.Provider
+	rpcPool          *rpcpool.Pool
+	ratesProvider    rates.Provider
+	notifiers        *notify.Registry
 
 	// Clients
 	kmsClient *client.KMSInternalAPI
@@ -61,8 +69,10 @@ type Locator struct {
 	transactionService *transaction.Service
 	paymentService     *payment.Service
 	walletService      *wallet.Service
+	exchangeService    *exchange.Service
 	processingService  *processing.Service
 	jobLogger          *log.JobLogger
+	scanner            *scanner.Scanner
 }
 
 func New(ctx context.Context, cfg *config.Config, logger *zerolog.Logger) *Locator {
@@ -137,19 +147,63 @@ func (loc *Locator) TatumProvider() *tatum.Provider {
 
 func (loc *Locator) TrongridProvider() *trongrid.Provider {
 	loc.init("provider.trongrid", func() {
-		loc.trongridProvider = trongrid.New(loc.config.Providers.Trongrid, loc.logger)
+		loc.trongridProvider = trongrid.New(
+			loc.config.Providers.Trongrid,
+			loc.logger,
+			trongrid.WithTransport(loc.RPCPool().Transport()),
+		)
 	})
 
 	return loc.trongridProvider
 }
 
+func (loc *Locator) RPCPool() *rpcpool.Pool {
+	loc.init("provider.rpc", func() {
+		loc.rpcPool = rpcpool.New(
+			loc.config.Providers.RPC,
+			loc.TatumProvider(),
+			loc.config.Providers.Trongrid,
+			loc.logger,
+		)
+	})
+
+	return loc.rpcPool
+}
+
+func (loc *Locator) RatesProvider() rates.Provider {
+	loc.init("provider.rates", func() {
+		provider, err := rates.New(loc.config.Providers.Rates, rates.NewTatum(loc.TatumProvider()), loc.logger)
+		if err != nil {
+			loc.logger.Fatal().Err(err).Msg("unable to setup exchange rate providers")
+		}
+
+		loc.ratesProvider = exchange.NewRecorder(provider, loc.Repository())
+	})
+
+	return loc.ratesProvider
+}
+
+func (loc *Locator) NotifyProviders() *notify.Registry {
+	loc.init("provider.notify", func() {
+		cfg := loc.config.Providers.Notify
+
+		loc.notifiers = notify.NewRegistry(
+			notify.NewAlchemy(cfg.Alchemy, loc.logger),
+			notify.NewQuickNode(cfg.QuickNode),
+			notify.NewMoralis(cfg.Moralis, loc.logger),
+		)
+	})
+
+	return loc.notifiers
+}
+
 func (loc *Locator) KMSClient() *client.KMSInternalAPI {
 	loc.init("client.kms", func() {
-		kms := client.NewHTTPClientWithConfig(strfmt.Default, &client.TransportConfig{
+		kms := client.NewHTTPClientWithSecret(strfmt.Default, &client.TransportConfig{
 			Host:     loc.config.Providers.KmsClient.Host,
 			BasePath: loc.config.Providers.KmsClient.BasePath,
 			Schemes:  loc.config.Providers.KmsClient.Schemes,
-		})
+		}, loc.config.Providers.KmsClient.AuthSecret)
 
 		// transport wrapper
 		kms.SetTransport(log.ClientTransport(kms.Transport))
@@ -176,10 +230,13 @@ func (loc *Locator) BlockchainService() *blockchain.Service {
 		}
 
 		loc.blockchainService = blockchain.New(
+			loc.config.Oxygen.Blockchain,
 			currencies,
 			blockchain.Providers{
 				Tatum:    loc.TatumProvider(),
 				Trongrid: loc.TrongridProvider(),
+				RPC:      loc.RPCPool(),
+				Rates:    loc.RatesProvider(),
 			},
 			true,
 			loc.logger,
@@ -242,6 +299,7 @@ func (loc *Locator) PaymentService() *payment.Service {
 			loc.TransactionService(),
 			loc.MerchantService(),
 			loc.WalletService(),
+			loc.ExchangeService(),
 			loc.BlockchainService(),
 			loc.EventBus(),
 			loc.logger,
@@ -259,6 +317,14 @@ func (loc *Locator) WalletService() *wallet.Service {
 	return loc.walletService
 }
 
+func (loc *Locator) ExchangeService() *exchange.Service {
+	loc.init("service.exchange", func() {
+		loc.exchangeService = exchange.New(loc.Repository(), loc.WalletService(), loc.logger)
+	})
+
+	return loc.exchangeService
+}
+
 func (loc *Locator) ProcessingService() *processing.Service {
 	loc.init("service.processing", func() {
 		loc.processingService = processing.New(
@@ -269,6 +335,7 @@ func (loc *Locator) ProcessingService() *processing.Service {
 			loc.TransactionService(),
 			loc.BlockchainService(),
 			loc.TatumProvider(),
+			loc.NotifyProviders(),
 			loc.EventBus(),
 			loc.Locker(),
 			loc.logger,
@@ -278,6 +345,24 @@ func (loc *Locator) ProcessingService() *processing.Service {
 	return loc.processingService
 }
 
+func (loc *Locator) Scanner() *scanner.Scanner {
+	loc.init("scanner", func() {
+		loc.scanner = scanner.New(
+			loc.config.Oxygen.Scanner,
+			loc.config.Oxygen.Processing.BlockchainsByIncomingProvider(processing.IncomingProviderScanner),
+			loc.WalletService(),
+			loc.BlockchainService(),
+			loc.RegistryService(),
+			loc.ProcessingService(),
+			loc.RPCPool(),
+			loc.TrongridProvider(),
+			loc.logger,
+		)
+	})
+
+	return loc.scanner
+}
+
 func (loc *Locator) JobLogger() *log.JobLogger {
 	loc.init("service.jogLogger", func() {
 		loc.jobLogger = log.NewJobLogger(loc.Store())
@@ -0,0 +1,227 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type AlchemyConfig struct {
+	AuthToken   string            `yaml:"auth_token" env:"ALCHEMY_AUTH_TOKEN" env-description:"Alchemy Notify auth token. Used for adding addresses to webhooks"`
+	SigningKeys []string          `yaml:"signing_keys" env:"ALCHEMY_SIGNING_KEYS" env-description:"Alchemy webhooks signing keys (comma-separated)"`
+	Webhooks    map[string]string `yaml:"webhooks" env:"ALCHEMY_WEBHOOKS" env-description:"Alchemy webhook ids per network. Example: 'ETH:wh_1,ETH_TEST:wh_2'"`
+}
+
+// AlchemyProvider handles Alchemy Notify "Address Activity" webhooks.
+// See https://docs.alchemy.com/reference/address-activity-webhook
+type AlchemyProvider struct {
+	config AlchemyConfig
+	client http.Client
+	logger *zerolog.Logger
+}
+
+// alchemyNetworks maps Alchemy network names to chain ids.
+var alchemyNetworks = map[string]string{
+	"ETH_MAINNET":   "1",
+	"ETH_GOERLI":    "5",
+	"ETH_SEPOLIA":   "11155111",
+	"MATIC_MAINNET": "137",
+	"MATIC_MUMBAI":  "80001",
+	"BNB_MAINNET":   "56",
+	"BNB_TESTNET":   "97",
+}
+
+const (
+	alchemyBaseURL         = "https://dashboard.alchemy.com"
+	headerAlchemySignature = "X-Alchemy-Signature"
+	headerAlchemyToken     = "X-Alchemy-Token"
+)
+
+func NewAlchemy(config AlchemyConfig, logger *zerolog.Logger) *AlchemyProvider {
+	log := logger.With().Str("channel", "alchemy_provider").Logger()
+
+	return &AlchemyProvider{
+		config: config,
+		client: http.Client{Timeout: time.Second * 5},
+		logger: &log,
+	}
+}
+
+func (p *AlchemyProvider) Name() string {
+	return Alchemy
+}
+
+// VerifySignature validates HMAC-SHA256 signature of the body. As each Alchemy webhook has its own
+// signing key, signature is checked against all configured keys.
+func (p *AlchemyProvider) VerifySignature(header http.Header, body []byte) error {
+	if len(p.config.SigningKeys) == 0 {
+		return errors.Wrap(ErrNotConfigured, "signing keys are empty")
+	}
+
+	signature := header.Get(headerAlchemySignature)
+
+	for _, key := range p.config.SigningKeys {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write(body)
+
+		if equalSignatures(hex.EncodeToString(mac.Sum(nil)), signature) {
+			return nil
+		}
+	}
+
+	return ErrSignature
+}
+
+//	{
+//	  "webhookId": "wh_octjglnywaupz6th",
+//	  "id": "whevt_ogrc5v64myey69ux",
+//	  "type": "ADDRESS_ACTIVITY",
+//	  "event": {
+//	    "network": "ETH_MAINNET",
+//	    "activity": [
+//	      {
+//	        "blockNum": "0xdf34a3",
+//	        "hash": "0x7a4a39da2a3fa1fc2ef88fd1eaea070286ed2aba21e0419dcfb6d5c5d9f02a72",
+//	        "fromAddress": "0x503828976d22510aad0201ac7ec88293211d23da",
+//	        "toAddress": "0xbe3f4b43db5eb49d1f48f53443b9abce45da3b79",
+//	        "value": 293.092129,
+//	        "asset": "USDC",
+//	        "category": "token",
+//	        "rawContract": {
+//	          "rawValue": "0x0000000000000000000000000000000000000000000000000000000011783b21",
+//	          "address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+//	          "decimals": 6
+//	        }
+//	      }
+//	    ]
+//	  }
+//	}
+type alchemyWebhook struct {
+	Type  string `json:"type"`
+	Event struct {
+		Network  string `json:"network"`
+		Activity []struct {
+			BlockNum    string `json:"blockNum"`
+			Hash        string `json:"hash"`
+			FromAddress string `json:"fromAddress"`
+			ToAddress   string `json:"toAddress"`
+			Category    string `json:"category"`
+			RawContract struct {
+				RawValue string `json:"rawValue"`
+				Address  string `json:"address"`
+			} `json:"rawContract"`
+		} `json:"activity"`
+	} `json:"event"`
+}
+
+func (p *AlchemyProvider) Parse(body []byte) ([]Notification, error) {
+	var wh alchemyWebhook
+	if err := json.Unmarshal(body, &wh); err != nil {
+		return nil, errors.Wrap(ErrParse, err.Error())
+	}
+
+	if wh.Type != "ADDRESS_ACTIVITY" {
+		return nil, nil
+	}
+
+	notifications := make([]Notification, 0, len(wh.Event.Activity))
+
+	for _, a := range wh.Event.Activity {
+		var contract string
+
+		switch a.Category {
+		case "external", "internal":
+		case "token", "erc20":
+			contract = a.RawContract.Address
+		default:
+			// NFTs and other assets are not supported
+			continue
+		}
+
+		amount, ok := parseBigInt(a.RawContract.RawValue)
+		if !ok {
+			return nil, errors.Wrapf(ErrParse, "invalid value %q of %s", a.RawContract.RawValue, a.Hash)
+		}
+
+		block, _ := parseBigInt(a.BlockNum)
+		if block == nil {
+			block = big.NewInt(0)
+		}
+
+		notifications = append(notifications, Notification{
+			TransactionID:   a.Hash,
+			Sender:          a.FromAddress,
+			Recipient:       a.ToAddress,
+			ContractAddress: contract,
+			Amount:          amount,
+			BlockNumber:     block.Int64(),
+			NetworkID:       alchemyNetworks[wh.Event.Network],
+		})
+	}
+
+	return notifications, nil
+}
+
+// SubscribeAddress adds address to network's webhook.
+// See https://docs.alchemy.com/reference/update-webhook-addresses
+func (p *AlchemyProvider) SubscribeAddress(ctx context.Context, chain money.Blockchain, address string, isTest bool) error {
+	webhookID, ok := lookup(p.config.Webhooks, networkKey(chain, isTest))
+	if !ok || p.config.AuthToken == "" {
+		return errors.Wrapf(ErrNotConfigured, "webhook for %s is not set", networkKey(chain, isTest))
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"webhook_id":          webhookID,
+		"addresses_to_add":    []string{address},
+		"addresses_to_remove": []string{},
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal payload")
+	}
+
+	url := alchemyBaseURL + "/api/update-webhook-addresses"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrap(err, "unable to create request")
+	}
+
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set(headerAlchemyToken, p.config.AuthToken)
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "response error")
+	}
+
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errors.Wrap(err, "unable to read response")
+	}
+
+	p.logger.Info().
+		Str("webhook_id", webhookID).
+		Str("address", address).
+		Int("response_code", res.StatusCode).
+		Str("response", string(resBody)).
+		Msg("UpdateWebhookAddresses response")
+
+	if res.StatusCode != http.StatusOK {
+		return errors.Wrapf(ErrResponse, "got %d response code", res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,227 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type MoralisConfig struct {
+	APIKey  string            `yaml:"api_key" env:"MORALIS_API_KEY" env-description:"Moralis API key. Used both for API calls and webhooks signature verification"`
+	Streams map[string]string `yaml:"streams" env:"MORALIS_STREAMS" env-description:"Moralis stream ids per network. Example: 'ETH:id1,ETH_TEST:id2'"`
+}
+
+// MoralisProvider handles Moralis Streams webhooks.
+// See https://docs.moralis.io/streams-api/evm/webhook-data
+type MoralisProvider struct {
+	config MoralisConfig
+	client http.Client
+	logger *zerolog.Logger
+}
+
+const (
+	moralisBaseURL         = "https://api.moralis-streams.com"
+	headerMoralisSignature = "X-Signature"
+	headerMoralisAPIKey    = "X-API-Key"
+)
+
+func NewMoralis(config MoralisConfig, logger *zerolog.Logger) *MoralisProvider {
+	log := logger.With().Str("channel", "moralis_provider").Logger()
+
+	return &MoralisProvider{
+		config: config,
+		client: http.Client{Timeout: time.Second * 5},
+		logger: &log,
+	}
+}
+
+func (p *MoralisProvider) Name() string {
+	return Moralis
+}
+
+// VerifySignature validates keccak256(body + secret) signature.
+func (p *MoralisProvider) VerifySignature(header http.Header, body []byte) error {
+	if p.config.APIKey == "" {
+		return errors.Wrap(ErrNotConfigured, "api key is empty")
+	}
+
+	hash := crypto.Keccak256Hash(body, []byte(p.config.APIKey))
+
+	if !equalSignatures(hash.Hex(), header.Get(headerMoralisSignature)) {
+		return ErrSignature
+	}
+
+	return nil
+}
+
+//	{
+//	  "confirmed": true,
+//	  "chainId": "0x1",
+//	  "block": { "number": "17034008" },
+//	  "txs": [
+//	    {
+//	      "hash": "0x1a4b...",
+//	      "fromAddress": "0x503828976d22510aad0201ac7ec88293211d23da",
+//	      "toAddress": "0xbe3f4b43db5eb49d1f48f53443b9abce45da3b79",
+//	      "value": "1000000000000000",
+//	      "receiptStatus": "1"
+//	    }
+//	  ],
+//	  "erc20Transfers": [
+//	    {
+//	      "transactionHash": "0x2b5c...",
+//	      "contract": "0xdac17f958d2ee523a2206206994597c13d831ec7",
+//	      "from": "0x503828976d22510aad0201ac7ec88293211d23da",
+//	      "to": "0xbe3f4b43db5eb49d1f48f53443b9abce45da3b79",
+//	      "value": "50000000"
+//	    }
+//	  ]
+//	}
+type moralisWebhook struct {
+	Confirmed bool   `json:"confirmed"`
+	ChainID   string `json:"chainId"`
+	Block     struct {
+		Number string `json:"number"`
+	} `json:"block"`
+	Txs []struct {
+		Hash          string `json:"hash"`
+		FromAddress   string `json:"fromAddress"`
+		ToAddress     string `json:"toAddress"`
+		Value         string `json:"value"`
+		ReceiptStatus string `json:"receiptStatus"`
+	} `json:"txs"`
+	ERC20Transfers []struct {
+		TransactionHash string `json:"transactionHash"`
+		Contract        string `json:"contract"`
+		From            string `json:"from"`
+		To              string `json:"to"`
+		Value           string `json:"value"`
+	} `json:"erc20Transfers"`
+}
+
+// Parse parses webhook. Note that Moralis sends each block twice: when it's mined and when it's confirmed.
+func (p *MoralisProvider) Parse(body []byte) ([]Notification, error) {
+	// test webhook that is sent on stream creation
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+
+	var wh moralisWebhook
+	if err := json.Unmarshal(body, &wh); err != nil {
+		return nil, errors.Wrap(ErrParse, err.Error())
+	}
+
+	var blockNumber int64
+	if block, ok := parseBigInt(wh.Block.Number); ok {
+		blockNumber = block.Int64()
+	}
+
+	var (
+		networkID     = chainIDToNetworkID(wh.ChainID)
+		notifications []Notification
+	)
+
+	for _, tx := range wh.Txs {
+		// skip failed transactions
+		if tx.ReceiptStatus != "" && tx.ReceiptStatus != "1" {
+			continue
+		}
+
+		amount, ok := parseBigInt(tx.Value)
+		if !ok {
+			return nil, errors.Wrapf(ErrParse, "invalid value %q of %s", tx.Value, tx.Hash)
+		}
+
+		// contract call w/o value
+		if amount.Sign() == 0 {
+			continue
+		}
+
+		notifications = append(notifications, Notification{
+			TransactionID: tx.Hash,
+			Sender:        tx.FromAddress,
+			Recipient:     tx.ToAddress,
+			Amount:        amount,
+			BlockNumber:   blockNumber,
+			NetworkID:     networkID,
+			Pending:       !wh.Confirmed,
+		})
+	}
+
+	for _, t := range wh.ERC20Transfers {
+		amount, ok := parseBigInt(t.Value)
+		if !ok {
+			return nil, errors.Wrapf(ErrParse, "invalid value %q of %s", t.Value, t.TransactionHash)
+		}
+
+		notifications = append(notifications, Notification{
+			TransactionID:   t.TransactionHash,
+			Sender:          t.From,
+			Recipient:       t.To,
+			ContractAddress: t.Contract,
+			Amount:          amount,
+			BlockNumber:     blockNumber,
+			NetworkID:       networkID,
+			Pending:         !wh.Confirmed,
+		})
+	}
+
+	return notifications, nil
+}
+
+// SubscribeAddress adds address to network's stream.
+// See https://docs.moralis.io/streams-api/evm/reference/add-address-to-stream
+func (p *MoralisProvider) SubscribeAddress(ctx context.Context, chain money.Blockchain, address string, isTest bool) error {
+	streamID, ok := lookup(p.config.Streams, networkKey(chain, isTest))
+	if !ok || p.config.APIKey == "" {
+		return errors.Wrapf(ErrNotConfigured, "stream for %s is not set", networkKey(chain, isTest))
+	}
+
+	payload, err := json.Marshal(map[string]string{"address": address})
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal payload")
+	}
+
+	url := moralisBaseURL + "/streams/evm/" + streamID + "/address"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrap(err, "unable to create request")
+	}
+
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set(headerMoralisAPIKey, p.config.APIKey)
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "response error")
+	}
+
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errors.Wrap(err, "unable to read response")
+	}
+
+	p.logger.Info().
+		Str("stream_id", streamID).
+		Str("address", address).
+		Int("response_code", res.StatusCode).
+		Str("response", string(resBody)).
+		Msg("AddAddressToStream response")
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		return errors.Wrapf(ErrResponse, "got %d response code", res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+// Package notify contains adapters for third-party providers that send
+// incoming transactions notifications (webhooks) to our addresses.
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/pkg/errors"
+)
+
+type Config struct {
+	Alchemy   AlchemyConfig   `yaml:"alchemy"`
+	QuickNode QuickNodeConfig `yaml:"quicknode"`
+	Moralis   MoralisConfig   `yaml:"moralis"`
+}
+
+// Notification represents provider-neutral transfer notification.
+type Notification struct {
+	TransactionID string
+	Sender        string
+	Recipient     string
+
+	// ContractAddress token contract address. Empty for native coin transfers.
+	ContractAddress string
+
+	// Amount raw amount in minimal units (wei, sun, ...)
+	Amount *big.Int
+
+	BlockNumber int64
+
+	// NetworkID EVM chain id reported by the provider as a decimal string (e.g. "1", "137").
+	// Unlike the webhook URL, it's covered by the signature.
+	NetworkID string
+
+	// Pending transaction is not included in a block yet (or block is not confirmed yet).
+	Pending bool
+}
+
+// Provider represents inbound notifications provider.
+type Provider interface {
+	Name() string
+	VerifySignature(header http.Header, body []byte) error
+	Parse(body []byte) ([]Notification, error)
+}
+
+// AddressSubscriber is implemented by providers that require explicit
+// address registration in order to send notifications.
+type AddressSubscriber interface {
+	SubscribeAddress(ctx context.Context, chain money.Blockchain, address string, isTest bool) error
+}
+
+const (
+	Alchemy   = "alchemy"
+	QuickNode = "quicknode"
+	Moralis   = "moralis"
+)
+
+var (
+	ErrUnknownProvider = errors.New("unknown notifications provider")
+	ErrNotConfigured   = errors.New("notifications provider is not configured")
+	ErrSignature       = errors.New("invalid signature")
+	ErrParse           = errors.New("unable to parse notification")
+	ErrResponse        = errors.New("error response")
+	ErrNetworkMismatch = errors.New("notification network doesn't match webhook network")
+	ErrExpired         = errors.New("notification timestamp is out of allowed window")
+	ErrReplay          = errors.New("notification has already been received")
+)
+
+type Registry struct {
+	providers map[string]Provider
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[string]Provider, len(providers))}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+
+	return r
+}
+
+// Get returns provider by its name.
+func (r *Registry) Get(name string) (Provider, error) {
+	p, ok := r.providers[strings.ToLower(name)]
+	if !ok {
+		return nil, errors.Wrap(ErrUnknownProvider, name)
+	}
+
+	return p, nil
+}
+
+// networkKey returns key of network-specific config maps. Example: "ETH", "ETH_TEST".
+func networkKey(chain money.Blockchain, isTest bool) string {
+	if isTest {
+		return chain.String() + "_TEST"
+	}
+
+	return chain.String()
+}
+
+func lookup(m map[string]string, key string) (string, bool) {
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, v != ""
+		}
+	}
+
+	return "", false
+}
+
+func equalSignatures(expected, actual string) bool {
+	return hmac.Equal([]byte(strings.ToLower(expected)), []byte(strings.ToLower(actual)))
+}
+
+// parseBigInt parses both decimal ("123") and hex ("0x7b") values.
+func parseBigInt(raw string) (*big.Int, bool) {
+	raw = strings.TrimSpace(raw)
+
+	if strings.HasPrefix(raw, "0x") || strings.HasPrefix(raw, "0X") {
+		hex := raw[2:]
+		if hex == "" {
+			return big.NewInt(0), true
+		}
+
+		return new(big.Int).SetString(hex, 16)
+	}
+
+	return new(big.Int).SetString(raw, 10)
+}
+
+// VerifyNetwork ensures that all notifications belong to the network of the webhook URL. As the URL
+// is not signed, a notification of one network (e.g. testnet) could be replayed to another one.
+func VerifyNetwork(notifications []Notification, networkID string) error {
+	for _, n := range notifications {
+		if n.NetworkID == "" || n.NetworkID != networkID {
+			return errors.Wrapf(ErrNetworkMismatch, "got %q, expected %q", n.NetworkID, networkID)
+		}
+	}
+
+	return nil
+}
+
+// chainIDToNetworkID converts hex ("0x89") or decimal ("137") chain id to decimal string.
+func chainIDToNetworkID(raw string) string {
+	id, ok := parseBigInt(raw)
+	if !ok || id.Sign() <= 0 {
+		return ""
+	}
+
+	return id.String()
+}
@@ -0,0 +1,205 @@
+package notify_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/oxygenpay/oxygen/internal/provider/notify"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlchemy(t *testing.T) {
+	logger := zerolog.Nop()
+	p := notify.NewAlchemy(notify.AlchemyConfig{SigningKeys: []string{"key-1", "key-2"}}, &logger)
+
+	body := []byte(`{
+	  "webhookId": "wh_octjglnywaupz6th",
+	  "type": "ADDRESS_ACTIVITY",
+	  "event": {
+		"network": "ETH_MAINNET",
+		"activity": [
+		  {
+			"blockNum": "0xdf34a3",
+			"hash": "0x7a4a",
+			"fromAddress": "0x5038",
+			"toAddress": "0xbe3f",
+			"category": "token",
+			"rawContract": {"rawValue": "0x11783b21", "address": "0xa0b8", "decimals": 6}
+		  },
+		  {
+			"blockNum": "0xdf34a3",
+			"hash": "0x8b5b",
+			"fromAddress": "0x5038",
+			"toAddress": "0xbe3f",
+			"category": "external",
+			"rawContract": {"rawValue": "0x38d7ea4c68000", "decimals": 18}
+		  },
+		  {
+			"blockNum": "0xdf34a3",
+			"hash": "0x9c6c",
+			"category": "erc721",
+			"rawContract": {"rawValue": "0x1", "address": "0xnft"}
+		  }
+		]
+	  }
+	}`)
+
+	t.Run("signature", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-Alchemy-Signature", hmacSHA256("key-2", body))
+		assert.NoError(t, p.VerifySignature(header, body))
+
+		header.Set("X-Alchemy-Signature", hmacSHA256("key-3", body))
+		assert.ErrorIs(t, p.VerifySignature(header, body), notify.ErrSignature)
+	})
+
+	t.Run("parse", func(t *testing.T) {
+		notifications, err := p.Parse(body)
+		require.NoError(t, err)
+		require.Len(t, notifications, 2)
+
+		assert.Equal(t, "0x7a4a", notifications[0].TransactionID)
+		assert.Equal(t, "0xa0b8", notifications[0].ContractAddress)
+		assert.Equal(t, "293092129", notifications[0].Amount.String())
+		assert.Equal(t, int64(14628003), notifications[0].BlockNumber)
+
+		assert.Equal(t, "", notifications[1].ContractAddress)
+		assert.Equal(t, "1000000000000000", notifications[1].Amount.String())
+
+		assert.NoError(t, notify.VerifyNetwork(notifications, "1"))
+		assert.ErrorIs(t, notify.VerifyNetwork(notifications, "5"), notify.ErrNetworkMismatch)
+	})
+}
+
+func TestQuickNode(t *testing.T) {
+	p := notify.NewQuickNode(notify.QuickNodeConfig{SecurityToken: "token"})
+
+	body := []byte(`[{"hash": "0x7a4a", "from": "0x5038", "to": "0xbe3f", "value": "1000", "contract": "", "blockNumber": "0x10", "chainId": "0x89"}]`)
+
+	signedHeader := func(nonce string, timestamp time.Time) http.Header {
+		ts := strconv.FormatInt(timestamp.Unix(), 10)
+
+		header := http.Header{}
+		header.Set("X-QN-Nonce", nonce)
+		header.Set("X-QN-Timestamp", ts)
+		header.Set("X-QN-Signature", hmacSHA256("token", append([]byte(nonce+ts), body...)))
+
+		return header
+	}
+
+	t.Run("signature", func(t *testing.T) {
+		header := signedHeader("nonce-1", time.Now())
+		assert.NoError(t, p.VerifySignature(header, body))
+
+		header = signedHeader("nonce-2", time.Now())
+		header.Set("X-QN-Nonce", "another-nonce")
+		assert.ErrorIs(t, p.VerifySignature(header, body), notify.ErrSignature)
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		header := signedHeader("nonce-3", time.Now())
+
+		assert.NoError(t, p.VerifySignature(header, body))
+		assert.ErrorIs(t, p.VerifySignature(header, body), notify.ErrReplay)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		header := signedHeader("nonce-4", time.Now().Add(-time.Hour))
+		assert.ErrorIs(t, p.VerifySignature(header, body), notify.ErrExpired)
+
+		header = signedHeader("nonce-5", time.Now().Add(time.Hour))
+		assert.ErrorIs(t, p.VerifySignature(header, body), notify.ErrExpired)
+	})
+
+	t.Run("parse", func(t *testing.T) {
+		notifications, err := p.Parse(body)
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+
+		assert.Equal(t, "1000", notifications[0].Amount.String())
+		assert.Equal(t, int64(16), notifications[0].BlockNumber)
+		assert.Equal(t, "137", notifications[0].NetworkID)
+	})
+
+	t.Run("chain id is required", func(t *testing.T) {
+		notifications, err := p.Parse([]byte(`[{"hash": "0x7a4a", "to": "0xbe3f", "value": "1000"}]`))
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, notify.VerifyNetwork(notifications, "137"), notify.ErrNetworkMismatch)
+	})
+}
+
+func TestMoralis(t *testing.T) {
+	logger := zerolog.Nop()
+	p := notify.NewMoralis(notify.MoralisConfig{APIKey: "secret"}, &logger)
+
+	body := []byte(`{
+	  "confirmed": false,
+	  "chainId": "0x1",
+	  "block": {"number": "17034008"},
+	  "txs": [
+		{"hash": "0x1a4b", "fromAddress": "0x5038", "toAddress": "0xbe3f", "value": "1000", "receiptStatus": "1"},
+		{"hash": "0x2c5d", "fromAddress": "0x5038", "toAddress": "0xbe3f", "value": "1000", "receiptStatus": "0"}
+	  ],
+	  "erc20Transfers": [
+		{"transactionHash": "0x3d6e", "contract": "0xdac1", "from": "0x5038", "to": "0xbe3f", "value": "50000000"}
+	  ]
+	}`)
+
+	t.Run("signature", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("X-Signature", crypto.Keccak256Hash(body, []byte("secret")).Hex())
+		assert.NoError(t, p.VerifySignature(header, body))
+
+		header.Set("X-Signature", crypto.Keccak256Hash(body, []byte("another")).Hex())
+		assert.ErrorIs(t, p.VerifySignature(header, body), notify.ErrSignature)
+	})
+
+	t.Run("parse", func(t *testing.T) {
+		notifications, err := p.Parse(body)
+		require.NoError(t, err)
+		require.Len(t, notifications, 2)
+
+		assert.Equal(t, "0x1a4b", notifications[0].TransactionID)
+		assert.True(t, notifications[0].Pending)
+
+		assert.Equal(t, "0xdac1", notifications[1].ContractAddress)
+		assert.Equal(t, int64(17034008), notifications[1].BlockNumber)
+		assert.Equal(t, "1", notifications[1].NetworkID)
+	})
+
+	t.Run("empty test webhook", func(t *testing.T) {
+		notifications, err := p.Parse(nil)
+		assert.NoError(t, err)
+		assert.Empty(t, notifications)
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	r := notify.NewRegistry(notify.NewQuickNode(notify.QuickNodeConfig{}))
+
+	p, err := r.Get("QuickNode")
+	assert.NoError(t, err)
+	assert.Equal(t, notify.QuickNode, p.Name())
+
+	_, err = r.Get("unknown")
+	assert.ErrorIs(t, err, notify.ErrUnknownProvider)
+
+	// provider w/o secret rejects all requests
+	assert.ErrorIs(t, p.VerifySignature(http.Header{}, []byte("{}")), notify.ErrNotConfigured)
+}
+
+func hmacSHA256(key string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,166 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/pkg/errors"
+)
+
+type QuickNodeConfig struct {
+	SecurityToken string `yaml:"security_token" env:"QUICKNODE_SECURITY_TOKEN" env-description:"QuickNode Streams security token"`
+}
+
+// QuickNodeProvider handles QuickNode Streams webhooks. Streams deliver the output of stream's
+// filter function, so the stream should be configured with a filter that matches transfers
+// to our addresses and returns a list of transfers:
+//
+//	[
+//	  {
+//	    "hash": "0x7a4a39da2a3fa1fc2ef88fd1eaea070286ed2aba21e0419dcfb6d5c5d9f02a72",
+//	    "from": "0x503828976d22510aad0201ac7ec88293211d23da",
+//	    "to": "0xbe3f4b43db5eb49d1f48f53443b9abce45da3b79",
+//	    "value": "0x11783b21",
+//	    "contract": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+//	    "blockNumber": "0xdf34a3",
+//	    "chainId": "0x1"
+//	  }
+//	]
+//
+// "contract" should be empty for native coin transfers. Values can be either hex or decimal strings.
+// "chainId" is required as it binds signed payload to the network.
+// See https://www.quicknode.com/docs/streams/filters
+//
+// Seen nonces are kept in memory, so with several replicas the same request can be accepted by each of them
+// within the time window. That's harmless as incoming transactions are processed idempotently.
+type QuickNodeProvider struct {
+	config QuickNodeConfig
+	mu     sync.Mutex
+	nonces *ttlcache.Cache[string, struct{}]
+	now    func() time.Time
+}
+
+const (
+	headerQuickNodeSignature = "X-QN-Signature"
+	headerQuickNodeNonce     = "X-QN-Nonce"
+	headerQuickNodeTimestamp = "X-QN-Timestamp"
+
+	// quickNodeMaxSkew allowed difference between request timestamp and current time.
+	quickNodeMaxSkew = 5 * time.Minute
+)
+
+func NewQuickNode(config QuickNodeConfig) *QuickNodeProvider {
+	nonces := ttlcache.New[string, struct{}](
+		ttlcache.WithTTL[string, struct{}](2*quickNodeMaxSkew),
+		ttlcache.WithDisableTouchOnHit[string, struct{}](),
+	)
+
+	go nonces.Start()
+
+	return &QuickNodeProvider{
+		config: config,
+		nonces: nonces,
+		now:    time.Now,
+	}
+}
+
+func (p *QuickNodeProvider) Name() string {
+	return QuickNode
+}
+
+// VerifySignature validates HMAC-SHA256 signature of nonce + timestamp + body. Requests with
+// stale timestamp or already seen nonce are rejected.
+func (p *QuickNodeProvider) VerifySignature(header http.Header, body []byte) error {
+	if p.config.SecurityToken == "" {
+		return errors.Wrap(ErrNotConfigured, "security token is empty")
+	}
+
+	var (
+		nonce        = header.Get(headerQuickNodeNonce)
+		rawTimestamp = header.Get(headerQuickNodeTimestamp)
+	)
+
+	if nonce == "" || rawTimestamp == "" {
+		return ErrSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(p.config.SecurityToken))
+	mac.Write([]byte(nonce))
+	mac.Write([]byte(rawTimestamp))
+	mac.Write(body)
+
+	if !equalSignatures(hex.EncodeToString(mac.Sum(nil)), header.Get(headerQuickNodeSignature)) {
+		return ErrSignature
+	}
+
+	timestamp, err := strconv.ParseInt(rawTimestamp, 10, 64)
+	if err != nil {
+		return ErrSignature
+	}
+
+	skew := p.now().Sub(time.Unix(timestamp, 0))
+	if skew > quickNodeMaxSkew || skew < -quickNodeMaxSkew {
+		return ErrExpired
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.nonces.Get(nonce) != nil {
+		return ErrReplay
+	}
+
+	p.nonces.Set(nonce, struct{}{}, ttlcache.DefaultTTL)
+
+	return nil
+}
+
+type quickNodeTransfer struct {
+	Hash        string `json:"hash"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Value       string `json:"value"`
+	Contract    string `json:"contract"`
+	BlockNumber string `json:"blockNumber"`
+	ChainID     string `json:"chainId"`
+}
+
+func (p *QuickNodeProvider) Parse(body []byte) ([]Notification, error) {
+	var transfers []quickNodeTransfer
+	if err := json.Unmarshal(body, &transfers); err != nil {
+		return nil, errors.Wrap(ErrParse, err.Error())
+	}
+
+	notifications := make([]Notification, 0, len(transfers))
+
+	for _, t := range transfers {
+		amount, ok := parseBigInt(t.Value)
+		if !ok {
+			return nil, errors.Wrapf(ErrParse, "invalid value %q of %s", t.Value, t.Hash)
+		}
+
+		var blockNumber int64
+		if block, ok := parseBigInt(t.BlockNumber); ok {
+			blockNumber = block.Int64()
+		}
+
+		notifications = append(notifications, Notification{
+			TransactionID:   t.Hash,
+			Sender:          t.From,
+			Recipient:       t.To,
+			ContractAddress: t.Contract,
+			Amount:          amount,
+			BlockNumber:     blockNumber,
+			NetworkID:       chainIDToNetworkID(t.ChainID),
+		})
+	}
+
+	return notifications, nil
+}
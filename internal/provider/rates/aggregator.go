@@ -0,0 +1,191 @@
+package rates
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/errgroup"
+)
+
+// Aggregator requests rates from all providers and returns median of their quotes.
+// Quotes that deviate from the median more than maxDeviation are rejected as outliers.
+// If some providers fail, rate is calculated using the rest of them as long as
+// the majority of configured providers agree on the rate (quorum).
+type Aggregator struct {
+	providers    []Provider
+	maxDeviation float64
+	logger       *zerolog.Logger
+}
+
+func NewAggregator(maxDeviation float64, logger *zerolog.Logger, providers ...Provider) *Aggregator {
+	log := logger.With().Str("channel", "rates_aggregator").Logger()
+
+	return &Aggregator{
+		providers:    providers,
+		maxDeviation: maxDeviation,
+		logger:       &log,
+	}
+}
+
+// New creates aggregator from providers listed in the config.
+func New(cfg Config, tatumProvider Provider, logger *zerolog.Logger) (*Aggregator, error) {
+	providers := make([]Provider, 0, len(cfg.Providers))
+
+	for _, name := range cfg.Providers {
+		switch name {
+		case Tatum:
+			providers = append(providers, tatumProvider)
+		case CoinGecko:
+			providers = append(providers, NewCoinGecko(cfg.CoinGecko))
+		case Binance:
+			providers = append(providers, NewBinance(cfg.Binance))
+		case Kraken:
+			providers = append(providers, NewKraken(cfg.Kraken))
+		case Static:
+			static, err := NewStatic(cfg.Static)
+			if err != nil {
+				return nil, err
+			}
+
+			providers = append(providers, static)
+		default:
+			return nil, errors.Wrap(ErrUnknownProvider, name)
+		}
+	}
+
+	if len(providers) == 0 {
+		return nil, errors.New("at least one exchange rate provider should be configured")
+	}
+
+	return NewAggregator(cfg.MaxDeviation, logger, providers...), nil
+}
+
+func (a *Aggregator) Name() string {
+	return "aggregator"
+}
+
+func (a *Aggregator) GetRate(ctx context.Context, desired, selected string) (Rate, error) {
+	// single provider: nothing to aggregate
+	if len(a.providers) == 1 {
+		return a.providers[0].GetRate(ctx, desired, selected)
+	}
+
+	var (
+		group  errgroup.Group
+		mu     sync.Mutex
+		quotes []Rate
+	)
+
+	for i := range a.providers {
+		provider := a.providers[i]
+		group.Go(func() error {
+			rate, err := provider.GetRate(ctx, desired, selected)
+			if err != nil {
+				if !errors.Is(err, ErrUnsupportedPair) {
+					a.logger.Warn().Err(err).
+						Str("provider", provider.Name()).
+						Str("pair", selected+"/"+desired).
+						Msg("unable to get exchange rate")
+				}
+
+				return nil
+			}
+
+			mu.Lock()
+			quotes = append(quotes, rate)
+			mu.Unlock()
+
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+
+	if len(quotes) == 0 {
+		return Rate{}, errors.Wrapf(ErrNoRates, "%s/%s", selected, desired)
+	}
+
+	accepted := a.rejectOutliers(quotes)
+	if len(accepted) < a.quorum() {
+		a.logger.Error().
+			Str("pair", selected+"/"+desired).
+			Int("providers_count", len(a.providers)).
+			Int("quotes_count", len(quotes)).
+			Int("accepted_count", len(accepted)).
+			Msg("exchange rate providers don't agree")
+
+		return Rate{}, errors.Wrapf(
+			ErrNoRates,
+			"%s/%s: only %d of %d providers agree",
+			selected, desired, len(accepted), len(a.providers),
+		)
+	}
+
+	rate := median(accepted)
+
+	if len(accepted) < len(quotes) {
+		a.logger.Warn().
+			Str("pair", selected+"/"+desired).
+			Int("quotes_count", len(quotes)).
+			Int("accepted_count", len(accepted)).
+			Float64("rate", rate.Value).
+			Msg("rejected outlier exchange rates")
+	}
+
+	return rate, nil
+}
+
+// quorum returns amount of agreeing quotes required for the rate: majority of configured providers.
+func (a *Aggregator) quorum() int {
+	return len(a.providers)/2 + 1
+}
+
+// rejectOutliers removes quotes which deviation from the median exceeds maxDeviation.
+// Might return empty slice if quotes are split into distant groups.
+func (a *Aggregator) rejectOutliers(quotes []Rate) []Rate {
+	if a.maxDeviation <= 0 {
+		return quotes
+	}
+
+	m := median(quotes).Value
+
+	accepted := make([]Rate, 0, len(quotes))
+	for _, q := range quotes {
+		if math.Abs(q.Value-m)/m <= a.maxDeviation {
+			accepted = append(accepted, q)
+		}
+	}
+
+	return accepted
+}
+
+// median returns median rate. For even amount of quotes returns average of two middle ones.
+// Quotes should not be empty.
+func median(quotes []Rate) Rate {
+	sorted := make([]Rate, len(quotes))
+	copy(sorted, quotes)
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Value < sorted[j].Value })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+
+	a, b := sorted[mid-1], sorted[mid]
+
+	at := a.At
+	if b.At.Before(at) {
+		at = b.At
+	}
+
+	return Rate{
+		Value:  (a.Value + b.Value) / 2,
+		At:     at,
+		Source: a.Source + "," + b.Source,
+	}
+}
@@ -0,0 +1,70 @@
+package rates
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+type BinanceConfig struct {
+	BaseURL string `yaml:"base_url" env:"BINANCE_BASE_URL" env-default:"https://api.binance.com" env-description:"Binance API base path"`
+}
+
+// BinanceProvider fetches last price via /ticker/price endpoint.
+// Binance has no USD markets, so USDT is used as a USD proxy.
+type BinanceProvider struct {
+	config BinanceConfig
+	client *http.Client
+}
+
+func NewBinance(cfg BinanceConfig) *BinanceProvider {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.binance.com"
+	}
+
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+
+	return &BinanceProvider{config: cfg, client: newHTTPClient()}
+}
+
+func (p *BinanceProvider) Name() string {
+	return Binance
+}
+
+func (p *BinanceProvider) GetRate(ctx context.Context, desired, selected string) (Rate, error) {
+	base, quote := asset(selected), asset(desired)
+	if quote == "USD" {
+		quote = "USDT"
+	}
+
+	// USDT/USD is 1 by definition of the proxy
+	if base == quote {
+		return Rate{Value: 1, At: time.Now(), Source: Binance}, nil
+	}
+
+	query := url.Values{}
+	query.Set("symbol", base+quote)
+
+	body, err := getJSON(ctx, p.client, p.config.BaseURL+"/api/v3/ticker/price?"+query.Encode(), nil)
+	if err != nil {
+		// {"code":-1121,"msg":"Invalid symbol."}
+		if strings.Contains(err.Error(), "Invalid symbol") {
+			return Rate{}, errors.Wrapf(ErrUnsupportedPair, "%s/%s", selected, desired)
+		}
+
+		return Rate{}, err
+	}
+
+	// {"symbol":"ETHUSDT","price":"1500.12000000"}
+	value := gjson.GetBytes(body, "price").Float()
+	if !validRate(value) {
+		return Rate{}, errors.Wrapf(ErrResponse, "invalid rate %q", string(body))
+	}
+
+	return Rate{Value: value, At: time.Now(), Source: Binance}, nil
+}
@@ -0,0 +1,94 @@
+package rates
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+type CoinGeckoConfig struct {
+	BaseURL string `yaml:"base_url" env:"COINGECKO_BASE_URL" env-default:"https://api.coingecko.com" env-description:"CoinGecko API base path"`
+	APIKey  string `yaml:"api_key" env:"COINGECKO_API_KEY" env-description:"CoinGecko API Key (optional)"`
+}
+
+// CoinGeckoProvider fetches crypto prices via /simple/price endpoint.
+// Fiat to fiat conversion is not supported.
+type CoinGeckoProvider struct {
+	config CoinGeckoConfig
+	client *http.Client
+}
+
+// coinGeckoIDs maps tickers to CoinGecko coin ids.
+var coinGeckoIDs = map[string]string{
+	"ETH":   "ethereum",
+	"MATIC": "matic-network",
+	"BNB":   "binancecoin",
+	"TRX":   "tron",
+	"USDT":  "tether",
+	"USDC":  "usd-coin",
+	"BUSD":  "binance-usd",
+}
+
+//nolint:gosec
+const coinGeckoAPIKeyHeader = "x-cg-demo-api-key"
+
+func NewCoinGecko(cfg CoinGeckoConfig) *CoinGeckoProvider {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.coingecko.com"
+	}
+
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+
+	return &CoinGeckoProvider{config: cfg, client: newHTTPClient()}
+}
+
+func (p *CoinGeckoProvider) Name() string {
+	return CoinGecko
+}
+
+func (p *CoinGeckoProvider) GetRate(ctx context.Context, desired, selected string) (Rate, error) {
+	id, ok := coinGeckoIDs[asset(selected)]
+	if !ok {
+		return Rate{}, errors.Wrapf(ErrUnsupportedPair, "%s/%s", selected, desired)
+	}
+
+	vs := strings.ToLower(desired)
+
+	query := url.Values{}
+	query.Set("ids", id)
+	query.Set("vs_currencies", vs)
+	query.Set("include_last_updated_at", "true")
+
+	var headers map[string]string
+	if p.config.APIKey != "" {
+		headers = map[string]string{coinGeckoAPIKeyHeader: p.config.APIKey}
+	}
+
+	body, err := getJSON(ctx, p.client, p.config.BaseURL+"/api/v3/simple/price?"+query.Encode(), headers)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	// {"ethereum":{"usd":1500.12,"last_updated_at":1687000000}}
+	value := gjson.GetBytes(body, fmt.Sprintf("%s.%s", id, vs))
+	if !value.Exists() {
+		return Rate{}, errors.Wrapf(ErrUnsupportedPair, "%s/%s", selected, desired)
+	}
+
+	if !validRate(value.Float()) {
+		return Rate{}, errors.Wrapf(ErrResponse, "invalid rate %q", value.Raw)
+	}
+
+	at := time.Now()
+	if updatedAt := gjson.GetBytes(body, id+".last_updated_at").Int(); updatedAt > 0 {
+		at = time.Unix(updatedAt, 0)
+	}
+
+	return Rate{Value: value.Float(), At: at, Source: CoinGecko}, nil
+}
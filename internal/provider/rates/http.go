@@ -0,0 +1,44 @@
+package rates
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: requestTimeout}
+}
+
+// getJSON performs GET request and returns response body. Non-2xx responses are treated as errors.
+func getJSON(ctx context.Context, client *http.Client, url string, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create request")
+	}
+
+	req.Header.Set("accept", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to send request")
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response")
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, errors.Wrapf(ErrResponse, "got %d response code: %s", res.StatusCode, string(body))
+	}
+
+	return body, nil
+}
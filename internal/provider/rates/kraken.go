@@ -0,0 +1,69 @@
+package rates
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+type KrakenConfig struct {
+	BaseURL string `yaml:"base_url" env:"KRAKEN_BASE_URL" env-default:"https://api.kraken.com" env-description:"Kraken API base path"`
+}
+
+// KrakenProvider fetches last trade price via public Ticker endpoint.
+type KrakenProvider struct {
+	config KrakenConfig
+	client *http.Client
+}
+
+func NewKraken(cfg KrakenConfig) *KrakenProvider {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.kraken.com"
+	}
+
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+
+	return &KrakenProvider{config: cfg, client: newHTTPClient()}
+}
+
+func (p *KrakenProvider) Name() string {
+	return Kraken
+}
+
+func (p *KrakenProvider) GetRate(ctx context.Context, desired, selected string) (Rate, error) {
+	query := url.Values{}
+	query.Set("pair", asset(selected)+asset(desired))
+
+	body, err := getJSON(ctx, p.client, p.config.BaseURL+"/0/public/Ticker?"+query.Encode(), nil)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	// {"error":["EQuery:Unknown asset pair"]}
+	if errs := gjson.GetBytes(body, "error").Array(); len(errs) > 0 {
+		if strings.Contains(errs[0].String(), "Unknown asset pair") {
+			return Rate{}, errors.Wrapf(ErrUnsupportedPair, "%s/%s", selected, desired)
+		}
+
+		return Rate{}, errors.Wrap(ErrResponse, errs[0].String())
+	}
+
+	// {"error":[],"result":{"XETHZUSD":{"c":["1500.12","0.01"], ...}}}
+	// Pair name in result differs from requested one, so the first entry is taken.
+	var value float64
+	gjson.GetBytes(body, "result").ForEach(func(_, pair gjson.Result) bool {
+		value = pair.Get("c.0").Float()
+		return false
+	})
+
+	if !validRate(value) {
+		return Rate{}, errors.Wrapf(ErrResponse, "invalid rate %q", string(body))
+	}
+
+	return Rate{Value: value, At: time.Now(), Source: Kraken}, nil
+}
@@ -0,0 +1,74 @@
+// Package rates contains adapters for exchange rate providers and
+// aggregator that combines their quotes into a single rate.
+package rates
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type Config struct {
+	Providers    []string          `yaml:"providers" env:"RATES_PROVIDERS" env-default:"tatum" env-description:"Exchange rate providers separated by comma. Available: tatum, coingecko, binance, kraken, static. With several providers the majority of them should agree on the rate"`
+	MaxDeviation float64           `yaml:"max_deviation" env:"RATES_MAX_DEVIATION" env-default:"0.05" env-description:"Max deviation of provider's rate from the median. Rates with bigger deviation are rejected as outliers"`
+	Static       map[string]string `yaml:"static" env:"RATES_STATIC" env-description:"Static exchange rates. Example: 'ETH/USD:1500,USDT/USD:1'"`
+	CoinGecko    CoinGeckoConfig   `yaml:"coingecko"`
+	Binance      BinanceConfig     `yaml:"binance"`
+	Kraken       KrakenConfig      `yaml:"kraken"`
+}
+
+// Rate represents price of 1 unit of selected currency in desired currency.
+type Rate struct {
+	Value  float64
+	At     time.Time
+	Source string
+
+	// ID of the persisted rate. Zero if the rate wasn't stored.
+	ID int64
+}
+
+// Provider represents exchange rate provider.
+type Provider interface {
+	Name() string
+
+	// GetRate returns exchange rate of selected currency in desired currency.
+	// Example: if 1 ETH = $1500, then GetRate(ctx, "USD", "ETH") returns 1500.
+	GetRate(ctx context.Context, desired, selected string) (Rate, error)
+}
+
+const (
+	Tatum     = "tatum"
+	CoinGecko = "coingecko"
+	Binance   = "binance"
+	Kraken    = "kraken"
+	Static    = "static"
+)
+
+var (
+	ErrUnknownProvider = errors.New("unknown exchange rate provider")
+	ErrUnsupportedPair = errors.New("currency pair is not supported")
+	ErrResponse        = errors.New("error response")
+	ErrNoRates         = errors.New("no exchange rates available")
+)
+
+const requestTimeout = 5 * time.Second
+
+// assetAliases maps our tickers to tickers commonly used by exchanges.
+var assetAliases = map[string]string{
+	"TRON": "TRX",
+}
+
+func asset(ticker string) string {
+	ticker = strings.ToUpper(ticker)
+	if alias, ok := assetAliases[ticker]; ok {
+		return alias
+	}
+
+	return ticker
+}
+
+func validRate(value float64) bool {
+	return value > 0
+}
@@ -0,0 +1,216 @@
+package rates_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/oxygenpay/oxygen/internal/provider/rates"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregator(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	static := func(t *testing.T, name string, value float64) rates.Provider {
+		p, err := rates.NewStatic(nil)
+		require.NoError(t, err)
+
+		p.Set("USD", "ETH", value)
+
+		return &namedProvider{Provider: p, name: name}
+	}
+
+	for _, tt := range []struct {
+		name        string
+		providers   []rates.Provider
+		expected    float64
+		expectError bool
+	}{
+		{
+			name:      "single provider",
+			providers: []rates.Provider{static(t, "a", 1500)},
+			expected:  1500,
+		},
+		{
+			name: "median of odd amount",
+			providers: []rates.Provider{
+				static(t, "a", 1500),
+				static(t, "b", 1510),
+				static(t, "c", 1505),
+			},
+			expected: 1505,
+		},
+		{
+			name: "median of even amount",
+			providers: []rates.Provider{
+				static(t, "a", 1500),
+				static(t, "b", 1510),
+			},
+			expected: 1505,
+		},
+		{
+			name: "outlier is rejected",
+			providers: []rates.Provider{
+				static(t, "a", 1500),
+				static(t, "b", 1502),
+				static(t, "c", 1504),
+				static(t, "d", 3000),
+			},
+			expected: 1502,
+		},
+		{
+			name: "failed provider is skipped",
+			providers: []rates.Provider{
+				static(t, "a", 1500),
+				&failingProvider{},
+				static(t, "c", 1510),
+			},
+			expected: 1505,
+		},
+		{
+			name: "two providers disagree",
+			providers: []rates.Provider{
+				static(t, "a", 1500),
+				static(t, "b", 1700),
+			},
+			expectError: true,
+		},
+		{
+			name: "quotes are split into distant groups",
+			providers: []rates.Provider{
+				static(t, "a", 100),
+				static(t, "b", 100),
+				static(t, "c", 112),
+				static(t, "d", 112),
+			},
+			expectError: true,
+		},
+		{
+			name: "single quote is not a quorum",
+			providers: []rates.Provider{
+				static(t, "a", 1500),
+				&failingProvider{},
+				&failingProvider{},
+			},
+			expectError: true,
+		},
+		{
+			name:        "all providers failed",
+			providers:   []rates.Provider{&failingProvider{}, &failingProvider{}},
+			expectError: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// ARRANGE
+			agg := rates.NewAggregator(0.05, &logger, tt.providers...)
+
+			// ACT
+			rate, err := agg.GetRate(ctx, "USD", "ETH")
+
+			// ASSERT
+			if tt.expectError {
+				assert.ErrorIs(t, err, rates.ErrNoRates)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected, rate.Value, 0.0001)
+		})
+	}
+}
+
+func TestProviders(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		switch {
+		case r.URL.Path == "/api/v3/simple/price" && q.Get("ids") == "ethereum" && q.Get("vs_currencies") == "usd":
+			_, _ = w.Write([]byte(`{"ethereum":{"usd":1500.5,"last_updated_at":1687000000}}`))
+		case r.URL.Path == "/api/v3/ticker/price" && q.Get("symbol") == "ETHUSDT":
+			_, _ = w.Write([]byte(`{"symbol":"ETHUSDT","price":"1501.00000000"}`))
+		case r.URL.Path == "/api/v3/ticker/price":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":-1121,"msg":"Invalid symbol."}`))
+		case r.URL.Path == "/0/public/Ticker" && q.Get("pair") == "TRXUSD":
+			_, _ = w.Write([]byte(`{"error":[],"result":{"TRXUSD":{"c":["0.0701","100"]}}}`))
+		case r.URL.Path == "/0/public/Ticker":
+			_, _ = w.Write([]byte(`{"error":["EQuery:Unknown asset pair"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("CoinGecko", func(t *testing.T) {
+		p := rates.NewCoinGecko(rates.CoinGeckoConfig{BaseURL: srv.URL})
+
+		rate, err := p.GetRate(ctx, "USD", "ETH")
+		require.NoError(t, err)
+		assert.Equal(t, 1500.5, rate.Value)
+		assert.Equal(t, int64(1687000000), rate.At.Unix())
+
+		_, err = p.GetRate(ctx, "USD", "EUR")
+		assert.ErrorIs(t, err, rates.ErrUnsupportedPair)
+	})
+
+	t.Run("Binance", func(t *testing.T) {
+		p := rates.NewBinance(rates.BinanceConfig{BaseURL: srv.URL})
+
+		rate, err := p.GetRate(ctx, "USD", "ETH")
+		require.NoError(t, err)
+		assert.Equal(t, 1501.0, rate.Value)
+
+		rate, err = p.GetRate(ctx, "USD", "USDT")
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, rate.Value)
+
+		_, err = p.GetRate(ctx, "EUR", "USD")
+		assert.ErrorIs(t, err, rates.ErrUnsupportedPair)
+	})
+
+	t.Run("Kraken", func(t *testing.T) {
+		p := rates.NewKraken(rates.KrakenConfig{BaseURL: srv.URL})
+
+		rate, err := p.GetRate(ctx, "USD", "TRON")
+		require.NoError(t, err)
+		assert.Equal(t, 0.0701, rate.Value)
+
+		_, err = p.GetRate(ctx, "USD", "BNB")
+		assert.ErrorIs(t, err, rates.ErrUnsupportedPair)
+	})
+
+	t.Run("Static", func(t *testing.T) {
+		p, err := rates.NewStatic(map[string]string{"ETH/USD": "1500"})
+		require.NoError(t, err)
+
+		rate, err := p.GetRate(ctx, "USD", "ETH")
+		require.NoError(t, err)
+		assert.Equal(t, 1500.0, rate.Value)
+
+		_, err = rates.NewStatic(map[string]string{"ETH": "1500"})
+		assert.Error(t, err)
+	})
+}
+
+type namedProvider struct {
+	rates.Provider
+	name string
+}
+
+func (p *namedProvider) Name() string { return p.name }
+
+type failingProvider struct{}
+
+func (p *failingProvider) Name() string { return "failing" }
+
+func (p *failingProvider) GetRate(_ context.Context, _, _ string) (rates.Rate, error) {
+	return rates.Rate{}, errors.New("provider is down")
+}
@@ -0,0 +1,65 @@
+package rates
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// StaticProvider returns predefined rates. Useful for tests and local development.
+type StaticProvider struct {
+	mu    sync.RWMutex
+	rates map[string]float64
+}
+
+// NewStatic creates static provider from map of "SELECTED/DESIRED" -> rate, e.g. "ETH/USD" -> "1500".
+func NewStatic(rates map[string]string) (*StaticProvider, error) {
+	p := &StaticProvider{rates: make(map[string]float64, len(rates))}
+
+	for pair, raw := range rates {
+		selected, desired, ok := strings.Cut(pair, "/")
+		if !ok {
+			return nil, errors.Errorf("invalid static rate pair %q", pair)
+		}
+
+		value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || !validRate(value) {
+			return nil, errors.Errorf("invalid static rate %q for %q", raw, pair)
+		}
+
+		p.Set(desired, selected, value)
+	}
+
+	return p, nil
+}
+
+func (p *StaticProvider) Name() string {
+	return Static
+}
+
+func (p *StaticProvider) Set(desired, selected string, value float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rates[staticKey(desired, selected)] = value
+}
+
+func (p *StaticProvider) GetRate(_ context.Context, desired, selected string) (Rate, error) {
+	p.mu.RLock()
+	value, ok := p.rates[staticKey(desired, selected)]
+	p.mu.RUnlock()
+
+	if !ok {
+		return Rate{}, errors.Wrapf(ErrUnsupportedPair, "%s/%s", selected, desired)
+	}
+
+	return Rate{Value: value, At: time.Now(), Source: Static}, nil
+}
+
+func staticKey(desired, selected string) string {
+	return strings.ToUpper(strings.TrimSpace(selected)) + "/" + strings.ToUpper(strings.TrimSpace(desired))
+}
@@ -0,0 +1,48 @@
+package rates
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/antihax/optional"
+	"github.com/oxygenpay/oxygen/internal/provider/tatum"
+	client "github.com/oxygenpay/tatum-sdk/tatum"
+	"github.com/pkg/errors"
+)
+
+type TatumProvider struct {
+	tatum *tatum.Provider
+}
+
+func NewTatum(tatumProvider *tatum.Provider) *TatumProvider {
+	return &TatumProvider{tatum: tatumProvider}
+}
+
+func (p *TatumProvider) Name() string {
+	return Tatum
+}
+
+func (p *TatumProvider) GetRate(ctx context.Context, desired, selected string) (Rate, error) {
+	opts := &client.ExchangeRateApiGetExchangeRateOpts{BasePair: optional.NewString(desired)}
+
+	res, _, err := p.tatum.Main().ExchangeRateApi.GetExchangeRate(ctx, selected, opts)
+	if err != nil {
+		if errSwagger, ok := err.(client.GenericSwaggerError); ok {
+			return Rate{}, errors.Wrap(ErrResponse, string(errSwagger.Body()))
+		}
+
+		return Rate{}, err
+	}
+
+	value, err := strconv.ParseFloat(res.Value, 64)
+	if err != nil || !validRate(value) {
+		return Rate{}, errors.Wrapf(ErrResponse, "invalid rate %q", res.Value)
+	}
+
+	return Rate{
+		Value:  value,
+		At:     time.UnixMilli(int64(res.Timestamp)),
+		Source: Tatum,
+	}, nil
+}
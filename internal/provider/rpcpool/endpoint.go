@@ -0,0 +1,87 @@
+package rpcpool
+
+import (
+	"math"
+	"net/url"
+	"sync"
+	"time"
+)
+
+type endpoint struct {
+	url     string
+	primary bool
+
+	mu             sync.Mutex
+	latency        time.Duration
+	failures       int64
+	unhealthyUntil time.Time
+	lastError      string
+}
+
+// EndpointStatus represents health of RPC endpoint.
+type EndpointStatus struct {
+	Network   string
+	Endpoint  string
+	Primary   bool
+	Healthy   bool
+	Latency   time.Duration
+	Failures  int64
+	LastError string
+}
+
+// sortLatency treats unknown latency as the worst one.
+func (s EndpointStatus) sortLatency() time.Duration {
+	if s.Latency == 0 {
+		return math.MaxInt64
+	}
+
+	return s.Latency
+}
+
+// name returns endpoint's scheme & host. Path is omitted as it might contain API key.
+func (e *endpoint) name() string {
+	u, err := url.Parse(e.url)
+	if err != nil {
+		return "invalid-url"
+	}
+
+	return u.Scheme + "://" + u.Host
+}
+
+func (e *endpoint) success(latency time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// exponential moving average
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = (e.latency*4 + latency) / 5
+	}
+
+	e.failures = 0
+	e.unhealthyUntil = time.Time{}
+}
+
+func (e *endpoint) failure(reason string, cooldown time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.failures++
+	e.lastError = reason
+	e.unhealthyUntil = time.Now().Add(cooldown)
+}
+
+func (e *endpoint) status(now time.Time) EndpointStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return EndpointStatus{
+		Endpoint:  e.name(),
+		Primary:   e.primary,
+		Healthy:   now.After(e.unhealthyUntil),
+		Latency:   e.latency,
+		Failures:  e.failures,
+		LastError: e.lastError,
+	}
+}
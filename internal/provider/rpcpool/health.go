@@ -0,0 +1,182 @@
+package rpcpool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"golang.org/x/sync/errgroup"
+)
+
+var evmHealthRequest = []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+
+// CheckHealth probes all endpoints (eth_blockNumber for EVM, /wallet/getnowblock for TRON)
+// and updates their health & latency. Endpoint is marked as unhealthy if it returns an error
+// (including JSON-RPC errors) or if its latest block lags behind the best endpoint of the network
+// by more than Config.MaxBlockLag blocks. Endpoints that recovered become available again.
+func (p *Pool) CheckHealth(ctx context.Context) error {
+	var (
+		probes  errgroup.Group
+		mu      sync.Mutex
+		results = make(map[*group][]probeResult)
+	)
+
+	probes.SetLimit(8)
+
+	for _, g := range p.groups {
+		for _, e := range g.endpoints {
+			g, e := g, e
+			probes.Go(func() error {
+				start := time.Now()
+				height, err := p.probe(ctx, g, e)
+
+				mu.Lock()
+				results[g] = append(results[g], probeResult{
+					endpoint: e,
+					height:   height,
+					latency:  time.Since(start),
+					err:      err,
+				})
+				mu.Unlock()
+
+				return nil
+			})
+		}
+	}
+
+	_ = probes.Wait()
+
+	for _, r := range results {
+		p.applyProbes(r)
+	}
+
+	for _, s := range p.Status() {
+		evt := p.logger.Debug()
+		if !s.Healthy {
+			evt = p.logger.Warn()
+		}
+
+		evt.Str("network", s.Network).
+			Str("endpoint", s.Endpoint).
+			Bool("healthy", s.Healthy).
+			Dur("latency", s.Latency).
+			Str("last_error", s.LastError).
+			Msg("rpc endpoint health")
+	}
+
+	return nil
+}
+
+type probeResult struct {
+	endpoint *endpoint
+	height   uint64
+	latency  time.Duration
+	err      error
+}
+
+// applyProbes updates health of network's endpoints. Endpoints that are behind
+// the highest block of the network by more than Config.MaxBlockLag are considered unhealthy.
+func (p *Pool) applyProbes(probes []probeResult) {
+	var best uint64
+	for _, r := range probes {
+		if r.err == nil && r.height > best {
+			best = r.height
+		}
+	}
+
+	for _, r := range probes {
+		switch {
+		case r.err != nil:
+			r.endpoint.failure(r.err.Error(), p.config.Cooldown)
+		case best-r.height > p.config.MaxBlockLag:
+			r.endpoint.failure(fmt.Sprintf("lagging %d blocks behind", best-r.height), p.config.Cooldown)
+		default:
+			r.endpoint.success(r.latency)
+		}
+	}
+}
+
+// Status returns health of all endpoints sorted by network.
+func (p *Pool) Status() []EndpointStatus {
+	now := time.Now()
+
+	var results []EndpointStatus
+	for _, g := range p.groups {
+		for _, e := range g.endpoints {
+			s := e.status(now)
+			s.Network = g.network
+			results = append(results, s)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Network < results[j].Network
+	})
+
+	return results
+}
+
+// probe requests the latest block of the endpoint and returns its height.
+func (p *Pool) probe(ctx context.Context, g *group, e *endpoint) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.config.Timeout)
+	defer cancel()
+
+	target, body := e.url, evmHealthRequest
+	if g.kind == kindTron {
+		target, body = e.url+"/wallet/getnowblock", []byte("{}")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to create request")
+	}
+
+	req.Header.Set("content-type", "application/json")
+	if g.kind == kindTron && e.primary && p.tronAPIKey != "" {
+		req.Header.Set(tronAPIKeyHeader, p.tronAPIKey)
+	}
+
+	res, err := p.transport.base.RoundTrip(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to read response")
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return 0, errors.Errorf("got %d response code", res.StatusCode)
+	}
+
+	if g.kind == kindTron {
+		number := gjson.GetBytes(resBody, "block_header.raw_data.number")
+		if !gjson.GetBytes(resBody, "blockID").Exists() || !number.Exists() {
+			return 0, errors.New("invalid block response")
+		}
+
+		return number.Uint(), nil
+	}
+
+	if rpcErr := gjson.GetBytes(resBody, "error"); rpcErr.Exists() {
+		return 0, errors.Errorf("json-rpc error: %s", rpcErr.Get("message").String())
+	}
+
+	height, err := hexutil.DecodeUint64(gjson.GetBytes(resBody, "result").String())
+	if err != nil {
+		return 0, errors.New("invalid json-rpc response")
+	}
+
+	return height, nil
+}
@@ -0,0 +1,244 @@
+package rpcpool
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/provider/tatum"
+	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type Config struct {
+	Endpoints map[string]string `yaml:"endpoints" env:"RPC_ENDPOINTS" env-description:"Extra RPC endpoints per network separated by space. Tatum (EVM) and Trongrid (TRON) are always used. Example: 'ETH:https://eth.node.com https://eth.llamarpc.com,TRON:https://tron.node.com'"`
+	Cooldown  time.Duration     `yaml:"cooldown" env:"RPC_COOLDOWN" env-default:"30s" env-description:"Time during which failed RPC endpoint is used only as a last resort"`
+	Timeout   time.Duration     `yaml:"timeout" env:"RPC_TIMEOUT" env-default:"10s" env-description:"Timeout of a single RPC request to one endpoint"`
+	// MaxBlockLag amount of blocks endpoint can be behind the best endpoint of the network before it's considered unhealthy.
+	MaxBlockLag uint64 `yaml:"max_block_lag" env:"RPC_MAX_BLOCK_LAG" env-default:"10" env-description:"Amount of blocks RPC endpoint can lag behind the best endpoint of the network before it's marked as unhealthy"`
+}
+
+// Pool routes RPC requests to the healthiest endpoint of the network and fails over
+// to the next one on network errors or 5xx/429 responses.
+// Tatum (EVM) and Trongrid (TRON) endpoints act as primary ones: clients are created
+// with their URLs and transport transparently rewrites requests to selected endpoint.
+type Pool struct {
+	config     Config
+	groups     map[string]*group
+	tronAPIKey string
+	transport  *transport
+	logger     *zerolog.Logger
+}
+
+var ErrUnsupportedNetwork = errors.New("network is not supported by rpc pool")
+
+const (
+	kindEVM  = "evm"
+	kindTron = "tron"
+
+	tronAPIKeyHeader = "TRON-PRO-API-KEY"
+
+	defaultCooldown = 30 * time.Second
+	defaultTimeout  = 10 * time.Second
+)
+
+func New(config Config, tatumProvider *tatum.Provider, trongridConfig trongrid.Config, logger *zerolog.Logger) *Pool {
+	log := logger.With().Str("channel", "rpc_pool").Logger()
+
+	if config.Cooldown <= 0 {
+		config.Cooldown = defaultCooldown
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	p := &Pool{
+		config:     config,
+		groups:     make(map[string]*group),
+		tronAPIKey: trongridConfig.APIKey,
+		logger:     &log,
+	}
+
+	for _, chain := range []money.Blockchain{"ETH", "MATIC", "BSC"} {
+		for _, isTest := range []bool{false, true} {
+			p.addGroup(kindEVM, chain, isTest, tatumProvider.RPCEndpoint(chain, isTest))
+		}
+	}
+
+	p.addGroup(kindTron, "TRON", false, trongridConfig.MainnetBaseURL)
+	p.addGroup(kindTron, "TRON", true, trongridConfig.TestnetBaseURL)
+
+	p.transport = &transport{
+		pool: p,
+		base: http.DefaultTransport,
+	}
+
+	return p
+}
+
+func (p *Pool) addGroup(kind string, chain money.Blockchain, isTest bool, primary string) {
+	network := networkKey(chain, isTest)
+	primary = strings.TrimRight(primary, "/")
+
+	g := &group{
+		network: network,
+		kind:    kind,
+		primary: primary,
+	}
+
+	if primary != "" {
+		g.endpoints = append(g.endpoints, &endpoint{url: primary, primary: true})
+	}
+
+	for bc, raw := range p.config.Endpoints {
+		if !strings.EqualFold(bc, network) {
+			continue
+		}
+
+		for _, u := range strings.Fields(raw) {
+			g.endpoints = append(g.endpoints, &endpoint{url: strings.TrimRight(u, "/")})
+		}
+	}
+
+	if len(g.endpoints) == 0 {
+		return
+	}
+
+	if g.primary == "" {
+		g.primary = g.endpoints[0].url
+		g.endpoints[0].primary = true
+	}
+
+	if kind == kindTron {
+		g.privateHeaders = []string{tronAPIKeyHeader}
+	}
+
+	p.groups[network] = g
+}
+
+// Transport returns http.RoundTripper with failover. Requests to unknown URLs are passed as is.
+func (p *Pool) Transport() http.RoundTripper {
+	return p.transport
+}
+
+// EVM returns JSON-RPC client of EVM-compatible blockchain backed by failover transport.
+func (p *Pool) EVM(ctx context.Context, chain money.Blockchain, isTest bool) (*ethclient.Client, error) {
+	g, ok := p.groups[networkKey(chain, isTest)]
+	if !ok || g.kind != kindEVM {
+		return nil, errors.Wrap(ErrUnsupportedNetwork, networkKey(chain, isTest))
+	}
+
+	c, err := rpc.DialHTTPWithClient(g.primary, &http.Client{Transport: p.transport})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to dial rpc")
+	}
+
+	return ethclient.NewClient(c), nil
+}
+
+// EVMEndpoints returns clients of each network's endpoint (healthiest first) w/o failover.
+// Used for cross-checking data between independent sources.
+func (p *Pool) EVMEndpoints(chain money.Blockchain, isTest bool) ([]*ethclient.Client, error) {
+	g, ok := p.groups[networkKey(chain, isTest)]
+	if !ok || g.kind != kindEVM {
+		return nil, errors.Wrap(ErrUnsupportedNetwork, networkKey(chain, isTest))
+	}
+
+	endpoints := g.candidates(time.Now())
+	clients := make([]*ethclient.Client, 0, len(endpoints))
+
+	for _, e := range endpoints {
+		c, err := rpc.DialHTTPWithClient(e.url, &http.Client{Timeout: p.config.Timeout})
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to dial %s", e.name())
+		}
+
+		clients = append(clients, ethclient.NewClient(c))
+	}
+
+	return clients, nil
+}
+
+// TronEndpoints returns Trongrid-compatible providers of each TRON endpoint (healthiest first) w/o failover.
+func (p *Pool) TronEndpoints(isTest bool) ([]*trongrid.Provider, error) {
+	g, ok := p.groups[networkKey("TRON", isTest)]
+	if !ok {
+		return nil, errors.Wrap(ErrUnsupportedNetwork, networkKey("TRON", isTest))
+	}
+
+	endpoints := g.candidates(time.Now())
+	providers := make([]*trongrid.Provider, 0, len(endpoints))
+
+	for _, e := range endpoints {
+		cfg := trongrid.Config{MainnetBaseURL: e.url, TestnetBaseURL: e.url}
+		if e.primary {
+			cfg.APIKey = p.tronAPIKey
+		}
+
+		providers = append(providers, trongrid.New(cfg, p.logger))
+	}
+
+	return providers, nil
+}
+
+// route finds endpoints group by request url.
+// Returns url part that should be appended to selected endpoint (e.g. "/wallet/getnowblock").
+func (p *Pool) route(url string) (*group, string) {
+	var (
+		match    *group
+		matchLen int
+	)
+
+	for _, g := range p.groups {
+		if strings.HasPrefix(url, g.primary) && len(g.primary) > matchLen {
+			match, matchLen = g, len(g.primary)
+		}
+	}
+
+	if match == nil {
+		return nil, ""
+	}
+
+	return match, url[matchLen:]
+}
+
+type group struct {
+	network        string
+	kind           string
+	primary        string
+	endpoints      []*endpoint
+	privateHeaders []string
+}
+
+// candidates returns endpoints sorted by health and latency.
+// Unhealthy endpoints are kept at the end as a last resort.
+func (g *group) candidates(now time.Time) []*endpoint {
+	endpoints := make([]*endpoint, len(g.endpoints))
+	copy(endpoints, g.endpoints)
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		a, b := endpoints[i].status(now), endpoints[j].status(now)
+		if a.Healthy != b.Healthy {
+			return a.Healthy
+		}
+
+		return a.sortLatency() < b.sortLatency()
+	})
+
+	return endpoints
+}
+
+func networkKey(chain money.Blockchain, isTest bool) string {
+	if isTest {
+		return fmt.Sprintf("%s_TEST", chain)
+	}
+
+	return chain.String()
+}
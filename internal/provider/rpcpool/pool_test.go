@@ -0,0 +1,243 @@
+package rpcpool_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/oxygenpay/oxygen/internal/provider/rpcpool"
+	"github.com/oxygenpay/oxygen/internal/provider/tatum"
+	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool_Transport(t *testing.T) {
+	logger := zerolog.Nop()
+
+	const backupBlock = `{"blockID":"backup","block_header":{"raw_data":{"number":100}}}`
+
+	// Given primary TRON endpoint that is down
+	var primaryDown atomic.Bool
+	primaryDown.Store(true)
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if primaryDown.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"blockID":"primary","block_header":{"raw_data":{"number":100}}}`))
+	}))
+	defer primary.Close()
+
+	// And a backup endpoint
+	var (
+		backupPath   string
+		backupBody   string
+		backupAPIKey string
+	)
+
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/wallet/getblockbynum" {
+			body, _ := io.ReadAll(r.Body)
+
+			backupPath = r.URL.Path
+			backupBody = string(body)
+			backupAPIKey = r.Header.Get("TRON-PRO-API-KEY")
+		}
+
+		_, _ = w.Write([]byte(backupBlock))
+	}))
+	defer backup.Close()
+
+	pool := rpcpool.New(
+		rpcpool.Config{
+			Endpoints: map[string]string{"TRON": backup.URL},
+			Cooldown:  time.Minute,
+			Timeout:   time.Second,
+		},
+		tatum.New(tatum.Config{BasePath: "https://tatum.local"}, nil, &logger),
+		trongrid.Config{MainnetBaseURL: primary.URL, APIKey: "secret"},
+		&logger,
+	)
+
+	client := &http.Client{Transport: pool.Transport()}
+
+	send := func(t *testing.T) string {
+		req, err := http.NewRequestWithContext(
+			context.Background(),
+			http.MethodPost,
+			primary.URL+"/wallet/getblockbynum",
+			strings.NewReader(`{"num":1}`),
+		)
+		require.NoError(t, err)
+		req.Header.Set("TRON-PRO-API-KEY", "secret")
+
+		res, err := client.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		return string(body)
+	}
+
+	t.Run("Fails over to backup endpoint", func(t *testing.T) {
+		// ACT
+		body := send(t)
+
+		// ASSERT
+		assert.Equal(t, backupBlock, body)
+		assert.Equal(t, "/wallet/getblockbynum", backupPath)
+		assert.Equal(t, `{"num":1}`, backupBody)
+
+		// API key of the primary endpoint is not leaked
+		assert.Empty(t, backupAPIKey)
+
+		statuses := statusByEndpoint(pool.Status())
+		assert.False(t, statuses[primary.URL].Healthy)
+		assert.Equal(t, "TRON", statuses[primary.URL].Network)
+		assert.True(t, statuses[backup.URL].Healthy)
+	})
+
+	t.Run("Unhealthy endpoint is skipped during cooldown", func(t *testing.T) {
+		// ARRANGE
+		primaryDown.Store(false)
+
+		// ACT
+		body := send(t)
+
+		// ASSERT
+		assert.Equal(t, backupBlock, body)
+	})
+
+	t.Run("Health check restores endpoint", func(t *testing.T) {
+		// ACT
+		require.NoError(t, pool.CheckHealth(context.Background()))
+
+		// ASSERT
+		statuses := statusByEndpoint(pool.Status())
+		assert.True(t, statuses[primary.URL].Healthy)
+		assert.True(t, statuses[backup.URL].Healthy)
+	})
+
+	t.Run("Unknown url is passed as is", func(t *testing.T) {
+		other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("other"))
+		}))
+		defer other.Close()
+
+		res, err := client.Get(other.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "other", string(body))
+	})
+}
+
+func TestPool_EVM(t *testing.T) {
+	logger := zerolog.Nop()
+
+	// Given a node that serves eth_blockNumber
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+	defer node.Close()
+
+	// And tatum that is down
+	tatumServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer tatumServer.Close()
+
+	pool := rpcpool.New(
+		rpcpool.Config{Endpoints: map[string]string{"ETH": node.URL}},
+		tatum.New(tatum.Config{BasePath: tatumServer.URL, APIKey: "key"}, nil, &logger),
+		trongrid.Config{},
+		&logger,
+	)
+
+	// ACT
+	client, err := pool.EVM(context.Background(), "ETH", false)
+	require.NoError(t, err)
+
+	blockNumber, err := client.BlockNumber(context.Background())
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, uint64(16), blockNumber)
+
+	// And unsupported network returns an error
+	_, err = pool.EVM(context.Background(), "TRON", false)
+	assert.ErrorIs(t, err, rpcpool.ErrUnsupportedNetwork)
+}
+
+func TestPool_CheckHealth(t *testing.T) {
+	logger := zerolog.Nop()
+
+	newNode := func(response string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(response))
+		}))
+	}
+
+	// Given synced tatum node
+	tatumServer := newNode(`{"jsonrpc":"2.0","id":1,"result":"0x64"}`)
+	defer tatumServer.Close()
+
+	// And a node that is slightly behind
+	behind := newNode(`{"jsonrpc":"2.0","id":1,"result":"0x60"}`)
+	defer behind.Close()
+
+	// And a node that lags too much
+	lagging := newNode(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`)
+	defer lagging.Close()
+
+	// And a node that responds with JSON-RPC error
+	broken := newNode(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"header not found"}}`)
+	defer broken.Close()
+
+	pool := rpcpool.New(
+		rpcpool.Config{
+			Endpoints:   map[string]string{"ETH": strings.Join([]string{behind.URL, lagging.URL, broken.URL}, " ")},
+			Timeout:     time.Second,
+			MaxBlockLag: 10,
+		},
+		tatum.New(tatum.Config{BasePath: tatumServer.URL, APIKey: "key"}, nil, &logger),
+		trongrid.Config{},
+		&logger,
+	)
+
+	// ACT
+	require.NoError(t, pool.CheckHealth(context.Background()))
+
+	// ASSERT
+	statuses := statusByEndpoint(pool.Status())
+
+	assert.True(t, statuses[behind.URL].Healthy)
+
+	assert.False(t, statuses[lagging.URL].Healthy)
+	assert.Equal(t, "lagging 84 blocks behind", statuses[lagging.URL].LastError)
+
+	assert.False(t, statuses[broken.URL].Healthy)
+	assert.Contains(t, statuses[broken.URL].LastError, "header not found")
+}
+
+func statusByEndpoint(statuses []rpcpool.EndpointStatus) map[string]rpcpool.EndpointStatus {
+	res := make(map[string]rpcpool.EndpointStatus, len(statuses))
+	for _, s := range statuses {
+		res[s.Endpoint] = s
+	}
+
+	return res
+}
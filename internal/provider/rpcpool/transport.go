@@ -0,0 +1,138 @@
+package rpcpool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type transport struct {
+	pool *Pool
+	base http.RoundTripper
+}
+
+// RoundTrip sends request to the healthiest endpoint of the group. On network error or
+// 5xx/429 response, endpoint is marked as unhealthy and request is retried with the next one.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	g, relative := t.pool.route(req.URL.String())
+	if g == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read request body")
+		}
+
+		body = b
+	}
+
+	var (
+		lastRes *http.Response
+		lastErr error
+	)
+
+	for _, e := range g.candidates(time.Now()) {
+		if lastRes != nil {
+			_ = lastRes.Body.Close()
+			lastRes = nil
+		}
+
+		res, err := t.send(req, g, e, relative, body)
+		if err == nil {
+			return res, nil
+		}
+
+		lastErr = err
+		if res != nil {
+			lastRes = res
+		}
+
+		t.pool.logger.Warn().Err(err).
+			Str("network", g.network).
+			Str("endpoint", e.name()).
+			Msg("rpc endpoint failure")
+
+		if req.Context().Err() != nil {
+			break
+		}
+	}
+
+	if lastRes != nil {
+		return lastRes, nil
+	}
+
+	return nil, lastErr
+}
+
+// send performs request to selected endpoint. Returns both response and error
+// if endpoint responded with failure status code.
+func (t *transport) send(req *http.Request, g *group, e *endpoint, relative string, body []byte) (*http.Response, error) {
+	target, err := url.Parse(e.url + relative)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid endpoint url")
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.pool.config.Timeout)
+
+	r := req.Clone(ctx)
+	r.URL = target
+	r.Host = ""
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+
+	if !e.primary {
+		for _, h := range g.privateHeaders {
+			r.Header.Del(h)
+		}
+	}
+
+	start := time.Now()
+
+	res, err := t.base.RoundTrip(r)
+	if err != nil {
+		cancel()
+		e.failure(err.Error(), t.pool.config.Cooldown)
+
+		return nil, err
+	}
+
+	// cancel request's context only when response body is consumed
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+
+	if isFailureStatus(res.StatusCode) {
+		reason := fmt.Sprintf("got %d response code", res.StatusCode)
+		e.failure(reason, t.pool.config.Cooldown)
+
+		return res, errors.New(reason)
+	}
+
+	e.success(time.Since(start))
+
+	return res, nil
+}
+
+func isFailureStatus(code int) bool {
+	return code >= http.StatusInternalServerError || code == http.StatusTooManyRequests
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
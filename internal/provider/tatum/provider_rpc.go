@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/ethereum/go-ethereum/ethclient"
+	kms "github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/oxygenpay/oxygen/internal/money"
 )
 
 func (p *Provider) EthereumRPC(ctx context.Context, isTest bool) (*ethclient.Client, error) {
@@ -20,6 +22,17 @@ func (p *Provider) BinanceSmartChainRPC(ctx context.Context, isTest bool) (*ethc
 	return ethclient.DialContext(ctx, p.rpcPath("v3/blockchain/node/BSC", isTest))
 }
 
+// RPCEndpoint returns JSON-RPC endpoint of EVM-compatible blockchain.
+// Returns empty string if blockchain is not supported.
+func (p *Provider) RPCEndpoint(chain money.Blockchain, isTest bool) string {
+	switch kms.Blockchain(chain) {
+	case kms.ETH, kms.MATIC, kms.BSC:
+		return p.rpcPath("v3/blockchain/node/"+chain.String(), isTest)
+	}
+
+	return ""
+}
+
 func (p *Provider) rpcPath(path string, isTest bool) string {
 	url := fmt.Sprintf("%s/%s/%s", p.config.BasePath, path, p.config.APIKey)
 	if !isTest {
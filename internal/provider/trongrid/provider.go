@@ -72,19 +72,36 @@ var (
 	ErrNotFound = errors.New("transaction not found")
 )
 
-func New(cfg Config, logger *zerolog.Logger) *Provider {
+type Opt func(p *Provider)
+
+// WithTransport sets custom http transport (e.g. rpc pool with failover).
+// Client timeout is disabled as the transport is expected to handle timeouts of each attempt.
+func WithTransport(rt http.RoundTripper) Opt {
+	return func(p *Provider) {
+		p.client.Transport = rt
+		p.client.Timeout = 0
+	}
+}
+
+func New(cfg Config, logger *zerolog.Logger, opts ...Opt) *Provider {
 	log := logger.With().Str("channel", "trongrid_provider").Logger()
 
 	cfg.MainnetBaseURL = strings.TrimRight(cfg.MainnetBaseURL, "/")
 	cfg.TestnetBaseURL = strings.TrimRight(cfg.TestnetBaseURL, "/")
 
-	return &Provider{
+	p := &Provider{
 		config: cfg,
 		client: http.Client{
 			Timeout: time.Second * 5,
 		},
 		logger: &log,
 	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
 }
 
 // CreateTransaction fcking TRON makes offline tx creation so hard
@@ -258,6 +275,7 @@ type TransactionReceipt struct {
 	Confirmations int64
 	IsConfirmed   bool
 	Success       bool
+	BlockNumber   int64
 }
 
 func (p *Provider) GetTransactionReceipt(
@@ -352,6 +370,7 @@ func (p *Provider) GetTransactionReceipt(
 		Confirmations: confirmations,
 		IsConfirmed:   confirmations >= confirmationBlocks,
 		Success:       success,
+		BlockNumber:   txBlockNumber,
 	}, nil
 }
 
@@ -433,9 +452,9 @@ func (p *Provider) getLatestBlock(ctx context.Context, isTest bool) ([]byte, err
 
 	defer res.Body.Close()
 
-	p.logger.Info().
+	// response contains all block's transactions and is polled by the scanner, so it's not logged
+	p.logger.Debug().
 		Str("url", req.URL.String()).
-		RawJSON("response", body).
 		Int("response_code", res.StatusCode).
 		Msg("GetLatestBlockNumber response")
 
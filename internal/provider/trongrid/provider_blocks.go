@@ -0,0 +1,164 @@
+package trongrid
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// Block represents TRON block with its transactions.
+type Block struct {
+	Number       int64
+	Transactions []BlockTransaction
+}
+
+// BlockTransaction represents the first contract of a TRON transaction.
+// Addresses are in hex format (e.g. 41b35b60a4572e473e492ee35f0750f95c682e081c).
+type BlockTransaction struct {
+	Hash            string
+	Type            string
+	Success         bool
+	OwnerAddress    string
+	ToAddress       string
+	ContractAddress string
+	Amount          int64
+}
+
+// TransactionInfo represents transaction execution result including emitted event logs.
+type TransactionInfo struct {
+	Hash        string           `json:"id"`
+	BlockNumber int64            `json:"blockNumber"`
+	Result      string           `json:"result"`
+	Logs        []TransactionLog `json:"log"`
+}
+
+// TransactionLog represents event log. Address is in hex format without "41" prefix.
+type TransactionLog struct {
+	Address string   `json:"address"`
+	Topics  []string `json:"topics"`
+	Data    string   `json:"data"`
+}
+
+// Failed returns true if smart contract execution failed (e.g. reverted).
+func (info TransactionInfo) Failed() bool {
+	return info.Result == "FAILED"
+}
+
+const (
+	TransferContract           = "TransferContract"
+	TriggerSmartContract       = "TriggerSmartContract"
+	maxBlocksPerRequest  int64 = 100
+)
+
+// GetLatestBlockNumber returns number of the latest solidified (irreversible) block.
+func (p *Provider) GetLatestBlockNumber(ctx context.Context, isTest bool) (int64, error) {
+	res, err := p.getLatestBlock(ctx, isTest)
+	if err != nil {
+		return 0, err
+	}
+
+	number := gjson.GetBytes(res, "block_header.raw_data.number")
+	if !number.Exists() {
+		return 0, errors.Wrap(ErrResponse, "block number is missing")
+	}
+
+	return number.Int(), nil
+}
+
+// ListBlocks returns blocks within [from, to] range.
+func (p *Provider) ListBlocks(ctx context.Context, from, to int64, isTest bool) ([]Block, error) {
+	var blocks []Block
+
+	for start := from; start <= to; start += maxBlocksPerRequest {
+		end := start + maxBlocksPerRequest
+		if end > to+1 {
+			end = to + 1
+		}
+
+		// endNum is exclusive
+		payload := map[string]int64{"startNum": start, "endNum": end}
+
+		body, err := p.post(ctx, "/wallet/getblockbylimitnext", payload, isTest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to get blocks %d-%d", start, end-1)
+		}
+
+		for _, b := range gjson.GetBytes(body, "block").Array() {
+			blocks = append(blocks, parseBlock(b))
+		}
+	}
+
+	return blocks, nil
+}
+
+// ListTransactionsInfo returns execution info of all block's transactions.
+func (p *Provider) ListTransactionsInfo(ctx context.Context, blockNumber int64, isTest bool) ([]TransactionInfo, error) {
+	payload := map[string]int64{"num": blockNumber}
+
+	body, err := p.post(ctx, "/wallet/gettransactioninfobyblocknum", payload, isTest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get transactions info of block %d", blockNumber)
+	}
+
+	var infos []TransactionInfo
+	if err := json.Unmarshal(body, &infos); err != nil {
+		return nil, errors.Wrap(err, "unmarshal error")
+	}
+
+	return infos, nil
+}
+
+func parseBlock(b gjson.Result) Block {
+	block := Block{Number: b.Get("block_header.raw_data.number").Int()}
+
+	for _, tx := range b.Get("transactions").Array() {
+		value := tx.Get("raw_data.contract.0.parameter.value")
+
+		block.Transactions = append(block.Transactions, BlockTransaction{
+			Hash:            tx.Get("txID").String(),
+			Type:            tx.Get("raw_data.contract.0.type").String(),
+			Success:         tx.Get("ret.0.contractRet").String() == "SUCCESS",
+			OwnerAddress:    value.Get("owner_address").String(),
+			ToAddress:       value.Get("to_address").String(),
+			ContractAddress: value.Get("contract_address").String(),
+			Amount:          value.Get("amount").Int(),
+		})
+	}
+
+	return block
+}
+
+func (p *Provider) post(ctx context.Context, path string, payload any, isTest bool) ([]byte, error) {
+	req, err := p.newRequest(ctx, http.MethodPost, path, payload, isTest)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create request")
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "response error")
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response")
+	}
+
+	if res.StatusCode != http.StatusOK {
+		p.logger.Error().
+			Str("url", req.URL.String()).
+			Int("response_code", res.StatusCode).
+			Str("response", string(body)).
+			Msg("unexpected response")
+
+		return nil, errors.Wrapf(ErrResponse, "got %d response code", res.StatusCode)
+	}
+
+	return body, nil
+}
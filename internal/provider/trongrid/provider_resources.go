@@ -0,0 +1,250 @@
+package trongrid
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// Resource TRON account resource that could be obtained by staking TRX.
+type Resource string
+
+const (
+	ResourceEnergy    Resource = "ENERGY"
+	ResourceBandwidth Resource = "BANDWIDTH"
+)
+
+// AccountResources represents /wallet/getaccountresource response. Energy & bandwidth limits
+// include both resources obtained by staking and resources delegated by other accounts.
+//
+//	{
+//	  "freeNetLimit": 600,
+//	  "NetLimit": 12,
+//	  "EnergyLimit": 130000,
+//	  "EnergyUsed": 31895,
+//	  "TotalEnergyLimit": 90000000000,
+//	  "TotalEnergyWeight": 13432314329
+//	}
+type AccountResources struct {
+	FreeNetLimit int64 `json:"freeNetLimit"`
+	FreeNetUsed  int64 `json:"freeNetUsed"`
+	NetLimit     int64 `json:"NetLimit"`
+	NetUsed      int64 `json:"NetUsed"`
+	EnergyLimit  int64 `json:"EnergyLimit"`
+	EnergyUsed   int64 `json:"EnergyUsed"`
+
+	// TotalEnergyLimit & TotalEnergyWeight network-wide values that are used
+	// for converting staked TRX to energy.
+	TotalEnergyLimit  int64 `json:"TotalEnergyLimit"`
+	TotalEnergyWeight int64 `json:"TotalEnergyWeight"`
+}
+
+// AvailableEnergy returns energy that could be spent right away.
+func (r AccountResources) AvailableEnergy() int64 {
+	if available := r.EnergyLimit - r.EnergyUsed; available > 0 {
+		return available
+	}
+
+	return 0
+}
+
+// AvailableBandwidth returns bandwidth (including free daily one) that could be spent right away.
+func (r AccountResources) AvailableBandwidth() int64 {
+	available := r.FreeNetLimit - r.FreeNetUsed + r.NetLimit - r.NetUsed
+	if available > 0 {
+		return available
+	}
+
+	return 0
+}
+
+// EnergyToSun returns amount of staked SUN required to obtain specified energy. Note that
+// the ratio changes over time as it depends on the total amount of TRX staked in the network.
+func (r AccountResources) EnergyToSun(energy int64) (int64, error) {
+	if r.TotalEnergyLimit <= 0 || r.TotalEnergyWeight <= 0 {
+		return 0, errors.Wrap(ErrResponse, "total energy limit & weight are missing")
+	}
+
+	// TotalEnergyWeight is in TRX: sun = ceil(energy * weight * 1_000_000 / limit)
+	sun := new(big.Int).Mul(big.NewInt(energy), big.NewInt(r.TotalEnergyWeight))
+	sun.Mul(sun, big.NewInt(1_000_000))
+
+	limit := big.NewInt(r.TotalEnergyLimit)
+	sun.Add(sun, new(big.Int).Sub(limit, big.NewInt(1)))
+	sun.Div(sun, limit)
+
+	if !sun.IsInt64() {
+		return 0, errors.New("staked amount overflow")
+	}
+
+	return sun.Int64(), nil
+}
+
+// FreezeBalanceRequest stakes TRX for resources (Stake 2.0).
+// See https://developers.tron.network/reference/freezebalancev2-1
+type FreezeBalanceRequest struct {
+	OwnerAddress  string   `json:"owner_address"`
+	FrozenBalance int64    `json:"frozen_balance"`
+	Resource      Resource `json:"resource"`
+	Visible       bool     `json:"visible"`
+}
+
+// DelegateResourceRequest delegates (or reclaims) resources obtained by staked balance to another account.
+// See https://developers.tron.network/reference/delegateresource-1
+type DelegateResourceRequest struct {
+	OwnerAddress    string   `json:"owner_address"`
+	ReceiverAddress string   `json:"receiver_address"`
+	Balance         int64    `json:"balance"`
+	Resource        Resource `json:"resource"`
+	Lock            bool     `json:"lock,omitempty"`
+	Visible         bool     `json:"visible"`
+}
+
+// GetAccountResources returns resources of the account. Address should be in base58 format.
+func (p *Provider) GetAccountResources(ctx context.Context, address string, isTest bool) (AccountResources, error) {
+	payload := map[string]any{"address": address, "visible": true}
+
+	var resources AccountResources
+	if err := p.postJSON(ctx, "/wallet/getaccountresource", payload, isTest, &resources); err != nil {
+		return AccountResources{}, err
+	}
+
+	return resources, nil
+}
+
+// GetAccountBalance returns liquid (not staked) account balance in SUN.
+func (p *Provider) GetAccountBalance(ctx context.Context, address string, isTest bool) (int64, error) {
+	payload := map[string]any{"address": address, "visible": true}
+
+	var account struct {
+		Balance int64 `json:"balance"`
+	}
+
+	if err := p.postJSON(ctx, "/wallet/getaccount", payload, isTest, &account); err != nil {
+		return 0, err
+	}
+
+	return account.Balance, nil
+}
+
+// GetDelegatableBalance returns max amount of staked SUN that could be delegated to other accounts.
+func (p *Provider) GetDelegatableBalance(ctx context.Context, address string, resource Resource, isTest bool) (int64, error) {
+	resourceType := 0
+	if resource == ResourceEnergy {
+		resourceType = 1
+	}
+
+	payload := map[string]any{"owner_address": address, "type": resourceType, "visible": true}
+
+	var res struct {
+		MaxSize int64 `json:"max_size"`
+	}
+
+	if err := p.postJSON(ctx, "/wallet/getcandelegatedmaxsize", payload, isTest, &res); err != nil {
+		return 0, err
+	}
+
+	return res.MaxSize, nil
+}
+
+// GetDelegatedAccounts returns base58 addresses of accounts that received resources from the owner.
+func (p *Provider) GetDelegatedAccounts(ctx context.Context, owner string, isTest bool) ([]string, error) {
+	payload := map[string]any{"value": owner, "visible": true}
+
+	var res struct {
+		ToAccounts []string `json:"toAccounts"`
+	}
+
+	if err := p.postJSON(ctx, "/wallet/getdelegatedresourceaccountindexv2", payload, isTest, &res); err != nil {
+		return nil, err
+	}
+
+	return res.ToAccounts, nil
+}
+
+// GetDelegatedBalance returns amount of staked SUN which resource is delegated from owner to receiver.
+func (p *Provider) GetDelegatedBalance(
+	ctx context.Context,
+	owner, receiver string,
+	resource Resource,
+	isTest bool,
+) (int64, error) {
+	payload := map[string]any{"fromAddress": owner, "toAddress": receiver, "visible": true}
+
+	var res struct {
+		DelegatedResource []struct {
+			FrozenBalanceForEnergy    int64 `json:"frozen_balance_for_energy"`
+			FrozenBalanceForBandwidth int64 `json:"frozen_balance_for_bandwidth"`
+		} `json:"delegatedResource"`
+	}
+
+	if err := p.postJSON(ctx, "/wallet/getdelegatedresourcev2", payload, isTest, &res); err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, r := range res.DelegatedResource {
+		if resource == ResourceEnergy {
+			total += r.FrozenBalanceForEnergy
+		} else {
+			total += r.FrozenBalanceForBandwidth
+		}
+	}
+
+	return total, nil
+}
+
+// FreezeBalance creates unsigned staking transaction.
+func (p *Provider) FreezeBalance(ctx context.Context, payload FreezeBalanceRequest, isTest bool) (Transaction, error) {
+	return p.createResourceTransaction(ctx, "/wallet/freezebalancev2", payload, isTest)
+}
+
+// DelegateResource creates unsigned resource delegation transaction.
+func (p *Provider) DelegateResource(ctx context.Context, payload DelegateResourceRequest, isTest bool) (Transaction, error) {
+	return p.createResourceTransaction(ctx, "/wallet/delegateresource", payload, isTest)
+}
+
+// UnDelegateResource creates unsigned transaction that reclaims previously delegated resource.
+func (p *Provider) UnDelegateResource(ctx context.Context, payload DelegateResourceRequest, isTest bool) (Transaction, error) {
+	return p.createResourceTransaction(ctx, "/wallet/undelegateresource", payload, isTest)
+}
+
+func (p *Provider) createResourceTransaction(
+	ctx context.Context,
+	path string,
+	payload any,
+	isTest bool,
+) (Transaction, error) {
+	var tx Transaction
+	if err := p.postJSON(ctx, path, payload, isTest, &tx); err != nil {
+		return Transaction{}, err
+	}
+
+	if tx.Error != "" {
+		return Transaction{}, errors.Wrap(ErrResponse, tx.Error)
+	}
+
+	return tx, nil
+}
+
+// postJSON performs POST request and unmarshals response body into out.
+func (p *Provider) postJSON(ctx context.Context, path string, payload any, isTest bool, out any) error {
+	body, err := p.post(ctx, path, payload, isTest)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Info().
+		Interface("request", payload).
+		Str("path", path).
+		RawJSON("response", body).
+		Msg("trongrid response")
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return errors.Wrap(err, "unmarshal error")
+	}
+
+	return nil
+}
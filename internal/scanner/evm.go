@@ -0,0 +1,265 @@
+package scanner
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/provider/rpcpool"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/pkg/errors"
+)
+
+// erc20TransferTopic keccak256("Transfer(address,address,uint256)")
+var erc20TransferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+type evmScanner struct {
+	*Scanner
+}
+
+func (s *evmScanner) latestBlock(ctx context.Context, chain money.Blockchain, isTest bool) (int64, error) {
+	client, err := s.rpc(ctx, chain, isTest)
+	if err != nil {
+		return 0, err
+	}
+
+	defer client.Close()
+
+	block, err := client.BlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(block), nil
+}
+
+func (s *evmScanner) scanBlocks(ctx context.Context, r blockRange) ([]Transfer, error) {
+	if len(r.wallets) == 0 {
+		return nil, nil
+	}
+
+	client, err := s.rpc(ctx, r.chain, r.isTest)
+	if err != nil {
+		return nil, err
+	}
+
+	defer client.Close()
+
+	coins, err := s.scanCoinTransfers(ctx, client, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to scan coin transfers")
+	}
+
+	tokens, err := s.scanTokenTransfers(ctx, client, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to scan token transfers")
+	}
+
+	return append(coins, tokens...), nil
+}
+
+// scanCoinTransfers traverses each block's transactions and looks for native coin transfers to our wallets.
+func (s *evmScanner) scanCoinTransfers(ctx context.Context, client *ethclient.Client, r blockRange) ([]Transfer, error) {
+	coin, err := s.resolver.GetNativeCoin(r.chain)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get chain id")
+	}
+
+	signer := types.LatestSignerForChainID(chainID)
+
+	var transfers []Transfer
+
+	for number := r.from; number <= r.to; number++ {
+		block, err := client.BlockByNumber(ctx, big.NewInt(number))
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to get block %d", number)
+		}
+
+		for _, tx := range block.Transactions() {
+			w, sender, ok, err := matchCoinTransfer(signer, tx, r.wallets)
+			if err != nil {
+				return nil, err
+			}
+
+			if !ok {
+				continue
+			}
+
+			// skip failed transactions
+			receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to get receipt of %s", tx.Hash().Hex())
+			}
+
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				continue
+			}
+
+			amount, err := coin.MakeAmountFromBigInt(tx.Value())
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to make amount of %s", tx.Hash().Hex())
+			}
+
+			transfers = append(transfers, Transfer{
+				Wallet:      w,
+				Currency:    coin,
+				Amount:      amount,
+				Sender:      sender.Hex(),
+				Hash:        tx.Hash().Hex(),
+				BlockNumber: number,
+			})
+		}
+	}
+
+	return transfers, nil
+}
+
+// scanTokenTransfers filters ERC-20 Transfer events of supported tokens to our wallets.
+func (s *evmScanner) scanTokenTransfers(ctx context.Context, client *ethclient.Client, r blockRange) ([]Transfer, error) {
+	tokens := make(map[string]money.CryptoCurrency)
+	var contracts []common.Address
+
+	for _, c := range s.resolver.ListBlockchainCurrencies(r.chain) {
+		if c.Type != money.Token {
+			continue
+		}
+
+		addr := c.ChooseContractAddress(r.isTest)
+		if addr == "" {
+			continue
+		}
+
+		tokens[strings.ToLower(addr)] = c
+		contracts = append(contracts, common.HexToAddress(addr))
+	}
+
+	if len(contracts) == 0 {
+		return nil, nil
+	}
+
+	logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: big.NewInt(r.from),
+		ToBlock:   big.NewInt(r.to),
+		Addresses: contracts,
+		Topics:    [][]common.Hash{{erc20TransferTopic}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to filter logs")
+	}
+
+	return tokenTransfers(logs, tokens, r)
+}
+
+// matchCoinTransfer checks whether transaction sends native coins to one of the wallets.
+// Returns recipient wallet & sender.
+func matchCoinTransfer(
+	signer types.Signer,
+	tx *types.Transaction,
+	wallets map[string]*wallet.Wallet,
+) (*wallet.Wallet, common.Address, bool, error) {
+	if tx.To() == nil || tx.Value().Sign() <= 0 {
+		return nil, common.Address{}, false, nil
+	}
+
+	w, ok := wallets[strings.ToLower(tx.To().Hex())]
+	if !ok {
+		return nil, common.Address{}, false, nil
+	}
+
+	sender, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, common.Address{}, false, errors.Wrapf(err, "unable to resolve sender of %s", tx.Hash().Hex())
+	}
+
+	return w, sender, true, nil
+}
+
+// tokenTransfers picks transfers of supported tokens to the wallets.
+// Tokens are indexed by lowercased contract address.
+func tokenTransfers(logs []types.Log, tokens map[string]money.CryptoCurrency, r blockRange) ([]Transfer, error) {
+	var transfers []Transfer
+
+	for i := range logs {
+		event, ok := parseERC20Transfer(logs[i])
+		if !ok {
+			continue
+		}
+
+		w, ok := r.wallets[strings.ToLower(event.to.Hex())]
+		if !ok {
+			continue
+		}
+
+		token, ok := tokens[strings.ToLower(event.contract.Hex())]
+		if !ok {
+			continue
+		}
+
+		amount, err := token.MakeAmountFromBigInt(event.value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to make amount of %s", logs[i].TxHash.Hex())
+		}
+
+		transfers = append(transfers, Transfer{
+			Wallet:      w,
+			Currency:    token,
+			Amount:      amount,
+			Sender:      event.from.Hex(),
+			Hash:        logs[i].TxHash.Hex(),
+			BlockNumber: int64(logs[i].BlockNumber),
+		})
+	}
+
+	return transfers, nil
+}
+
+type erc20Transfer struct {
+	contract common.Address
+	from     common.Address
+	to       common.Address
+	value    *big.Int
+}
+
+// parseERC20Transfer parses Transfer(address indexed from, address indexed to, uint256 value) event log.
+// Logs removed due to chain reorganization are skipped.
+func parseERC20Transfer(l types.Log) (erc20Transfer, bool) {
+	if l.Removed || len(l.Topics) != 3 || l.Topics[0] != erc20TransferTopic {
+		return erc20Transfer{}, false
+	}
+
+	value := new(big.Int).SetBytes(l.Data)
+	if value.Sign() <= 0 {
+		return erc20Transfer{}, false
+	}
+
+	return erc20Transfer{
+		contract: l.Address,
+		from:     common.BytesToAddress(l.Topics[1].Bytes()),
+		to:       common.BytesToAddress(l.Topics[2].Bytes()),
+		value:    value,
+	}, true
+}
+
+func (s *evmScanner) rpc(ctx context.Context, chain money.Blockchain, isTest bool) (*ethclient.Client, error) {
+	if endpoint := s.rpcEndpoint(chain, isTest); endpoint != "" {
+		return ethclient.DialContext(ctx, endpoint)
+	}
+
+	c, err := s.rpcPool.EVM(ctx, chain, isTest)
+	if errors.Is(err, rpcpool.ErrUnsupportedNetwork) {
+		return nil, errors.Wrap(ErrUnsupportedBlockchain, chain.String())
+	}
+
+	return c, err
+}
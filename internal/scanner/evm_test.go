@@ -0,0 +1,148 @@
+package scanner
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextBlockRange(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		cursor   int64
+		latest   int64
+		perRun   int64
+		from, to int64
+		ok       bool
+	}{
+		{name: "no new blocks", cursor: 100, latest: 100, perRun: 10},
+		{name: "cursor is ahead", cursor: 101, latest: 100, perRun: 10},
+		{name: "single block", cursor: 99, latest: 100, perRun: 10, from: 100, to: 100, ok: true},
+		{name: "up to latest", cursor: 90, latest: 100, perRun: 20, from: 91, to: 100, ok: true},
+		{name: "limited by per run", cursor: 50, latest: 100, perRun: 10, from: 51, to: 60, ok: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, ok := nextBlockRange(tt.cursor, tt.latest, tt.perRun)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.from, from)
+			assert.Equal(t, tt.to, to)
+		})
+	}
+}
+
+func TestMatchCoinTransfer(t *testing.T) {
+	key, err := crypto.HexToECDSA("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	require.NoError(t, err)
+
+	var (
+		sender    = crypto.PubkeyToAddress(key.PublicKey)
+		ours      = common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+		foreign   = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		ourWallet = &wallet.Wallet{ID: 1, Address: ours.Hex()}
+		wallets   = map[string]*wallet.Wallet{strings.ToLower(ours.Hex()): ourWallet}
+		signer    = types.LatestSignerForChainID(big.NewInt(1))
+	)
+
+	newTx := func(to *common.Address, value int64) *types.Transaction {
+		tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   big.NewInt(1),
+			To:        to,
+			Value:     big.NewInt(value),
+			Gas:       21000,
+			GasFeeCap: big.NewInt(1),
+			GasTipCap: big.NewInt(1),
+		})
+		require.NoError(t, err)
+
+		return tx
+	}
+
+	t.Run("transfer to our wallet", func(t *testing.T) {
+		w, from, ok, err := matchCoinTransfer(signer, newTx(&ours, 100), wallets)
+
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, ourWallet, w)
+		assert.Equal(t, sender, from)
+	})
+
+	for name, tx := range map[string]*types.Transaction{
+		"transfer to foreign address": newTx(&foreign, 100),
+		"zero value":                  newTx(&ours, 0),
+		"contract creation":           newTx(nil, 100),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, ok, err := matchCoinTransfer(signer, tx, wallets)
+
+			require.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestTokenTransfers(t *testing.T) {
+	var (
+		usdt      = common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+		unknown   = common.HexToAddress("0x0000000000000000000000000000000000000002")
+		sender    = common.HexToAddress("0xb35b60a4572e473e492ee35f0750f95c682e081c")
+		ours      = common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+		foreign   = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		ourWallet = &wallet.Wallet{ID: 1, Address: ours.Hex()}
+		approval  = crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+	)
+
+	token := money.CryptoCurrency{Blockchain: "ETH", Ticker: "ETH_USDT", Type: money.Token, Decimals: 6}
+	tokens := map[string]money.CryptoCurrency{strings.ToLower(usdt.Hex()): token}
+
+	r := blockRange{
+		chain:   "ETH",
+		from:    10,
+		to:      20,
+		wallets: map[string]*wallet.Wallet{strings.ToLower(ours.Hex()): ourWallet},
+	}
+
+	newLog := func(contract common.Address, topic common.Hash, to common.Address, value int64) types.Log {
+		return types.Log{
+			Address:     contract,
+			Topics:      []common.Hash{topic, common.BytesToHash(sender.Bytes()), common.BytesToHash(to.Bytes())},
+			Data:        common.LeftPadBytes(big.NewInt(value).Bytes(), 32),
+			BlockNumber: 15,
+			TxHash:      common.HexToHash("0xabc"),
+		}
+	}
+
+	removed := newLog(usdt, erc20TransferTopic, ours, 100)
+	removed.Removed = true
+
+	malformed := newLog(usdt, erc20TransferTopic, ours, 100)
+	malformed.Topics = malformed.Topics[:2]
+
+	transfers, err := tokenTransfers([]types.Log{
+		newLog(usdt, erc20TransferTopic, ours, 100_000_000),
+		newLog(usdt, erc20TransferTopic, foreign, 100),
+		newLog(unknown, erc20TransferTopic, ours, 100),
+		newLog(usdt, approval, ours, 100),
+		newLog(usdt, erc20TransferTopic, ours, 0),
+		removed,
+		malformed,
+	}, tokens, r)
+
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+
+	assert.Equal(t, ourWallet, transfers[0].Wallet)
+	assert.Equal(t, token.Ticker, transfers[0].Currency.Ticker)
+	assert.Equal(t, "100", transfers[0].Amount.String())
+	assert.Equal(t, sender.Hex(), transfers[0].Sender)
+	assert.Equal(t, common.HexToHash("0xabc").Hex(), transfers[0].Hash)
+	assert.Equal(t, int64(15), transfers[0].BlockNumber)
+}
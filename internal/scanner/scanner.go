@@ -0,0 +1,328 @@
+// Package scanner implements tracking of incoming transactions by following blockchain blocks
+// instead of relying on third-party address subscriptions (e.g. Tatum webhooks).
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	kmswallet "github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/provider/rpcpool"
+	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/processing"
+	"github.com/oxygenpay/oxygen/internal/service/registry"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type Config struct {
+	BlocksPerRun int64 `yaml:"blocks_per_run" env:"SCANNER_BLOCKS_PER_RUN" env-default:"100" env-description:"Max amount of blocks to scan per network in a single run"`
+	ScanTestnets bool  `yaml:"scan_testnets" env:"SCANNER_SCAN_TESTNETS" env-default:"true" env-description:"Enables scanning of test networks"`
+
+	// RPC maps EVM network (e.g. "ETH" or "ETH_TEST") to JSON-RPC endpoint. Tatum nodes are used by default.
+	// TRON blocks are fetched via Trongrid provider.
+	RPC map[string]string `yaml:"rpc" env:"SCANNER_RPC" env-description:"JSON-RPC endpoints per network. Example: 'ETH:https://eth.node,ETH_TEST:https://goerli.node'"`
+}
+
+// Processor ingests transfers found by the scanner.
+type Processor interface {
+	ProcessIncomingTransfer(ctx context.Context, wt *wallet.Wallet, input processing.Input) error
+}
+
+type Scanner struct {
+	config      Config
+	blockchains []money.Blockchain
+	wallets     *wallet.Service
+	resolver    blockchain.Resolver
+	registry    *registry.Service
+	processor   Processor
+	rpcPool     *rpcpool.Pool
+	trongrid    *trongrid.Provider
+	logger      *zerolog.Logger
+}
+
+// Transfer represents incoming transfer to one of our wallets found in a block.
+type Transfer struct {
+	Wallet      *wallet.Wallet
+	Currency    money.CryptoCurrency
+	Amount      money.Money
+	Sender      string
+	Hash        string
+	BlockNumber int64
+}
+
+var ErrUnsupportedBlockchain = errors.New("blockchain is not supported by scanner")
+
+func New(
+	config Config,
+	blockchains []money.Blockchain,
+	wallets *wallet.Service,
+	resolver blockchain.Resolver,
+	registryService *registry.Service,
+	processor Processor,
+	rpcPool *rpcpool.Pool,
+	trongridProvider *trongrid.Provider,
+	logger *zerolog.Logger,
+) *Scanner {
+	log := logger.With().Str("channel", "scanner").Logger()
+
+	if config.BlocksPerRun < 1 {
+		config.BlocksPerRun = 100
+	}
+
+	return &Scanner{
+		config:      config,
+		blockchains: blockchains,
+		wallets:     wallets,
+		resolver:    resolver,
+		registry:    registryService,
+		processor:   processor,
+		rpcPool:     rpcPool,
+		trongrid:    trongridProvider,
+		logger:      &log,
+	}
+}
+
+// Blockchains returns list of blockchains that are tracked by the scanner.
+func (s *Scanner) Blockchains() []money.Blockchain {
+	return s.blockchains
+}
+
+// Scan scans new blocks of each tracked blockchain (both mainnet & testnet) and ingests
+// all incoming transfers to our wallets. Cursor is advanced only if all transfers were processed,
+// so failed blocks are re-scanned during the next run.
+func (s *Scanner) Scan(ctx context.Context) error {
+	var errs []string
+
+	for _, chain := range s.blockchains {
+		networks := []bool{false}
+		if s.config.ScanTestnets {
+			networks = append(networks, true)
+		}
+
+		for _, isTest := range networks {
+			if err := s.scanNetwork(ctx, chain, isTest); err != nil {
+				s.logger.Error().Err(err).
+					Str("blockchain", chain.String()).
+					Bool("is_test", isTest).
+					Msg("unable to scan network")
+
+				errs = append(errs, fmt.Sprintf("%s (test: %t): %s", chain, isTest, err.Error()))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func (s *Scanner) scanNetwork(ctx context.Context, chain money.Blockchain, isTest bool) error {
+	coin, err := s.resolver.GetNativeCoin(chain)
+	if err != nil {
+		return errors.Wrap(err, "unable to get native coin")
+	}
+
+	networkID := coin.ChooseNetwork(isTest)
+
+	n, err := s.networkScanner(chain)
+	if err != nil {
+		return err
+	}
+
+	latest, err := n.latestBlock(ctx, chain, isTest)
+	if err != nil {
+		return errors.Wrap(err, "unable to get latest block")
+	}
+
+	cursor, found, err := s.getCursor(ctx, chain, networkID)
+	if err != nil {
+		return errors.Wrap(err, "unable to get cursor")
+	}
+
+	// first run: start from the latest block instead of scanning the whole history
+	if !found {
+		s.logger.Info().
+			Str("blockchain", chain.String()).
+			Str("network_id", networkID).
+			Int64("block", latest).
+			Msg("cursor not found, starting from the latest block")
+
+		return s.setCursor(ctx, chain, networkID, latest)
+	}
+
+	from, to, ok := nextBlockRange(cursor, latest, s.config.BlocksPerRun)
+	if !ok {
+		return nil
+	}
+
+	wallets, err := s.listWallets(ctx, chain)
+	if err != nil {
+		return errors.Wrap(err, "unable to list wallets")
+	}
+
+	transfers, err := n.scanBlocks(ctx, blockRange{
+		chain:   chain,
+		isTest:  isTest,
+		from:    from,
+		to:      to,
+		wallets: wallets,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "unable to scan blocks %d-%d", from, to)
+	}
+
+	for _, t := range transfers {
+		input := processing.Input{
+			Currency:      t.Currency,
+			Amount:        t.Amount,
+			SenderAddress: t.Sender,
+			TransactionID: t.Hash,
+			NetworkID:     networkID,
+		}
+
+		if err := s.processor.ProcessIncomingTransfer(ctx, t.Wallet, input); err != nil {
+			return errors.Wrapf(err, "unable to process transfer %s", t.Hash)
+		}
+
+		s.logger.Info().
+			Str("blockchain", chain.String()).
+			Str("network_id", networkID).
+			Int64("block", t.BlockNumber).
+			Int64("wallet_id", t.Wallet.ID).
+			Str("blockchain_tx_hash_id", t.Hash).
+			Str("amount", t.Amount.String()).
+			Str("currency", t.Currency.Ticker).
+			Msg("ingested incoming transfer")
+	}
+
+	return s.setCursor(ctx, chain, networkID, to)
+}
+
+// blockRange represents the range of blocks [from, to] to scan.
+type blockRange struct {
+	chain   money.Blockchain
+	isTest  bool
+	from    int64
+	to      int64
+	wallets map[string]*wallet.Wallet
+}
+
+// nextBlockRange returns blocks [from, to] that follow the cursor, limited by perRun. Returns false
+// if there are no new blocks.
+func nextBlockRange(cursor, latest, perRun int64) (int64, int64, bool) {
+	from := cursor + 1
+	if from > latest {
+		return 0, 0, false
+	}
+
+	to := from + perRun - 1
+	if to > latest {
+		to = latest
+	}
+
+	return from, to, true
+}
+
+type networkScanner interface {
+	latestBlock(ctx context.Context, chain money.Blockchain, isTest bool) (int64, error)
+	scanBlocks(ctx context.Context, r blockRange) ([]Transfer, error)
+}
+
+func (s *Scanner) networkScanner(chain money.Blockchain) (networkScanner, error) {
+	switch chain {
+	case "ETH", "MATIC", "BSC":
+		return &evmScanner{Scanner: s}, nil
+	case "TRON":
+		return &tronScanner{Scanner: s}, nil
+	}
+
+	return nil, errors.Wrap(ErrUnsupportedBlockchain, chain.String())
+}
+
+// listWallets returns inbound wallets of the blockchain indexed by lowercased address. Other wallets
+// (outbound, gas, staking) receive internal transfers that should not be treated as incoming payments.
+func (s *Scanner) listWallets(ctx context.Context, chain money.Blockchain) (map[string]*wallet.Wallet, error) {
+	var (
+		start   int64
+		results = make(map[string]*wallet.Wallet)
+	)
+
+	for {
+		wallets, nextID, err := s.wallets.List(ctx, wallet.Pagination{
+			Start:              start,
+			Limit:              500,
+			FilterByBlockchain: kmswallet.Blockchain(chain),
+			FilterByType:       wallet.TypeInbound,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, w := range wallets {
+			results[strings.ToLower(w.Address)] = w
+		}
+
+		if nextID == nil {
+			return results, nil
+		}
+
+		start = *nextID
+	}
+}
+
+func (s *Scanner) getCursor(ctx context.Context, chain money.Blockchain, networkID string) (int64, bool, error) {
+	v, err := s.registry.Get(ctx, cursorKey(chain, networkID))
+
+	switch {
+	case errors.Is(err, registry.ErrNotFound):
+		return 0, false, nil
+	case err != nil:
+		return 0, false, err
+	}
+
+	block, err := strconv.ParseInt(v.Value, 10, 64)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "invalid cursor value %q", v.Value)
+	}
+
+	return block, true, nil
+}
+
+func (s *Scanner) setCursor(ctx context.Context, chain money.Blockchain, networkID string, block int64) error {
+	_, err := s.registry.Set(ctx, cursorKey(chain, networkID), strconv.FormatInt(block, 10))
+
+	return err
+}
+
+func cursorKey(chain money.Blockchain, networkID string) string {
+	return fmt.Sprintf("scanner.%s.%s.block", strings.ToLower(chain.String()), networkID)
+}
+
+// rpcKey returns key of Config.RPC. Example: "ETH", "ETH_TEST".
+func rpcKey(chain money.Blockchain, isTest bool) string {
+	if isTest {
+		return chain.String() + "_TEST"
+	}
+
+	return chain.String()
+}
+
+func (s *Scanner) rpcEndpoint(chain money.Blockchain, isTest bool) string {
+	key := rpcKey(chain, isTest)
+
+	for k, v := range s.config.RPC {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,209 @@
+package scanner
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
+	"github.com/oxygenpay/oxygen/internal/util"
+	"github.com/pkg/errors"
+)
+
+// tronScanner scans solidified TRON blocks. Note that account activation (1 TRX transfer)
+// is ingested as a regular coin transfer and handled by the processing service.
+type tronScanner struct {
+	*Scanner
+}
+
+func (s *tronScanner) latestBlock(ctx context.Context, _ money.Blockchain, isTest bool) (int64, error) {
+	return s.trongrid.GetLatestBlockNumber(ctx, isTest)
+}
+
+func (s *tronScanner) scanBlocks(ctx context.Context, r blockRange) ([]Transfer, error) {
+	if len(r.wallets) == 0 {
+		return nil, nil
+	}
+
+	coin, err := s.resolver.GetNativeCoin(r.chain)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make(map[string]money.CryptoCurrency)
+	for _, c := range s.resolver.ListBlockchainCurrencies(r.chain) {
+		if c.Type == money.Token && c.ChooseContractAddress(r.isTest) != "" {
+			tokens[c.ChooseContractAddress(r.isTest)] = c
+		}
+	}
+
+	blocks, err := s.trongrid.ListBlocks(ctx, r.from, r.to, r.isTest)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list blocks")
+	}
+
+	var transfers []Transfer
+
+	for _, block := range blocks {
+		coinTransfers, err := s.coinTransfers(block, coin, r)
+		if err != nil {
+			return nil, err
+		}
+
+		transfers = append(transfers, coinTransfers...)
+
+		if len(tokens) == 0 || !hasContractCalls(block) {
+			continue
+		}
+
+		infos, err := s.trongrid.ListTransactionsInfo(ctx, block.Number, r.isTest)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to list transactions info")
+		}
+
+		tokenTransfers, err := s.tokenTransfers(infos, tokens, r)
+		if err != nil {
+			return nil, err
+		}
+
+		transfers = append(transfers, tokenTransfers...)
+	}
+
+	return transfers, nil
+}
+
+func (s *tronScanner) coinTransfers(block trongrid.Block, coin money.CryptoCurrency, r blockRange) ([]Transfer, error) {
+	var transfers []Transfer
+
+	for _, tx := range block.Transactions {
+		if tx.Type != trongrid.TransferContract || !tx.Success || tx.Amount <= 0 {
+			continue
+		}
+
+		w, ok := r.wallets[strings.ToLower(util.TronHexToBase58(tx.ToAddress))]
+		if !ok {
+			continue
+		}
+
+		amount, err := coin.MakeAmountFromBigInt(big.NewInt(tx.Amount))
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to make amount of %s", tx.Hash)
+		}
+
+		transfers = append(transfers, Transfer{
+			Wallet:      w,
+			Currency:    coin,
+			Amount:      amount,
+			Sender:      util.TronHexToBase58(tx.OwnerAddress),
+			Hash:        tx.Hash,
+			BlockNumber: block.Number,
+		})
+	}
+
+	return transfers, nil
+}
+
+func (s *tronScanner) tokenTransfers(
+	infos []trongrid.TransactionInfo,
+	tokens map[string]money.CryptoCurrency,
+	r blockRange,
+) ([]Transfer, error) {
+	var transfers []Transfer
+
+	for _, info := range infos {
+		if info.Failed() {
+			continue
+		}
+
+		for _, l := range info.Logs {
+			event, ok := parseTRC20Transfer(l)
+			if !ok {
+				continue
+			}
+
+			token, ok := tokens[event.contract]
+			if !ok {
+				continue
+			}
+
+			w, ok := r.wallets[strings.ToLower(event.to)]
+			if !ok {
+				continue
+			}
+
+			amount, err := token.MakeAmountFromBigInt(event.value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to make amount of %s", info.Hash)
+			}
+
+			transfers = append(transfers, Transfer{
+				Wallet:      w,
+				Currency:    token,
+				Amount:      amount,
+				Sender:      event.from,
+				Hash:        info.Hash,
+				BlockNumber: info.BlockNumber,
+			})
+		}
+	}
+
+	return transfers, nil
+}
+
+func hasContractCalls(block trongrid.Block) bool {
+	for _, tx := range block.Transactions {
+		if tx.Type == trongrid.TriggerSmartContract {
+			return true
+		}
+	}
+
+	return false
+}
+
+type trc20Transfer struct {
+	contract string
+	from     string
+	to       string
+	value    *big.Int
+}
+
+// parseTRC20Transfer parses Transfer(address,address,uint256) event log.
+// All returned addresses are in base58 format.
+func parseTRC20Transfer(l trongrid.TransactionLog) (trc20Transfer, bool) {
+	if len(l.Topics) != 3 || strings.TrimPrefix(l.Topics[0], "0x") != erc20TransferTopic.Hex()[2:] {
+		return trc20Transfer{}, false
+	}
+
+	from, ok := topicToTronAddress(l.Topics[1])
+	if !ok {
+		return trc20Transfer{}, false
+	}
+
+	to, ok := topicToTronAddress(l.Topics[2])
+	if !ok {
+		return trc20Transfer{}, false
+	}
+
+	value, ok := new(big.Int).SetString(l.Data, 16)
+	if !ok || value.Sign() <= 0 {
+		return trc20Transfer{}, false
+	}
+
+	return trc20Transfer{
+		contract: util.TronHexToBase58("41" + l.Address),
+		from:     from,
+		to:       to,
+		value:    value,
+	}, true
+}
+
+// topicToTronAddress converts 32-bytes topic to base58 address
+func topicToTronAddress(topic string) (string, bool) {
+	topic = strings.TrimPrefix(topic, "0x")
+	if len(topic) != 64 {
+		return "", false
+	}
+
+	return util.TronHexToBase58("41" + topic[24:]), true
+}
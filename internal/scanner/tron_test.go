@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTRC20Transfer(t *testing.T) {
+	const (
+		// USDT TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t
+		usdtHex         = "a614f803b6fd780986a42c78ec9c7f77e6ded13c"
+		fromTopic       = "000000000000000000000000b35b60a4572e473e492ee35f0750f95c682e081c"
+		toTopic         = "000000000000000000000000a614f803b6fd780986a42c78ec9c7f77e6ded13c"
+		transferTopic   = "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+		oneHundredUSDT  = "0000000000000000000000000000000000000000000000000000000005f5e100"
+		approvalTopic   = "8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"
+		zeroValueAmount = "0000000000000000000000000000000000000000000000000000000000000000"
+	)
+
+	for _, tt := range []struct {
+		name     string
+		log      trongrid.TransactionLog
+		expected trc20Transfer
+		ok       bool
+	}{
+		{
+			name: "transfer",
+			log: trongrid.TransactionLog{
+				Address: usdtHex,
+				Topics:  []string{transferTopic, fromTopic, toTopic},
+				Data:    oneHundredUSDT,
+			},
+			expected: trc20Transfer{
+				contract: "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t",
+				from:     "TSKZRR9egK9YSXGdbVQGrVoBVc18AYpEBz",
+				to:       "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t",
+			},
+			ok: true,
+		},
+		{
+			name: "approval is skipped",
+			log: trongrid.TransactionLog{
+				Address: usdtHex,
+				Topics:  []string{approvalTopic, fromTopic, toTopic},
+				Data:    oneHundredUSDT,
+			},
+		},
+		{
+			name: "zero value is skipped",
+			log: trongrid.TransactionLog{
+				Address: usdtHex,
+				Topics:  []string{transferTopic, fromTopic, toTopic},
+				Data:    zeroValueAmount,
+			},
+		},
+		{
+			name: "malformed topics",
+			log: trongrid.TransactionLog{
+				Address: usdtHex,
+				Topics:  []string{transferTopic, fromTopic},
+				Data:    oneHundredUSDT,
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := parseTRC20Transfer(tt.log)
+
+			assert.Equal(t, tt.ok, ok)
+			if !tt.ok {
+				return
+			}
+
+			assert.Equal(t, tt.expected.contract, actual.contract)
+			assert.Equal(t, tt.expected.from, actual.from)
+			assert.Equal(t, tt.expected.to, actual.to)
+			assert.Equal(t, "100000000", actual.value.String())
+		})
+	}
+}
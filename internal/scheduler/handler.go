@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync/atomic"
 
 	"github.com/oxygenpay/oxygen/internal/log"
 	"github.com/oxygenpay/oxygen/internal/money"
@@ -25,7 +26,12 @@ type Handler struct {
 	wallets      *wallet.Service
 	processing   ProcessingService
 	transactions *transaction.Service
+	scanner      BlockScanner
+	rpc          HealthChecker
 	tableLogger  *log.JobLogger
+
+	// reorgCursor id of the last transaction checked for chain reorganization
+	reorgCursor atomic.Int64
 }
 
 type ContextJobID struct{}
@@ -33,20 +39,37 @@ type ContextJobEnableTableLogger struct{}
 
 type ProcessingService interface {
 	BatchCheckIncomingTransactions(ctx context.Context, transactionIDs []int64) error
+	BatchCheckReorganizations(ctx context.Context, transactionIDs []int64) error
+	ApplySweepPolicies(ctx context.Context, balances []*wallet.Balance) (*processing.SweepPolicyResult, error)
+	BatchFundGas(ctx context.Context, balances []*wallet.Balance) (*processing.GasFundingResult, error)
 	BatchCreateInternalTransfers(ctx context.Context, balances []*wallet.Balance) (*processing.TransferResult, error)
 	BatchCheckInternalTransfers(ctx context.Context, transactionIDs []int64) error
 	BatchCreateWithdrawals(ctx context.Context, paymentsIDs []int64) (*processing.TransferResult, error)
 	BatchCheckWithdrawals(ctx context.Context, transactionIDs []int64) error
+	BatchBumpStuckTransactions(ctx context.Context, transactionIDs []int64) error
+	ReconcileNonceForward(ctx context.Context, walletID int64, isTest bool) (*processing.NonceReconciliation, error)
+	ManageTronEnergy(ctx context.Context, isTest bool) (*processing.TronEnergyResult, error)
+	SweepOutboundToCold(ctx context.Context) (*processing.ColdSweepResult, error)
 	EnsureOutboundWallet(ctx context.Context, chain money.Blockchain) (*wallet.Wallet, bool, error)
 	BatchExpirePayments(ctx context.Context, paymentsIDs []int64) error
 }
 
+type BlockScanner interface {
+	Scan(ctx context.Context) error
+}
+
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
 func New(
 	payments *payment.Service,
 	blockchains *blockchain.Service,
 	wallets *wallet.Service,
 	processingService ProcessingService,
 	transactions *transaction.Service,
+	scanner BlockScanner,
+	rpc HealthChecker,
 	jobLogger *log.JobLogger,
 ) *Handler {
 	return &Handler{
@@ -55,6 +78,8 @@ func New(
 		blockchains:  blockchains,
 		processing:   processingService,
 		transactions: transactions,
+		scanner:      scanner,
+		rpc:          rpc,
 		tableLogger:  jobLogger,
 	}
 }
@@ -86,6 +111,71 @@ func (h *Handler) CheckIncomingTransactionsProgress(ctx context.Context) error {
 	return nil
 }
 
+// CheckReorganizations re-verifies recently completed incoming transactions
+// so chain reorganizations don't leave merchants with credited but non-existent funds.
+// Each run continues from the last checked transaction and starts over after the last page.
+func (h *Handler) CheckReorganizations(ctx context.Context) error {
+	const limit = 200
+
+	txs, err := h.transactions.ListForReorgCheck(ctx, h.reorgCursor.Load(), limit)
+	if err != nil {
+		return errors.Wrap(err, "unable to list completed transactions")
+	}
+
+	if len(txs) < limit {
+		h.reorgCursor.Store(0)
+	} else {
+		h.reorgCursor.Store(txs[len(txs)-1].ID)
+	}
+
+	// payments of orphaned transactions that are still successful (e.g. payment update failed after orphaning)
+	orphaned, err := h.transactions.ListOrphanedWithPaymentStatus(ctx, payment.StatusSuccess.String(), limit)
+	if err != nil {
+		return errors.Wrap(err, "unable to list orphaned transactions")
+	}
+
+	txs = append(txs, orphaned...)
+
+	if len(txs) == 0 {
+		return nil
+	}
+
+	ids := util.MapSlice(txs, func(t *transaction.Transaction) int64 { return t.ID })
+
+	if err := h.processing.BatchCheckReorganizations(ctx, ids); err != nil {
+		return errors.Wrap(err, "unable to batch check reorganizations")
+	}
+
+	return nil
+}
+
+// CheckRPCHealth probes RPC endpoints so failed ones are brought back once they recover.
+func (h *Handler) CheckRPCHealth(ctx context.Context) error {
+	if h.rpc == nil {
+		return nil
+	}
+
+	if err := h.rpc.CheckHealth(ctx); err != nil {
+		return errors.Wrap(err, "unable to check rpc health")
+	}
+
+	return nil
+}
+
+// ScanIncomingTransactions scans new blocks of blockchains that are tracked
+// by self-hosted scanner instead of provider's webhooks.
+func (h *Handler) ScanIncomingTransactions(ctx context.Context) error {
+	if h.scanner == nil {
+		return nil
+	}
+
+	if err := h.scanner.Scan(ctx); err != nil {
+		return errors.Wrap(err, "unable to scan blocks")
+	}
+
+	return nil
+}
+
 // PerformInternalWalletTransfer performs money transfer from INBOUND wallets to OUTBOUND ones
 // so later customers can withdraw their assets.
 func (h *Handler) PerformInternalWalletTransfer(ctx context.Context) error {
@@ -171,7 +261,49 @@ func (h *Handler) PerformInternalWalletTransfer(ctx context.Context) error {
 		}),
 	})
 
-	result, err := h.processing.BatchCreateInternalTransfers(ctx, matchedBalances)
+	// 4. Postpone sweeps when network conditions are unfavorable
+	sweep, err := h.processing.ApplySweepPolicies(ctx, matchedBalances)
+	if err != nil {
+		return errors.Wrap(err, "unable to apply sweep policies")
+	}
+
+	if len(sweep.SkippedBalances) > 0 || len(sweep.ForcedBalanceIDs) > 0 || len(sweep.UnhandledErrors) > 0 {
+		h.tableLogger.Log(ctx, log.Info, jobID, "applied sweep policies", map[string]any{
+			"readyBalancesCount": len(sweep.ReadyBalances),
+			"forcedBalanceIDs":   sweep.ForcedBalanceIDs,
+			"skippedBalances": util.MapSlice(sweep.SkippedBalances, func(s processing.SkippedSweep) string {
+				return fmt.Sprintf("balance#%d with %s amount of %s: %s",
+					s.Balance.ID,
+					s.Balance.Currency,
+					s.Balance.Amount.String(),
+					s.Reason,
+				)
+			}),
+			"errorMessages": util.MapSlice(sweep.UnhandledErrors, func(e error) string { return e.Error() }),
+		})
+	}
+
+	// 5. Make sure that wallets holding tokens have enough coins to pay network fees
+	gas, err := h.processing.BatchFundGas(ctx, sweep.ReadyBalances)
+	if err != nil {
+		return errors.Wrap(err, "unable to fund inbound wallets with gas")
+	}
+
+	h.tableLogger.Log(ctx, log.Info, jobID, "funded inbound wallets with gas", map[string]any{
+		"readyBalancesCount": len(gas.ReadyBalances),
+		"awaitingWalletIDs":  gas.AwaitingWalletIDs,
+		"fundingTransactionsList": util.MapSlice(gas.FundingTransactions, func(tx *transaction.Transaction) string {
+			return fmt.Sprintf("tx#%d: send %s of %s to %s",
+				tx.ID,
+				tx.Amount.String(),
+				tx.Amount.Ticker(),
+				tx.RecipientAddress,
+			)
+		}),
+		"errorMessages": util.MapSlice(gas.UnhandledErrors, func(e error) string { return e.Error() }),
+	})
+
+	result, err := h.processing.BatchCreateInternalTransfers(ctx, gas.ReadyBalances)
 	if err != nil {
 		return errors.Wrap(err, "unable to transfer money from inbound to outbound wallets")
 	}
@@ -199,7 +331,7 @@ func (h *Handler) CheckInternalTransferProgress(ctx context.Context) error {
 	const limit = 200
 
 	filter := transaction.Filter{
-		Types:    []transaction.Type{transaction.TypeInternal},
+		Types:    []transaction.Type{transaction.TypeInternal, transaction.TypeGasFunding, transaction.TypeColdSweep},
 		Statuses: []transaction.Status{transaction.StatusPending, transaction.StatusInProgress},
 	}
 
@@ -314,6 +446,150 @@ func (h *Handler) CheckWithdrawalsProgress(ctx context.Context) error {
 	return nil
 }
 
+// BumpStuckTransactions re-broadcasts outbound transactions that are pending for too long with a higher fee.
+func (h *Handler) BumpStuckTransactions(ctx context.Context) error {
+	const limit = 200
+
+	filter := transaction.Filter{
+		Types:    []transaction.Type{transaction.TypeInternal, transaction.TypeWithdrawal, transaction.TypeGasFunding, transaction.TypeColdSweep},
+		Statuses: []transaction.Status{transaction.StatusPending, transaction.StatusInProgress},
+	}
+
+	txs, err := h.transactions.ListByFilter(ctx, filter, limit)
+	if err != nil {
+		return errors.Wrap(err, "unable to list outbound transactions")
+	}
+
+	if len(txs) == 0 {
+		return nil
+	}
+
+	ids := util.MapSlice(txs, func(t *transaction.Transaction) int64 { return t.ID })
+
+	if err := h.processing.BatchBumpStuckTransactions(ctx, ids); err != nil {
+		return errors.Wrap(err, "unable to batch bump stuck transactions")
+	}
+
+	return nil
+}
+
+// ReconcileNonces moves nonces of outbound & gas EVM wallets forward if blockchain is ahead and reports nonce gaps
+// that prevent further transactions from being included into blocks. Gaps are neither filled nor rolled back
+// automatically: the job isn't serialized with outbound transactions, so a nonce that is being broadcasted
+// right now might look like a gap. Operator resolves persistent gaps via admin API.
+func (h *Handler) ReconcileNonces(ctx context.Context) error {
+	logger := zerolog.Ctx(ctx)
+
+	var wallets []*wallet.Wallet
+	for _, walletType := range []wallet.Type{wallet.TypeOutbound, wallet.TypeGas} {
+		list, _, err := h.wallets.List(ctx, wallet.Pagination{
+			Start:        0,
+			Limit:        300,
+			FilterByType: walletType,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "unable to list %s wallets", walletType)
+		}
+
+		wallets = append(wallets, list...)
+	}
+
+	var failed []int64
+
+	for _, w := range wallets {
+		result, err := h.processing.ReconcileNonceForward(ctx, w.ID, false)
+
+		switch {
+		case errors.Is(err, processing.ErrNonceUnsupported):
+			continue
+		case err != nil:
+			logger.Error().Err(err).Int64("wallet_id", w.ID).Msg("unable to reconcile wallet nonce")
+			failed = append(failed, w.ID)
+			continue
+		}
+
+		switch result.Action {
+		case processing.NonceActionUnknownNonces:
+			logger.Warn().
+				Int64("wallet_id", w.ID).
+				Int64("unknown_in_flight", result.Before.UnknownInFlight).
+				Msg("unable to reconcile wallet nonce: some in-flight transactions have no nonce")
+		case processing.NonceActionGapsDetected:
+			logger.Error().
+				Int64("wallet_id", w.ID).
+				Ints64("gaps", result.Before.Gaps).
+				Ints64("in_flight", result.Before.InFlight).
+				Msg("wallet has nonce gaps that block outbound transactions, fill them via admin api if they persist")
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("unable to reconcile nonces of wallets %v", failed)
+	}
+
+	return nil
+}
+
+// ManageTronEnergy stakes TRX of the staking wallet and delegates obtained energy to TRON outbound wallet.
+func (h *Handler) ManageTronEnergy(ctx context.Context) error {
+	if _, err := h.processing.ManageTronEnergy(ctx, false); err != nil {
+		return errors.Wrap(err, "unable to manage TRON energy")
+	}
+
+	return nil
+}
+
+// PerformColdWalletSweep moves excess funds of OUTBOUND wallets to cold addresses
+// and alerts when OUTBOUND balances can't cover pending withdrawals.
+func (h *Handler) PerformColdWalletSweep(ctx context.Context) error {
+	jobID := ctx.Value(ContextJobID{}).(string)
+	logger := zerolog.Ctx(ctx)
+
+	result, err := h.processing.SweepOutboundToCold(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to sweep outbound wallets to cold wallets")
+	}
+
+	for _, s := range result.Shortfalls {
+		logger.Error().
+			Int64("wallet_id", s.WalletID).
+			Str("address", s.Address).
+			Str("balance", s.Balance.String()).
+			Str("required", s.Required.String()).
+			Str("currency", s.Balance.Ticker()).
+			Msg("outbound wallet balance is lower than pending withdrawals, refill is required")
+	}
+
+	if len(result.Shortfalls) > 0 {
+		h.tableLogger.Log(ctx, log.Alert, jobID, "outbound wallets require refill", map[string]any{
+			"shortfalls": util.MapSlice(result.Shortfalls, func(s processing.HotWalletShortfall) string {
+				return fmt.Sprintf("wallet#%d (%s): has %s %s, pending withdrawals require %s",
+					s.WalletID,
+					s.Address,
+					s.Balance.String(),
+					s.Balance.Ticker(),
+					s.Required.String(),
+				)
+			}),
+		})
+	}
+
+	h.tableLogger.Log(ctx, log.Info, jobID, "created cold sweep transactions", map[string]any{
+		"transactionIDs": util.MapSlice(result.CreatedTransactions, func(tx *transaction.Transaction) int64 { return tx.ID }),
+		"transactionsList": util.MapSlice(result.CreatedTransactions, func(tx *transaction.Transaction) string {
+			return fmt.Sprintf("tx#%d: send %s of %s to %s",
+				tx.ID,
+				tx.Amount.String(),
+				tx.Amount.Ticker(),
+				tx.RecipientAddress,
+			)
+		}),
+		"errorMessages": util.MapSlice(result.UnhandledErrors, func(e error) string { return e.Error() }),
+	})
+
+	return nil
+}
+
 func (h *Handler) CancelExpiredPayments(ctx context.Context) error {
 	// it will be definitely enough for first months of usage.
 	const limit = 200
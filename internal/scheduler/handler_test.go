@@ -49,6 +49,8 @@ func setup(t *testing.T) *TestContext {
 			tc.Services.Wallet,
 			processingMock,
 			tc.Services.Transaction,
+			nil,
+			nil,
 			tc.Services.JobLogger,
 		),
 	}
@@ -132,7 +134,8 @@ func TestScheduler(t *testing.T) {
 				tc.SetupCreateWalletWithSubscription(bc.String(), "abc-123", "pub-key-123")
 			}
 
-			// And mocked processing method
+			// And mocked processing methods
+			tc.ProcessingMock.SetupBatchFundGas(nil, nil, nil)
 			tc.ProcessingMock.SetupBatchCreateInternalTransfers(nil, nil, nil)
 
 			// ACT
@@ -178,8 +181,9 @@ func TestScheduler(t *testing.T) {
 			}
 
 			// Check job logs
-			// "fetched inbound wallets" + "matched inbound balances" + "created internal transactions"
-			tc.AssertTableRows(t, "job_logs", 3)
+			// "fetched inbound wallets" + "matched inbound balances" + "funded inbound wallets with gas"
+			// + "created internal transactions"
+			tc.AssertTableRows(t, "job_logs", 4)
 			tc.AssertTableRows(t, "wallets", 4)
 
 			// Check that duplicate outbound wallet duplicate creation is not possible
@@ -223,7 +227,8 @@ func TestScheduler(t *testing.T) {
 				b3 := makeBalance(w1, ethUSDT, "120_000000", 1, false)
 				b4 := makeBalance(w2, maticUSDT, "130_000000", 1, false)
 
-				// And mocked processing method response
+				// And mocked processing methods responses
+				tc.ProcessingMock.SetupBatchFundGas([]*wallet.Balance{b3, b4}, nil, nil)
 				tc.ProcessingMock.SetupBatchCreateInternalTransfers(
 					[]*wallet.Balance{b3, b4},
 					&processing.TransferResult{
@@ -239,8 +244,9 @@ func TestScheduler(t *testing.T) {
 				assert.NoError(t, err)
 
 				// Check job logs
-				// "fetched inbound wallets" + "matched inbound balances" + "created internal transactions"
-				tc.AssertTableRows(t, "job_logs", 3)
+				// "fetched inbound wallets" + "matched inbound balances" + "funded inbound wallets with gas"
+				// + "created internal transactions"
+				tc.AssertTableRows(t, "job_logs", 4)
 			})
 		})
 	})
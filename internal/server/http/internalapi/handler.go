@@ -1,6 +1,7 @@
 package internalapi
 
 import (
+	"context"
 	"net/http"
 	"sort"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/oxygenpay/oxygen/internal/scheduler"
 	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/processing"
 	"github.com/oxygenpay/oxygen/internal/service/wallet"
 	"github.com/rs/zerolog"
 )
@@ -18,9 +20,15 @@ type BlockchainService interface {
 	blockchain.FeeCalculator
 }
 
+type NonceManager interface {
+	GetNonceState(ctx context.Context, walletID int64, isTest bool) (*processing.NonceState, error)
+	ReconcileNonce(ctx context.Context, walletID int64, isTest, fillGaps bool) (*processing.NonceReconciliation, error)
+}
+
 type Handler struct {
 	wallet     *wallet.Service
 	blockchain BlockchainService
+	nonces     NonceManager
 	scheduler  *scheduler.Handler
 	logger     *zerolog.Logger
 }
@@ -28,6 +36,7 @@ type Handler struct {
 func New(
 	walletService *wallet.Service,
 	blockchainService BlockchainService,
+	nonceManager NonceManager,
 	schedulerHandler *scheduler.Handler,
 	logger *zerolog.Logger,
 ) *Handler {
@@ -36,6 +45,7 @@ func New(
 	return &Handler{
 		wallet:     walletService,
 		blockchain: blockchainService,
+		nonces:     nonceManager,
 		scheduler:  schedulerHandler,
 		logger:     &log,
 	}
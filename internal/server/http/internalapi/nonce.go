@@ -0,0 +1,117 @@
+package internalapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/oxygenpay/oxygen/internal/server/http/common"
+	"github.com/oxygenpay/oxygen/internal/service/processing"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	admin "github.com/oxygenpay/oxygen/pkg/api-admin/v1/model"
+	"github.com/pkg/errors"
+)
+
+func (h *Handler) GetWalletNonce(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	w, err := h.getWallet(ctx, c.Param(paramWalletID))
+
+	switch {
+	case errors.Is(err, errInvalidID):
+		return common.ValidationErrorResponse(c, errInvalidID)
+	case errors.Is(err, wallet.ErrNotFound):
+		return common.NotFoundResponse(c, "wallet not found")
+	case err != nil:
+		return errors.Wrap(err, "unable to get wallet")
+	}
+
+	var isTest bool
+	if isTestRaw := c.QueryParam("isTest"); isTestRaw != "" {
+		b, err := strconv.ParseBool(isTestRaw)
+		if err != nil {
+			return common.ValidationErrorItemResponse(c, "isTest", "invalid value")
+		}
+		isTest = b
+	}
+
+	state, err := h.nonces.GetNonceState(ctx, w.ID, isTest)
+
+	switch {
+	case errors.Is(err, processing.ErrNonceUnsupported):
+		return common.ValidationErrorResponse(c, err)
+	case err != nil:
+		return common.ErrorResponse(c, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, nonceStateToResponse(state))
+}
+
+func (h *Handler) ReconcileWalletNonce(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := &admin.ReconcileNonceRequest{}
+	if !common.BindAndValidateRequest(c, req) {
+		return nil
+	}
+
+	w, err := h.getWallet(ctx, c.Param(paramWalletID))
+
+	switch {
+	case errors.Is(err, errInvalidID):
+		return common.ValidationErrorResponse(c, errInvalidID)
+	case errors.Is(err, wallet.ErrNotFound):
+		return common.NotFoundResponse(c, "wallet not found")
+	case err != nil:
+		return errors.Wrap(err, "unable to get wallet")
+	}
+
+	result, err := h.nonces.ReconcileNonce(ctx, w.ID, req.IsTest, req.FillGaps)
+
+	switch {
+	case errors.Is(err, processing.ErrNonceUnsupported):
+		return common.ValidationErrorResponse(c, err)
+	case err != nil:
+		return common.ErrorResponse(c, err.Error())
+	}
+
+	filledGaps := make(map[string]string, len(result.FilledGaps))
+	for nonce, txHash := range result.FilledGaps {
+		filledGaps[strconv.FormatInt(nonce, 10)] = txHash
+	}
+
+	return c.JSON(http.StatusOK, &admin.NonceReconciliation{
+		Action:     string(result.Action),
+		Before:     nonceStateToResponse(result.Before),
+		After:      nonceStateToResponse(result.After),
+		FilledGaps: filledGaps,
+	})
+}
+
+func nonceStateToResponse(state *processing.NonceState) *admin.WalletNonce {
+	inFlight := state.InFlight
+	if inFlight == nil {
+		inFlight = []int64{}
+	}
+
+	gaps := state.Gaps
+	if gaps == nil {
+		gaps = []int64{}
+	}
+
+	return &admin.WalletNonce{
+		WalletID:        state.WalletID,
+		Address:         state.Address,
+		Blockchain:      state.Blockchain.String(),
+		IsTest:          state.IsTest,
+		Confirmed:       state.Confirmed,
+		Pending:         state.Pending,
+		Next:            state.Next(),
+		ChainLatest:     state.ChainLatest,
+		ChainPending:    state.ChainPending,
+		InFlight:        inFlight,
+		UnknownInFlight: state.UnknownInFlight,
+		Gaps:            gaps,
+		InSync:          state.InSync(),
+	}
+}
@@ -28,11 +28,18 @@ func (h *Handler) RunSchedulerJob(c echo.Context) error {
 	ctx = h.logger.WithContext(ctx)
 
 	jobs := map[string]func(context.Context) error{
+		"scanIncomingTransactions":          h.scheduler.ScanIncomingTransactions,
 		"checkIncomingTransactionsProgress": h.scheduler.CheckIncomingTransactionsProgress,
+		"checkReorganizations":              h.scheduler.CheckReorganizations,
+		"checkRPCHealth":                    h.scheduler.CheckRPCHealth,
 		"performInternalWalletTransfer":     h.scheduler.PerformInternalWalletTransfer,
 		"checkInternalTransferProgress":     h.scheduler.CheckInternalTransferProgress,
 		"performWithdrawalsCreation":        h.scheduler.PerformWithdrawalsCreation,
 		"checkWithdrawalsProgress":          h.scheduler.CheckWithdrawalsProgress,
+		"bumpStuckTransactions":             h.scheduler.BumpStuckTransactions,
+		"reconcileNonces":                   h.scheduler.ReconcileNonces,
+		"manageTronEnergy":                  h.scheduler.ManageTronEnergy,
+		"performColdWalletSweep":            h.scheduler.PerformColdWalletSweep,
 		"cancelExpiredPayments":             h.scheduler.CancelExpiredPayments,
 		"ensureOutboundWallets":             h.scheduler.EnsureOutboundWallets,
 	}
@@ -270,6 +270,48 @@ func (h *Handler) ListWallets(c echo.Context) error {
 	})
 }
 
+func (h *Handler) SignWalletMessage(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := &admin.SignMessageRequest{}
+	if !common.BindAndValidateRequest(c, req) {
+		return nil
+	}
+
+	w, err := h.getWallet(ctx, c.Param(paramWalletID))
+
+	switch {
+	case errors.Is(err, errInvalidID):
+		return common.ValidationErrorResponse(c, errInvalidID)
+	case errors.Is(err, wallet.ErrNotFound):
+		return common.NotFoundResponse(c, "wallet not found")
+	case err != nil:
+		return errors.Wrap(err, "unable to get wallet")
+	}
+
+	params := kms.SignMessageParams{
+		Type:      kms.MessageType(req.Type),
+		Challenge: req.Challenge,
+	}
+
+	signed, err := h.wallet.SignMessage(ctx, w, params)
+
+	switch {
+	case errors.Is(err, wallet.ErrMessageRejected):
+		return common.ValidationErrorResponse(c, err)
+	case err != nil:
+		return errors.Wrap(err, "unable to sign message")
+	}
+
+	return c.JSON(http.StatusOK, &admin.SignedMessage{
+		Address:   signed.Address,
+		Message:   signed.Message,
+		Timestamp: signed.Timestamp.Unix(),
+		Hash:      signed.Hash,
+		Signature: signed.Signature,
+	})
+}
+
 func (h *Handler) getWallet(ctx context.Context, id string) (*wallet.Wallet, error) {
 	walletID, err := strconv.Atoi(id)
 	if err != nil {
@@ -2,12 +2,16 @@ package merchantapi
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/oxygenpay/oxygen/internal/server/http/common"
 	"github.com/oxygenpay/oxygen/internal/server/http/middleware"
+	"github.com/oxygenpay/oxygen/internal/service/exchange"
 	"github.com/oxygenpay/oxygen/internal/service/wallet"
 	"github.com/oxygenpay/oxygen/internal/util"
 	"github.com/oxygenpay/oxygen/pkg/api-dashboard/v1/model"
+	"github.com/pkg/errors"
 )
 
 func (h *Handler) ListBalances(c echo.Context) error {
@@ -48,3 +52,49 @@ func (h *Handler) balanceToResponse(b *wallet.Balance) *model.MerchantBalance {
 		MinimalWithdrawalAmountUSD: minWithdrawal.String(),
 	}
 }
+
+func (h *Handler) ListBalanceValuations(c echo.Context) error {
+	ctx := c.Request().Context()
+	mt := middleware.ResolveMerchant(c)
+
+	if c.QueryParam(queryParamAt) == "" {
+		return common.ValidationErrorItemResponse(c, queryParamAt, "required")
+	}
+
+	at, err := queryTime(c, queryParamAt, time.Now())
+	if err != nil {
+		return common.ValidationErrorItemResponse(c, queryParamAt, "invalid date")
+	}
+
+	valuations, err := h.exchange.RevalueBalances(ctx, mt.ID, at)
+	if err != nil {
+		return errors.Wrap(err, "unable to revalue balances")
+	}
+
+	return c.JSON(http.StatusOK, &model.MerchantBalanceValuationList{
+		Results: util.MapSlice(valuations, h.valuationToResponse),
+	})
+}
+
+func (h *Handler) valuationToResponse(v *exchange.Valuation) *model.MerchantBalanceValuation {
+	currency, _ := h.blockchain.GetCurrencyByTicker(v.Balance.Currency)
+
+	isTest := v.Balance.NetworkID != currency.NetworkID
+
+	res := &model.MerchantBalanceValuation{
+		ID:         v.Balance.UUID.String(),
+		Blockchain: currency.Blockchain.String(),
+		IsTest:     isTest,
+		Ticker:     currency.Ticker,
+		Amount:     v.Amount.String(),
+		UsdAmount:  "0",
+	}
+
+	// test balances have no real value
+	if !isTest && v.Rate != nil {
+		res.UsdAmount = v.USDAmount.String()
+		res.ExchangeRate = rateToResponse(v.Rate)
+	}
+
+	return res
+}
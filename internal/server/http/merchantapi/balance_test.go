@@ -3,6 +3,7 @@ package merchantapi_test
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/oxygenpay/oxygen/internal/auth"
@@ -11,6 +12,7 @@ import (
 	"github.com/oxygenpay/oxygen/internal/test"
 	"github.com/oxygenpay/oxygen/pkg/api-dashboard/v1/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBalanceRoutes(t *testing.T) {
@@ -102,4 +104,60 @@ func TestBalanceRoutes(t *testing.T) {
 		assert.Equal(t, "0", body.Results[2].UsdAmount) // < $0.01 is 0
 		assert.False(t, body.Results[2].IsTest)
 	})
+
+	t.Run("ListBalanceValuations", func(t *testing.T) {
+		const valuationRoute = "/api/dashboard/v1/merchant/:merchantId/balance/valuation"
+
+		// ARRANGE
+		// Given a merchant
+		mt, _ := tc.Must.CreateMerchant(t, user.ID)
+
+		// And ETH balance
+		withEthBalance := test.WithBalanceFromCurrency(eth, "500_000_000_000_000_000", false)
+		b1 := tc.Must.CreateBalance(t, wallet.EntityTypeMerchant, mt.ID, withEthBalance)
+
+		// And stored ETH/USD rate
+		conv, err := tc.Services.Blockchain.Convert(tc.Context, eth.Ticker, money.USD.String(), "1")
+		require.NoError(t, err)
+
+		valuate := func(at string) *test.Response {
+			return tc.Client.
+				GET().
+				Path(valuationRoute).
+				Param(paramMerchantID, mt.UUID.String()).
+				Query("at", at).
+				WithCSRF().
+				WithToken(token).
+				Do()
+		}
+
+		// ACT
+		res := valuate(time.Now().Add(time.Minute).Format(time.RFC3339))
+
+		// ASSERT
+		var body model.MerchantBalanceValuationList
+
+		assert.Equal(t, http.StatusOK, res.StatusCode(), res.String())
+		assert.NoError(t, res.JSON(&body))
+		require.Len(t, body.Results, 1)
+
+		assert.Equal(t, b1.UUID.String(), body.Results[0].ID)
+		assert.Equal(t, "0.5", body.Results[0].Amount)
+		assert.Equal(t, "900", body.Results[0].UsdAmount)
+		require.NotNil(t, body.Results[0].ExchangeRate)
+		assert.Equal(t, conv.RateID, body.Results[0].ExchangeRate.ID)
+
+		// And before the rate was stored USD amount is unknown
+		res = valuate(time.Now().Add(-time.Hour).Format(time.RFC3339))
+
+		body = model.MerchantBalanceValuationList{}
+		assert.Equal(t, http.StatusOK, res.StatusCode(), res.String())
+		assert.NoError(t, res.JSON(&body))
+		require.Len(t, body.Results, 1)
+		assert.Equal(t, "0", body.Results[0].UsdAmount)
+		assert.Nil(t, body.Results[0].ExchangeRate)
+
+		// And date is required
+		assert.Equal(t, http.StatusBadRequest, valuate("").StatusCode())
+	})
 }
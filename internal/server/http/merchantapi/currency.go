@@ -2,10 +2,14 @@ package merchantapi
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/go-openapi/strfmt"
 	"github.com/labstack/echo/v4"
 	"github.com/oxygenpay/oxygen/internal/server/http/common"
 	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/exchange"
+	"github.com/oxygenpay/oxygen/internal/util"
 	"github.com/oxygenpay/oxygen/pkg/api-dashboard/v1/model"
 	"github.com/pkg/errors"
 )
@@ -47,3 +51,61 @@ func conversionToResponse(conv blockchain.Conversion) *model.Conversion {
 		ConvertedAmount: conv.To.String(),
 	}
 }
+
+const (
+	queryParamStart = "start"
+	queryParamEnd   = "end"
+	queryParamAt    = "at"
+
+	defaultRatesPeriod = 24 * time.Hour
+)
+
+func (h *Handler) ListExchangeRates(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	from := c.QueryParam(queryParamFrom)
+	to := c.QueryParam(queryParamTo)
+
+	end, err := queryTime(c, queryParamEnd, time.Now())
+	if err != nil {
+		return common.ValidationErrorItemResponse(c, queryParamEnd, "invalid date")
+	}
+
+	start, err := queryTime(c, queryParamStart, end.Add(-defaultRatesPeriod))
+	if err != nil {
+		return common.ValidationErrorItemResponse(c, queryParamStart, "invalid date")
+	}
+
+	list, err := h.exchange.ListRates(ctx, from, to, start, end, 0)
+	switch {
+	case errors.Is(err, exchange.ErrValidation):
+		return common.ValidationErrorResponse(c, err)
+	case err != nil:
+		return errors.Wrapf(err, "unable to list exchange rates of %q/%q", from, to)
+	}
+
+	return c.JSON(http.StatusOK, &model.HistoricalExchangeRateList{
+		Results: util.MapSlice(list, rateToResponse),
+	})
+}
+
+func rateToResponse(rate *exchange.Rate) *model.HistoricalExchangeRate {
+	return &model.HistoricalExchangeRate{
+		ID:        rate.ID,
+		From:      rate.From,
+		To:        rate.To,
+		Rate:      rate.Rate,
+		Source:    rate.Source,
+		CreatedAt: strfmt.DateTime(rate.CreatedAt),
+	}
+}
+
+// queryTime parses RFC3339 date from query param or returns fallback if param is empty.
+func queryTime(c echo.Context, param string, fallback time.Time) (time.Time, error) {
+	raw := c.QueryParam(param)
+	if raw == "" {
+		return fallback, nil
+	}
+
+	return time.Parse(time.RFC3339, raw)
+}
@@ -4,19 +4,23 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/oxygenpay/oxygen/internal/auth"
 	"github.com/oxygenpay/oxygen/internal/money"
 	"github.com/oxygenpay/oxygen/internal/test"
 	"github.com/oxygenpay/oxygen/pkg/api-dashboard/v1/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
 	conversionRoute  = "/api/dashboard/v1/merchant/:merchantId/currency-convert"
+	ratesRoute       = "/api/dashboard/v1/merchant/:merchantId/currency-rate"
 	queryParamFrom   = "from"
 	queryParamTo     = "to"
 	queryParamAmount = "amount"
+	queryParamStart  = "start"
 )
 
 func TestHandler_GetCurrencyConvert(t *testing.T) {
@@ -170,3 +174,60 @@ func TestHandler_GetCurrencyConvert(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_ListExchangeRates(t *testing.T) {
+	tc := test.NewIntegrationTest(t)
+
+	tc.Providers.TatumMock.SetupRates("ETH", money.USD, 1000)
+
+	// Given a user and a merchant
+	user, token := tc.Must.CreateUser(t, auth.GoogleUser{Name: "A", Email: "john@gmail.com"})
+	mt, _ := tc.Must.CreateMerchant(t, user.ID)
+
+	// And a conversion that used ETH/USD rate
+	conv, err := tc.Services.Blockchain.Convert(tc.Context, "ETH", "USD", "2")
+	require.NoError(t, err)
+	require.NotZero(t, conv.RateID)
+
+	list := func(from, to, start string) *test.Response {
+		req := tc.Client.
+			GET().
+			Path(ratesRoute).
+			Query(queryParamFrom, from).
+			Query(queryParamTo, to).
+			Param(paramMerchantID, mt.UUID.String())
+
+		if start != "" {
+			req = req.Query(queryParamStart, start)
+		}
+
+		return req.WithCSRF().WithToken(token).Do()
+	}
+
+	t.Run("Returns stored rate", func(t *testing.T) {
+		// ACT
+		res := list("eth", "usd", "")
+
+		// ASSERT
+		var body model.HistoricalExchangeRateList
+
+		assert.Equal(t, http.StatusOK, res.StatusCode(), res.String())
+		assert.NoError(t, res.JSON(&body))
+		require.Len(t, body.Results, 1)
+
+		assert.Equal(t, conv.RateID, body.Results[0].ID)
+		assert.Equal(t, "ETH", body.Results[0].From)
+		assert.Equal(t, "USD", body.Results[0].To)
+		assert.Equal(t, 1000.0, body.Results[0].Rate)
+		assert.Equal(t, "tatum", body.Results[0].Source)
+	})
+
+	t.Run("Validates input", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, list("", "USD", "").StatusCode())
+		assert.Equal(t, http.StatusBadRequest, list("ETH", "USD", "yesterday").StatusCode())
+
+		// start is after the end
+		future := time.Now().Add(time.Hour).Format(time.RFC3339)
+		assert.Equal(t, http.StatusBadRequest, list("ETH", "USD", future).StatusCode())
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/oxygenpay/oxygen/internal/auth"
 	"github.com/oxygenpay/oxygen/internal/bus"
 	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/exchange"
 	"github.com/oxygenpay/oxygen/internal/service/merchant"
 	"github.com/oxygenpay/oxygen/internal/service/payment"
 	"github.com/oxygenpay/oxygen/internal/service/wallet"
@@ -20,6 +21,7 @@ type Handler struct {
 	tokens     *auth.TokenAuthManager
 	payments   *payment.Service
 	wallets    *wallet.Service
+	exchange   *exchange.Service
 	blockchain BlockchainService
 	publisher  bus.Publisher
 	logger     *zerolog.Logger
@@ -30,6 +32,7 @@ func NewHandler(
 	tokens *auth.TokenAuthManager,
 	payments *payment.Service,
 	wallets *wallet.Service,
+	exchangeService *exchange.Service,
 	blockchainService BlockchainService,
 	publisher bus.Publisher,
 	logger *zerolog.Logger,
@@ -41,6 +44,7 @@ func NewHandler(
 		tokens:     tokens,
 		payments:   payments,
 		wallets:    wallets,
+		exchange:   exchangeService,
 		blockchain: blockchainService,
 		publisher:  publisher,
 		logger:     &log,
@@ -172,6 +172,7 @@ func paymentToResponse(pr payment.PaymentWithRelations) *model.Payment {
 		if tx != nil {
 			info.SelectedCurrency = util.Ptr(tx.Currency.DisplayName())
 			info.ServiceFee = util.Ptr(tx.ServiceFee.String())
+			info.Confirmations, info.RequiredConfirmations = tx.Confirmations()
 		}
 
 		if customer != nil {
@@ -11,6 +11,7 @@ import (
 	"github.com/oxygenpay/oxygen/internal/money"
 	"github.com/oxygenpay/oxygen/internal/server/http/common"
 	"github.com/oxygenpay/oxygen/internal/server/http/middleware"
+	"github.com/oxygenpay/oxygen/internal/service/exchange"
 	"github.com/oxygenpay/oxygen/internal/service/merchant"
 	"github.com/oxygenpay/oxygen/internal/service/payment"
 	"github.com/oxygenpay/oxygen/internal/service/wallet"
@@ -52,6 +53,12 @@ func (h *Handler) CreateWithdrawal(c echo.Context) error {
 		return common.ValidationErrorItemResponse(c, "amount", err.Error())
 	case errors.Is(err, payment.ErrWithdrawalAmountTooSmall):
 		return common.ValidationErrorItemResponse(c, "amount", err.Error())
+	case errors.Is(err, exchange.ErrQuoteNotFound):
+		return common.ValidationErrorItemResponse(c, "feeQuoteId", "quote not found")
+	case errors.Is(err, exchange.ErrQuoteExpired):
+		return common.ValidationErrorItemResponse(c, "feeQuoteId", "quote is expired")
+	case errors.Is(err, exchange.ErrQuoteUsed):
+		return common.ValidationErrorItemResponse(c, "feeQuoteId", "quote is already used")
 	case err != nil:
 		return err
 	}
@@ -108,14 +115,28 @@ func parseWithdrawalRequest(req model.CreateWithdrawalRequest) (payment.CreateWi
 		}))
 	}
 
+	var feeQuoteID *uuid.UUID
+	if req.FeeQuoteID != nil {
+		id, err := uuid.Parse(*req.FeeQuoteID)
+		if err != nil {
+			errComposite.Errors = append(errComposite.Errors, common.WrapErrorItem(&model.ErrorResponseItem{
+				Field:   "feeQuoteId",
+				Message: "invalid uuid",
+			}))
+		}
+
+		feeQuoteID = &id
+	}
+
 	if len(errComposite.Errors) > 0 {
 		return payment.CreateWithdrawalProps{}, errComposite
 	}
 
 	return payment.CreateWithdrawalProps{
-		BalanceID: balanceID,
-		AddressID: addressID,
-		AmountRaw: amount,
+		BalanceID:  balanceID,
+		AddressID:  addressID,
+		AmountRaw:  amount,
+		FeeQuoteID: feeQuoteID,
 	}, nil
 }
 
@@ -132,5 +153,7 @@ func withdrawalFeeToResponse(fee *payment.WithdrawalFee) *model.WithdrawalFee {
 		UsdFee:       usdFee,
 		CurrencyFee:  fee.CryptoFee.String(),
 		IsTest:       fee.IsTest,
+		QuoteID:      fee.QuoteID.String(),
+		ExpiresAt:    strfmt.DateTime(fee.ExpiresAt),
 	}
 }
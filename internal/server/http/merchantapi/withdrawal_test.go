@@ -4,9 +4,11 @@ import (
 	"net/http"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/exchange"
 	"github.com/oxygenpay/oxygen/internal/service/merchant"
 	"github.com/oxygenpay/oxygen/internal/service/payment"
 	"github.com/oxygenpay/oxygen/internal/service/wallet"
@@ -101,6 +103,95 @@ func TestWithdrawalRoutes(t *testing.T) {
 			assert.Equal(t, addr.ID, r.Address.ID)
 		})
 
+		t.Run("Creates withdrawal with locked fee quote", func(t *testing.T) {
+			// ARRANGE
+			// Given a merchant with address and balance
+			mt, _ := tc.Must.CreateMerchant(t, user.ID)
+
+			addr, err := tc.Services.Merchants.CreateMerchantAddress(tc.Context, mt.ID, merchant.CreateMerchantAddressParams{
+				Name:       "A1",
+				Blockchain: "ETH",
+				Address:    "0x690b9a9e9aa1c9db991c7721a92d351db4fac990",
+			})
+			require.NoError(t, err)
+
+			withETH := test.WithBalanceFromCurrency(eth, "500_000_000_000_000_000", false)
+			balance := tc.Must.CreateBalance(t, wallet.EntityTypeMerchant, mt.ID, withETH)
+
+			// And withdrawal fee preview
+			res := tc.Client.
+				GET().
+				Path(withdrawalFeeRoute).
+				WithToken(token).
+				Param(paramMerchantID, mt.UUID.String()).
+				Query(queryParamBalanceID, balance.UUID.String()).
+				Do()
+
+			var fee model.WithdrawalFee
+			require.Equal(t, http.StatusOK, res.StatusCode(), res.String())
+			require.NoError(t, res.JSON(&fee))
+			require.NotEmpty(t, fee.QuoteID)
+			require.True(t, time.Time(fee.ExpiresAt).After(time.Now()))
+
+			createWithdrawal := func(quoteID string) *test.Response {
+				return tc.Client.
+					POST().
+					Path(withdrawalsRoute).
+					WithToken(token).
+					Param(paramMerchantID, mt.UUID.String()).
+					JSON(&model.CreateWithdrawalRequest{
+						AddressID:  addr.UUID.String(),
+						BalanceID:  balance.UUID.String(),
+						Amount:     "0.1",
+						FeeQuoteID: &quoteID,
+					}).
+					Do()
+			}
+
+			// ACT
+			res = createWithdrawal(fee.QuoteID)
+
+			// ASSERT
+			var body model.Withdrawal
+			assert.Equal(t, http.StatusCreated, res.StatusCode(), res.String())
+			assert.NoError(t, res.JSON(&body))
+
+			// Check that quote is linked to the withdrawal
+			r, err := tc.Services.Payment.GetByMerchantOrderIDWithRelations(tc.Context, mt.ID, uuid.MustParse(body.PaymentID))
+			require.NoError(t, err)
+
+			quote, err := tc.Services.Payment.GetWithdrawalFeeQuote(tc.Context, r.Payment)
+			require.NoError(t, err)
+			assert.Equal(t, fee.QuoteID, quote.UUID.String())
+			assert.Equal(t, fee.CurrencyFee, quote.To.String())
+
+			// And quote can't be used twice
+			res = createWithdrawal(fee.QuoteID)
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode(), res.String())
+			assert.Contains(t, res.String(), "quote is already used")
+
+			// And expired quote is rejected
+			conv, err := tc.Services.Blockchain.FiatToCrypto(tc.Context, lo.Must(money.USD.MakeAmount("300")), eth)
+			require.NoError(t, err)
+
+			expired, err := tc.Services.Exchange.CreateQuote(tc.Context, exchange.CreateQuoteParams{
+				MerchantID: mt.ID,
+				Type:       exchange.QuoteWithdrawalFee,
+				Conversion: conv,
+				TTL:        time.Nanosecond,
+			})
+			require.NoError(t, err)
+
+			res = createWithdrawal(expired.UUID.String())
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode(), res.String())
+			assert.Contains(t, res.String(), "quote is expired")
+
+			// And unknown quote is rejected
+			res = createWithdrawal(uuid.New().String())
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode(), res.String())
+			assert.Contains(t, res.String(), "quote not found")
+		})
+
 		t.Run("Fails", func(t *testing.T) {
 			t.Run("Invalid request", func(t *testing.T) {
 				// ARRANGE
@@ -8,25 +8,40 @@ import (
 	mw "github.com/labstack/echo/v4/middleware"
 )
 
-func BodyDump() echo.MiddlewareFunc {
-	return mw.BodyDump(func(c echo.Context, req, res []byte) {
-		tpl := "%s %s. Response: %s"
-		args := []any{
-			c.Request().Method,
-			c.Request().URL.Path,
-		}
-
-		if len(req) > 0 {
-			tpl = "%s %s with body %s. Response: %s"
-			args = append(args, string(req))
-		}
-
-		args = append(args, string(res))
-
-		c.Logger().Infof(tpl, args...)
+// BodyDump logs request & response bodies. Routes from skipPaths (e.g. the ones that carry secrets)
+// are not logged.
+func BodyDump(skipPaths ...string) echo.MiddlewareFunc {
+	skip := make(map[string]struct{}, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = struct{}{}
+	}
+
+	return mw.BodyDumpWithConfig(mw.BodyDumpConfig{
+		Skipper: func(c echo.Context) bool {
+			_, ok := skip[c.Path()]
+			return ok
+		},
+		Handler: dumpBody,
 	})
 }
 
+func dumpBody(c echo.Context, req, res []byte) {
+	tpl := "%s %s. Response: %s"
+	args := []any{
+		c.Request().Method,
+		c.Request().URL.Path,
+	}
+
+	if len(req) > 0 {
+		tpl = "%s %s with body %s. Response: %s"
+		args = append(args, string(req))
+	}
+
+	args = append(args, string(res))
+
+	c.Logger().Infof(tpl, args...)
+}
+
 const RequestIDKey = "request_id"
 
 type ctxRequestID struct{}
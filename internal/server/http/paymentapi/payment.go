@@ -181,6 +181,8 @@ func (h *Handler) LockPaymentOptions(c echo.Context) error {
 	switch {
 	case errors.Is(err, processing.ErrPaymentOptionsMissing):
 		return common.ValidationErrorResponse(c, processing.ErrPaymentOptionsMissing)
+	case errors.Is(err, processing.ErrQuoteExpired):
+		return common.ValidationErrorResponse(c, processing.ErrQuoteExpired)
 	case err != nil:
 		h.logger.
 			Warn().Err(err).
@@ -235,7 +237,7 @@ func customerToResponse(c *payment.Customer) *model.Customer {
 }
 
 func paymentMethodToResponse(m *payment.Method) *model.PaymentMethod {
-	return &model.PaymentMethod{
+	res := &model.PaymentMethod{
 		Blockchain:     m.Currency.Blockchain.String(),
 		BlockchainName: m.Currency.BlockchainName,
 		DisplayName:    m.Currency.DisplayName(),
@@ -244,6 +246,15 @@ func paymentMethodToResponse(m *payment.Method) *model.PaymentMethod {
 		NetworkID:      m.NetworkID,
 		IsTest:         m.IsTest,
 	}
+
+	if m.Quote != nil {
+		res.Amount = m.Quote.To.String()
+		res.QuoteID = m.Quote.UUID.String()
+		res.QuoteExpiresAt = strfmt.DateTime(m.Quote.ExpiresAt)
+		res.QuoteExpiresIn = int64(m.Quote.TTL().Seconds())
+	}
+
+	return res
 }
 
 func paymentInfoToResponse(i *processing.PaymentInfo) *model.PaymentInfo {
@@ -263,6 +274,9 @@ func paymentInfoToResponse(i *processing.PaymentInfo) *model.PaymentInfo {
 		ExpiresAt:             strfmt.DateTime(i.ExpiresAt),
 		ExpirationDurationMin: i.ExpirationDurationMin,
 
+		Confirmations:         i.Confirmations,
+		RequiredConfirmations: i.RequiredConfirmations,
+
 		SuccessAction:  successAction,
 		SuccessURL:     i.SuccessURL,
 		SuccessMessage: i.SuccessMessage,
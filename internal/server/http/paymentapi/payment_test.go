@@ -210,6 +210,13 @@ func TestHandlers(t *testing.T) {
 			assert.Equal(t, currency.NetworkID, body.PaymentMethod.NetworkID)
 			assert.False(t, body.PaymentMethod.IsTest)
 
+			// Check that crypto amount is locked by a quote
+			require.NotNil(t, method.Quote)
+			assert.Equal(t, method.Quote.UUID.String(), body.PaymentMethod.QuoteID)
+			assert.Equal(t, method.Quote.To.String(), body.PaymentMethod.Amount)
+			assert.Equal(t, method.TX().Amount, method.Quote.To)
+			assert.Positive(t, body.PaymentMethod.QuoteExpiresIn)
+
 			t.Run("Returns updated payment method", func(t *testing.T) {
 				// ARRANGE
 				ticker := "MATIC"
@@ -844,14 +851,16 @@ func TestHandlers(t *testing.T) {
 		mt, _ := tc.Must.CreateMerchant(t, 1)
 
 		testCases := []struct {
-			name     string
-			customer string
-			ticker   string
-			error    bool
+			name        string
+			customer    string
+			ticker      string
+			expireQuote bool
+			error       bool
 		}{
 			{name: "no customer, no payment selected", error: true},
 			{name: "no ticker selected", customer: "test@me.com", error: true},
 			{name: "all good", customer: "test@me.com", ticker: "ETH"},
+			{name: "quote expired", customer: "test@me.com", ticker: "ETH", expireQuote: true, error: true},
 		}
 
 		for _, testCase := range testCases {
@@ -872,6 +881,10 @@ func TestHandlers(t *testing.T) {
 					require.NoError(t, err)
 				}
 
+				if testCase.expireQuote {
+					tc.Must.ExpirePaymentQuotes(t, p.ID)
+				}
+
 				// ACT
 				res := tc.
 					PUT().WithCSRF().
@@ -882,6 +895,15 @@ func TestHandlers(t *testing.T) {
 				// ASSERT
 				if testCase.error {
 					assert.Equal(t, http.StatusBadRequest, res.StatusCode(), res.String())
+
+					if testCase.expireQuote {
+						// selecting the same method again refreshes the quote
+						method, err := tc.Services.Processing.SetPaymentMethod(tc.Context, p, testCase.ticker)
+						require.NoError(t, err)
+						require.NotNil(t, method.Quote)
+						assert.False(t, method.Quote.Expired())
+					}
+
 					return
 				}
 
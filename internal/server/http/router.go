@@ -98,6 +98,7 @@ func WithDashboardAPI(
 
 		// Currency
 		merchantGroup.GET("/currency-convert", handler.GetCurrencyConvert)
+		merchantGroup.GET("/currency-rate", handler.ListExchangeRates)
 
 		setupCommonMerchantRoutes(merchantGroup, handler)
 	}
@@ -134,6 +135,7 @@ func setupCommonMerchantRoutes(g *echo.Group, handler *merchantapi.Handler) {
 	paymentLinkGroup.POST("", handler.CreatePaymentLink)
 
 	g.GET("/balance", handler.ListBalances)
+	g.GET("/balance/valuation", handler.ListBalanceValuations)
 
 	g.GET("/customer", handler.ListCustomers)
 	g.GET("/customer/:customerId", handler.GetCustomerDetails)
@@ -179,6 +181,7 @@ func WithWebhookAPI(handler *webhook.Handler) Opt {
 	return func(s *Server) {
 		webhookAPI := s.echo.Group("/api/webhook/v1")
 		webhookAPI.POST("/tatum/:networkId/:walletId", handler.ReceiveTatum)
+		webhookAPI.POST("/:provider/:blockchain/:networkId", handler.ReceiveNotification)
 	}
 }
 
@@ -205,6 +208,9 @@ func WithInternalAPI(h *v1.Handler) Opt {
 		admin.GET("/wallet/:walletID", h.GetWallet)
 		admin.POST("/wallet", h.CreateWallet)
 		admin.POST("/wallet/bulk", h.BulkCreateWallets)
+		admin.GET("/wallet/:walletID/nonce", h.GetWalletNonce)
+		admin.POST("/wallet/:walletID/nonce/reconcile", h.ReconcileWalletNonce)
+		admin.POST("/wallet/:walletID/message", h.SignWalletMessage)
 		admin.POST("/job", h.RunSchedulerJob)
 
 		admin.POST("/blockchain/fee", h.CalculateTransactionFee)
@@ -129,9 +129,10 @@ func withHealthcheck(e *echo.Echo) {
 	})
 }
 
-func WithBodyDump() Opt {
+// WithBodyDump logs request & response bodies except for skipPaths routes.
+func WithBodyDump(skipPaths ...string) Opt {
 	return func(s *Server) {
-		s.echo.Use(middleware.BodyDump())
+		s.echo.Use(middleware.BodyDump(skipPaths...))
 	}
 }
 
@@ -13,6 +13,8 @@ type Handler struct {
 const (
 	paramWalletID   = "walletId"
 	paramNetworkID  = "networkId"
+	paramProvider   = "provider"
+	paramBlockchain = "blockchain"
 	headerTatumHMAC = "x-payload-hash"
 )
 
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/oxygenpay/oxygen/internal/log"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/provider/notify"
+	"github.com/oxygenpay/oxygen/internal/server/http/common"
+	"github.com/pkg/errors"
+)
+
+// ReceiveNotification handles webhooks of notifications providers such as Alchemy, QuickNode or Moralis.
+func (h *Handler) ReceiveNotification(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	// 1. Parse request params
+	providerName := c.Param(paramProvider)
+	networkID := c.Param(paramNetworkID)
+	chain := money.Blockchain(strings.ToUpper(c.Param(paramBlockchain)))
+
+	provider, err := h.processing.NotificationProvider(providerName, chain)
+	if err != nil {
+		return common.ValidationErrorResponse(c, err)
+	}
+
+	// 2. Verify signature
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	if err := provider.VerifySignature(c.Request().Header, body); err != nil {
+		h.logger.Error().Err(err).
+			EmbedObject(log.Ctx(ctx)).
+			Str("provider", providerName).
+			Str("body", string(body)).
+			Msg("invalid signature")
+
+		return common.ValidationErrorResponse(c, errors.New("invalid signature"))
+	}
+
+	// 3. Parse request
+	notifications, err := provider.Parse(body)
+	if err != nil {
+		return common.ValidationErrorResponse(c, err)
+	}
+
+	if err := notify.VerifyNetwork(notifications, networkID); err != nil {
+		h.logger.Error().Err(err).
+			EmbedObject(log.Ctx(ctx)).
+			Str("provider", providerName).
+			Str("network_id", networkID).
+			Msg("notification network mismatch")
+
+		return common.ValidationErrorResponse(c, err)
+	}
+
+	// 4. Process notifications
+	if err := h.processing.ProcessNotifications(ctx, chain, networkID, notifications); err != nil {
+		h.logger.Error().Err(err).
+			Str("provider", providerName).
+			Str("blockchain", chain.String()).
+			Str("network_id", networkID).
+			Interface("notifications", notifications).
+			Msg("unable to process notifications")
+
+		return c.JSON(http.StatusBadRequest, "unable to process notifications")
+	}
+
+	h.logger.Info().
+		Str("provider", providerName).
+		Str("blockchain", chain.String()).
+		Str("network_id", networkID).
+		Int("notifications_count", len(notifications)).
+		Msg("processed incoming notifications")
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,112 @@
+package blockchain
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/pkg/errors"
+)
+
+type Config struct {
+	// Confirmations maps blockchain to required confirmations. Blockchain can be suffixed
+	// with USD amount threshold: "ETH>10000" applies to transactions with USD amount >= 10000.
+	Confirmations map[string]string `yaml:"confirmations" env:"BLOCKCHAIN_CONFIRMATIONS" env-description:"Required confirmations per blockchain and USD amount band. Example: 'ETH:12,ETH>10000:24,MATIC:30'"`
+
+	// ReceiptCrossCheckUSD USD amount starting from which incoming transaction receipt
+	// is verified by two independent RPC endpoints before confirmation. Zero disables the check.
+	// Such transactions stay unconfirmed on networks with a single RPC endpoint.
+	ReceiptCrossCheckUSD float64 `yaml:"receipt_cross_check_usd" env:"BLOCKCHAIN_RECEIPT_CROSS_CHECK_USD" env-default:"10000" env-description:"USD amount of incoming transaction that requires receipt cross-check between two RPC endpoints (see RPC_ENDPOINTS). Such transactions are not confirmed on networks with a single endpoint. 0 to disable"`
+}
+
+// ConfirmationsResolver resolves amount of block confirmations required for transaction finality.
+type ConfirmationsResolver interface {
+	RequiredConfirmations(chain money.Blockchain, usdAmount money.Money) int64
+}
+
+var defaultConfirmations = map[money.Blockchain]int64{
+	"ETH":   12,
+	"MATIC": 30,
+	"BSC":   15,
+	"TRON":  10,
+}
+
+// confirmationsRule nil usdThreshold represents base rule of the blockchain.
+type confirmationsRule struct {
+	usdThreshold  *money.Money
+	confirmations int64
+}
+
+// confirmationsPolicy contains rules per blockchain sorted by USD threshold (desc).
+// Base rule (w/o threshold) is always the last one.
+type confirmationsPolicy map[money.Blockchain][]confirmationsRule
+
+func parseConfirmations(cfg map[string]string) (confirmationsPolicy, error) {
+	base := make(map[money.Blockchain]int64, len(defaultConfirmations))
+	for chain, confirmations := range defaultConfirmations {
+		base[chain] = confirmations
+	}
+
+	bands := make(map[money.Blockchain][]confirmationsRule)
+
+	for key, value := range cfg {
+		confirmations, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || confirmations < 0 {
+			return nil, errors.Errorf("invalid confirmations value %q for %q", value, key)
+		}
+
+		chainRaw, thresholdRaw, hasThreshold := strings.Cut(key, ">")
+		chain := money.Blockchain(strings.ToUpper(strings.TrimSpace(chainRaw)))
+
+		if !hasThreshold {
+			base[chain] = confirmations
+			continue
+		}
+
+		thresholdFloat, err := strconv.ParseFloat(strings.TrimSpace(thresholdRaw), 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid USD threshold in %q", key)
+		}
+
+		threshold, err := money.FiatFromFloat64(money.USD, thresholdFloat)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid USD threshold in %q", key)
+		}
+
+		bands[chain] = append(bands[chain], confirmationsRule{usdThreshold: &threshold, confirmations: confirmations})
+	}
+
+	policy := make(confirmationsPolicy)
+
+	for chain, rules := range bands {
+		sort.Slice(rules, func(i, j int) bool {
+			return rules[i].usdThreshold.GreaterThan(*rules[j].usdThreshold)
+		})
+
+		policy[chain] = rules
+	}
+
+	for chain, confirmations := range base {
+		policy[chain] = append(policy[chain], confirmationsRule{confirmations: confirmations})
+	}
+
+	return policy, nil
+}
+
+// required returns confirmations of the first rule which threshold is less or equal to usdAmount.
+func (p confirmationsPolicy) required(chain money.Blockchain, usdAmount money.Money) int64 {
+	for _, rule := range p[chain] {
+		if rule.usdThreshold == nil || usdAmount.GreaterThanOrEqual(*rule.usdThreshold) {
+			return rule.confirmations
+		}
+	}
+
+	return 0
+}
+
+// RequiredConfirmations returns amount of confirmations required for transaction of given USD amount.
+// Pass empty money (money.Money{}) to get base requirement of the blockchain.
+func (s *Service) RequiredConfirmations(chain money.Blockchain, usdAmount money.Money) int64 {
+	return s.confirmations.required(chain, usdAmount)
+}
@@ -0,0 +1,52 @@
+package blockchain_test
+
+import (
+	"testing"
+
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_RequiredConfirmations(t *testing.T) {
+	logger := zerolog.Nop()
+
+	config := blockchain.Config{
+		Confirmations: map[string]string{
+			"ETH":        "10",
+			"ETH>10000":  "24",
+			"ETH>1000":   "16",
+			"matic>5000": "64",
+		},
+	}
+
+	s := blockchain.New(config, blockchain.NewCurrencies(), blockchain.Providers{}, false, &logger)
+
+	usd := func(amount float64) money.Money {
+		m, err := money.FiatFromFloat64(money.USD, amount)
+		assert.NoError(t, err)
+
+		return m
+	}
+
+	for _, tt := range []struct {
+		chain    money.Blockchain
+		amount   money.Money
+		expected int64
+	}{
+		{chain: "ETH", amount: money.Money{}, expected: 10},
+		{chain: "ETH", amount: usd(999.99), expected: 10},
+		{chain: "ETH", amount: usd(1000), expected: 16},
+		{chain: "ETH", amount: usd(10000), expected: 24},
+		{chain: "ETH", amount: usd(50000), expected: 24},
+		{chain: "MATIC", amount: usd(100), expected: 30},
+		{chain: "MATIC", amount: usd(5000), expected: 64},
+		{chain: "TRON", amount: usd(100000), expected: 10},
+		{chain: "BTC", amount: usd(1), expected: 0},
+	} {
+		t.Run(string(tt.chain)+"/"+tt.amount.String(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, s.RequiredConfirmations(tt.chain, tt.amount))
+		})
+	}
+}
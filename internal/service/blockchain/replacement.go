@@ -0,0 +1,107 @@
+package blockchain
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// ReplacementFeeBump EVM nodes accept transaction that replaces pending one with the same nonce
+// only if both max fee and priority fee are at least 10% higher. Let's bump by 12.5% to be safe.
+const ReplacementFeeBump = 1.125
+
+var ErrReplacementUnsupported = errors.New("transaction replacement is not supported")
+
+// GasPrices returns max fee per gas & priority fee per gas (in wei) of EVM fee.
+func (f *Fee) GasPrices() (gasPrice, priorityFee string, err error) {
+	switch fee := f.raw.(type) {
+	case EthFee:
+		return fee.GasPrice, fee.PriorityFee, nil
+	case MaticFee:
+		return fee.GasPrice, fee.PriorityFee, nil
+	case BSCFee:
+		return fee.GasPrice, fee.PriorityFee, nil
+	}
+
+	return "", "", ErrReplacementUnsupported
+}
+
+// ReplacementFee returns fee for transaction that replaces stuck one. Both gas price and priority fee
+// are the max of current network fee and previous fee multiplied by ReplacementFeeBump.
+// Only fields required for transaction signing are updated.
+func ReplacementFee(current Fee, prevGasPrice, prevPriorityFee string) (Fee, error) {
+	switch fee := current.raw.(type) {
+	case EthFee:
+		gasPrice, priorityFee, totalCost, err := replacementPrices(fee.GasUnits, fee.GasPrice, fee.PriorityFee, prevGasPrice, prevPriorityFee)
+		if err != nil {
+			return Fee{}, err
+		}
+
+		fee.GasPrice, fee.PriorityFee, fee.TotalCostWEI = gasPrice, priorityFee, totalCost
+		current.raw = fee
+	case MaticFee:
+		gasPrice, priorityFee, totalCost, err := replacementPrices(fee.GasUnits, fee.GasPrice, fee.PriorityFee, prevGasPrice, prevPriorityFee)
+		if err != nil {
+			return Fee{}, err
+		}
+
+		fee.GasPrice, fee.PriorityFee, fee.TotalCostWEI = gasPrice, priorityFee, totalCost
+		current.raw = fee
+	case BSCFee:
+		gasPrice, priorityFee, totalCost, err := replacementPrices(fee.GasUnits, fee.GasPrice, fee.PriorityFee, prevGasPrice, prevPriorityFee)
+		if err != nil {
+			return Fee{}, err
+		}
+
+		fee.GasPrice, fee.PriorityFee, fee.TotalCostWEI = gasPrice, priorityFee, totalCost
+		current.raw = fee
+	default:
+		return Fee{}, errors.Wrap(ErrReplacementUnsupported, current.Currency.Blockchain.String())
+	}
+
+	return current, nil
+}
+
+func replacementPrices(
+	gasUnits uint,
+	gasPrice, priorityFee, prevGasPrice, prevPriorityFee string,
+) (string, string, string, error) {
+	newGasPrice, err := bumpWei(gasPrice, prevGasPrice)
+	if err != nil {
+		return "", "", "", errors.Wrap(err, "unable to bump gas price")
+	}
+
+	newPriorityFee, err := bumpWei(priorityFee, prevPriorityFee)
+	if err != nil {
+		return "", "", "", errors.Wrap(err, "unable to bump priority fee")
+	}
+
+	totalCost := new(big.Int).Add(newGasPrice, newPriorityFee)
+	totalCost.Mul(totalCost, new(big.Int).SetUint64(uint64(gasUnits)))
+
+	return newGasPrice.String(), newPriorityFee.String(), totalCost.String(), nil
+}
+
+// bumpWei returns max(current, ceil(prev * ReplacementFeeBump)).
+func bumpWei(current, prev string) (*big.Int, error) {
+	c, ok := new(big.Int).SetString(current, 10)
+	if !ok {
+		return nil, errors.Wrapf(ErrParseMoney, "invalid current value %q", current)
+	}
+
+	p, ok := new(big.Int).SetString(prev, 10)
+	if !ok {
+		return nil, errors.Wrapf(ErrParseMoney, "invalid previous value %q", prev)
+	}
+
+	// p * 1125 / 1000 rounded up
+	bumped := new(big.Int).Mul(p, big.NewInt(int64(ReplacementFeeBump*1000)))
+	bumped.Add(bumped, big.NewInt(999))
+	bumped.Div(bumped, big.NewInt(1000))
+
+	if bumped.Cmp(c) > 0 {
+		return bumped, nil
+	}
+
+	return c, nil
+}
@@ -0,0 +1,104 @@
+package blockchain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplacementFee(t *testing.T) {
+	eth := money.CryptoCurrency{Blockchain: "ETH", Ticker: "ETH", Type: money.Coin}
+	tron := money.CryptoCurrency{Blockchain: "TRON", Ticker: "TRON", Type: money.Coin}
+
+	for _, tt := range []struct {
+		name                string
+		current             blockchain.Fee
+		prevGasPrice        string
+		prevPriorityFee     string
+		expectedGasPrice    string
+		expectedPriorityFee string
+		expectedTotalCost   string
+		expectError         bool
+	}{
+		{
+			name: "previous fee is bumped",
+			current: blockchain.NewFee(eth, time.Now(), false, blockchain.EthFee{
+				GasUnits:    21_000,
+				GasPrice:    "10000000000",
+				PriorityFee: "1000000000",
+			}),
+			prevGasPrice:        "10000000000",
+			prevPriorityFee:     "1000000000",
+			expectedGasPrice:    "11250000000",
+			expectedPriorityFee: "1125000000",
+			expectedTotalCost:   "259875000000000",
+		},
+		{
+			name: "current fee is higher than bumped one",
+			current: blockchain.NewFee(eth, time.Now(), false, blockchain.EthFee{
+				GasUnits:    21_000,
+				GasPrice:    "30000000000",
+				PriorityFee: "1000000000",
+			}),
+			prevGasPrice:        "10000000000",
+			prevPriorityFee:     "1000000000",
+			expectedGasPrice:    "30000000000",
+			expectedPriorityFee: "1125000000",
+			expectedTotalCost:   "653625000000000",
+		},
+		{
+			name: "bumped value is rounded up",
+			current: blockchain.NewFee(eth, time.Now(), false, blockchain.EthFee{
+				GasUnits:    1,
+				GasPrice:    "1",
+				PriorityFee: "1",
+			}),
+			prevGasPrice:        "3",
+			prevPriorityFee:     "1",
+			expectedGasPrice:    "4",
+			expectedPriorityFee: "2",
+			expectedTotalCost:   "6",
+		},
+		{
+			name: "invalid previous fee",
+			current: blockchain.NewFee(eth, time.Now(), false, blockchain.EthFee{
+				GasUnits:    21_000,
+				GasPrice:    "10000000000",
+				PriorityFee: "1000000000",
+			}),
+			prevGasPrice:    "abc",
+			prevPriorityFee: "1000000000",
+			expectError:     true,
+		},
+		{
+			name:            "tron is not supported",
+			current:         blockchain.NewFee(tron, time.Now(), false, blockchain.TronFee{FeeLimitSun: 1000}),
+			prevGasPrice:    "1",
+			prevPriorityFee: "1",
+			expectError:     true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fee, err := blockchain.ReplacementFee(tt.current, tt.prevGasPrice, tt.prevPriorityFee)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			gasPrice, priorityFee, err := fee.GasPrices()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedGasPrice, gasPrice)
+			assert.Equal(t, tt.expectedPriorityFee, priorityFee)
+
+			ethFee, err := fee.ToEthFee()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTotalCost, ethFee.TotalCostWEI)
+		})
+	}
+}
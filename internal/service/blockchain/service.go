@@ -4,9 +4,11 @@ import (
 	"time"
 
 	"github.com/jellydator/ttlcache/v3"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/provider/rates"
+	"github.com/oxygenpay/oxygen/internal/provider/rpcpool"
 	"github.com/oxygenpay/oxygen/internal/provider/tatum"
 	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
-	client "github.com/oxygenpay/tatum-sdk/tatum"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
@@ -18,11 +20,16 @@ var (
 	ErrParseMoney         = errors.New("unable to parse money value")
 	ErrInsufficientFunds  = errors.New("wallet has insufficient funds")
 	ErrInvalidTransaction = errors.New("transaction is invalid")
+	ErrTxNotFound         = errors.New("transaction not found")
 )
 
 type Providers struct {
 	Tatum    *tatum.Provider
 	Trongrid *trongrid.Provider
+	Rates    rates.Provider
+
+	// RPC optional pool of RPC endpoints with failover. If nil, Tatum's RPC gateway is used.
+	RPC *rpcpool.Pool
 }
 
 type Service struct {
@@ -30,29 +37,80 @@ type Service struct {
 	providers Providers
 	logger    *zerolog.Logger
 
-	ratesCache *ttlcache.Cache[string, client.ExchangeRate]
+	confirmations       confirmationsPolicy
+	crossCheckThreshold *money.Money
+
+	ratesCache *ttlcache.Cache[string, rates.Rate]
 }
 
 const exchangeRateCacheTTL = time.Second * 30
 
-func New(currencies *CurrencyResolver, providers Providers, enableCache bool, logger *zerolog.Logger) *Service {
+func New(
+	config Config,
+	currencies *CurrencyResolver,
+	providers Providers,
+	enableCache bool,
+	logger *zerolog.Logger,
+) *Service {
 	log := logger.With().Str("channel", "blockchain_service").Logger()
 
+	confirmations, err := parseConfirmations(config.Confirmations)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid confirmations config")
+	}
+
+	var crossCheckThreshold *money.Money
+	if config.ReceiptCrossCheckUSD > 0 {
+		threshold, err := money.FiatFromFloat64(money.USD, config.ReceiptCrossCheckUSD)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid receipt cross-check threshold")
+		}
+
+		crossCheckThreshold = &threshold
+	}
+
 	s := &Service{
 		CurrencyResolver: currencies,
 		providers:        providers,
 		logger:           &log,
+		confirmations:    confirmations,
+
+		crossCheckThreshold: crossCheckThreshold,
+	}
+
+	if crossCheckThreshold != nil {
+		s.warnCrossCheckEndpoints()
 	}
 
 	// Cache for storing exchange rates
-	// I know that it looks a bit of spaghetti but atm we have only one exchange provider,
-	// so lets KISS and then refactor to more generic approach
 	if enableCache {
-		withTTL := ttlcache.WithTTL[string, client.ExchangeRate](exchangeRateCacheTTL)
-		s.ratesCache = ttlcache.New[string, client.ExchangeRate](withTTL)
+		withTTL := ttlcache.WithTTL[string, rates.Rate](exchangeRateCacheTTL)
+		s.ratesCache = ttlcache.New[string, rates.Rate](withTTL)
 
 		go s.ratesCache.Start()
 	}
 
 	return s
 }
+
+// warnCrossCheckEndpoints reports networks where receipt cross-check can't be performed.
+// Incoming transactions above the threshold are not confirmed there until another endpoint is added.
+func (s *Service) warnCrossCheckEndpoints() {
+	endpoints := make(map[string]int)
+	if s.providers.RPC != nil {
+		for _, status := range s.providers.RPC.Status() {
+			endpoints[status.Network]++
+		}
+	}
+
+	for _, network := range []string{"ETH", "MATIC", "BSC", "TRON"} {
+		if endpoints[network] >= crossCheckSources {
+			continue
+		}
+
+		s.logger.Warn().
+			Str("network", network).
+			Int("rpc_endpoints", endpoints[network]).
+			Msg("receipt cross-check requires at least 2 rpc endpoints: high-value incoming transactions won't be confirmed")
+	}
+}
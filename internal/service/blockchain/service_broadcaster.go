@@ -9,12 +9,14 @@ import (
 	"sync"
 
 	"github.com/antihax/optional"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	kms "github.com/oxygenpay/oxygen/internal/kms/wallet"
 	"github.com/oxygenpay/oxygen/internal/money"
 	"github.com/oxygenpay/oxygen/internal/provider/tatum"
+	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
 	client "github.com/oxygenpay/tatum-sdk/tatum"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
@@ -23,6 +25,8 @@ import (
 type Broadcaster interface {
 	BroadcastTransaction(ctx context.Context, blockchain money.Blockchain, hex string, isTest bool) (string, error)
 	GetTransactionReceipt(ctx context.Context, blockchain money.Blockchain, transactionID string, isTest bool) (*TransactionReceipt, error)
+	CrossCheckTransactionReceipt(ctx context.Context, receipt *TransactionReceipt, usdAmount money.Money) error
+	CrossCheckTransactionMissing(ctx context.Context, blockchain money.Blockchain, txHash string, isTest bool) error
 }
 
 func (s *Service) BroadcastTransaction(ctx context.Context, blockchain money.Blockchain, rawTX string, isTest bool) (string, error) {
@@ -61,6 +65,16 @@ func (s *Service) BroadcastTransaction(ctx context.Context, blockchain money.Blo
 
 	if err != nil {
 		errSwagger, ok := err.(client.GenericSwaggerError)
+		if !ok && s.providers.RPC != nil && kms.Blockchain(blockchain) != kms.TRON {
+			// Tatum is unavailable, send tx directly to the node
+			s.logger.Warn().Err(err).
+				Str("blockchain", blockchain.String()).
+				Bool("is_test", isTest).
+				Msg("broadcast API failed, falling back to rpc")
+
+			return s.broadcastViaRPC(ctx, blockchain, rawTX, isTest)
+		}
+
 		if !ok {
 			return "", errors.Wrap(err, "unknown swagger error")
 		}
@@ -88,6 +102,14 @@ type TransactionReceipt struct {
 	Success       bool
 	Confirmations int64
 	IsConfirmed   bool
+
+	// BlockNumber & BlockHash of the block that includes the transaction.
+	// BlockHash is empty for chains with instant finality (e.g. TRON solidified blocks).
+	BlockNumber int64
+	BlockHash   string
+
+	// RequiredConfirmations base amount of confirmations required by the blockchain.
+	RequiredConfirmations int64
 }
 
 func (s *Service) GetTransactionReceipt(
@@ -116,11 +138,7 @@ func (s *Service) getTransactionReceipt(
 	transactionID string,
 	isTest bool,
 ) (*TransactionReceipt, error) {
-	const (
-		ethConfirmations   = 12
-		maticConfirmations = 30
-		bscConfirmations   = 15
-	)
+	requiredConfirmations := s.RequiredConfirmations(blockchain, money.Money{})
 
 	nativeCoin, err := s.GetNativeCoin(blockchain)
 	if err != nil {
@@ -128,29 +146,18 @@ func (s *Service) getTransactionReceipt(
 	}
 
 	switch kms.Blockchain(blockchain) {
-	case kms.ETH:
-		rpc, err := s.providers.Tatum.EthereumRPC(ctx, isTest)
-		if err != nil {
-			return nil, err
-		}
-
-		return s.getEthReceipt(ctx, rpc, nativeCoin, transactionID, ethConfirmations, isTest)
-	case kms.MATIC:
-		rpc, err := s.providers.Tatum.MaticRPC(ctx, isTest)
+	case kms.ETH, kms.MATIC, kms.BSC:
+		rpc, err := s.evmRPC(ctx, blockchain, isTest)
 		if err != nil {
 			return nil, err
 		}
 
-		return s.getEthReceipt(ctx, rpc, nativeCoin, transactionID, maticConfirmations, isTest)
-	case kms.BSC:
-		rpc, err := s.providers.Tatum.BinanceSmartChainRPC(ctx, isTest)
-		if err != nil {
-			return nil, err
-		}
-
-		return s.getEthReceipt(ctx, rpc, nativeCoin, transactionID, bscConfirmations, isTest)
+		return s.getEthReceipt(ctx, rpc, nativeCoin, transactionID, requiredConfirmations, isTest)
 	case kms.TRON:
 		receipt, err := s.providers.Trongrid.GetTransactionReceipt(ctx, transactionID, isTest)
+		if errors.Is(err, trongrid.ErrNotFound) {
+			return nil, ErrTxNotFound
+		}
 		if err != nil {
 			return nil, errors.Wrap(err, "unable to get tron transaction receipt")
 		}
@@ -169,7 +176,10 @@ func (s *Service) getTransactionReceipt(
 			NetworkFee:    networkFee,
 			Success:       receipt.Success,
 			Confirmations: receipt.Confirmations,
-			IsConfirmed:   receipt.IsConfirmed,
+			IsConfirmed:   receipt.Confirmations >= requiredConfirmations,
+			BlockNumber:   receipt.BlockNumber,
+
+			RequiredConfirmations: requiredConfirmations,
 		}, nil
 	}
 
@@ -196,6 +206,9 @@ func (s *Service) getEthReceipt(
 
 	group.Go(func() error {
 		txByHash, _, err := rpc.TransactionByHash(ctx, hash)
+		if errors.Is(err, ethereum.NotFound) {
+			return ErrTxNotFound
+		}
 		if err != nil {
 			return err
 		}
@@ -209,6 +222,9 @@ func (s *Service) getEthReceipt(
 
 	group.Go(func() error {
 		r, err := rpc.TransactionReceipt(ctx, hash)
+		if errors.Is(err, ethereum.NotFound) {
+			return ErrTxNotFound
+		}
 		if err != nil {
 			return err
 		}
@@ -265,6 +281,10 @@ func (s *Service) getEthReceipt(
 		Success:       receipt.Status == 1,
 		Confirmations: confirmations,
 		IsConfirmed:   confirmations >= requiredConfirmations,
+		BlockNumber:   receipt.BlockNumber.Int64(),
+		BlockHash:     receipt.BlockHash.Hex(),
+
+		RequiredConfirmations: requiredConfirmations,
 	}, nil
 }
 
@@ -7,10 +7,9 @@ import (
 	"strings"
 	"time"
 
-	"github.com/antihax/optional"
 	"github.com/jellydator/ttlcache/v3"
 	"github.com/oxygenpay/oxygen/internal/money"
-	client "github.com/oxygenpay/tatum-sdk/tatum"
+	"github.com/oxygenpay/oxygen/internal/provider/rates"
 	"github.com/pkg/errors"
 )
 
@@ -27,6 +26,9 @@ type ExchangeRate struct {
 	To           string
 	Rate         float64
 	CalculatedAt time.Time
+
+	// ID of the persisted historical rate. Zero if unknown.
+	ID int64
 }
 
 type ConversionType string
@@ -42,6 +44,9 @@ type Conversion struct {
 	Rate float64
 	From money.Money
 	To   money.Money
+
+	// RateID ID of the persisted historical rate used for conversion. Zero if unknown.
+	RateID int64
 }
 
 func (s *Service) GetExchangeRate(ctx context.Context, from, to string) (ExchangeRate, error) {
@@ -64,19 +69,16 @@ func (s *Service) GetExchangeRate(ctx context.Context, from, to string) (Exchang
 		return ExchangeRate{}, err
 	}
 
-	var (
-		rate float64
-		at   time.Time
-	)
+	var rate rates.Rate
 
 	switch convType {
 	case ConversionTypeFiatToFiat, ConversionTypeCryptoToFiat:
-		rate, at, err = s.getExchangeRate(ctx, NormalizeTicker(to), NormalizeTicker(from))
+		rate, err = s.getExchangeRate(ctx, NormalizeTicker(to), NormalizeTicker(from))
 	case ConversionTypeFiatToCrypto:
-		// Tatum does not support USD to ETH, that's why we need to calculate ETH to USD and reverse it
-		rate, at, err = s.getExchangeRate(ctx, NormalizeTicker(from), NormalizeTicker(to))
+		// Providers don't support USD to ETH, that's why we need to calculate ETH to USD and reverse it
+		rate, err = s.getExchangeRate(ctx, NormalizeTicker(from), NormalizeTicker(to))
 		if err == nil {
-			rate = 1 / rate
+			rate.Value = 1 / rate.Value
 		}
 	default:
 		return ExchangeRate{}, errors.Errorf("unsupported conversion type %q", convType)
@@ -89,8 +91,9 @@ func (s *Service) GetExchangeRate(ctx context.Context, from, to string) (Exchang
 	return ExchangeRate{
 		From:         from,
 		To:           to,
-		Rate:         rate,
-		CalculatedAt: at,
+		Rate:         rate.Value,
+		CalculatedAt: rate.At,
+		ID:           rate.ID,
 	}, nil
 }
 
@@ -193,6 +196,8 @@ func (s *Service) FiatToFiat(ctx context.Context, from money.Money, to money.Fia
 		From: from,
 		To:   toMoney,
 		Rate: rate.Rate,
+
+		RateID: rate.ID,
 	}, nil
 }
 
@@ -242,6 +247,8 @@ func (s *Service) FiatToCrypto(ctx context.Context, from money.Money, to money.C
 		Rate: rate.Rate,
 		From: from,
 		To:   cryptoMoney,
+
+		RateID: rate.ID,
 	}, nil
 }
 
@@ -265,31 +272,15 @@ func (s *Service) CryptoToFiat(ctx context.Context, from money.Money, to money.F
 		Rate: rate.Rate,
 		From: from,
 		To:   fiatMoney,
+
+		RateID: rate.ID,
 	}, nil
 }
 
 // getExchangeRate. Example: is 1 ETH = $1500, then semantics are following:
-// getExchangeRate(ctx, "USD", "ETH") (1500, time.Time, nil)
-func (s *Service) getExchangeRate(ctx context.Context, desired, selected string) (float64, time.Time, error) {
-	res, err := s.getTatumExchangeRate(ctx, desired, selected)
-	if err != nil {
-		return 0, time.Time{}, errors.Wrapf(err, "unable to get exchange rate of %q / %q", desired, selected)
-	}
-
-	rate, err := strconv.ParseFloat(res.Value, 64)
-	if err != nil {
-		return 0, time.Time{}, err
-	}
-
-	return rate, time.UnixMilli(int64(res.Timestamp)), nil
-}
-
-func tatumRateCacheKey(desired, selected string) string {
-	return fmt.Sprintf("%s/%s", selected, desired)
-}
-
-func (s *Service) getTatumExchangeRate(ctx context.Context, desired, selected string) (client.ExchangeRate, error) {
-	key := tatumRateCacheKey(desired, selected)
+// getExchangeRate(ctx, "USD", "ETH") (Rate{Value: 1500}, nil)
+func (s *Service) getExchangeRate(ctx context.Context, desired, selected string) (rates.Rate, error) {
+	key := rateCacheKey(desired, selected)
 
 	if s.ratesCache != nil {
 		if hit := s.ratesCache.Get(key); hit != nil {
@@ -297,17 +288,20 @@ func (s *Service) getTatumExchangeRate(ctx context.Context, desired, selected st
 		}
 	}
 
-	opts := &client.ExchangeRateApiGetExchangeRateOpts{BasePair: optional.NewString(desired)}
-	res, _, err := s.providers.Tatum.Main().ExchangeRateApi.GetExchangeRate(ctx, selected, opts)
+	rate, err := s.providers.Rates.GetRate(ctx, desired, selected)
 	if err != nil {
-		return client.ExchangeRate{}, errors.Wrapf(err, "unable to get exchange rate of %q / %q", desired, selected)
+		return rates.Rate{}, errors.Wrapf(err, "unable to get exchange rate of %q / %q", desired, selected)
 	}
 
 	if s.ratesCache != nil {
-		s.ratesCache.Set(key, res, ttlcache.DefaultTTL)
+		s.ratesCache.Set(key, rate, ttlcache.DefaultTTL)
 	}
 
-	return res, err
+	return rate, nil
+}
+
+func rateCacheKey(desired, selected string) string {
+	return fmt.Sprintf("%s/%s", selected, desired)
 }
 
 func determineConversionType(from, to string) (ConversionType, error) {
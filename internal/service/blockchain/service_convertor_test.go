@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/provider/rates"
 	"github.com/oxygenpay/oxygen/internal/service/blockchain"
 	"github.com/oxygenpay/oxygen/internal/test"
 	"github.com/rs/zerolog"
@@ -186,8 +187,9 @@ func newConvertor(enableCache bool) (*blockchain.Service, *test.TatumMock) {
 	tatumAPI, mock := test.NewTatum(nil, &logger)
 
 	bc := blockchain.New(
+		blockchain.Config{},
 		currencies,
-		blockchain.Providers{Tatum: tatumAPI},
+		blockchain.Providers{Tatum: tatumAPI, Rates: rates.NewTatum(tatumAPI)},
 		enableCache,
 		&logger,
 	)
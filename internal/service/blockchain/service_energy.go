@@ -0,0 +1,76 @@
+package blockchain
+
+import (
+	"context"
+
+	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
+	"github.com/pkg/errors"
+)
+
+// TronTokenTransferEnergy approximate energy consumed by TRC-20 transfer to an address that already holds the token.
+const TronTokenTransferEnergy int64 = 65_000
+
+// ResourceResolver resolves TRON account resources (energy & bandwidth) and staking state.
+type ResourceResolver interface {
+	GetTronResources(ctx context.Context, address string, isTest bool) (trongrid.AccountResources, error)
+	GetTronStaking(ctx context.Context, address string, isTest bool) (TronStaking, error)
+}
+
+// TronStaking represents staking state of TRON account. All amounts are in SUN.
+type TronStaking struct {
+	Resources trongrid.AccountResources
+
+	// LiquidSun balance that is not staked.
+	LiquidSun int64
+
+	// DelegatableSun staked balance which energy could be delegated to other accounts.
+	DelegatableSun int64
+
+	// Delegations maps receiver address to staked balance which energy is delegated to the receiver.
+	Delegations map[string]int64
+}
+
+func (s *Service) GetTronResources(ctx context.Context, address string, isTest bool) (trongrid.AccountResources, error) {
+	return s.providers.Trongrid.GetAccountResources(ctx, address, isTest)
+}
+
+func (s *Service) GetTronStaking(ctx context.Context, address string, isTest bool) (TronStaking, error) {
+	resources, err := s.providers.Trongrid.GetAccountResources(ctx, address, isTest)
+	if err != nil {
+		return TronStaking{}, errors.Wrap(err, "unable to get account resources")
+	}
+
+	liquid, err := s.providers.Trongrid.GetAccountBalance(ctx, address, isTest)
+	if err != nil {
+		return TronStaking{}, errors.Wrap(err, "unable to get account balance")
+	}
+
+	delegatable, err := s.providers.Trongrid.GetDelegatableBalance(ctx, address, trongrid.ResourceEnergy, isTest)
+	if err != nil {
+		return TronStaking{}, errors.Wrap(err, "unable to get delegatable balance")
+	}
+
+	receivers, err := s.providers.Trongrid.GetDelegatedAccounts(ctx, address, isTest)
+	if err != nil {
+		return TronStaking{}, errors.Wrap(err, "unable to get delegated accounts")
+	}
+
+	delegations := make(map[string]int64, len(receivers))
+	for _, receiver := range receivers {
+		balance, err := s.providers.Trongrid.GetDelegatedBalance(ctx, address, receiver, trongrid.ResourceEnergy, isTest)
+		if err != nil {
+			return TronStaking{}, errors.Wrapf(err, "unable to get balance delegated to %s", receiver)
+		}
+
+		if balance > 0 {
+			delegations[receiver] = balance
+		}
+	}
+
+	return TronStaking{
+		Resources:      resources,
+		LiquidSun:      liquid,
+		DelegatableSun: delegatable,
+		Delegations:    delegations,
+	}, nil
+}
@@ -3,6 +3,7 @@ package blockchain
 import (
 	"context"
 	"math/big"
+	"strconv"
 	"time"
 
 	kmswallet "github.com/oxygenpay/oxygen/internal/kms/wallet"
@@ -12,6 +13,7 @@ import (
 
 type FeeCalculator interface {
 	CalculateFee(ctx context.Context, baseCurrency, currency money.CryptoCurrency, isTest bool) (Fee, error)
+	CalculateSenderFee(ctx context.Context, baseCurrency, currency money.CryptoCurrency, sender string, isTest bool) (Fee, error)
 	CalculateWithdrawalFeeUSD(ctx context.Context, baseCurrency, currency money.CryptoCurrency, isTest bool) (money.Money, error)
 }
 
@@ -34,12 +36,37 @@ func (s *Service) CalculateFee(ctx context.Context, baseCurrency, currency money
 	case kmswallet.BSC:
 		return s.bscFee(ctx, baseCurrency, currency, isTest)
 	case kmswallet.TRON:
-		return s.tronFee(ctx, baseCurrency, currency, isTest)
+		return s.tronFee(ctx, baseCurrency, currency, 0, isTest)
 	}
 
 	return Fee{}, errors.New("unsupported blockchain for fees calculations " + currency.Ticker)
 }
 
+// CalculateSenderFee calculates blockchain transaction fee for specific sender address.
+// For TRON sender's energy (staked or delegated) is taken into account, for other blockchains
+// it's the same as CalculateFee.
+func (s *Service) CalculateSenderFee(
+	ctx context.Context,
+	baseCurrency, currency money.CryptoCurrency,
+	sender string,
+	isTest bool,
+) (Fee, error) {
+	if kmswallet.Blockchain(currency.Blockchain) != kmswallet.TRON || currency.Type != money.Token {
+		return s.CalculateFee(ctx, baseCurrency, currency, isTest)
+	}
+
+	if baseCurrency.Type != money.Coin || baseCurrency.Blockchain != currency.Blockchain {
+		return Fee{}, errors.New("invalid arguments")
+	}
+
+	resources, err := s.GetTronResources(ctx, sender, isTest)
+	if err != nil {
+		return Fee{}, errors.Wrap(err, "unable to get sender's resources")
+	}
+
+	return s.tronFee(ctx, baseCurrency, currency, resources.AvailableEnergy(), isTest)
+}
+
 // CalculateWithdrawalFeeUSD withdrawal fees are tied to network fee but calculated in USD
 // Example: usdFee, err := CalculateWithdrawalFeeUSD(ctx, eth, ethUSD, false)
 func (s *Service) CalculateWithdrawalFeeUSD(
@@ -96,6 +123,58 @@ func NewFee(currency money.CryptoCurrency, at time.Time, isTest bool, fee any) F
 	}
 }
 
+// TotalCost returns max network fee in native coin of the blockchain (e.g. ETH for ETH_USDT transfer).
+// For TRON it's the amount of TRX that would be burned considering sender's energy.
+func (f *Fee) TotalCost(baseCurrency money.CryptoCurrency) (money.Money, error) {
+	if baseCurrency.Type != money.Coin || baseCurrency.Blockchain != f.Currency.Blockchain {
+		return money.Money{}, errors.New("invalid base currency")
+	}
+
+	var raw string
+
+	switch fee := f.raw.(type) {
+	case EthFee:
+		raw = fee.TotalCostWEI
+	case MaticFee:
+		raw = fee.TotalCostWEI
+	case BSCFee:
+		raw = fee.TotalCostWEI
+	case TronFee:
+		raw = strconv.FormatUint(fee.EstimatedSun, 10)
+	default:
+		return money.Money{}, errors.New("unknown fee type")
+	}
+
+	return baseCurrency.MakeAmount(raw)
+}
+
+// EffectiveGasPrice returns price per gas in WEI that transaction pays for EVM blockchains:
+// gas price (base fee with a margin) plus priority fee, same as used in TotalCost.
+// Returns false for blockchains without gas price (e.g. TRON).
+func (f *Fee) EffectiveGasPrice() (*big.Int, bool) {
+	gasPrice, priorityFee, err := f.GasPrices()
+	if err != nil {
+		return nil, false
+	}
+
+	price, ok := new(big.Int).SetString(gasPrice, 10)
+	if !ok {
+		return nil, false
+	}
+
+	// legacy (pre EIP-1559) fee has no priority fee
+	if priorityFee == "" {
+		return price, true
+	}
+
+	tip, ok := new(big.Int).SetString(priorityFee, 10)
+	if !ok {
+		return nil, false
+	}
+
+	return price.Add(price, tip), true
+}
+
 type EthFee struct {
 	GasUnits     uint   `json:"gasUnits"`
 	GasPrice     string `json:"gasPrice"`
@@ -128,7 +207,7 @@ func (s *Service) ethFee(ctx context.Context, baseCurrency, currency money.Crypt
 	}
 
 	// 1. Connect to ETH node
-	client, err := s.providers.Tatum.EthereumRPC(ctx, isTest)
+	client, err := s.evmRPC(ctx, baseCurrency.Blockchain, isTest)
 	if err != nil {
 		return Fee{}, errors.Wrap(err, "unable to setup RPC")
 	}
@@ -226,7 +305,7 @@ func (s *Service) maticFee(ctx context.Context, baseCurrency, currency money.Cry
 	}
 
 	// 1. Connect to MATIC node
-	client, err := s.providers.Tatum.MaticRPC(ctx, isTest)
+	client, err := s.evmRPC(ctx, baseCurrency.Blockchain, isTest)
 	if err != nil {
 		return Fee{}, errors.Wrap(err, "unable to setup RPC")
 	}
@@ -320,7 +399,7 @@ func (s *Service) bscFee(ctx context.Context, baseCurrency, currency money.Crypt
 	)
 
 	// 1. Connect to BSC node
-	client, err := s.providers.Tatum.BinanceSmartChainRPC(ctx, isTest)
+	client, err := s.evmRPC(ctx, baseCurrency.Blockchain, isTest)
 	if err != nil {
 		return Fee{}, errors.Wrap(err, "unable to setup RPC")
 	}
@@ -391,6 +470,16 @@ type TronFee struct {
 	FeeLimitTRX string `json:"feeLimitTrx"`
 	FeeLimitUSD string `json:"feeLimitUsd"`
 
+	// Energy required by the transaction & energy available to the sender.
+	Energy          int64 `json:"energy"`
+	AvailableEnergy int64 `json:"availableEnergy"`
+
+	// EstimatedSun amount of TRX that would be burned. Lower than fee limit when
+	// sender has enough energy.
+	EstimatedSun uint64 `json:"estimatedCost"`
+	EstimatedTRX string `json:"estimatedCostTrx"`
+	EstimatedUSD string `json:"estimatedCostUsd"`
+
 	feeLimitUSD money.Money
 }
 
@@ -402,7 +491,14 @@ func (f *Fee) ToTronFee() (TronFee, error) {
 	return TronFee{}, errors.New("invalid fee type assertion for TRON")
 }
 
-func (s *Service) tronFee(ctx context.Context, baseCurrency, currency money.CryptoCurrency, isTest bool) (Fee, error) {
+// tronFee calculates TRON transaction fee. Fee limit is always the same as it caps the amount of TRX burned
+// for energy, but the estimated cost is reduced proportionally to the energy available to the sender.
+func (s *Service) tronFee(
+	ctx context.Context,
+	baseCurrency, currency money.CryptoCurrency,
+	availableEnergy int64,
+	isTest bool,
+) (Fee, error) {
 	const (
 		bandwidthSunCost      = int64(1000)
 		coinTransferBandwidth = int64(350)
@@ -416,9 +512,24 @@ func (s *Service) tronFee(ctx context.Context, baseCurrency, currency money.Cryp
 		return money.NewFromBigInt(money.Crypto, baseCurrency.Ticker, big.NewInt(i), baseCurrency.Decimals)
 	}
 
-	feeLimit := bandwidthSunCost * coinTransferBandwidth
+	var (
+		feeLimit  = bandwidthSunCost * coinTransferBandwidth
+		estimated = feeLimit
+		energy    int64
+	)
+
 	if currency.Type == money.Token {
 		feeLimit = tokenTransactionSun
+		energy = TronTokenTransferEnergy
+
+		covered := availableEnergy
+		if covered > energy {
+			covered = energy
+		}
+
+		// bandwidth is paid anyway, energy is burned only for the uncovered part
+		bandwidthCost := bandwidthSunCost * coinTransferBandwidth
+		estimated = bandwidthCost + (tokenTransactionSun-bandwidthCost)*(energy-covered)/energy
 	}
 
 	feeLimitTRON, err := intToTRON(feeLimit)
@@ -426,16 +537,33 @@ func (s *Service) tronFee(ctx context.Context, baseCurrency, currency money.Cryp
 		return Fee{}, errors.Wrap(err, "unable to make TRON from int")
 	}
 
+	estimatedTRON, err := intToTRON(estimated)
+	if err != nil {
+		return Fee{}, errors.Wrap(err, "unable to make TRON from int")
+	}
+
 	conv, err := s.CryptoToFiat(ctx, feeLimitTRON, money.USD)
 	if err != nil {
 		return Fee{}, errors.Wrap(err, "unable to calculate total cost in USD")
 	}
 
+	estimatedConv, err := s.CryptoToFiat(ctx, estimatedTRON, money.USD)
+	if err != nil {
+		return Fee{}, errors.Wrap(err, "unable to calculate estimated cost in USD")
+	}
+
 	return NewFee(currency, time.Now().UTC(), isTest, TronFee{
 		FeeLimitSun: uint64(feeLimit),
 		FeeLimitTRX: feeLimitTRON.String(),
 		FeeLimitUSD: conv.To.String(),
 
+		Energy:          energy,
+		AvailableEnergy: availableEnergy,
+
+		EstimatedSun: uint64(estimated),
+		EstimatedTRX: estimatedTRON.String(),
+		EstimatedUSD: estimatedConv.To.String(),
+
 		feeLimitUSD: conv.To,
 	}), nil
 }
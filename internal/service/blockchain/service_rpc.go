@@ -0,0 +1,287 @@
+package blockchain
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	kms "github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
+	"github.com/pkg/errors"
+)
+
+// ErrReceiptMismatch transaction receipts from different RPC endpoints don't match.
+var ErrReceiptMismatch = errors.New("transaction receipts mismatch")
+
+// crossCheckSources amount of independent endpoints that should return the same receipt.
+const crossCheckSources = 2
+
+// evmRPC returns RPC client of EVM-compatible blockchain. Uses rpc pool with failover
+// if it's configured, otherwise falls back to Tatum's RPC gateway.
+func (s *Service) evmRPC(ctx context.Context, blockchain money.Blockchain, isTest bool) (*ethclient.Client, error) {
+	if s.providers.RPC != nil {
+		return s.providers.RPC.EVM(ctx, blockchain, isTest)
+	}
+
+	switch kms.Blockchain(blockchain) {
+	case kms.ETH:
+		return s.providers.Tatum.EthereumRPC(ctx, isTest)
+	case kms.MATIC:
+		return s.providers.Tatum.MaticRPC(ctx, isTest)
+	case kms.BSC:
+		return s.providers.Tatum.BinanceSmartChainRPC(ctx, isTest)
+	}
+
+	return nil, kms.ErrUnknownBlockchain
+}
+
+// broadcastViaRPC sends raw EVM transaction directly to the node.
+// Used as a fallback when Tatum's broadcast API is unavailable.
+func (s *Service) broadcastViaRPC(ctx context.Context, blockchain money.Blockchain, rawTX string, isTest bool) (string, error) {
+	client, err := s.evmRPC(ctx, blockchain, isTest)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to setup RPC")
+	}
+
+	raw, err := hexutil.Decode(ensureHexPrefix(rawTX))
+	if err != nil {
+		return "", errors.Wrap(ErrInvalidTransaction, err.Error())
+	}
+
+	tx := &types.Transaction{}
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return "", errors.Wrap(ErrInvalidTransaction, err.Error())
+	}
+
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		if strings.Contains(err.Error(), "insufficient funds") {
+			return "", ErrInsufficientFunds
+		}
+
+		return "", errors.Wrap(ErrInvalidTransaction, err.Error())
+	}
+
+	return tx.Hash().Hex(), nil
+}
+
+func ensureHexPrefix(s string) string {
+	if strings.HasPrefix(s, "0x") {
+		return s
+	}
+
+	return "0x" + s
+}
+
+// CrossCheckTransactionReceipt fetches transaction receipt from two independent RPC endpoints
+// and compares it with provided receipt. Applicable only for transactions with USD amount
+// greater or equal to Config.ReceiptCrossCheckUSD. Returns ErrReceiptMismatch if any source
+// reports different transaction status or block. Fails closed: if network has less than
+// two endpoints, ErrReceiptMismatch is returned as well so the transaction is not confirmed.
+func (s *Service) CrossCheckTransactionReceipt(ctx context.Context, receipt *TransactionReceipt, usdAmount money.Money) error {
+	if s.crossCheckThreshold == nil || usdAmount.LessThan(*s.crossCheckThreshold) {
+		return nil
+	}
+
+	if s.providers.RPC == nil {
+		return errors.Wrapf(ErrReceiptMismatch, "no rpc endpoints to cross-check transaction %s", receipt.Hash)
+	}
+
+	var (
+		sources []*TransactionReceipt
+		err     error
+	)
+
+	switch kms.Blockchain(receipt.Blockchain) {
+	case kms.ETH, kms.MATIC, kms.BSC:
+		sources, err = s.evmReceiptSources(ctx, receipt)
+	case kms.TRON:
+		sources, err = s.tronReceiptSources(ctx, receipt)
+	default:
+		return kms.ErrUnknownBlockchain
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// pool has only one endpoint: nothing to compare with
+	if sources == nil {
+		return errors.Wrapf(ErrReceiptMismatch, "not enough rpc endpoints to cross-check transaction %s", receipt.Hash)
+	}
+
+	for _, source := range sources {
+		if source.Success != receipt.Success || source.BlockNumber != receipt.BlockNumber {
+			return errors.Wrapf(ErrReceiptMismatch, "transaction %s", receipt.Hash)
+		}
+
+		if receipt.BlockHash != "" && !strings.EqualFold(source.BlockHash, receipt.BlockHash) {
+			return errors.Wrapf(ErrReceiptMismatch, "transaction %s", receipt.Hash)
+		}
+	}
+
+	return nil
+}
+
+// CrossCheckTransactionMissing asks every RPC endpoint of the network for transaction receipt.
+// Returns ErrReceiptMismatch if any endpoint still knows the transaction. Endpoint errors other
+// than "not found" are returned as is, so the caller can't treat unreachable nodes as a confirmation.
+// Without rpc pool there is nothing to compare with and the check is skipped.
+func (s *Service) CrossCheckTransactionMissing(ctx context.Context, blockchain money.Blockchain, txHash string, isTest bool) error {
+	if s.providers.RPC == nil {
+		return nil
+	}
+
+	switch kms.Blockchain(blockchain) {
+	case kms.ETH, kms.MATIC, kms.BSC:
+		clients, err := s.providers.RPC.EVMEndpoints(blockchain, isTest)
+		if err != nil {
+			return errors.Wrap(err, "unable to get rpc endpoints")
+		}
+
+		for _, client := range clients {
+			_, err := client.TransactionReceipt(ctx, common.HexToHash(txHash))
+			switch {
+			case errors.Is(err, ethereum.NotFound):
+				continue
+			case err != nil:
+				return errors.Wrap(err, "unable to get transaction receipt")
+			}
+
+			return errors.Wrapf(ErrReceiptMismatch, "transaction %s is still known by rpc endpoint", txHash)
+		}
+	case kms.TRON:
+		providers, err := s.providers.RPC.TronEndpoints(isTest)
+		if err != nil {
+			return errors.Wrap(err, "unable to get rpc endpoints")
+		}
+
+		for _, provider := range providers {
+			_, err := provider.GetTransactionReceipt(ctx, txHash, isTest)
+			switch {
+			case errors.Is(err, trongrid.ErrNotFound):
+				continue
+			case err != nil:
+				return errors.Wrap(err, "unable to get transaction receipt")
+			}
+
+			return errors.Wrapf(ErrReceiptMismatch, "transaction %s is still known by rpc endpoint", txHash)
+		}
+	default:
+		return kms.ErrUnknownBlockchain
+	}
+
+	return nil
+}
+
+func (s *Service) evmReceiptSources(ctx context.Context, receipt *TransactionReceipt) ([]*TransactionReceipt, error) {
+	clients, err := s.providers.RPC.EVMEndpoints(receipt.Blockchain, receipt.IsTest)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get rpc endpoints")
+	}
+
+	if len(clients) < crossCheckSources {
+		return nil, nil
+	}
+
+	var (
+		sources []*TransactionReceipt
+		lastErr error
+	)
+
+	for _, client := range clients {
+		r, err := client.TransactionReceipt(ctx, common.HexToHash(receipt.Hash))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		sources = append(sources, &TransactionReceipt{
+			Success:     r.Status == 1,
+			BlockNumber: r.BlockNumber.Int64(),
+			BlockHash:   r.BlockHash.Hex(),
+		})
+
+		if len(sources) == crossCheckSources {
+			return sources, nil
+		}
+	}
+
+	return nil, errors.Wrap(lastErr, "unable to get receipts from enough rpc endpoints")
+}
+
+func (s *Service) tronReceiptSources(ctx context.Context, receipt *TransactionReceipt) ([]*TransactionReceipt, error) {
+	providers, err := s.providers.RPC.TronEndpoints(receipt.IsTest)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get rpc endpoints")
+	}
+
+	if len(providers) < crossCheckSources {
+		return nil, nil
+	}
+
+	var (
+		sources []*TransactionReceipt
+		lastErr error
+	)
+
+	for _, provider := range providers {
+		r, err := provider.GetTransactionReceipt(ctx, receipt.Hash, receipt.IsTest)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		sources = append(sources, &TransactionReceipt{
+			Success:     r.Success,
+			BlockNumber: r.BlockNumber,
+		})
+
+		if len(sources) == crossCheckSources {
+			return sources, nil
+		}
+	}
+
+	return nil, errors.Wrap(lastErr, "unable to get receipts from enough rpc endpoints")
+}
+
+// NonceResolver resolves on-chain nonces of EVM addresses.
+type NonceResolver interface {
+	GetNonce(ctx context.Context, blockchain money.Blockchain, address string, isTest bool) (Nonce, error)
+}
+
+// Nonce represents amount of transactions sent from the address.
+type Nonce struct {
+	// Latest amount of transactions included into blocks. Equals to the nonce of the next mined transaction.
+	Latest uint64
+
+	// Pending includes transactions from the mempool that are executable (without nonce gaps).
+	// Equals to the nonce that should be used for the next transaction.
+	Pending uint64
+}
+
+// GetNonce returns eth_getTransactionCount of the address for "latest" & "pending" blocks.
+func (s *Service) GetNonce(ctx context.Context, blockchain money.Blockchain, address string, isTest bool) (Nonce, error) {
+	client, err := s.evmRPC(ctx, blockchain, isTest)
+	if err != nil {
+		return Nonce{}, errors.Wrap(err, "unable to setup RPC")
+	}
+
+	addr := common.HexToAddress(address)
+
+	latest, err := client.NonceAt(ctx, addr, nil)
+	if err != nil {
+		return Nonce{}, errors.Wrap(err, "unable to get latest nonce")
+	}
+
+	pending, err := client.PendingNonceAt(ctx, addr)
+	if err != nil {
+		return Nonce{}, errors.Wrap(err, "unable to get pending nonce")
+	}
+
+	return Nonce{Latest: latest, Pending: pending}, nil
+}
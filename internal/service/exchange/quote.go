@@ -0,0 +1,223 @@
+package exchange
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+	"github.com/oxygenpay/oxygen/internal/db/repository"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/pkg/errors"
+)
+
+// Quote represents conversion with locked rate that is guaranteed until ExpiresAt.
+type Quote struct {
+	ID         int64
+	UUID       uuid.UUID
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	MerchantID int64
+	Type       QuoteType
+
+	From money.Money
+	To   money.Money
+	Rate float64
+
+	// RateID ID of the persisted historical rate. Nil if unknown.
+	RateID *int64
+
+	// PaymentID ID of the payment that uses the quote. Nil if quote is not used yet.
+	PaymentID *int64
+}
+
+type QuoteType string
+
+const (
+	// QuotePaymentMethod payment price in crypto for selected payment method.
+	QuotePaymentMethod QuoteType = "payment_method"
+
+	// QuoteWithdrawalFee withdrawal fee in crypto.
+	QuoteWithdrawalFee QuoteType = "withdrawal_fee"
+)
+
+var (
+	ErrQuoteNotFound = errors.New("quote not found")
+	ErrQuoteExpired  = errors.New("quote is expired")
+	ErrQuoteUsed     = errors.New("quote is already used")
+)
+
+// Expired checks whether quote can't be used anymore.
+func (q *Quote) Expired() bool {
+	return !time.Now().Before(q.ExpiresAt)
+}
+
+// TTL returns time left until quote expiration.
+func (q *Quote) TTL() time.Duration {
+	if q.Expired() {
+		return 0
+	}
+
+	return time.Until(q.ExpiresAt)
+}
+
+type CreateQuoteParams struct {
+	MerchantID int64
+	Type       QuoteType
+	Conversion blockchain.Conversion
+	TTL        time.Duration
+
+	// PaymentID optional payment id.
+	PaymentID int64
+}
+
+// CreateQuote locks conversion's rate and amounts for given TTL.
+func (s *Service) CreateQuote(ctx context.Context, params CreateQuoteParams) (*Quote, error) {
+	if params.TTL <= 0 {
+		return nil, errors.Wrap(ErrValidation, "ttl should be positive")
+	}
+
+	rate := pgtype.Numeric{}
+	if err := rate.Set(params.Conversion.Rate); err != nil {
+		return nil, errors.Wrap(err, "unable to convert rate")
+	}
+
+	from, to := params.Conversion.From, params.Conversion.To
+	now := time.Now()
+
+	entry, err := s.repo.CreateQuote(ctx, repository.CreateQuoteParams{
+		Uuid:         uuid.New(),
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(params.TTL),
+		MerchantID:   params.MerchantID,
+		Type:         string(params.Type),
+		FromCurrency: from.Ticker(),
+		FromAmount:   repository.MoneyToNumeric(from),
+		FromDecimals: int32(from.Decimals()),
+		ToCurrency:   to.Ticker(),
+		ToAmount:     repository.MoneyToNumeric(to),
+		ToDecimals:   int32(to.Decimals()),
+		Rate:         rate,
+		RateID:       optionalID(params.Conversion.RateID),
+		PaymentID:    optionalID(params.PaymentID),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create quote")
+	}
+
+	return entryToQuote(entry)
+}
+
+func (s *Service) GetQuoteByUUID(ctx context.Context, merchantID int64, id uuid.UUID) (*Quote, error) {
+	entry, err := s.repo.GetQuoteByUUID(ctx, repository.GetQuoteByUUIDParams{
+		MerchantID: merchantID,
+		Uuid:       id,
+	})
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, ErrQuoteNotFound
+	case err != nil:
+		return nil, errors.Wrap(err, "unable to get quote")
+	}
+
+	return entryToQuote(entry)
+}
+
+// GetPaymentQuote returns the latest quote of given type used by the payment.
+func (s *Service) GetPaymentQuote(ctx context.Context, paymentID int64, quoteType QuoteType) (*Quote, error) {
+	entry, err := s.repo.GetLatestQuoteByPaymentID(ctx, repository.GetLatestQuoteByPaymentIDParams{
+		PaymentID: repository.Int64ToNullable(paymentID),
+		Type:      string(quoteType),
+	})
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, ErrQuoteNotFound
+	case err != nil:
+		return nil, errors.Wrap(err, "unable to get quote")
+	}
+
+	return entryToQuote(entry)
+}
+
+// UseQuote links unexpired quote to the payment. Each quote can be used only once.
+func (s *Service) UseQuote(ctx context.Context, quote *Quote, paymentID int64) (*Quote, error) {
+	if quote.Expired() {
+		return nil, ErrQuoteExpired
+	}
+
+	if quote.PaymentID != nil {
+		return nil, ErrQuoteUsed
+	}
+
+	entry, err := s.repo.UpdateQuotePaymentID(ctx, repository.UpdateQuotePaymentIDParams{
+		ID:        quote.ID,
+		PaymentID: repository.Int64ToNullable(paymentID),
+	})
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, ErrQuoteUsed
+	case err != nil:
+		return nil, errors.Wrap(err, "unable to update quote")
+	}
+
+	return entryToQuote(entry)
+}
+
+func entryToQuote(entry repository.Quote) (*Quote, error) {
+	from, err := repository.NumericToMoney(
+		entry.FromAmount,
+		moneyType(entry.FromCurrency),
+		entry.FromCurrency,
+		int64(entry.FromDecimals),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse quote amount")
+	}
+
+	to, err := repository.NumericToMoney(
+		entry.ToAmount,
+		moneyType(entry.ToCurrency),
+		entry.ToCurrency,
+		int64(entry.ToDecimals),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse quote amount")
+	}
+
+	var rate float64
+	if err := entry.Rate.AssignTo(&rate); err != nil {
+		return nil, errors.Wrap(err, "unable to parse quote rate")
+	}
+
+	return &Quote{
+		ID:         entry.ID,
+		UUID:       entry.Uuid,
+		CreatedAt:  entry.CreatedAt,
+		ExpiresAt:  entry.ExpiresAt,
+		MerchantID: entry.MerchantID,
+		Type:       QuoteType(entry.Type),
+		From:       from,
+		To:         to,
+		Rate:       rate,
+		RateID:     repository.NullableInt64ToPointer(entry.RateID),
+		PaymentID:  repository.NullableInt64ToPointer(entry.PaymentID),
+	}, nil
+}
+
+func optionalID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id > 0}
+}
+
+func moneyType(ticker string) money.Type {
+	if _, err := money.MakeFiatCurrency(ticker); err == nil {
+		return money.Fiat
+	}
+
+	return money.Crypto
+}
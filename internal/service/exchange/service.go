@@ -0,0 +1,250 @@
+// Package exchange stores exchange rates used for conversions and provides
+// historical rates for valuation of balances at a given date.
+package exchange
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+	"github.com/oxygenpay/oxygen/internal/db/repository"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/provider/rates"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// Rate historical exchange rate: 1 From = Rate To. Example: 1 ETH = 1500 USD.
+type Rate struct {
+	ID        int64
+	CreatedAt time.Time
+	From      string
+	To        string
+	Rate      float64
+	Source    string
+}
+
+// Valuation represents balance revalued in USD at a given date.
+type Valuation struct {
+	Balance   *wallet.Balance
+	Amount    money.Money
+	USDAmount money.Money
+	Rate      *Rate
+}
+
+type Service struct {
+	repo    *repository.Queries
+	wallets *wallet.Service
+	logger  *zerolog.Logger
+}
+
+var (
+	ErrNotFound   = errors.New("exchange rate not found")
+	ErrValidation = errors.New("invalid data provided")
+)
+
+const maxListLimit = 1000
+
+func New(repo *repository.Queries, wallets *wallet.Service, logger *zerolog.Logger) *Service {
+	log := logger.With().Str("channel", "exchange_service").Logger()
+
+	return &Service{
+		repo:    repo,
+		wallets: wallets,
+		logger:  &log,
+	}
+}
+
+// GetRateAt returns the latest stored rate of from/to pair at the given moment.
+// If only reverse pair is stored (e.g. USD/EUR for EUR/USD), its inverted rate is returned.
+func (s *Service) GetRateAt(ctx context.Context, from, to string, at time.Time) (*Rate, error) {
+	from, to = blockchain.NormalizeTicker(from), blockchain.NormalizeTicker(to)
+	if from == "" || to == "" {
+		return nil, ErrValidation
+	}
+
+	if from == to {
+		return &Rate{CreatedAt: at, From: from, To: to, Rate: 1}, nil
+	}
+
+	entry, err := s.repo.GetExchangeRateAt(ctx, repository.GetExchangeRateAtParams{
+		FromCurrency: from,
+		ToCurrency:   to,
+		CreatedAt:    at,
+	})
+
+	switch {
+	case err == nil:
+		return entryToRate(entry)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, errors.Wrap(err, "unable to get exchange rate")
+	}
+
+	entry, err = s.repo.GetExchangeRateAt(ctx, repository.GetExchangeRateAtParams{
+		FromCurrency: to,
+		ToCurrency:   from,
+		CreatedAt:    at,
+	})
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, ErrNotFound
+	case err != nil:
+		return nil, errors.Wrap(err, "unable to get exchange rate")
+	}
+
+	rate, err := entryToRate(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	rate.From, rate.To, rate.Rate = from, to, 1/rate.Rate
+
+	return rate, nil
+}
+
+// ListRates returns stored rates of from/to pair within [start, end] period ordered by date.
+func (s *Service) ListRates(ctx context.Context, from, to string, start, end time.Time, limit int32) ([]*Rate, error) {
+	from, to = blockchain.NormalizeTicker(from), blockchain.NormalizeTicker(to)
+
+	switch {
+	case from == "" || to == "":
+		return nil, errors.Wrap(ErrValidation, "from and to are required")
+	case end.Before(start):
+		return nil, errors.Wrap(ErrValidation, "end should be after start")
+	}
+
+	if limit < 1 || limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	entries, err := s.repo.ListExchangeRates(ctx, repository.ListExchangeRatesParams{
+		FromCurrency: from,
+		ToCurrency:   to,
+		CreatedAt:    start,
+		CreatedAt_2:  end,
+		Limit:        limit,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list exchange rates")
+	}
+
+	results := make([]*Rate, len(entries))
+	for i := range entries {
+		if results[i], err = entryToRate(entries[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// RevalueBalances returns balances of the merchant with amounts and USD rates at the given moment.
+func (s *Service) RevalueBalances(ctx context.Context, merchantID int64, at time.Time) ([]*Valuation, error) {
+	balances, err := s.wallets.ListBalances(ctx, wallet.EntityTypeMerchant, merchantID, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list balances")
+	}
+
+	valuations := make([]*Valuation, 0, len(balances))
+
+	for _, b := range balances {
+		amount, err := s.wallets.AmountAt(ctx, b, at)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to get amount of balance %d", b.ID)
+		}
+
+		usdAmount, err := money.FiatFromFloat64(money.USD, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		valuation := &Valuation{Balance: b, Amount: amount, USDAmount: usdAmount}
+
+		rate, err := s.GetRateAt(ctx, b.Currency, money.USD.String(), at)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			// no rates were stored before that moment, USD amount is unknown
+			valuations = append(valuations, valuation)
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		if valuation.USDAmount, err = money.CryptoToFiat(amount, money.USD, rate.Rate); err != nil {
+			return nil, errors.Wrap(err, "unable to calculate usd amount")
+		}
+
+		valuation.Rate = rate
+		valuations = append(valuations, valuation)
+	}
+
+	return valuations, nil
+}
+
+// Recorder persists every rate returned by underlying provider, so
+// conversions can be linked to the exact rate that was used.
+type Recorder struct {
+	provider rates.Provider
+	repo     *repository.Queries
+}
+
+func NewRecorder(provider rates.Provider, repo *repository.Queries) *Recorder {
+	return &Recorder{provider: provider, repo: repo}
+}
+
+func (r *Recorder) Name() string {
+	return r.provider.Name()
+}
+
+func (r *Recorder) GetRate(ctx context.Context, desired, selected string) (rates.Rate, error) {
+	rate, err := r.provider.GetRate(ctx, desired, selected)
+	if err != nil {
+		return rates.Rate{}, err
+	}
+
+	value := pgtype.Numeric{}
+	if err := value.Set(rate.Value); err != nil {
+		return rates.Rate{}, errors.Wrap(err, "unable to convert rate")
+	}
+
+	source := rate.Source
+	if len(source) > 64 {
+		source = source[:64]
+	}
+
+	entry, err := r.repo.CreateExchangeRate(ctx, repository.CreateExchangeRateParams{
+		CreatedAt:    time.Now(),
+		FromCurrency: strings.ToUpper(selected),
+		ToCurrency:   strings.ToUpper(desired),
+		Rate:         value,
+		Source:       source,
+	})
+	if err != nil {
+		return rates.Rate{}, errors.Wrap(err, "unable to store exchange rate")
+	}
+
+	rate.ID = entry.ID
+
+	return rate, nil
+}
+
+func entryToRate(entry repository.ExchangeRate) (*Rate, error) {
+	var value float64
+	if err := entry.Rate.AssignTo(&value); err != nil {
+		return nil, errors.Wrap(err, "unable to parse rate")
+	}
+
+	return &Rate{
+		ID:        entry.ID,
+		CreatedAt: entry.CreatedAt,
+		From:      entry.FromCurrency,
+		To:        entry.ToCurrency,
+		Rate:      value,
+		Source:    entry.Source,
+	}, nil
+}
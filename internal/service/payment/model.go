@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/exchange"
 	"github.com/oxygenpay/oxygen/internal/service/transaction"
 	"github.com/oxygenpay/oxygen/internal/service/wallet"
 	"github.com/oxygenpay/oxygen/internal/util"
@@ -187,6 +188,9 @@ type Method struct {
 	NetworkID     string
 	IsTest        bool
 
+	// Quote locked crypto amount of the payment. Nil for payments created before quotes were introduced.
+	Quote *exchange.Quote
+
 	tx *transaction.Transaction
 }
 
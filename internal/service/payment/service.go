@@ -18,6 +18,7 @@ import (
 	"github.com/oxygenpay/oxygen/internal/db/repository"
 	"github.com/oxygenpay/oxygen/internal/money"
 	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/exchange"
 	"github.com/oxygenpay/oxygen/internal/service/merchant"
 	"github.com/oxygenpay/oxygen/internal/service/transaction"
 	"github.com/oxygenpay/oxygen/internal/service/wallet"
@@ -44,6 +45,7 @@ type Service struct {
 	transactions TransactionResolver
 	merchants    *merchant.Service
 	wallets      *wallet.Service
+	exchange     *exchange.Service
 	blockchain   BlockchainService
 	publisher    bus.Publisher
 }
@@ -55,6 +57,12 @@ const ExpirationPeriodForLocked = time.Minute * 20
 // e.g. when payment is created but user haven't opened the page or haven't locked a cryptocurrency.
 const ExpirationPeriodForNotLocked = time.Hour * 6
 
+// QuoteTTLPaymentMethod period during which customer can lock payment with quoted crypto amount.
+const QuoteTTLPaymentMethod = time.Minute * 10
+
+// QuoteTTLWithdrawalFee period during which merchant can create withdrawal with quoted fee.
+const QuoteTTLWithdrawalFee = time.Minute * 5
+
 const MerchantIDWildcard = transaction.MerchantIDWildcard
 
 const (
@@ -81,6 +89,7 @@ func New(
 	transactionService TransactionResolver,
 	merchantService *merchant.Service,
 	walletService *wallet.Service,
+	exchangeService *exchange.Service,
 	blockchainService BlockchainService,
 	publisher bus.Publisher,
 	logger *zerolog.Logger,
@@ -93,6 +102,7 @@ func New(
 		transactions: transactionService,
 		merchants:    merchantService,
 		wallets:      walletService,
+		exchange:     exchangeService,
 		blockchain:   blockchainService,
 		publisher:    publisher,
 		logger:       &log,
@@ -676,7 +686,30 @@ func (s *Service) GetPaymentMethod(ctx context.Context, p *Payment) (*Method, er
 		return nil, errors.Wrap(err, "unable to get payment currency")
 	}
 
-	return MakeMethod(tx, currency), nil
+	method := MakeMethod(tx, currency)
+
+	quote, err := s.exchange.GetPaymentQuote(ctx, p.ID, exchange.QuotePaymentMethod)
+	switch {
+	case errors.Is(err, exchange.ErrQuoteNotFound):
+		// payment method was set before quotes were introduced
+	case err != nil:
+		return nil, errors.Wrap(err, "unable to get payment quote")
+	case quote.To.Ticker() == currency.Ticker:
+		method.Quote = quote
+	}
+
+	return method, nil
+}
+
+// QuotePaymentMethod locks crypto amount of the payment for QuoteTTLPaymentMethod.
+func (s *Service) QuotePaymentMethod(ctx context.Context, p *Payment, conv blockchain.Conversion) (*exchange.Quote, error) {
+	return s.exchange.CreateQuote(ctx, exchange.CreateQuoteParams{
+		MerchantID: p.MerchantID,
+		Type:       exchange.QuotePaymentMethod,
+		Conversion: conv,
+		TTL:        QuoteTTLPaymentMethod,
+		PaymentID:  p.ID,
+	})
 }
 
 func MakeMethod(tx *transaction.Transaction, currency money.CryptoCurrency) *Method {
@@ -10,6 +10,9 @@ import (
 	"github.com/oxygenpay/oxygen/internal/bus"
 	"github.com/oxygenpay/oxygen/internal/db/repository"
 	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/exchange"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
 	"github.com/oxygenpay/oxygen/internal/util"
 	"github.com/pkg/errors"
 )
@@ -18,6 +21,10 @@ type CreateWithdrawalProps struct {
 	BalanceID uuid.UUID
 	AddressID uuid.UUID
 	AmountRaw string // "0.123"
+
+	// FeeQuoteID optional id of the quote returned by GetWithdrawalFee.
+	// If set, withdrawal fee is taken from the quote instead of being recalculated.
+	FeeQuoteID *uuid.UUID
 }
 
 func (s *Service) ListWithdrawals(ctx context.Context, status Status, filterByIDs []int64) ([]*Payment, error) {
@@ -77,9 +84,9 @@ func (s *Service) CreateWithdrawal(ctx context.Context, merchantID int64, props
 	}
 
 	// 2. Check if balance has sufficient funds
-	withdrawalFee, err := s.GetWithdrawalFee(ctx, merchantID, balance.UUID)
+	withdrawalFee, feeQuote, err := s.resolveWithdrawalFee(ctx, merchantID, balance, props.FeeQuoteID)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to get withdrawal fee")
+		return nil, err
 	}
 	if errCovers := balance.Covers(amount, withdrawalFee.CryptoFee); errCovers != nil {
 		return nil, errors.WithMessagef(
@@ -155,6 +162,17 @@ func (s *Service) CreateWithdrawal(ctx context.Context, merchantID int64, props
 		return nil, errors.Wrap(err, "unable to create payment")
 	}
 
+	if feeQuote != nil {
+		if _, err := s.exchange.UseQuote(ctx, feeQuote, p.ID); err != nil {
+			// quote was used concurrently, withdrawal shouldn't be processed with another fee
+			if _, errFail := s.Update(ctx, merchantID, p.ID, UpdateProps{Status: StatusFailed}); errFail != nil {
+				return nil, errors.Wrap(errFail, "unable to mark withdrawal as failed")
+			}
+
+			return nil, errors.Wrap(err, "unable to use withdrawal fee quote")
+		}
+	}
+
 	err = s.publisher.Publish(bus.TopicWithdrawals, bus.WithdrawalCreatedEvent{
 		MerchantID: p.MerchantID,
 		PaymentID:  p.ID,
@@ -174,36 +192,115 @@ type WithdrawalFee struct {
 	USDFee       money.Money
 	CryptoFee    money.Money
 	IsTest       bool
+
+	// QuoteID & ExpiresAt are set when fee is locked by a quote.
+	QuoteID   uuid.UUID
+	ExpiresAt time.Time
 }
 
+// GetWithdrawalFee calculates withdrawal fee and locks it by a quote for QuoteTTLWithdrawalFee.
 func (s *Service) GetWithdrawalFee(ctx context.Context, merchantID int64, balanceID uuid.UUID) (*WithdrawalFee, error) {
 	balance, err := s.wallets.GetMerchantBalanceByUUID(ctx, merchantID, balanceID)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to get merchant balance")
 	}
 
+	fee, conv, err := s.calculateWithdrawalFee(ctx, balance)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := s.exchange.CreateQuote(ctx, exchange.CreateQuoteParams{
+		MerchantID: merchantID,
+		Type:       exchange.QuoteWithdrawalFee,
+		Conversion: conv,
+		TTL:        QuoteTTLWithdrawalFee,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create withdrawal fee quote")
+	}
+
+	fee.QuoteID = quote.UUID
+	fee.ExpiresAt = quote.ExpiresAt
+
+	return fee, nil
+}
+
+// GetWithdrawalFeeQuote returns fee quote used by the withdrawal.
+func (s *Service) GetWithdrawalFeeQuote(ctx context.Context, withdrawal *Payment) (*exchange.Quote, error) {
+	return s.exchange.GetPaymentQuote(ctx, withdrawal.ID, exchange.QuoteWithdrawalFee)
+}
+
+// resolveWithdrawalFee returns fee from the quote (if provided) or calculates a new one.
+func (s *Service) resolveWithdrawalFee(
+	ctx context.Context,
+	merchantID int64,
+	balance *wallet.Balance,
+	quoteID *uuid.UUID,
+) (*WithdrawalFee, *exchange.Quote, error) {
+	if quoteID == nil {
+		fee, _, err := s.calculateWithdrawalFee(ctx, balance)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return fee, nil, nil
+	}
+
+	quote, err := s.exchange.GetQuoteByUUID(ctx, merchantID, *quoteID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	currency, err := s.blockchain.GetCurrencyByTicker(balance.Currency)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unable to get currency by ticker")
+	}
+
+	switch {
+	case quote.Type != exchange.QuoteWithdrawalFee || quote.To.Ticker() != currency.Ticker:
+		return nil, nil, exchange.ErrQuoteNotFound
+	case quote.PaymentID != nil:
+		return nil, nil, exchange.ErrQuoteUsed
+	case quote.Expired():
+		return nil, nil, exchange.ErrQuoteExpired
+	}
+
+	return &WithdrawalFee{
+		CalculatedAt: quote.CreatedAt,
+		Blockchain:   currency.Blockchain,
+		Currency:     currency.Ticker,
+		IsTest:       balance.NetworkID != currency.NetworkID,
+		USDFee:       quote.From,
+		CryptoFee:    quote.To,
+		QuoteID:      quote.UUID,
+		ExpiresAt:    quote.ExpiresAt,
+	}, quote, nil
+}
+
+func (s *Service) calculateWithdrawalFee(ctx context.Context, balance *wallet.Balance) (*WithdrawalFee, blockchain.Conversion, error) {
 	// e.g. ETH_USDT
 	currency, err := s.blockchain.GetCurrencyByTicker(balance.Currency)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to  get currency by ticker")
+		return nil, blockchain.Conversion{}, errors.Wrap(err, "unable to  get currency by ticker")
 	}
 
 	// e.g. ETH
 	baseCurrency, err := s.blockchain.GetNativeCoin(currency.Blockchain)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to get currency by ticker")
+		return nil, blockchain.Conversion{}, errors.Wrap(err, "unable to get currency by ticker")
 	}
 
 	isTest := balance.NetworkID != currency.NetworkID
 
 	usdFee, err := s.blockchain.CalculateWithdrawalFeeUSD(ctx, baseCurrency, currency, isTest)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to get fee")
+		return nil, blockchain.Conversion{}, errors.Wrap(err, "unable to get fee")
 	}
 
 	conv, err := s.blockchain.FiatToCrypto(ctx, usdFee, currency)
 	if err != nil {
-		return nil, errors.Wrapf(err, "unable to get currency withdrawal fee in crypto")
+		return nil, blockchain.Conversion{}, errors.Wrapf(err, "unable to get currency withdrawal fee in crypto")
 	}
 
 	return &WithdrawalFee{
@@ -213,5 +310,5 @@ func (s *Service) GetWithdrawalFee(ctx context.Context, merchantID int64, balanc
 		IsTest:       isTest,
 		USDFee:       usdFee,
 		CryptoFee:    conv.To,
-	}, nil
+	}, conv, nil
 }
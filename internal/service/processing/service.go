@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,6 +13,7 @@ import (
 	kmswallet "github.com/oxygenpay/oxygen/internal/kms/wallet"
 	"github.com/oxygenpay/oxygen/internal/lock"
 	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/provider/notify"
 	"github.com/oxygenpay/oxygen/internal/provider/tatum"
 	"github.com/oxygenpay/oxygen/internal/service/blockchain"
 	"github.com/oxygenpay/oxygen/internal/service/merchant"
@@ -27,6 +29,9 @@ type BlockchainService interface {
 	blockchain.Convertor
 	blockchain.Broadcaster
 	blockchain.FeeCalculator
+	blockchain.ConfirmationsResolver
+	blockchain.NonceResolver
+	blockchain.ResourceResolver
 }
 
 type Service struct {
@@ -37,9 +42,13 @@ type Service struct {
 	transactions  *transaction.Service
 	blockchain    BlockchainService
 	tatumProvider *tatum.Provider
+	notifiers     *notify.Registry
 	publisher     bus.Publisher
 	locker        *lock.Locker
 	logger        *zerolog.Logger
+
+	// subscriptions cache of addresses subscribed to notifications providers
+	subscriptions sync.Map
 }
 
 type Config struct {
@@ -48,6 +57,73 @@ type Config struct {
 	PaymentFrontendSubPath  string `yaml:"payment_frontend_sub_path" env:"PROCESSING_PAYMENT_FRONTEND_SUB_PATH" env-default:"/p" env-description:"Sub path for payment UI"`
 	// DefaultServiceFee as float percentage. 1% is 0.01
 	DefaultServiceFee float64 `yaml:"default_service_fee" env:"PROCESSING_DEFAULT_SERVICE_FEE" env-default:"0" env-description:"Internal variable"`
+	// IncomingProviders maps blockchain to the source of incoming transactions. Tatum is used if not specified.
+	IncomingProviders map[string]string `yaml:"incoming_providers" env:"PROCESSING_INCOMING_PROVIDERS" env-description:"Source of incoming transactions per blockchain ('tatum', 'scanner', 'alchemy', 'quicknode', 'moralis'). Example: 'ETH:scanner,MATIC:alchemy'"`
+	// ReorgCheckDepth amount of blocks after which completed incoming transaction is considered irreversible.
+	ReorgCheckDepth int64 `yaml:"reorg_check_depth" env:"PROCESSING_REORG_CHECK_DEPTH" env-default:"100" env-description:"Amount of blocks during which completed incoming transactions are re-verified against chain reorganizations"`
+	// ReorgOrphanChecks amount of consecutive reorg checks that should miss the transaction before it's orphaned.
+	ReorgOrphanChecks int64 `yaml:"reorg_orphan_checks" env:"PROCESSING_REORG_ORPHAN_CHECKS" env-default:"3" env-description:"Amount of consecutive reorg checks that should not find completed incoming transaction before its balance is reverted"`
+	// StuckTransactionTimeout duration after which pending outbound transaction is replaced with a higher fee.
+	StuckTransactionTimeout time.Duration `yaml:"stuck_transaction_timeout" env:"PROCESSING_STUCK_TRANSACTION_TIMEOUT" env-default:"15m" env-description:"Duration after which pending outbound transaction is re-broadcasted with a higher fee"`
+	// MaxFeeBumps limits amount of replacements of a single stuck transaction.
+	MaxFeeBumps int64 `yaml:"max_fee_bumps" env:"PROCESSING_MAX_FEE_BUMPS" env-default:"5" env-description:"Max amount of fee bumps for a single stuck outbound transaction"`
+	// TronOutboundEnergyTransfers amount of token transfers which energy is delegated to TRON outbound wallet.
+	TronOutboundEnergyTransfers int64 `yaml:"tron_outbound_energy_transfers" env:"PROCESSING_TRON_OUTBOUND_ENERGY_TRANSFERS" env-default:"0" env-description:"Amount of TRC-20 transfers which energy is kept on TRON outbound wallet by delegating it from the staking wallet. 0 disables delegation"`
+	// SweepPolicies maps blockchain to the policy of sweeping inbound wallets. Sweeps are not restricted if not specified.
+	SweepPolicies map[string]SweepPolicy `yaml:"sweep_policies"`
+	// ColdWallets maps currency ticker to hot wallet cap. Excess of outbound balance is moved to cold address.
+	ColdWallets map[string]ColdWallet `yaml:"cold_wallets"`
+}
+
+const (
+	IncomingProviderTatum   = "tatum"
+	IncomingProviderScanner = "scanner"
+)
+
+// IncomingProvider returns the source of incoming transactions for selected blockchain.
+func (c *Config) IncomingProvider(chain money.Blockchain) string {
+	for bc, provider := range c.IncomingProviders {
+		if strings.EqualFold(bc, chain.String()) && provider != "" {
+			return strings.ToLower(provider)
+		}
+	}
+
+	return IncomingProviderTatum
+}
+
+// SweepPolicy returns sweeping policy of inbound wallets for selected blockchain.
+func (c *Config) SweepPolicy(chain money.Blockchain) SweepPolicy {
+	for bc, policy := range c.SweepPolicies {
+		if strings.EqualFold(bc, chain.String()) {
+			return policy
+		}
+	}
+
+	return SweepPolicy{}
+}
+
+// ColdWallet returns cold wallet of selected currency.
+func (c *Config) ColdWallet(ticker string) (ColdWallet, bool) {
+	for t, cold := range c.ColdWallets {
+		if strings.EqualFold(t, ticker) && cold.Address != "" {
+			return cold, true
+		}
+	}
+
+	return ColdWallet{}, false
+}
+
+// BlockchainsByIncomingProvider returns list of blockchains that use selected incoming transactions source.
+func (c *Config) BlockchainsByIncomingProvider(provider string) []money.Blockchain {
+	var results []money.Blockchain
+
+	for _, bc := range kmswallet.ListBlockchains() {
+		if c.IncomingProvider(bc.ToMoneyBlockchain()) == provider {
+			results = append(results, bc.ToMoneyBlockchain())
+		}
+	}
+
+	return results
 }
 
 func (c *Config) PaymentFrontendPath() string {
@@ -66,6 +142,7 @@ var (
 	ErrPaymentOptionsMissing = errors.New("payment options are not fully fulfilled")
 	ErrSignatureVerification = errors.New("unable to verify request signature")
 	ErrInboundWallet         = errors.New("inbound wallet error")
+	ErrQuoteExpired          = errors.New("payment quote is expired")
 )
 
 func New(
@@ -76,6 +153,7 @@ func New(
 	transactions *transaction.Service,
 	blockchainService BlockchainService,
 	tatumProvider *tatum.Provider,
+	notifiers *notify.Registry,
 	publisher bus.Publisher,
 	locker *lock.Locker,
 	logger *zerolog.Logger,
@@ -90,6 +168,7 @@ func New(
 		transactions:  transactions,
 		blockchain:    blockchainService,
 		tatumProvider: tatumProvider,
+		notifiers:     notifiers,
 		publisher:     publisher,
 		locker:        locker,
 		logger:        &log,
@@ -116,6 +195,9 @@ type PaymentInfo struct {
 	ExpiresAt             time.Time
 	ExpirationDurationMin int64
 
+	Confirmations         int64
+	RequiredConfirmations int64
+
 	SuccessAction  *payment.SuccessAction
 	SuccessURL     *string
 	SuccessMessage *string
@@ -173,6 +255,8 @@ func (s *Service) GetDetailedPayment(ctx context.Context, merchantID, paymentID
 			return nil, err
 		}
 
+		confirmations, requiredConfirmations := tx.Confirmations()
+
 		result.PaymentInfo = &PaymentInfo{
 			Status:           pt.PublicStatus(),
 			PaymentLink:      paymentLink,
@@ -184,6 +268,9 @@ func (s *Service) GetDetailedPayment(ctx context.Context, merchantID, paymentID
 			ExpiresAt:             expiresAt,
 			ExpirationDurationMin: pt.ExpirationDurationMin(),
 
+			Confirmations:         confirmations,
+			RequiredConfirmations: requiredConfirmations,
+
 			SuccessAction:  pt.PublicSuccessAction(),
 			SuccessURL:     pt.PublicSuccessURL(),
 			SuccessMessage: pt.PublicSuccessMessage(),
@@ -209,6 +296,10 @@ func (s *Service) LockPaymentOptions(ctx context.Context, merchantID, paymentID
 		return ErrPaymentOptionsMissing
 	}
 
+	if quote := details.PaymentMethod.Quote; quote != nil && quote.Expired() {
+		return ErrQuoteExpired
+	}
+
 	_, err = s.payments.Update(ctx, merchantID, paymentID, payment.UpdateProps{Status: payment.StatusLocked})
 	if err != nil {
 		return errors.Wrap(err, "unable to lock payment")
@@ -259,8 +350,11 @@ func (s *Service) SetPaymentMethod(ctx context.Context, p *payment.Payment, tick
 			// This can happen when for example currency provider returns error when fetching currency rates.
 			method, errReturn = s.createIncomingTransaction(ctx, p, currency)
 		case tx.Currency.Ticker == currency.Ticker && tx.Currency.NetworkID == currency.NetworkID:
-			// case 3. no changes, do nothing
-			method = payment.MakeMethod(tx, currency)
+			// case 3. no changes, do nothing unless quote has expired
+			method, errReturn = s.payments.GetPaymentMethod(ctx, p)
+			if errReturn == nil && method.Quote != nil && method.Quote.Expired() {
+				method, errReturn = s.changePaymentMethod(ctx, p, tx, currency)
+			}
 		default:
 			// case 4. ticker has changed. Change pending transaction.
 			method, errReturn = s.changePaymentMethod(ctx, p, tx, currency)
@@ -298,15 +392,34 @@ func (s *Service) getPaymentMethod(ctx context.Context, mt *merchant.Merchant, t
 // and the system is subscribed to all of selected currencies both for mainnet & testnet.
 // Returning bool indicates whether the wallet was created or returned from db.
 func (s *Service) EnsureOutboundWallet(ctx context.Context, chain money.Blockchain) (*wallet.Wallet, bool, error) {
+	return s.ensureSystemWallet(ctx, chain, wallet.TypeOutbound)
+}
+
+// EnsureGasWallet makes sure that gas wallet for specified blockchain exists in the database
+// and the system is subscribed to its top-ups.
+func (s *Service) EnsureGasWallet(ctx context.Context, chain money.Blockchain) (*wallet.Wallet, bool, error) {
+	return s.ensureSystemWallet(ctx, chain, wallet.TypeGas)
+}
+
+func (s *Service) ensureSystemWallet(
+	ctx context.Context,
+	chain money.Blockchain,
+	walletType wallet.Type,
+) (*wallet.Wallet, bool, error) {
 	currencies := s.blockchain.ListBlockchainCurrencies(chain)
 	if len(currencies) == 0 {
 		return nil, false, errors.New("currencies are empty")
 	}
 
+	ensure := s.wallets.EnsureOutboundWallet
+	if walletType == wallet.TypeGas {
+		ensure = s.wallets.EnsureGasWallet
+	}
+
 	// wallet should exist in DB
-	w, justCreated, err := s.wallets.EnsureOutboundWallet(ctx, kmswallet.Blockchain(chain))
+	w, justCreated, err := ensure(ctx, kmswallet.Blockchain(chain))
 	if err != nil {
-		return nil, false, errors.Wrap(err, "unable to ensure outbound wallet")
+		return nil, false, errors.Wrapf(err, "unable to ensure %s wallet", walletType)
 	}
 
 	// wallet should be subscribed to notifications
@@ -343,7 +456,14 @@ func (s *Service) createIncomingTransaction(
 		return nil, err
 	}
 
-	cryptoAmount := conv.To
+	// Customer sees guaranteed amount until the quote expires
+	quote, err := s.payments.QuotePaymentMethod(ctx, pt, conv)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create quote")
+	}
+
+	cryptoAmount := quote.To
+	cryptoRateID := conv.RateID
 
 	var cryptoServiceFee money.Money
 	if s.config.DefaultServiceFee > 0 {
@@ -360,6 +480,12 @@ func (s *Service) createIncomingTransaction(
 
 	usdAmount := conv.To
 
+	// USD-priced payments don't need fiat conversion, so crypto rate defines the value
+	usdRateID := conv.RateID
+	if usdRateID == 0 {
+		usdRateID = cryptoRateID
+	}
+
 	// 2. Acquire available inbound wallet or create one.
 	acquiredWallet, err := s.wallets.AcquireLock(ctx, pt.MerchantID, currency, pt.IsTest)
 	if err != nil {
@@ -380,6 +506,7 @@ func (s *Service) createIncomingTransaction(
 		Amount:          cryptoAmount,
 		ServiceFee:      cryptoServiceFee,
 		USDAmount:       usdAmount,
+		USDRateID:       usdRateID,
 		IsTest:          pt.IsTest,
 	})
 
@@ -403,7 +530,10 @@ func (s *Service) createIncomingTransaction(
 		return nil, errors.Wrap(err, "unable to create tx")
 	}
 
-	return payment.MakeMethod(tx, currency), nil
+	method := payment.MakeMethod(tx, currency)
+	method.Quote = quote
+
+	return method, nil
 }
 
 func (s *Service) changePaymentMethod(
@@ -426,6 +556,14 @@ func (s *Service) changePaymentMethod(
 }
 
 func (s *Service) ensureWalletSubscription(ctx context.Context, w *wallet.Wallet, currency money.CryptoCurrency) error {
+	switch s.config.IncomingProvider(w.Blockchain.ToMoneyBlockchain()) {
+	case IncomingProviderTatum:
+	case IncomingProviderScanner:
+		return nil
+	default:
+		return s.ensureNotifySubscription(ctx, w)
+	}
+
 	params := func(networkID string, isTest bool) tatum.SubscriptionParams {
 		return tatum.SubscriptionParams{
 			Blockchain: w.Blockchain.ToMoneyBlockchain(),
@@ -0,0 +1,307 @@
+package processing
+
+import (
+	"context"
+	"sort"
+
+	kmswallet "github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/payment"
+	"github.com/oxygenpay/oxygen/internal/service/transaction"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/pkg/errors"
+)
+
+// ColdWallet represents hot wallet cap of a single currency.
+type ColdWallet struct {
+	// Address of a cold wallet that is not managed by KMS.
+	Address string `yaml:"address"`
+
+	// HotTarget amount of currency (e.g. "1000" for 1000 USDT) that is kept on outbound wallet
+	// on top of pending withdrawals. For coins it should also cover network fees of token transfers.
+	HotTarget string `yaml:"hot_target"`
+}
+
+// ColdSweepResult represents result of moving excess outbound funds to cold wallets.
+type ColdSweepResult struct {
+	CreatedTransactions []*transaction.Transaction
+
+	// Shortfalls outbound balances that can't cover pending withdrawals and should be refilled by an operator.
+	Shortfalls []HotWalletShortfall
+
+	UnhandledErrors []error
+}
+
+type HotWalletShortfall struct {
+	WalletID int64
+	Address  string
+	Balance  money.Money
+	Required money.Money
+}
+
+// SweepOutboundToCold moves outbound balances that exceed ColdWallet.HotTarget + pending withdrawals
+// to configured cold addresses. Outbound balances that can't cover pending withdrawals are reported as shortfalls.
+// Only mainnet balances are considered.
+func (s *Service) SweepOutboundToCold(ctx context.Context) (*ColdSweepResult, error) {
+	required, err := s.pendingWithdrawalsAmounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	outboundWallets, outboundBalances, err := s.getOutboundWalletsWithBalancesAsMap(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get outbound wallets with balances")
+	}
+
+	keys := make([]string, 0, len(outboundBalances))
+	for key := range outboundBalances {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	result := &ColdSweepResult{}
+
+	for _, key := range keys {
+		b := outboundBalances[key]
+
+		currency, err := s.blockchain.GetCurrencyByTicker(b.Currency)
+		if err != nil {
+			result.UnhandledErrors = append(result.UnhandledErrors, errors.Wrapf(err, "unable to get currency %q", b.Currency))
+			continue
+		}
+
+		if b.NetworkID != currency.NetworkID {
+			continue
+		}
+
+		w, ok := outboundWallets[b.EntityID]
+		if !ok {
+			result.UnhandledErrors = append(result.UnhandledErrors, errors.Errorf("unable to find outbound wallet %d", b.EntityID))
+			continue
+		}
+
+		requiredAmount, ok := required[key]
+		if !ok {
+			requiredAmount, _ = currency.MakeAmount("0")
+		}
+
+		if b.Amount.LessThan(requiredAmount) {
+			result.Shortfalls = append(result.Shortfalls, HotWalletShortfall{
+				WalletID: w.ID,
+				Address:  w.Address,
+				Balance:  b.Amount,
+				Required: requiredAmount,
+			})
+
+			continue
+		}
+
+		cold, ok := s.config.ColdWallet(currency.Ticker)
+		if !ok {
+			continue
+		}
+
+		tx, err := s.sweepToCold(ctx, w, b, currency, cold, requiredAmount)
+		if err != nil {
+			s.logger.Error().Err(err).
+				Int64("wallet_id", w.ID).
+				Int64("balance_id", b.ID).
+				Str("currency", b.Currency).
+				Msg("unable to sweep outbound balance to cold wallet")
+
+			result.UnhandledErrors = append(result.UnhandledErrors, errors.Wrapf(err, "balance #%d", b.ID))
+			continue
+		}
+
+		if tx != nil {
+			result.CreatedTransactions = append(result.CreatedTransactions, tx)
+		}
+	}
+
+	s.appendMissingBalanceShortfalls(result, required, outboundWallets, outboundBalances)
+
+	return result, nil
+}
+
+// appendMissingBalanceShortfalls reports pending withdrawals of currencies that have no outbound balance yet
+// (e.g. outbound wallet was never funded with this token) as shortfalls against zero balance.
+func (s *Service) appendMissingBalanceShortfalls(
+	result *ColdSweepResult,
+	required map[string]money.Money,
+	outboundWallets map[int64]*wallet.Wallet,
+	outboundBalances map[string]*wallet.Balance,
+) {
+	keys := make([]string, 0, len(required))
+	for key := range required {
+		if _, ok := outboundBalances[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		requiredAmount := required[key]
+
+		currency, err := s.blockchain.GetCurrencyByTicker(requiredAmount.Ticker())
+		if err != nil {
+			result.UnhandledErrors = append(result.UnhandledErrors, errors.Wrapf(err, "unable to get currency %q", requiredAmount.Ticker()))
+			continue
+		}
+
+		// only mainnet balances are considered
+		mainnetKey := balanceKey(&wallet.Balance{
+			EntityType: wallet.EntityTypeWallet,
+			NetworkID:  currency.NetworkID,
+			Currency:   currency.Ticker,
+		})
+		if key != mainnetKey {
+			continue
+		}
+
+		zero, err := currency.MakeAmount("0")
+		if err != nil {
+			result.UnhandledErrors = append(result.UnhandledErrors, errors.Wrapf(err, "unable to make %s amount", currency.Ticker))
+			continue
+		}
+
+		// outbound wallet might not exist yet
+		shortfall := HotWalletShortfall{Balance: zero, Required: requiredAmount}
+		for _, w := range outboundWallets {
+			if w.Blockchain.ToMoneyBlockchain() == currency.Blockchain {
+				shortfall.WalletID = w.ID
+				shortfall.Address = w.Address
+				break
+			}
+		}
+
+		result.Shortfalls = append(result.Shortfalls, shortfall)
+	}
+}
+
+// sweepToCold transfers balance's excess to cold address. Returns nil if there is nothing to transfer.
+func (s *Service) sweepToCold(
+	ctx context.Context,
+	w *wallet.Wallet,
+	b *wallet.Balance,
+	currency money.CryptoCurrency,
+	cold ColdWallet,
+	pendingWithdrawals money.Money,
+) (*transaction.Transaction, error) {
+	if err := kmswallet.ValidateAddress(kmswallet.Blockchain(currency.Blockchain), cold.Address); err != nil {
+		return nil, errors.Wrapf(err, "invalid cold address for %s", currency.Ticker)
+	}
+
+	target, err := money.CryptoFromStringFloat(currency.Ticker, cold.HotTarget, currency.Decimals)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid hot target for %s", currency.Ticker)
+	}
+
+	keep, err := target.Add(pendingWithdrawals)
+	if err != nil {
+		return nil, err
+	}
+
+	// coin balance pays network fee of the sweep itself
+	if currency.Type == money.Coin {
+		fee, err := s.blockchain.CalculateFee(ctx, currency, currency, false)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to calculate network fee")
+		}
+
+		feeCost, err := fee.TotalCost(currency)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get network fee total cost")
+		}
+
+		if keep, err = keep.Add(feeCost); err != nil {
+			return nil, err
+		}
+	}
+
+	if b.Amount.LessThanOrEqual(keep) {
+		return nil, nil
+	}
+
+	excess, err := b.Amount.Sub(keep)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to calculate excess amount")
+	}
+
+	minAmountUSD, err := s.blockchain.GetUSDMinimalInternalTransferByTicker(currency.Ticker)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get minimal internal transfer for %q", currency.Ticker)
+	}
+
+	minAmount, err := s.blockchain.FiatToCrypto(ctx, minAmountUSD, currency)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to convert %s to %s", minAmountUSD.Ticker(), currency.Ticker)
+	}
+
+	if excess.LessThan(minAmount.To) {
+		return nil, nil
+	}
+
+	params := internalTransferInput{
+		Type:             transaction.TypeColdSweep,
+		SenderWallet:     w,
+		SenderBalance:    b,
+		RecipientAddress: cold.Address,
+		Amount:           excess,
+	}
+
+	output, errTransfer := s.createInternalTransfer(ctx, w, params)
+	if errTransfer != nil {
+		if errRollback := s.rollbackInternalTransfer(ctx, params, output, errTransfer); errRollback != nil {
+			return nil, errors.Wrap(errRollback, "unable to rollback cold sweep")
+		}
+
+		return nil, errTransfer
+	}
+
+	s.logger.Info().
+		Int64("wallet_id", w.ID).
+		Int64("transaction_id", output.Transaction.ID).
+		Str("amount", excess.String()).
+		Str("currency", currency.Ticker).
+		Str("cold_address", cold.Address).
+		Msg("swept outbound balance to cold wallet")
+
+	return output.Transaction, nil
+}
+
+// pendingWithdrawalsAmounts returns amounts of pending withdrawals grouped by balanceKey.
+func (s *Service) pendingWithdrawalsAmounts(ctx context.Context) (map[string]money.Money, error) {
+	withdrawals, err := s.payments.ListWithdrawals(ctx, payment.StatusPending, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list pending withdrawals")
+	}
+
+	amounts := make(map[string]money.Money)
+
+	for _, withdrawal := range withdrawals {
+		currency, err := s.blockchain.GetCurrencyByTicker(withdrawal.Price.Ticker())
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get withdrawal currency")
+		}
+
+		key := balanceKey(&wallet.Balance{
+			EntityType: wallet.EntityTypeWallet,
+			NetworkID:  currency.ChooseNetwork(withdrawal.IsTest),
+			Currency:   currency.Ticker,
+		})
+
+		total, ok := amounts[key]
+		if !ok {
+			amounts[key] = withdrawal.Price
+			continue
+		}
+
+		if amounts[key], err = total.Add(withdrawal.Price); err != nil {
+			return nil, err
+		}
+	}
+
+	return amounts, nil
+}
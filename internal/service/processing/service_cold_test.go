@@ -0,0 +1,150 @@
+package processing_test
+
+import (
+	"testing"
+
+	kmswallet "github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/merchant"
+	"github.com/oxygenpay/oxygen/internal/service/payment"
+	"github.com/oxygenpay/oxygen/internal/service/transaction"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/oxygenpay/oxygen/internal/test"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:funlen
+func TestService_SweepOutboundToCold(t *testing.T) {
+	tc := test.NewIntegrationTest(t)
+	ctx := tc.Context
+
+	eth := tc.Must.GetCurrency(t, "ETH")
+	ethUSDT := tc.Must.GetCurrency(t, "ETH_USDT")
+	ethUSDC := tc.Must.GetCurrency(t, "ETH_USDC")
+
+	// see test.processingConfig
+	const coldAddress = "0x690b9a9e9aa1c9db991c7721a92d351db4fac990"
+
+	tc.Fakes.SetupAllFees(t, tc.Services.Blockchain)
+	tc.Providers.TatumMock.SetupRates("ETH", money.USD, 1600)
+	tc.Providers.TatumMock.SetupRates("ETH_USDT", money.USD, 1)
+	tc.Providers.TatumMock.SetupRates("ETH_USDC", money.USD, 1)
+
+	// ARRANGE
+	// Given merchant with ETH address and balances
+	mt, _ := tc.Must.CreateMerchant(t, 1)
+
+	addr, err := tc.Services.Merchants.CreateMerchantAddress(ctx, mt.ID, merchant.CreateMerchantAddressParams{
+		Name:       "John's Address",
+		Blockchain: kmswallet.Blockchain(eth.Blockchain),
+		Address:    "0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5",
+	})
+	require.NoError(t, err)
+
+	merchantETH := tc.Must.CreateBalance(t, wallet.EntityTypeMerchant, mt.ID, withBalance(eth, "1_000_000_000_000_000_000", false))
+	merchantUSDT := tc.Must.CreateBalance(t, wallet.EntityTypeMerchant, mt.ID, withBalance(ethUSDT, "2000_000_000", false))
+	merchantUSDC := tc.Must.CreateBalance(t, wallet.EntityTypeMerchant, mt.ID, withBalance(ethUSDC, "2000_000_000", false))
+
+	// And pending withdrawals of 0.5 ETH, 500 USDT & 100 USDC
+	createWithdrawal := func(b *wallet.Balance, amount money.Money) {
+		_, err := tc.Services.Payment.CreateWithdrawal(ctx, mt.ID, payment.CreateWithdrawalProps{
+			BalanceID: b.UUID,
+			AddressID: addr.UUID,
+			AmountRaw: amount.String(),
+		})
+		require.NoError(t, err)
+	}
+
+	createWithdrawal(merchantETH, lo.Must(eth.MakeAmount("500_000_000_000_000_000")))
+	createWithdrawal(merchantUSDT, lo.Must(ethUSDT.MakeAmount("500_000_000")))
+	createWithdrawal(merchantUSDC, lo.Must(ethUSDC.MakeAmount("100_000_000")))
+
+	// And outbound wallet with 0.1 ETH & 5000 USDT (without USDC balance)
+	outbound, outboundETH := tc.Must.CreateWalletWithBalance(t, "ETH", wallet.TypeOutbound, withBalance(eth, "100_000_000_000_000_000", false))
+	outboundUSDT := tc.Must.CreateBalance(t, wallet.EntityTypeWallet, outbound.ID, withBalance(ethUSDT, "5000_000_000", false))
+
+	// And mocked transaction creation & broadcast
+	const (
+		rawTx  = "0x123-cold"
+		txHash = "0xabc-cold"
+	)
+
+	tc.SetupCreateEthereumTransactionWildcard(rawTx)
+	tc.Fakes.SetupBroadcastTransaction(eth.Blockchain, rawTx, false, txHash, nil)
+
+	// ACT
+	result, err := tc.Services.Processing.SweepOutboundToCold(ctx)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Empty(t, result.UnhandledErrors)
+
+	// Check that ETH & USDC shortfalls are reported
+	require.Len(t, result.Shortfalls, 2)
+	assert.Equal(t, outbound.ID, result.Shortfalls[0].WalletID)
+	assert.Equal(t, outboundETH.Amount, result.Shortfalls[0].Balance)
+	assert.Equal(t, "0.5", result.Shortfalls[0].Required.String())
+
+	assert.Equal(t, outbound.ID, result.Shortfalls[1].WalletID)
+	assert.Equal(t, ethUSDC.Ticker, result.Shortfalls[1].Balance.Ticker())
+	assert.True(t, result.Shortfalls[1].Balance.IsZero())
+	assert.Equal(t, "100", result.Shortfalls[1].Required.String())
+
+	// Check that 5000 - 1000 (target) - 500 (withdrawal) USDT was swept
+	require.Len(t, result.CreatedTransactions, 1)
+
+	tx, err := tc.Services.Transaction.GetByID(ctx, transaction.SystemMerchantID, result.CreatedTransactions[0].ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, transaction.TypeColdSweep, tx.Type)
+	assert.Equal(t, transaction.StatusPending, tx.Status)
+	assert.Equal(t, outbound.ID, *tx.SenderWalletID)
+	assert.Nil(t, tx.RecipientWalletID)
+	assert.Equal(t, coldAddress, tx.RecipientAddress)
+	assert.Equal(t, "3500", tx.Amount.String())
+	assert.Equal(t, txHash, *tx.HashID)
+
+	outboundUSDT, err = tc.Services.Wallet.GetBalanceByID(ctx, wallet.EntityTypeWallet, outbound.ID, outboundUSDT.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "1500", outboundUSDT.Amount.String())
+
+	t.Run("Confirms cold sweep", func(t *testing.T) {
+		// ARRANGE
+		networkFee := lo.Must(eth.MakeAmount("1000"))
+
+		tc.Fakes.SetupGetTransactionReceipt(eth.Blockchain, txHash, false, &blockchain.TransactionReceipt{
+			Blockchain:    eth.Blockchain,
+			Sender:        outbound.Address,
+			Recipient:     coldAddress,
+			Hash:          txHash,
+			NetworkFee:    networkFee,
+			Success:       true,
+			Confirmations: 5,
+			IsConfirmed:   true,
+		}, nil)
+
+		// ACT
+		err := tc.Services.Processing.BatchCheckInternalTransfers(ctx, []int64{tx.ID})
+
+		// ASSERT
+		require.NoError(t, err)
+
+		tx, err := tc.Services.Transaction.GetByID(ctx, transaction.SystemMerchantID, tx.ID)
+		require.NoError(t, err)
+		assert.Equal(t, transaction.StatusCompleted, tx.Status)
+		assert.Equal(t, networkFee, *tx.NetworkFee)
+
+		// Check that network fee is paid by outbound wallet's coin balance
+		coinBalance, err := tc.Services.Wallet.GetBalanceByID(ctx, wallet.EntityTypeWallet, outbound.ID, outboundETH.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "99999999999999000", coinBalance.Amount.StringRaw())
+
+		// Check that USDT balance is unchanged
+		usdtBalance, err := tc.Services.Wallet.GetBalanceByID(ctx, wallet.EntityTypeWallet, outbound.ID, outboundUSDT.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "1500", usdtBalance.Amount.String())
+	})
+}
@@ -0,0 +1,274 @@
+package processing
+
+import (
+	"context"
+	"sort"
+
+	kmswallet "github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/transaction"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/pkg/errors"
+)
+
+var ErrInsufficientEnergy = errors.New("staking wallet has insufficient energy")
+
+const (
+	// tronStakingReserveSun liquid TRX that is left on staking wallet for paying bandwidth of staking transactions.
+	tronStakingReserveSun = int64(10 * 1_000_000)
+
+	// tronMinStakingSun min amount of TRX that could be frozen or delegated.
+	tronMinStakingSun = int64(1_000_000)
+)
+
+// TronEnergyResult represents result of TRON energy management.
+type TronEnergyResult struct {
+	// FrozenSun amount of staking wallet's TRX that was staked for energy.
+	FrozenSun int64
+
+	// Delegated & Reclaimed map wallet id to staked SUN which energy was delegated / reclaimed.
+	Delegated map[int64]int64
+	Reclaimed map[int64]int64
+}
+
+// ManageTronEnergy manages energy of TRON staking wallet:
+//   - liquid TRX of staking wallet are staked for energy (except small reserve for bandwidth);
+//   - energy is reclaimed from inbound wallets that have already transferred their tokens;
+//   - outbound wallet is kept with energy enough for Config.TronOutboundEnergyTransfers token transfers.
+//
+// Inbound wallets get energy on demand during gas funding (see BatchFundGas).
+func (s *Service) ManageTronEnergy(ctx context.Context, isTest bool) (*TronEnergyResult, error) {
+	stakingWallet, _, err := s.wallets.EnsureStakingWallet(ctx, kmswallet.TRON)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to ensure staking wallet")
+	}
+
+	staking, err := s.blockchain.GetTronStaking(ctx, stakingWallet.Address, isTest)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get staking wallet state")
+	}
+
+	result := &TronEnergyResult{
+		Delegated: make(map[int64]int64),
+		Reclaimed: make(map[int64]int64),
+	}
+
+	// 1. Stake liquid TRX
+	if freezable := staking.LiquidSun - tronStakingReserveSun; freezable >= tronMinStakingSun {
+		err := s.sendTronResourceTransaction(ctx, stakingWallet, kmswallet.TronFreeze, freezable, nil, isTest)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to stake TRX")
+		}
+
+		result.FrozenSun = freezable
+	}
+
+	outbound, _, err := s.wallets.EnsureOutboundWallet(ctx, kmswallet.TRON)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to ensure outbound wallet")
+	}
+
+	// 2. Reclaim energy from inbound wallets
+	receivers := make([]string, 0, len(staking.Delegations))
+	for address := range staking.Delegations {
+		receivers = append(receivers, address)
+	}
+
+	sort.Strings(receivers)
+
+	for _, address := range receivers {
+		if address == outbound.Address {
+			continue
+		}
+
+		w, err := s.wallets.GetByAddress(ctx, kmswallet.TRON, address)
+
+		switch {
+		case errors.Is(err, wallet.ErrNotFound):
+			// energy was delegated outside the app
+			continue
+		case err != nil:
+			return nil, errors.Wrapf(err, "unable to get wallet by address %s", address)
+		}
+
+		reclaimable, err := s.isEnergyReclaimable(ctx, w, isTest)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reclaimable {
+			continue
+		}
+
+		sun := staking.Delegations[address]
+		if err := s.sendTronResourceTransaction(ctx, stakingWallet, kmswallet.TronUndelegate, sun, w, isTest); err != nil {
+			return nil, errors.Wrapf(err, "unable to reclaim energy from wallet %d", w.ID)
+		}
+
+		result.Reclaimed[w.ID] = sun
+	}
+
+	// 3. Keep outbound wallet's energy
+	if s.config.TronOutboundEnergyTransfers > 0 {
+		energy := s.config.TronOutboundEnergyTransfers * blockchain.TronTokenTransferEnergy
+
+		sun, err := s.delegateEnergy(ctx, stakingWallet, &staking, outbound, energy, isTest)
+
+		switch {
+		case errors.Is(err, ErrInsufficientEnergy):
+			s.logger.Warn().Err(err).Int64("wallet_id", outbound.ID).Msg("unable to delegate energy to outbound wallet")
+		case err != nil:
+			return nil, errors.Wrap(err, "unable to delegate energy to outbound wallet")
+		case sun > 0:
+			result.Delegated[outbound.ID] = sun
+		}
+	}
+
+	s.logger.Info().
+		Int64("frozen_sun", result.FrozenSun).
+		Interface("delegated", result.Delegated).
+		Interface("reclaimed", result.Reclaimed).
+		Msg("managed TRON energy")
+
+	return result, nil
+}
+
+// delegateTokenTransfersEnergy delegates energy required for transferring tokens from TRON wallet.
+// Returns amount of staked SUN which energy was delegated or zero if wallet already has enough energy.
+func (s *Service) delegateTokenTransfersEnergy(ctx context.Context, w *wallet.Wallet, transfers int, isTest bool) (int64, error) {
+	stakingWallet, _, err := s.wallets.EnsureStakingWallet(ctx, kmswallet.TRON)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to ensure staking wallet")
+	}
+
+	staking, err := s.blockchain.GetTronStaking(ctx, stakingWallet.Address, isTest)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to get staking wallet state")
+	}
+
+	energy := int64(transfers) * blockchain.TronTokenTransferEnergy
+
+	return s.delegateEnergy(ctx, stakingWallet, &staking, w, energy, isTest)
+}
+
+// delegateEnergy ensures that recipient has the specified amount of available energy.
+// Staking state is updated in order to be reused for subsequent delegations.
+func (s *Service) delegateEnergy(
+	ctx context.Context,
+	stakingWallet *wallet.Wallet,
+	staking *blockchain.TronStaking,
+	recipient *wallet.Wallet,
+	energy int64,
+	isTest bool,
+) (int64, error) {
+	resources, err := s.blockchain.GetTronResources(ctx, recipient.Address, isTest)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to get recipient's resources")
+	}
+
+	lacking := energy - resources.AvailableEnergy()
+	if lacking <= 0 {
+		return 0, nil
+	}
+
+	sun, err := staking.Resources.EnergyToSun(lacking)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to convert energy to staked TRX")
+	}
+
+	if sun < tronMinStakingSun {
+		sun = tronMinStakingSun
+	}
+
+	if sun > staking.DelegatableSun {
+		return 0, errors.Wrapf(
+			ErrInsufficientEnergy,
+			"wallet %s can delegate %d SUN, required %d SUN for %d energy",
+			stakingWallet.Address, staking.DelegatableSun, sun, lacking,
+		)
+	}
+
+	if err := s.sendTronResourceTransaction(ctx, stakingWallet, kmswallet.TronDelegate, sun, recipient, isTest); err != nil {
+		return 0, err
+	}
+
+	staking.DelegatableSun -= sun
+
+	return sun, nil
+}
+
+// isEnergyReclaimable checks that inbound wallet has neither tokens to transfer nor in-flight transfers.
+func (s *Service) isEnergyReclaimable(ctx context.Context, w *wallet.Wallet, isTest bool) (bool, error) {
+	if w.Type != wallet.TypeInbound {
+		return false, nil
+	}
+
+	coin, err := s.blockchain.GetNativeCoin(w.Blockchain.ToMoneyBlockchain())
+	if err != nil {
+		return false, errors.Wrap(err, "unable to get native coin")
+	}
+
+	networkID := coin.ChooseNetwork(isTest)
+
+	inFlight, err := s.transactions.ListByFilter(ctx, transaction.Filter{
+		SenderWalletID: w.ID,
+		NetworkID:      networkID,
+		Types:          []transaction.Type{transaction.TypeInternal},
+		Statuses:       []transaction.Status{transaction.StatusPending, transaction.StatusInProgress},
+	}, 1)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to list in-flight transactions")
+	}
+
+	if len(inFlight) > 0 {
+		return false, nil
+	}
+
+	balances, err := s.wallets.ListBalances(ctx, wallet.EntityTypeWallet, w.ID, false)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to list wallet balances")
+	}
+
+	for _, b := range balances {
+		if b.NetworkID == networkID && b.CurrencyType == money.Token && !b.Amount.IsZero() {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (s *Service) sendTronResourceTransaction(
+	ctx context.Context,
+	stakingWallet *wallet.Wallet,
+	operation kmswallet.TronResourceOperation,
+	sun int64,
+	receiver *wallet.Wallet,
+	isTest bool,
+) error {
+	var receiverAddress string
+	if receiver != nil {
+		receiverAddress = receiver.Address
+	}
+
+	raw, err := s.wallets.CreateTronResourceTransaction(ctx, stakingWallet, operation, sun, receiverAddress, isTest)
+	if err != nil {
+		return err
+	}
+
+	txHash, err := s.blockchain.BroadcastTransaction(ctx, kmswallet.TRON.ToMoneyBlockchain(), raw, isTest)
+	if err != nil {
+		return errors.Wrapf(err, "unable to broadcast TRON %s transaction", operation)
+	}
+
+	s.logger.Info().
+		Str("operation", string(operation)).
+		Int64("amount_sun", sun).
+		Str("receiver", receiverAddress).
+		Str("transaction_hash", txHash).
+		Bool("is_test", isTest).
+		Msg("sent TRON staking transaction")
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package processing_test
+
+import (
+	"testing"
+
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/oxygenpay/oxygen/internal/test"
+	kmswallet "github.com/oxygenpay/oxygen/pkg/api-kms/v1/client/wallet"
+	kmsmodel "github.com/oxygenpay/oxygen/pkg/api-kms/v1/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:funlen
+func TestService_ManageTronEnergy(t *testing.T) {
+	tc := test.NewIntegrationTest(t)
+	ctx := tc.Context
+
+	tron := tc.Must.GetCurrency(t, "TRON")
+	tronUSDT := tc.Must.GetCurrency(t, "TRON_USDT")
+
+	// Given network-wide staking ratio: 1 TRX = 10 energy
+	networkResources := trongrid.AccountResources{
+		TotalEnergyLimit:  90_000_000_000,
+		TotalEnergyWeight: 9_000_000_000,
+	}
+
+	// Given shortcut for mocking signed & broadcasted staking transaction
+	mockResourceTransaction := func(sender *wallet.Wallet, operation string, amount int64, receiver, raw string) {
+		tc.Providers.KMS.
+			On("CreateTronResourceTransaction", mock.MatchedBy(func(p *kmswallet.CreateTronResourceTransactionParams) bool {
+				return p.WalletID == sender.UUID.String() &&
+					p.Data.Operation == operation &&
+					p.Data.Amount == amount &&
+					p.Data.Receiver == receiver
+			})).
+			Return(&kmswallet.CreateTronResourceTransactionCreated{
+				Payload: &kmsmodel.TronTransaction{RawDataHex: raw},
+			}, nil).
+			Once()
+
+		tc.Fakes.SetupBroadcastTransaction(tron.Blockchain, raw, false, raw+"-hash", nil)
+	}
+
+	t.Run("Stakes TRX, reclaims & delegates energy", func(t *testing.T) {
+		tc.Clear.Wallets(t)
+
+		// ARRANGE
+		// Given staking & outbound wallets
+		stakingWallet := tc.Must.CreateWallet(t, "TRON", "T-staking", "pub-key-staking", wallet.TypeStaking)
+		outbound := tc.Must.CreateWallet(t, "TRON", "T-outbound", "pub-key-outbound", wallet.TypeOutbound)
+
+		// And inbound wallet that has already transferred its tokens
+		inboundDone, _ := tc.Must.CreateWalletWithBalance(t, "TRON", wallet.TypeInbound, withBalance(tronUSDT, "0", false))
+
+		// And inbound wallet that still holds tokens
+		inboundPending, _ := tc.Must.CreateWalletWithBalance(t, "TRON", wallet.TypeInbound, withBalance(tronUSDT, "100_000_000", false))
+
+		// And staking wallet with 110 TRX liquid balance & 20k TRX staked
+		tc.Fakes.SetupGetTronStaking(stakingWallet.Address, false, blockchain.TronStaking{
+			Resources:      networkResources,
+			LiquidSun:      110_000_000,
+			DelegatableSun: 20_000_000_000,
+			Delegations: map[string]int64{
+				outbound.Address:       1_000_000_000,
+				inboundDone.Address:    6_500_000_000,
+				inboundPending.Address: 6_500_000_000,
+				"T-external-address":   1_000_000,
+			},
+		}, nil)
+
+		// And outbound wallet with energy for less than 2 transfers
+		tc.Fakes.SetupGetTronResources(outbound.Address, false, trongrid.AccountResources{
+			EnergyLimit: 40_000,
+			EnergyUsed:  10_000,
+		}, nil)
+
+		// And mocked staking transactions
+		mockResourceTransaction(stakingWallet, "freeze", 100_000_000, "", "raw-freeze")
+		mockResourceTransaction(stakingWallet, "undelegate", 6_500_000_000, inboundDone.Address, "raw-undelegate")
+		mockResourceTransaction(stakingWallet, "delegate", 10_000_000_000, outbound.Address, "raw-delegate")
+
+		// ACT
+		result, err := tc.Services.Processing.ManageTronEnergy(ctx, false)
+
+		// ASSERT
+		require.NoError(t, err)
+
+		// 110 TRX - 10 TRX reserve
+		assert.Equal(t, int64(100_000_000), result.FrozenSun)
+
+		// only inbound wallet without tokens
+		assert.Equal(t, map[int64]int64{inboundDone.ID: 6_500_000_000}, result.Reclaimed)
+
+		// (2 * 65k - 30k) energy = 10k TRX
+		assert.Equal(t, map[int64]int64{outbound.ID: 10_000_000_000}, result.Delegated)
+
+		tc.Providers.KMS.AssertExpectations(t)
+	})
+
+	t.Run("Delegates energy to inbound wallet instead of funding it with TRX", func(t *testing.T) {
+		tc.Clear.Wallets(t)
+
+		// ARRANGE
+		tc.Fakes.SetupAllFees(t, tc.Services.Blockchain)
+		tc.Providers.TatumMock.SetupRates("TRON", money.USD, 0.07)
+		tc.Providers.TatumMock.SetupRates("TRON_USDT", money.USD, 1)
+
+		// Given staking wallet
+		stakingWallet := tc.Must.CreateWallet(t, "TRON", "T-staking", "pub-key-staking", wallet.TypeStaking)
+
+		tc.Fakes.SetupGetTronStaking(stakingWallet.Address, false, blockchain.TronStaking{
+			Resources:      networkResources,
+			DelegatableSun: 20_000_000_000,
+		}, nil)
+
+		// And inbound wallet with USDT and without TRX & energy
+		w, usdtBalance := tc.Must.CreateWalletWithBalance(t, "TRON", wallet.TypeInbound, withBalance(tronUSDT, "100_000_000", false))
+		tc.Fakes.SetupGetTronResources(w.Address, false, trongrid.AccountResources{}, nil)
+
+		// And mocked delegation of energy for a single transfer
+		mockResourceTransaction(stakingWallet, "delegate", 6_500_000_000, w.Address, "raw-delegate-inbound")
+
+		// ACT
+		result, err := tc.Services.Processing.BatchFundGas(ctx, []*wallet.Balance{usdtBalance})
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Empty(t, result.UnhandledErrors)
+		assert.Empty(t, result.ReadyBalances)
+		assert.Empty(t, result.FundingTransactions)
+		assert.Equal(t, []int64{w.ID}, result.AwaitingWalletIDs)
+
+		tc.Providers.KMS.AssertExpectations(t)
+	})
+}
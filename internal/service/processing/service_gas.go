@@ -0,0 +1,284 @@
+package processing
+
+import (
+	"context"
+
+	kmswallet "github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/transaction"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/pkg/errors"
+)
+
+var ErrInsufficientGas = errors.New("gas wallet has insufficient balance")
+
+// GasFundingResult represents result of checking whether inbound wallets are able to pay network fees.
+type GasFundingResult struct {
+	// ReadyBalances balances that can be transferred to outbound wallets right away.
+	ReadyBalances []*wallet.Balance
+
+	// FundingTransactions gas funding transactions created during the run.
+	FundingTransactions []*transaction.Transaction
+
+	// AwaitingWalletIDs inbound wallets which balances wait for gas funding confirmation.
+	AwaitingWalletIDs []int64
+
+	UnhandledErrors []error
+}
+
+// BatchFundGas ensures that inbound wallets have enough native coin to pay network fees of token transfers.
+// Balances of wallets that are able to pay fees are returned as ready. Other wallets are funded from
+// the gas wallet with the exact amount of lacking coins, so their tokens would be transferred
+// on the next run after funding transaction is confirmed. TRON wallets get energy from the staking wallet
+// first and are funded with TRX only if staking wallet has not enough energy.
+//
+// Coin balances of wallets that hold tokens are postponed until tokens are transferred,
+// otherwise the coins reserved for network fees would be moved to outbound wallet as well.
+func (s *Service) BatchFundGas(ctx context.Context, inboundBalances []*wallet.Balance) (*GasFundingResult, error) {
+	if err := s.validateInboundBalances(ctx, inboundBalances); err != nil {
+		return nil, errors.Wrap(err, "validation error: balances are invalid")
+	}
+
+	type walletNetwork struct {
+		walletID  int64
+		networkID string
+	}
+
+	var (
+		keys    []walletNetwork
+		grouped = make(map[walletNetwork][]*wallet.Balance)
+	)
+
+	for _, b := range inboundBalances {
+		key := walletNetwork{walletID: b.EntityID, networkID: b.NetworkID}
+		if _, ok := grouped[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		grouped[key] = append(grouped[key], b)
+	}
+
+	result := &GasFundingResult{}
+
+	// wallets are processed sequentially because all of them are funded from the same gas wallet
+	for _, key := range keys {
+		if err := s.fundWalletGas(ctx, key.walletID, key.networkID, grouped[key], result); err != nil {
+			s.logger.Error().Err(err).
+				Int64("wallet_id", key.walletID).
+				Str("network_id", key.networkID).
+				Msg("unable to fund inbound wallet with gas")
+
+			result.UnhandledErrors = append(result.UnhandledErrors, err)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Service) fundWalletGas(
+	ctx context.Context,
+	walletID int64,
+	networkID string,
+	balances []*wallet.Balance,
+	result *GasFundingResult,
+) error {
+	var tokens []*wallet.Balance
+	for _, b := range balances {
+		if b.CurrencyType == money.Token {
+			tokens = append(tokens, b)
+		}
+	}
+
+	if len(tokens) == 0 {
+		result.ReadyBalances = append(result.ReadyBalances, balances...)
+		return nil
+	}
+
+	w, err := s.wallets.GetByID(ctx, walletID)
+	if err != nil {
+		return errors.Wrap(err, "unable to get wallet")
+	}
+
+	chain := w.Blockchain.ToMoneyBlockchain()
+
+	coin, err := s.blockchain.GetNativeCoin(chain)
+	if err != nil {
+		return errors.Wrap(err, "unable to get native coin")
+	}
+
+	isTest := coin.TestNetworkID == networkID
+
+	// 1. Wait for previous funding to be confirmed
+	inFlight, err := s.transactions.ListByFilter(ctx, transaction.Filter{
+		RecipientWalletID: w.ID,
+		NetworkID:         networkID,
+		Types:             []transaction.Type{transaction.TypeGasFunding},
+		Statuses:          []transaction.Status{transaction.StatusPending, transaction.StatusInProgress},
+	}, 1)
+	if err != nil {
+		return errors.Wrap(err, "unable to list gas funding transactions")
+	}
+
+	if len(inFlight) > 0 {
+		result.AwaitingWalletIDs = append(result.AwaitingWalletIDs, w.ID)
+		return nil
+	}
+
+	// 2. Calculate network fees for all token transfers
+	required, err := coin.MakeAmount("0")
+	if err != nil {
+		return err
+	}
+
+	for _, b := range tokens {
+		currency, err := s.blockchain.GetCurrencyByTicker(b.Currency)
+		if err != nil {
+			return errors.Wrap(err, "unable to get currency")
+		}
+
+		// TRON fees depend on wallet's energy
+		fee, err := s.blockchain.CalculateSenderFee(ctx, coin, currency, w.Address, isTest)
+		if err != nil {
+			return errors.Wrapf(err, "unable to calculate %s fee", currency.Ticker)
+		}
+
+		cost, err := fee.TotalCost(coin)
+		if err != nil {
+			return errors.Wrapf(err, "unable to get %s fee total cost", currency.Ticker)
+		}
+
+		if required, err = required.Add(cost); err != nil {
+			return err
+		}
+	}
+
+	// 3. Compare with wallet's coin balance
+	available, err := coin.MakeAmount("0")
+	if err != nil {
+		return err
+	}
+
+	coinBalance, err := s.wallets.GetWalletsBalance(ctx, w.ID, coin.Ticker, networkID)
+
+	switch {
+	case errors.Is(err, wallet.ErrBalanceNotFound):
+	case err != nil:
+		return errors.Wrap(err, "unable to get wallet's coin balance")
+	default:
+		available = coinBalance.Amount
+	}
+
+	if available.GreaterThanOrEqual(required) {
+		result.ReadyBalances = append(result.ReadyBalances, tokens...)
+		return nil
+	}
+
+	// 4. TRON: delegate energy from staking wallet instead of burning TRX
+	if w.Blockchain == kmswallet.TRON {
+		sun, err := s.delegateTokenTransfersEnergy(ctx, w, len(tokens), isTest)
+
+		switch {
+		case errors.Is(err, ErrInsufficientEnergy):
+			s.logger.Warn().Err(err).Int64("wallet_id", w.ID).Msg("unable to delegate energy, funding wallet with TRX")
+		case err != nil:
+			return errors.Wrap(err, "unable to delegate energy")
+		case sun > 0:
+			result.AwaitingWalletIDs = append(result.AwaitingWalletIDs, w.ID)
+
+			s.logger.Info().
+				Int64("wallet_id", w.ID).
+				Int64("delegated_sun", sun).
+				Msg("delegated energy to inbound wallet")
+
+			return nil
+		}
+	}
+
+	// 5. Fund the wallet with lacking amount
+	lacking, err := required.Sub(available)
+	if err != nil {
+		return errors.Wrap(err, "unable to calculate lacking amount")
+	}
+
+	tx, err := s.createGasFunding(ctx, w, coin, lacking, isTest)
+	if err != nil {
+		return err
+	}
+
+	result.FundingTransactions = append(result.FundingTransactions, tx)
+	result.AwaitingWalletIDs = append(result.AwaitingWalletIDs, w.ID)
+
+	s.logger.Info().
+		Int64("wallet_id", w.ID).
+		Int64("transaction_id", tx.ID).
+		Str("required_amount", required.String()).
+		Str("available_amount", available.String()).
+		Str("funding_amount", lacking.String()).
+		Str("funding_amount_usd", tx.USDAmount.String()).
+		Str("currency", coin.Ticker).
+		Msg("funded inbound wallet with gas")
+
+	return nil
+}
+
+// createGasFunding sends native coins from gas wallet to inbound wallet.
+// Both amount & network fee are accounted as gas wallet's expenses.
+func (s *Service) createGasFunding(
+	ctx context.Context,
+	recipient *wallet.Wallet,
+	coin money.CryptoCurrency,
+	amount money.Money,
+	isTest bool,
+) (*transaction.Transaction, error) {
+	// webhook subscription is managed by Service.EnsureGasWallet, so it's not repeated on every run
+	gasWallet, _, err := s.wallets.EnsureGasWallet(ctx, kmswallet.Blockchain(coin.Blockchain))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to ensure gas wallet")
+	}
+
+	gasBalance, err := s.wallets.GetWalletsBalance(ctx, gasWallet.ID, coin.Ticker, coin.ChooseNetwork(isTest))
+
+	switch {
+	case errors.Is(err, wallet.ErrBalanceNotFound):
+		return nil, errors.Wrapf(ErrInsufficientGas, "%s wallet %s has no balance", coin.Blockchain, gasWallet.Address)
+	case err != nil:
+		return nil, errors.Wrap(err, "unable to get gas wallet balance")
+	}
+
+	fee, err := s.blockchain.CalculateFee(ctx, coin, coin, isTest)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to calculate gas funding fee")
+	}
+
+	feeCost, err := fee.TotalCost(coin)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get gas funding fee total cost")
+	}
+
+	if errCovers := gasBalance.Covers(amount, feeCost); errCovers != nil {
+		return nil, errors.Wrapf(
+			ErrInsufficientGas,
+			"%s wallet %s has %s, required %s + %s fee",
+			coin.Blockchain, gasWallet.Address, gasBalance.Amount, amount, feeCost,
+		)
+	}
+
+	params := internalTransferInput{
+		Type:            transaction.TypeGasFunding,
+		SenderWallet:    gasWallet,
+		SenderBalance:   gasBalance,
+		RecipientWallet: recipient,
+		Amount:          amount,
+	}
+
+	output, errTransfer := s.createInternalTransfer(ctx, gasWallet, params)
+	if errTransfer == nil {
+		return output.Transaction, nil
+	}
+
+	if errRollback := s.rollbackInternalTransfer(ctx, params, output, errTransfer); errRollback != nil {
+		return nil, errors.Wrap(errRollback, "unable to rollback gas funding")
+	}
+
+	return nil, errors.Wrapf(errTransfer, "unable to fund wallet %d with gas", recipient.ID)
+}
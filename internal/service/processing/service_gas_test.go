@@ -0,0 +1,148 @@
+package processing_test
+
+import (
+	"testing"
+
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/processing"
+	"github.com/oxygenpay/oxygen/internal/service/transaction"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/oxygenpay/oxygen/internal/test"
+	kmswallet "github.com/oxygenpay/oxygen/pkg/api-kms/v1/client/wallet"
+	kmsmodel "github.com/oxygenpay/oxygen/pkg/api-kms/v1/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:funlen
+func TestService_BatchFundGas(t *testing.T) {
+	tc := test.NewIntegrationTest(t)
+	ctx := tc.Context
+
+	eth := tc.Must.GetCurrency(t, "ETH")
+	ethUSDT := tc.Must.GetCurrency(t, "ETH_USDT")
+
+	// Mock tx fees
+	tc.Fakes.SetupAllFees(t, tc.Services.Blockchain)
+
+	// Mock exchange rates
+	tc.Providers.TatumMock.SetupRates("ETH", money.USD, 1600)
+	tc.Providers.TatumMock.SetupRates("ETH_USDT", money.USD, 1)
+
+	// Given ETH_USDT transfer network fee
+	usdtFee, err := tc.Services.Blockchain.CalculateFee(ctx, eth, ethUSDT, false)
+	require.NoError(t, err)
+
+	usdtFeeCost, err := usdtFee.TotalCost(eth)
+	require.NoError(t, err)
+
+	t.Run("Wallet has enough coins", func(t *testing.T) {
+		tc.Clear.Wallets(t)
+
+		// ARRANGE
+		// Given inbound wallet with USDT and enough ETH to pay the fee
+		w, usdtBalance := tc.Must.CreateWalletWithBalance(t, "ETH", wallet.TypeInbound, withBalance(ethUSDT, "100_000_000", false))
+		ethBalance := tc.Must.CreateBalance(t, wallet.EntityTypeWallet, w.ID, withBalance(eth, "100_000_000_000_000_000", false))
+
+		// ACT
+		result, err := tc.Services.Processing.BatchFundGas(ctx, []*wallet.Balance{usdtBalance, ethBalance})
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Empty(t, result.UnhandledErrors)
+		assert.Empty(t, result.FundingTransactions)
+
+		// ETH balance is postponed until USDT is transferred
+		require.Len(t, result.ReadyBalances, 1)
+		assert.Equal(t, usdtBalance.ID, result.ReadyBalances[0].ID)
+	})
+
+	t.Run("Funds wallet without coins", func(t *testing.T) {
+		tc.Clear.Wallets(t)
+
+		// ARRANGE
+		// Given gas wallet with 1 ETH
+		gasWallet, gasBalance := tc.Must.CreateWalletWithBalance(t, "ETH", wallet.TypeGas, withBalance(eth, "1_000_000_000_000_000_000", false))
+
+		// And inbound wallet with USDT only
+		w, usdtBalance := tc.Must.CreateWalletWithBalance(t, "ETH", wallet.TypeInbound, withBalance(ethUSDT, "100_000_000", false))
+
+		// And mocked gas funding transaction
+		const (
+			rawTxData = "0x-gas-funding"
+			txHashID  = "0x-gas-funding-hash"
+		)
+
+		tc.Providers.KMS.
+			On("CreateEthereumTransaction", mock.MatchedBy(func(p *kmswallet.CreateEthereumTransactionParams) bool {
+				return p.WalletID == gasWallet.UUID.String() &&
+					p.Data.Recipient == w.Address &&
+					p.Data.Amount == usdtFeeCost.StringRaw()
+			})).
+			Return(&kmswallet.CreateEthereumTransactionCreated{
+				Payload: &kmsmodel.EthereumTransaction{RawTransaction: rawTxData},
+			}, nil).
+			Once()
+
+		tc.Fakes.SetupBroadcastTransaction(eth.Blockchain, rawTxData, false, txHashID, nil)
+
+		// ACT
+		result, err := tc.Services.Processing.BatchFundGas(ctx, []*wallet.Balance{usdtBalance})
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Empty(t, result.UnhandledErrors)
+		assert.Empty(t, result.ReadyBalances)
+		assert.Equal(t, []int64{w.ID}, result.AwaitingWalletIDs)
+		require.Len(t, result.FundingTransactions, 1)
+
+		tx, err := tc.Services.Transaction.GetByID(ctx, 0, result.FundingTransactions[0].ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, transaction.TypeGasFunding, tx.Type)
+		assert.Equal(t, usdtFeeCost, tx.Amount)
+		assert.Equal(t, gasWallet.ID, *tx.SenderWalletID)
+		assert.Equal(t, w.ID, *tx.RecipientWalletID)
+		assert.Equal(t, txHashID, *tx.HashID)
+
+		// Gas wallet balance is decremented
+		gasBalanceFresh, err := tc.Services.Wallet.GetBalanceByUUID(ctx, wallet.EntityTypeWallet, gasWallet.ID, gasBalance.UUID)
+		require.NoError(t, err)
+
+		expected, err := gasBalance.Amount.Sub(usdtFeeCost)
+		require.NoError(t, err)
+		assert.Equal(t, expected, gasBalanceFresh.Amount)
+
+		// ACT 2
+		// Funding is not confirmed yet
+		result, err = tc.Services.Processing.BatchFundGas(ctx, []*wallet.Balance{usdtBalance})
+
+		// ASSERT 2
+		require.NoError(t, err)
+		assert.Empty(t, result.ReadyBalances)
+		assert.Empty(t, result.FundingTransactions)
+		assert.Equal(t, []int64{w.ID}, result.AwaitingWalletIDs)
+	})
+
+	t.Run("Gas wallet has insufficient balance", func(t *testing.T) {
+		tc.Clear.Wallets(t)
+
+		// ARRANGE
+		// Given empty gas wallet
+		tc.Must.CreateWalletWithBalance(t, "ETH", wallet.TypeGas, withBalance(eth, "0", false))
+
+		// And inbound wallet with USDT only
+		_, usdtBalance := tc.Must.CreateWalletWithBalance(t, "ETH", wallet.TypeInbound, withBalance(ethUSDT, "100_000_000", false))
+
+		// ACT
+		result, err := tc.Services.Processing.BatchFundGas(ctx, []*wallet.Balance{usdtBalance})
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Empty(t, result.ReadyBalances)
+		assert.Empty(t, result.FundingTransactions)
+		require.Len(t, result.UnhandledErrors, 1)
+		assert.ErrorIs(t, result.UnhandledErrors[0], processing.ErrInsufficientGas)
+	})
+}
@@ -2,6 +2,7 @@ package processing
 
 import (
 	"context"
+	"strconv"
 	"sync"
 	"sync/atomic"
 
@@ -126,6 +127,7 @@ func (s *Service) createUnexpectedTransaction(ctx context.Context, wt *wallet.Wa
 		Currency:        input.Currency,
 		Amount:          input.Amount,
 		USDAmount:       conv.To,
+		USDRateID:       conv.RateID,
 		IsTest:          isTest,
 	}
 
@@ -245,18 +247,65 @@ func (s *Service) checkIncomingTransaction(ctx context.Context, txID int64) erro
 		return errors.Wrap(err, "unable to get transaction receipt")
 	}
 
-	if !receipt.IsConfirmed {
+	// large transfers might require more confirmations than blockchain's base requirement
+	required := s.blockchain.RequiredConfirmations(tx.Currency.Blockchain, tx.USDAmount)
+	if receipt.RequiredConfirmations > required {
+		required = receipt.RequiredConfirmations
+	}
+
+	if !receipt.IsConfirmed || receipt.Confirmations < required {
+		if err := s.updateConfirmations(ctx, tx, receipt.Confirmations, required); err != nil {
+			return err
+		}
+
+		// check later
+		return nil
+	}
+
+	// high-value transactions are verified by independent RPC endpoints
+	err = s.blockchain.CrossCheckTransactionReceipt(ctx, receipt, tx.USDAmount)
+	switch {
+	case errors.Is(err, blockchain.ErrReceiptMismatch):
+		s.logger.Warn().Err(err).
+			Int64("transaction_id", tx.ID).
+			Str("transaction_hash", receipt.Hash).
+			Msg("transaction receipt cross-check failed")
+
 		// check later
 		return nil
+	case err != nil:
+		return errors.Wrap(err, "unable to cross-check transaction receipt")
 	}
 
 	if !receipt.Success {
 		return s.cancelIncomingTransaction(ctx, tx)
 	}
 
+	tx.MetaData = tx.MetaData.WithConfirmations(receipt.Confirmations, required)
+
+	// remember the block to detect chain reorganizations later
+	if receipt.BlockNumber > 0 {
+		tx.MetaData[transaction.MetaBlockNumber] = strconv.FormatInt(receipt.BlockNumber, 10)
+		tx.MetaData[transaction.MetaBlockHash] = receipt.BlockHash
+	}
+
 	return s.confirmIncomingTransaction(ctx, tx, receipt)
 }
 
+// updateConfirmations stores confirmations progress so it can be shown on the payment page.
+func (s *Service) updateConfirmations(ctx context.Context, tx *transaction.Transaction, confirmations, required int64) error {
+	current, currentRequired := tx.Confirmations()
+	if current == confirmations && currentRequired == required {
+		return nil
+	}
+
+	if err := s.transactions.UpdateConfirmations(ctx, tx, confirmations, required); err != nil {
+		return errors.Wrap(err, "unable to update transaction confirmations")
+	}
+
+	return nil
+}
+
 func (s *Service) confirmIncomingTransaction(
 	ctx context.Context,
 	tx *transaction.Transaction,
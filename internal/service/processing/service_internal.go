@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/oxygenpay/oxygen/internal/money"
 	"github.com/oxygenpay/oxygen/internal/service/blockchain"
@@ -111,6 +112,7 @@ func (s *Service) BatchCreateInternalTransfers(
 			}
 
 			params := internalTransferInput{
+				Type:            transaction.TypeInternal,
 				SenderWallet:    senderWallet,
 				SenderBalance:   b,
 				RecipientWallet: recipientWallet,
@@ -208,10 +210,23 @@ func (s *Service) BatchCheckInternalTransfers(ctx context.Context, transactionID
 }
 
 type internalTransferInput struct {
+	// Type either TypeInternal, TypeGasFunding or TypeColdSweep
+	Type            transaction.Type
 	SenderWallet    *wallet.Wallet
 	SenderBalance   *wallet.Balance
 	RecipientWallet *wallet.Wallet
 	Amount          money.Money
+
+	// RecipientAddress is used when recipient is not managed by KMS (e.g. cold wallet)
+	RecipientAddress string
+}
+
+func (in internalTransferInput) recipientAddress() string {
+	if in.RecipientWallet != nil {
+		return in.RecipientWallet.Address
+	}
+
+	return in.RecipientAddress
 }
 
 type internalTransferOutput struct {
@@ -250,10 +265,10 @@ func (s *Service) createInternalTransfer(
 	}
 
 	// 1. Create signed transaction via KMS
-	txRaw, err := s.wallets.CreateSignedTransaction(
+	txRaw, nonce, err := s.wallets.CreateSignedTransaction(
 		ctx,
 		sender,
-		params.RecipientWallet.Address,
+		params.recipientAddress(),
 		currency,
 		params.Amount,
 		txNetworkFee,
@@ -273,13 +288,15 @@ func (s *Service) createInternalTransfer(
 
 	// 3. Create transaction in the DB
 	tx, err := s.transactions.Create(ctx, 0, transaction.CreateTransaction{
-		Type:            transaction.TypeInternal,
-		RecipientWallet: params.RecipientWallet,
-		SenderWallet:    params.SenderWallet,
-		Currency:        currency,
-		Amount:          params.Amount,
-		USDAmount:       conv.To,
-		IsTest:          isTest,
+		Type:             params.Type,
+		RecipientWallet:  params.RecipientWallet,
+		RecipientAddress: params.RecipientAddress,
+		SenderWallet:     params.SenderWallet,
+		Currency:         currency,
+		Amount:           params.Amount,
+		USDAmount:        conv.To,
+		USDRateID:        conv.RateID,
+		IsTest:           isTest,
 	})
 	if err != nil {
 		return out, errors.Wrap(err, "unable to create database transaction")
@@ -288,15 +305,20 @@ func (s *Service) createInternalTransfer(
 	out.Transaction = tx
 
 	// 4. Decrement balance sender's balance
+	meta := wallet.MetaData{
+		wallet.MetaTransactionID:  strconv.Itoa(int(tx.ID)),
+		wallet.MetaSenderWalletID: strconv.Itoa(int(params.SenderWallet.ID)),
+	}
+
+	if params.RecipientWallet != nil {
+		meta[wallet.MetaRecipientWalletID] = strconv.Itoa(int(params.RecipientWallet.ID))
+	}
+
 	_, err = s.wallets.UpdateBalanceByID(ctx, params.SenderBalance.ID, wallet.UpdateBalanceByIDQuery{
 		Operation: wallet.OperationDecrement,
 		Amount:    params.Amount,
-		Comment:   "locking balance for internal transaction",
-		MetaData: wallet.MetaData{
-			wallet.MetaTransactionID:     strconv.Itoa(int(tx.ID)),
-			wallet.MetaSenderWalletID:    strconv.Itoa(int(params.SenderWallet.ID)),
-			wallet.MetaRecipientWalletID: strconv.Itoa(int(params.RecipientWallet.ID)),
-		},
+		Comment:   fmt.Sprintf("locking balance for %s transaction", params.Type),
+		MetaData:  meta,
 	})
 
 	if err != nil {
@@ -322,6 +344,14 @@ func (s *Service) createInternalTransfer(
 			Msg("unable to update database tx hash id")
 	}
 
+	// nonce & fee are required for replacing the transaction if it gets stuck
+	broadcast := transaction.MetaData{}.WithBroadcast(nonce, txNetworkFee, time.Now())
+	if err := s.transactions.UpdateMetaData(ctx, tx, broadcast); err != nil {
+		s.logger.Error().Err(err).
+			Int64("transaction_id", tx.ID).Str("transaction_hash_id", transactionHashID).
+			Msg("unable to update database tx broadcast metadata")
+	}
+
 	// 5. if currency is TOKEN, then "steal" COIN balance and decrement it.
 	// UPD: we can do it when receiving confirmation webhook "transaction processed"
 	// because otherwise it's impossible to determine exact tx fees.
@@ -381,17 +411,17 @@ func (s *Service) checkInternalTransaction(ctx context.Context, txID int64) erro
 	}
 
 	switch {
-	case tx.Type != transaction.TypeInternal:
+	case tx.Type != transaction.TypeInternal && tx.Type != transaction.TypeGasFunding && tx.Type != transaction.TypeColdSweep:
 		return errors.New("invalid transaction type")
 	case tx.HashID == nil:
 		return errors.New("empty transaction hash")
 	case tx.SenderWalletID == nil:
 		return errors.New("empty sender wallet id")
-	case tx.RecipientWalletID == nil:
+	case tx.RecipientWalletID == nil && tx.Type != transaction.TypeColdSweep:
 		return errors.New("empty recipient wallet id")
 	}
 
-	receipt, err := s.blockchain.GetTransactionReceipt(ctx, tx.Currency.Blockchain, *tx.HashID, tx.IsTest)
+	receipt, err := s.getOutboundReceipt(ctx, tx)
 	if err != nil {
 		return errors.Wrap(err, "unable to get transaction receipt")
 	}
@@ -428,9 +458,8 @@ func (s *Service) confirmInternalTransfer(
 	s.logger.Info().Int64("transaction_id", tx.ID).Msg("confirming internal transfer")
 
 	var (
-		senderWalletID    = *tx.SenderWalletID
-		recipientWalletID = *tx.RecipientWalletID
-		txHashID          = *tx.HashID
+		senderWalletID = *tx.SenderWalletID
+		txHashID       = *tx.HashID
 	)
 
 	senderWallet, err := s.wallets.GetByID(ctx, senderWalletID)
@@ -464,7 +493,7 @@ func (s *Service) confirmInternalTransfer(
 	s.logger.Info().
 		Int64("transaction_id", tx.ID).
 		Int64("sender_waller_id", senderWalletID).
-		Int64("recipient_waller_id", recipientWalletID).
+		Str("recipient_address", tx.RecipientAddress).
 		Msg("processed internal transaction")
 
 	return nil
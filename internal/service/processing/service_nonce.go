@@ -0,0 +1,309 @@
+package processing
+
+import (
+	"context"
+	"sort"
+
+	kmswallet "github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/transaction"
+	"github.com/pkg/errors"
+)
+
+// NonceState compares wallet's nonce counters with the blockchain.
+type NonceState struct {
+	WalletID   int64
+	Address    string
+	Blockchain money.Blockchain
+	IsTest     bool
+
+	// Confirmed & Pending wallet's transaction counters. Their sum is the nonce of the next transaction.
+	Confirmed int64
+	Pending   int64
+
+	// ChainLatest & ChainPending results of eth_getTransactionCount for "latest" & "pending" blocks.
+	ChainLatest  int64
+	ChainPending int64
+
+	// InFlight nonces of outbound transactions that are not finalized yet.
+	InFlight []int64
+
+	// UnknownInFlight amount of outbound transactions that are not finalized and have no recorded nonce.
+	UnknownInFlight int64
+
+	// Gaps nonces that were allocated by the wallet but are neither on-chain nor used by in-flight transactions.
+	// Transactions with higher nonces are stuck until gaps are filled.
+	Gaps []int64
+}
+
+// Next returns nonce that would be used by the next transaction.
+func (s *NonceState) Next() int64 {
+	return s.Confirmed + s.Pending
+}
+
+// InSync checks whether wallet's nonce matches the blockchain.
+func (s *NonceState) InSync() bool {
+	return s.Next() == s.ChainPending
+}
+
+// NonceReconciliation result of nonce reconciliation.
+type NonceReconciliation struct {
+	Action NonceAction
+	Before *NonceState
+	After  *NonceState
+
+	// FilledGaps maps gap nonce to the hash of zero-value self-transfer.
+	FilledGaps map[int64]string
+}
+
+type NonceAction string
+
+const (
+	NonceActionNone           NonceAction = "none"
+	NonceActionCountersSynced NonceAction = "counters_synced"
+	NonceActionGapsFilled     NonceAction = "gaps_filled"
+	NonceActionGapsDetected   NonceAction = "gaps_detected"
+	NonceActionUnknownNonces  NonceAction = "unknown_nonces"
+)
+
+var ErrNonceUnsupported = errors.New("nonce reconciliation is supported only for EVM blockchains")
+
+// nonceFilterLimit max amount of in-flight transactions of a single wallet.
+const nonceFilterLimit = 1000
+
+// GetNonceState returns wallet's nonce counters along with on-chain nonces, in-flight transactions & gaps.
+func (s *Service) GetNonceState(ctx context.Context, walletID int64, isTest bool) (*NonceState, error) {
+	w, err := s.wallets.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get wallet")
+	}
+
+	if w.Blockchain != kmswallet.ETH && w.Blockchain != kmswallet.MATIC && w.Blockchain != kmswallet.BSC {
+		return nil, ErrNonceUnsupported
+	}
+
+	chain := w.Blockchain.ToMoneyBlockchain()
+
+	coin, err := s.blockchain.GetNativeCoin(chain)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get native coin")
+	}
+
+	chainNonce, err := s.blockchain.GetNonce(ctx, chain, w.Address, isTest)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get on-chain nonce")
+	}
+
+	txs, err := s.transactions.ListByFilter(ctx, transaction.Filter{
+		SenderWalletID: w.ID,
+		NetworkID:      coin.ChooseNetwork(isTest),
+		Types:          outboundTypes,
+		Statuses:       []transaction.Status{transaction.StatusPending, transaction.StatusInProgress},
+	}, nonceFilterLimit)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list in-flight transactions")
+	}
+
+	confirmed, pending := w.TransactionCounters(isTest)
+
+	state := &NonceState{
+		WalletID:     w.ID,
+		Address:      w.Address,
+		Blockchain:   chain,
+		IsTest:       isTest,
+		Confirmed:    confirmed,
+		Pending:      pending,
+		ChainLatest:  int64(chainNonce.Latest),
+		ChainPending: int64(chainNonce.Pending),
+	}
+
+	inFlight := make(map[int64]struct{}, len(txs))
+	for _, tx := range txs {
+		nonce, ok := tx.Nonce()
+		if !ok {
+			state.UnknownInFlight++
+			continue
+		}
+
+		inFlight[nonce] = struct{}{}
+		state.InFlight = append(state.InFlight, nonce)
+	}
+
+	sort.Slice(state.InFlight, func(i, j int) bool { return state.InFlight[i] < state.InFlight[j] })
+
+	for nonce := state.ChainPending; nonce < state.Next(); nonce++ {
+		if _, ok := inFlight[nonce]; !ok {
+			state.Gaps = append(state.Gaps, nonce)
+		}
+	}
+
+	return state, nil
+}
+
+// ReconcileNonce aligns wallet's nonce with the blockchain:
+//   - if blockchain is ahead (e.g. transaction was sent manually), counters are moved forward;
+//   - if wallet is ahead and none of in-flight transactions are waiting on-chain, counters are moved back;
+//   - if wallet is ahead and some in-flight transactions are stuck behind gaps, gaps are filled with
+//     zero-value self-transfers when fillGaps is true.
+//
+// Counters are not changed if some in-flight transactions have unknown nonce. Moving counters back is not
+// serialized with outbound transactions, so it's meant to be triggered by the operator only.
+func (s *Service) ReconcileNonce(ctx context.Context, walletID int64, isTest, fillGaps bool) (*NonceReconciliation, error) {
+	return s.reconcileNonce(ctx, walletID, isTest, fillGaps, true)
+}
+
+// ReconcileNonceForward moves wallet's counters forward if blockchain is ahead and reports gaps otherwise.
+// Counters are never moved back: a nonce that was just allocated for outbound transaction which
+// is not recorded yet looks like a gap, so rolling it back would lead to nonce reuse.
+func (s *Service) ReconcileNonceForward(ctx context.Context, walletID int64, isTest bool) (*NonceReconciliation, error) {
+	return s.reconcileNonce(ctx, walletID, isTest, false, false)
+}
+
+func (s *Service) reconcileNonce(
+	ctx context.Context,
+	walletID int64,
+	isTest, fillGaps, rollback bool,
+) (*NonceReconciliation, error) {
+	before, err := s.GetNonceState(ctx, walletID, isTest)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NonceReconciliation{Action: NonceActionNone, Before: before, After: before}
+
+	// in-flight transactions that are not in the mempool yet (waiting for gaps to be filled)
+	var waiting int
+	for _, nonce := range before.InFlight {
+		if nonce >= before.ChainPending {
+			waiting++
+		}
+	}
+
+	switch {
+	case before.InSync():
+		return result, nil
+	case before.UnknownInFlight > 0:
+		result.Action = NonceActionUnknownNonces
+		return result, nil
+	case before.Next() < before.ChainPending, rollback && len(before.Gaps) > 0 && waiting == 0:
+		if err := s.syncNonceCounters(ctx, before); err != nil {
+			return nil, err
+		}
+
+		result.Action = NonceActionCountersSynced
+	case len(before.Gaps) == 0:
+		// all nonces above chain's pending one belong to in-flight transactions
+		// that would be re-broadcasted as stuck ones.
+		return result, nil
+	case !fillGaps:
+		result.Action = NonceActionGapsDetected
+		return result, nil
+	default:
+		filled, err := s.fillNonceGaps(ctx, before)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Action = NonceActionGapsFilled
+		result.FilledGaps = filled
+	}
+
+	after, err := s.GetNonceState(ctx, walletID, isTest)
+	if err != nil {
+		return nil, err
+	}
+
+	result.After = after
+
+	s.logger.Info().
+		Int64("wallet_id", walletID).
+		Bool("is_test", isTest).
+		Str("action", string(result.Action)).
+		Int64("nonce_before", before.Next()).
+		Int64("nonce_after", after.Next()).
+		Int64("chain_pending_nonce", before.ChainPending).
+		Ints64("gaps", before.Gaps).
+		Msg("reconciled wallet nonce")
+
+	return result, nil
+}
+
+// syncNonceCounters sets wallet's next nonce to chain's pending one. In-flight transactions
+// remain pending, so their confirmation is still tracked.
+func (s *Service) syncNonceCounters(ctx context.Context, state *NonceState) error {
+	pending := int64(len(state.InFlight))
+	confirmed := state.ChainPending - pending
+
+	if confirmed < 0 {
+		confirmed, pending = 0, state.ChainPending
+	}
+
+	err := s.wallets.SetTransactionCounters(ctx, state.WalletID, state.IsTest, state.Next(), confirmed, pending)
+	if err != nil {
+		return errors.Wrap(err, "unable to set wallet transaction counters")
+	}
+
+	return nil
+}
+
+// fillNonceGaps broadcasts zero-value self-transfers for each gap. As those transactions are not tracked,
+// the corresponding amount is moved from pending to confirmed counter.
+func (s *Service) fillNonceGaps(ctx context.Context, state *NonceState) (map[int64]string, error) {
+	w, err := s.wallets.GetByID(ctx, state.WalletID)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get wallet")
+	}
+
+	coin, err := s.blockchain.GetNativeCoin(state.Blockchain)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get native coin")
+	}
+
+	zero, err := coin.MakeAmount("0")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to make zero amount")
+	}
+
+	fee, err := s.blockchain.CalculateFee(ctx, coin, coin, state.IsTest)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to calculate fee")
+	}
+
+	filled := make(map[int64]string, len(state.Gaps))
+
+	var errFill error
+	for _, nonce := range state.Gaps {
+		raw, err := s.wallets.CreateReplacementTransaction(ctx, w, w.Address, coin, zero, fee, nonce, state.IsTest)
+		if err != nil {
+			errFill = errors.Wrapf(err, "unable to create self-transfer for nonce %d", nonce)
+			break
+		}
+
+		txHash, err := s.blockchain.BroadcastTransaction(ctx, state.Blockchain, raw, state.IsTest)
+		if err != nil {
+			errFill = errors.Wrapf(err, "unable to broadcast self-transfer for nonce %d", nonce)
+			break
+		}
+
+		filled[nonce] = txHash
+	}
+
+	if len(filled) > 0 {
+		n := int64(len(filled))
+		pending := state.Pending - n
+		if pending < 0 {
+			pending = 0
+		}
+
+		err := s.wallets.SetTransactionCounters(ctx, w.ID, state.IsTest, state.Next(), state.Next()-pending, pending)
+		if err != nil {
+			return filled, errors.Wrap(err, "unable to set wallet transaction counters")
+		}
+	}
+
+	if errFill != nil {
+		return filled, errFill
+	}
+
+	return filled, nil
+}
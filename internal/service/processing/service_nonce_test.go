@@ -0,0 +1,232 @@
+package processing_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/processing"
+	"github.com/oxygenpay/oxygen/internal/service/transaction"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/oxygenpay/oxygen/internal/test"
+	kmswallet "github.com/oxygenpay/oxygen/pkg/api-kms/v1/client/wallet"
+	kmsmodel "github.com/oxygenpay/oxygen/pkg/api-kms/v1/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:funlen
+func TestService_ReconcileNonce(t *testing.T) {
+	tc := test.NewIntegrationTest(t)
+	ctx := tc.Context
+
+	eth := tc.Must.GetCurrency(t, "ETH")
+	tron := tc.Must.GetCurrency(t, "TRON")
+
+	tc.Fakes.SetupAllFees(t, tc.Services.Blockchain)
+
+	// Given shortcut for creating outbound wallet with specified counters
+	createWallet := func(t *testing.T, confirmed, pending int64) *wallet.Wallet {
+		wt, _ := tc.Must.CreateWalletWithBalance(t, eth.Ticker, wallet.TypeOutbound, test.WithBalanceFromCurrency(eth, "0", false))
+		require.NoError(t, tc.Services.Wallet.SetTransactionCounters(ctx, wt.ID, false, 0, confirmed, pending))
+
+		return wt
+	}
+
+	// Given shortcut for creating in-flight withdrawal with specified nonce
+	createInFlight := func(t *testing.T, wt *wallet.Wallet, nonce int64) *transaction.Transaction {
+		usdAmount, err := money.FiatFromFloat64(money.USD, 1)
+		require.NoError(t, err)
+
+		tx, err := tc.Services.Transaction.Create(ctx, 0, transaction.CreateTransaction{
+			Type:             transaction.TypeWithdrawal,
+			SenderWallet:     wt,
+			RecipientAddress: "0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5",
+			Currency:         eth,
+			Amount:           money.MustCryptoFromRaw(eth.Ticker, "1000", eth.Decimals),
+			USDAmount:        usdAmount,
+		})
+		require.NoError(t, err)
+
+		txHash := fmt.Sprintf("0x0123-abc-tx-%d", tx.ID)
+		require.NoError(t, tc.Services.Transaction.UpdateTransactionHash(ctx, transaction.SystemMerchantID, tx.ID, txHash))
+
+		fee, err := tc.Services.Blockchain.CalculateFee(ctx, eth, eth, false)
+		require.NoError(t, err)
+
+		meta := transaction.MetaData{}.WithBroadcast(nonce, fee, tx.CreatedAt)
+		require.NoError(t, tc.Services.Transaction.UpdateMetaData(ctx, tx, meta))
+
+		return tx
+	}
+
+	t.Run("Nonce is in sync", func(t *testing.T) {
+		tc.Clear.Wallets(t)
+
+		// ARRANGE
+		wt := createWallet(t, 5, 0)
+		tc.Fakes.SetupGetNonce(eth.Blockchain, wt.Address, false, blockchain.Nonce{Latest: 5, Pending: 5}, nil)
+
+		// ACT
+		result, err := tc.Services.Processing.ReconcileNonce(ctx, wt.ID, false, true)
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Equal(t, processing.NonceActionNone, result.Action)
+		assert.True(t, result.After.InSync())
+		assert.Empty(t, result.Before.Gaps)
+	})
+
+	t.Run("Blockchain is ahead", func(t *testing.T) {
+		tc.Clear.Wallets(t)
+
+		// ARRANGE
+		// Given wallet that was used outside the app
+		wt := createWallet(t, 2, 0)
+		tc.Fakes.SetupGetNonce(eth.Blockchain, wt.Address, false, blockchain.Nonce{Latest: 7, Pending: 7}, nil)
+
+		// ACT
+		result, err := tc.Services.Processing.ReconcileNonce(ctx, wt.ID, false, false)
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Equal(t, processing.NonceActionCountersSynced, result.Action)
+		assert.Equal(t, int64(7), result.After.Next())
+
+		fresh, err := tc.Services.Wallet.GetByID(ctx, wt.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), fresh.ConfirmedMainnetTransactions)
+		assert.Equal(t, int64(0), fresh.PendingMainnetTransactions)
+	})
+
+	t.Run("Wallet is ahead without in-flight transactions", func(t *testing.T) {
+		tc.Clear.Wallets(t)
+
+		// ARRANGE
+		// Given wallet with pending counter left after failed broadcast
+		wt := createWallet(t, 3, 2)
+		tc.Fakes.SetupGetNonce(eth.Blockchain, wt.Address, false, blockchain.Nonce{Latest: 3, Pending: 3}, nil)
+
+		// ACT
+		result, err := tc.Services.Processing.ReconcileNonce(ctx, wt.ID, false, false)
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Equal(t, processing.NonceActionCountersSynced, result.Action)
+		assert.Equal(t, []int64{3, 4}, result.Before.Gaps)
+		assert.True(t, result.After.InSync())
+
+		fresh, err := tc.Services.Wallet.GetByID(ctx, wt.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), fresh.ConfirmedMainnetTransactions)
+		assert.Equal(t, int64(0), fresh.PendingMainnetTransactions)
+	})
+
+	t.Run("Scheduled reconciliation doesn't move counters back", func(t *testing.T) {
+		tc.Clear.Wallets(t)
+
+		// ARRANGE
+		// Given wallet with nonces that are allocated for transactions that are not recorded yet
+		wt := createWallet(t, 3, 2)
+		tc.Fakes.SetupGetNonce(eth.Blockchain, wt.Address, false, blockchain.Nonce{Latest: 3, Pending: 3}, nil)
+
+		// ACT
+		result, err := tc.Services.Processing.ReconcileNonceForward(ctx, wt.ID, false)
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Equal(t, processing.NonceActionGapsDetected, result.Action)
+		assert.Equal(t, []int64{3, 4}, result.Before.Gaps)
+
+		fresh, err := tc.Services.Wallet.GetByID(ctx, wt.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), fresh.ConfirmedMainnetTransactions)
+		assert.Equal(t, int64(2), fresh.PendingMainnetTransactions)
+	})
+
+	t.Run("Fills gaps that block in-flight transaction", func(t *testing.T) {
+		tc.Clear.Wallets(t)
+
+		// ARRANGE
+		// Given wallet with in-flight transaction #5 while nonces #3 and #4 were never broadcasted
+		wt := createWallet(t, 3, 3)
+		createInFlight(t, wt, 5)
+
+		tc.Fakes.SetupGetNonce(eth.Blockchain, wt.Address, false, blockchain.Nonce{Latest: 3, Pending: 3}, nil)
+
+		// ACT 1
+		// Reconcile without gaps filling
+		result, err := tc.Services.Processing.ReconcileNonce(ctx, wt.ID, false, false)
+
+		// ASSERT 1
+		require.NoError(t, err)
+		assert.Equal(t, processing.NonceActionGapsDetected, result.Action)
+		assert.Equal(t, []int64{5}, result.Before.InFlight)
+		assert.Equal(t, []int64{3, 4}, result.Before.Gaps)
+
+		// ARRANGE 2
+		// Given mocked zero-value self-transfers
+		for _, nonce := range []int64{3, 4} {
+			nonce := nonce
+			raw := fmt.Sprintf("0xself-%d", nonce)
+
+			tc.Providers.KMS.
+				On("CreateEthereumTransaction", mock.MatchedBy(func(p *kmswallet.CreateEthereumTransactionParams) bool {
+					return p.WalletID == wt.UUID.String() &&
+						*p.Data.Nonce == nonce &&
+						p.Data.Recipient == wt.Address &&
+						p.Data.Amount == "0"
+				})).
+				Return(&kmswallet.CreateEthereumTransactionCreated{
+					Payload: &kmsmodel.EthereumTransaction{RawTransaction: raw},
+				}, nil).
+				Once()
+
+			tc.Fakes.SetupBroadcastTransaction(eth.Blockchain, raw, false, fmt.Sprintf("0xhash-%d", nonce), nil)
+		}
+
+		// ACT 2
+		result, err = tc.Services.Processing.ReconcileNonce(ctx, wt.ID, false, true)
+
+		// ASSERT 2
+		require.NoError(t, err)
+		assert.Equal(t, processing.NonceActionGapsFilled, result.Action)
+		assert.Equal(t, map[int64]string{3: "0xhash-3", 4: "0xhash-4"}, result.FilledGaps)
+
+		// only in-flight transaction remains pending
+		fresh, err := tc.Services.Wallet.GetByID(ctx, wt.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), fresh.ConfirmedMainnetTransactions)
+		assert.Equal(t, int64(1), fresh.PendingMainnetTransactions)
+	})
+
+	t.Run("Skips wallet with in-flight transactions without nonce", func(t *testing.T) {
+		tc.Clear.Wallets(t)
+
+		// ARRANGE
+		wt := createWallet(t, 0, 2)
+		tx := createInFlight(t, wt, 1)
+		require.NoError(t, tc.Services.Transaction.UpdateMetaData(ctx, tx, transaction.MetaData{transaction.MetaNonce: ""}))
+
+		tc.Fakes.SetupGetNonce(eth.Blockchain, wt.Address, false, blockchain.Nonce{Latest: 0, Pending: 0}, nil)
+
+		// ACT
+		result, err := tc.Services.Processing.ReconcileNonce(ctx, wt.ID, false, true)
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Equal(t, processing.NonceActionUnknownNonces, result.Action)
+		assert.Equal(t, int64(1), result.Before.UnknownInFlight)
+	})
+
+	t.Run("TRON is not supported", func(t *testing.T) {
+		tc.Clear.Wallets(t)
+
+		wt, _ := tc.Must.CreateWalletWithBalance(t, tron.Ticker, wallet.TypeOutbound, test.WithBalanceFromCurrency(tron, "0", false))
+
+		_, err := tc.Services.Processing.ReconcileNonce(ctx, wt.ID, false, true)
+		assert.ErrorIs(t, err, processing.ErrNonceUnsupported)
+	})
+}
@@ -0,0 +1,160 @@
+package processing
+
+import (
+	"context"
+	"fmt"
+
+	kms "github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/provider/notify"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/pkg/errors"
+)
+
+var ErrUnexpectedProvider = errors.New("blockchain is not tracked by this notifications provider")
+
+// NotificationProvider returns notifications provider by its name.
+// Returns ErrUnexpectedProvider if blockchain is configured to use another source of incoming transactions.
+func (s *Service) NotificationProvider(name string, chain money.Blockchain) (notify.Provider, error) {
+	provider, err := s.notifiers.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.config.IncomingProvider(chain) != provider.Name() {
+		return nil, errors.Wrapf(ErrUnexpectedProvider, "%s is not configured for %s", name, chain)
+	}
+
+	return provider, nil
+}
+
+// ProcessNotifications ingests transfers received from notifications provider (e.g. Alchemy, Moralis).
+// Transfers to unknown addresses (e.g. outgoing transactions from our wallets), to non-inbound wallets
+// (change, gas top-ups) and pending transfers are skipped.
+func (s *Service) ProcessNotifications(
+	ctx context.Context,
+	chain money.Blockchain,
+	networkID string,
+	notifications []notify.Notification,
+) error {
+	for _, n := range notifications {
+		if n.Pending {
+			continue
+		}
+
+		if err := s.processNotification(ctx, chain, networkID, n); err != nil {
+			return errors.Wrapf(err, "unable to process notification for %s", n.TransactionID)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) processNotification(ctx context.Context, chain money.Blockchain, networkID string, n notify.Notification) error {
+	wt, err := s.wallets.GetByAddress(ctx, kms.Blockchain(chain), n.Recipient)
+
+	switch {
+	case errors.Is(err, wallet.ErrNotFound):
+		s.logger.Info().
+			Str("blockchain_tx_hash_id", n.TransactionID).
+			Str("recipient", n.Recipient).
+			Msg("skipping notification: recipient is not our wallet")
+
+		return nil
+	case err != nil:
+		return errors.Wrap(err, "unable to get wallet by address")
+	case wt.Type != wallet.TypeInbound:
+		// change, gas top-ups & cold wallet refills are not customers' payments
+		s.logger.Info().
+			Str("blockchain_tx_hash_id", n.TransactionID).
+			Str("recipient", n.Recipient).
+			Str("wallet_type", string(wt.Type)).
+			Msg("skipping notification: recipient is not inbound wallet")
+
+		return nil
+	}
+
+	var currency money.CryptoCurrency
+	if n.ContractAddress == "" {
+		currency, err = s.blockchain.GetNativeCoin(chain)
+	} else {
+		currency, err = s.blockchain.GetCurrencyByBlockchainAndContract(chain, networkID, n.ContractAddress)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "unable to resolve currency")
+	}
+
+	if n.Amount == nil || n.Amount.Sign() <= 0 {
+		return nil
+	}
+
+	amount, err := currency.MakeAmountFromBigInt(n.Amount)
+	if err != nil {
+		return errors.Wrap(err, "unable to make amount")
+	}
+
+	input := Input{
+		Currency:      currency,
+		Amount:        amount,
+		SenderAddress: n.Sender,
+		TransactionID: n.TransactionID,
+		NetworkID:     networkID,
+	}
+
+	return s.ProcessIncomingTransfer(ctx, wt, input)
+}
+
+// ensureNotifySubscription registers inbound wallet's address in notifications provider (if required by provider)
+// for each network (mainnet, testnet) that is configured in the provider. Registration is idempotent,
+// so it's cached only in memory.
+func (s *Service) ensureNotifySubscription(ctx context.Context, w *wallet.Wallet) error {
+	if w.Type != wallet.TypeInbound {
+		return nil
+	}
+
+	chain := w.Blockchain.ToMoneyBlockchain()
+
+	provider, err := s.notifiers.Get(s.config.IncomingProvider(chain))
+	if err != nil {
+		return err
+	}
+
+	subscriber, ok := provider.(notify.AddressSubscriber)
+	if !ok {
+		return nil
+	}
+
+	var subscribed int
+
+	for _, isTest := range []bool{false, true} {
+		key := fmt.Sprintf("%s/%s/%s/%t", provider.Name(), chain, w.Address, isTest)
+		if _, ok := s.subscriptions.Load(key); ok {
+			subscribed++
+			continue
+		}
+
+		err := subscriber.SubscribeAddress(ctx, chain, w.Address, isTest)
+		switch {
+		case errors.Is(err, notify.ErrNotConfigured):
+			s.logger.Warn().Err(err).
+				Str("provider", provider.Name()).
+				Str("blockchain", chain.String()).
+				Bool("is_test", isTest).
+				Msg("skipping notifications subscription: network is not configured")
+
+			continue
+		case err != nil:
+			return errors.Wrapf(err, "unable to subscribe to %s notifications (test: %t)", provider.Name(), isTest)
+		}
+
+		s.subscriptions.Store(key, struct{}{})
+		subscribed++
+	}
+
+	if subscribed == 0 {
+		return errors.Wrapf(notify.ErrNotConfigured, "%s has no %s networks configured", provider.Name(), chain)
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package processing
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/payment"
+	"github.com/oxygenpay/oxygen/internal/service/transaction"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	reasonTxDisappeared = "transaction disappeared from blockchain after chain reorganization"
+	reasonTxFailed      = "transaction failed after chain reorganization"
+)
+
+// BatchCheckReorganizations re-verifies completed incoming transactions until they become
+// Config.ReorgCheckDepth blocks deep. If transaction disappeared from the blockchain or failed
+// after chain reorganization, balance increments are reverted and payment is marked as failed.
+// Disappeared transaction is orphaned only after Config.ReorgOrphanChecks consecutive misses
+// confirmed by every RPC endpoint, so a single lagging node can't revert a valid payment.
+// Orphaned transactions which payments are still successful are retried to mark the payment as failed.
+func (s *Service) BatchCheckReorganizations(ctx context.Context, transactionIDs []int64) error {
+	var (
+		group     errgroup.Group
+		checked   int64
+		failedTXs []int64
+		mu        sync.Mutex
+	)
+
+	group.SetLimit(8)
+
+	for i := range transactionIDs {
+		txID := transactionIDs[i]
+		group.Go(func() error {
+			if err := s.checkReorganization(ctx, txID); err != nil {
+				mu.Lock()
+				failedTXs = append(failedTXs, txID)
+				mu.Unlock()
+
+				return err
+			}
+
+			atomic.AddInt64(&checked, 1)
+
+			return nil
+		})
+	}
+
+	err := group.Wait()
+
+	evt := s.logger.Info()
+	if err != nil {
+		evt = s.logger.Error().Err(err)
+	}
+
+	evt.Int64("checked_transactions_count", checked).
+		Ints64("transaction_ids", transactionIDs).
+		Ints64("failed_transaction_ids", failedTXs).
+		Msg("Checked completed transactions for chain reorganizations")
+
+	return err
+}
+
+func (s *Service) checkReorganization(ctx context.Context, txID int64) error {
+	tx, err := s.transactions.GetByID(ctx, transaction.MerchantIDWildcard, txID)
+	if err != nil {
+		return errors.Wrap(err, "unable to get transaction")
+	}
+
+	blockNumber, blockHash := tx.Block()
+
+	switch {
+	case tx.Type != transaction.TypeIncoming:
+		return errors.New("invalid transaction type")
+	case tx.HashID == nil:
+		return errors.New("empty transaction hash")
+	case tx.Status == transaction.StatusOrphaned:
+		return s.failOrphanedPayment(ctx, tx)
+	case tx.Status != transaction.StatusCompleted && tx.Status != transaction.StatusCompletedInvalid:
+		return nil
+	case blockNumber == 0:
+		return nil
+	}
+
+	receipt, err := s.blockchain.GetTransactionReceipt(ctx, tx.Currency.Blockchain, *tx.HashID, tx.IsTest)
+
+	switch {
+	case errors.Is(err, blockchain.ErrTxNotFound):
+		return s.handleMissingTransaction(ctx, tx)
+	case err != nil:
+		return errors.Wrap(err, "unable to get transaction receipt")
+	case !receipt.Success:
+		return s.orphanIncomingTransaction(ctx, tx, reasonTxFailed)
+	}
+
+	// transaction was re-included into another block: funds are still here, but new block should be tracked
+	if receipt.BlockNumber != blockNumber || (blockHash != "" && !strings.EqualFold(receipt.BlockHash, blockHash)) {
+		s.logger.Warn().
+			Int64("transaction_id", tx.ID).
+			Str("transaction_hash", *tx.HashID).
+			Int64("block_number", blockNumber).
+			Int64("new_block_number", receipt.BlockNumber).
+			Str("block_hash", blockHash).
+			Str("new_block_hash", receipt.BlockHash).
+			Msg("incoming transaction was moved to another block after chain reorganization")
+
+		return s.transactions.UpdateMetaData(ctx, tx, transaction.MetaData{
+			transaction.MetaBlockNumber: strconv.FormatInt(receipt.BlockNumber, 10),
+			transaction.MetaBlockHash:   receipt.BlockHash,
+			transaction.MetaReorgMisses: "",
+		})
+	}
+
+	if receipt.Confirmations < s.config.ReorgCheckDepth {
+		// transaction was found again: previous misses were caused by lagging node
+		if tx.MetaData[transaction.MetaReorgMisses] != "" {
+			return s.transactions.UpdateMetaData(ctx, tx, transaction.MetaData{transaction.MetaReorgMisses: ""})
+		}
+
+		// check later
+		return nil
+	}
+
+	return s.transactions.UpdateMetaData(ctx, tx, transaction.MetaData{
+		transaction.MetaReorgVerified: "true",
+		transaction.MetaReorgMisses:   "",
+	})
+}
+
+// handleMissingTransaction counts consecutive checks that didn't find the transaction.
+// Transaction is orphaned only after Config.ReorgOrphanChecks misses and only if
+// none of RPC endpoints knows it anymore.
+func (s *Service) handleMissingTransaction(ctx context.Context, tx *transaction.Transaction) error {
+	misses, _ := strconv.ParseInt(tx.MetaData[transaction.MetaReorgMisses], 10, 64)
+	misses++
+
+	logger := s.logger.With().
+		Int64("transaction_id", tx.ID).
+		Str("transaction_hash", *tx.HashID).
+		Int64("misses", misses).
+		Logger()
+
+	if misses < s.config.ReorgOrphanChecks {
+		logger.Warn().Msg("completed incoming transaction was not found, will check again")
+
+		return s.transactions.UpdateMetaData(ctx, tx, transaction.MetaData{
+			transaction.MetaReorgMisses: strconv.FormatInt(misses, 10),
+		})
+	}
+
+	err := s.blockchain.CrossCheckTransactionMissing(ctx, tx.Currency.Blockchain, *tx.HashID, tx.IsTest)
+	switch {
+	case errors.Is(err, blockchain.ErrReceiptMismatch):
+		logger.Warn().Err(err).Msg("completed incoming transaction is still known by another rpc endpoint")
+
+		return s.transactions.UpdateMetaData(ctx, tx, transaction.MetaData{
+			transaction.MetaReorgMisses: strconv.FormatInt(misses, 10),
+		})
+	case err != nil:
+		return errors.Wrap(err, "unable to cross-check missing transaction")
+	}
+
+	return s.orphanIncomingTransaction(ctx, tx, reasonTxDisappeared)
+}
+
+func (s *Service) orphanIncomingTransaction(ctx context.Context, tx *transaction.Transaction, reason string) error {
+	if err := s.transactions.Orphan(ctx, tx, reason); err != nil {
+		return errors.Wrap(err, "unable to orphan transaction")
+	}
+
+	s.logger.Error().
+		Int64("transaction_id", tx.ID).
+		Int64("merchant_id", tx.MerchantID).
+		Str("transaction_hash", *tx.HashID).
+		Str("reason", reason).
+		Str("wallet_shortfall", tx.MetaData[transaction.MetaReorgWalletShortfall]).
+		Str("merchant_shortfall", tx.MetaData[transaction.MetaReorgMerchantShortfall]).
+		Msg("reverted incoming transaction due to chain reorganization")
+
+	return s.failOrphanedPayment(ctx, tx)
+}
+
+// failOrphanedPayment marks payment of orphaned transaction as failed. If it fails, the transaction
+// is selected for reorganization check again until the payment is updated.
+func (s *Service) failOrphanedPayment(ctx context.Context, tx *transaction.Transaction) error {
+	if tx.MerchantID == transaction.SystemMerchantID {
+		return nil
+	}
+
+	// merchant receives webhook on payment status update
+	_, err := s.payments.Update(ctx, tx.MerchantID, tx.EntityID, payment.UpdateProps{Status: payment.StatusFailed})
+	if err != nil {
+		return errors.Wrap(err, "unable to update payment")
+	}
+
+	return nil
+}
@@ -0,0 +1,275 @@
+package processing_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oxygenpay/oxygen/internal/bus"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/payment"
+	"github.com/oxygenpay/oxygen/internal/service/transaction"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/oxygenpay/oxygen/internal/test"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:funlen
+func TestService_BatchCheckReorganizations(t *testing.T) {
+	tc := test.NewIntegrationTest(t)
+
+	// SETUP
+	// Given a merchant
+	mt, _ := tc.Must.CreateMerchant(t, 1)
+
+	// And ETH currency
+	eth := tc.Must.GetCurrency(t, "ETH")
+
+	makeReceipt := func(tx *transaction.Transaction, blockNumber, confirmations int64, blockHash string) *blockchain.TransactionReceipt {
+		return &blockchain.TransactionReceipt{
+			Blockchain:    tx.Currency.Blockchain,
+			IsTest:        tx.IsTest,
+			Sender:        *tx.SenderAddress,
+			Recipient:     tx.RecipientAddress,
+			Hash:          *tx.HashID,
+			NetworkFee:    lo.Must(eth.MakeAmount("1000")),
+			Success:       true,
+			Confirmations: confirmations,
+			IsConfirmed:   true,
+			BlockNumber:   blockNumber,
+			BlockHash:     blockHash,
+		}
+	}
+
+	// Given shortcut for imitating completed incoming tx
+	completedTX := func(t *testing.T, txHash string) *transaction.Transaction {
+		tc.Must.CreateWallet(t, eth.Blockchain.String(), "0x123-inbound", "0x-pub-key", wallet.TypeInbound)
+
+		price, err := money.FiatFromFloat64(money.USD, 100)
+		require.NoError(t, err)
+
+		p, err := tc.Services.Payment.CreatePayment(tc.Context, mt.ID, payment.CreatePaymentProps{
+			MerchantOrderUUID: uuid.New(),
+			Money:             price,
+		})
+		require.NoError(t, err)
+
+		_, err = tc.Services.Payment.AssignCustomerByEmail(tc.Context, p, "user@me.com")
+		require.NoError(t, err)
+
+		tc.Providers.TatumMock.SetupRates(eth.Ticker, money.USD, 1)
+
+		method, err := tc.Services.Processing.SetPaymentMethod(tc.Context, p, eth.Ticker)
+		require.NoError(t, err)
+
+		require.NoError(t, tc.Services.Processing.LockPaymentOptions(tc.Context, mt.ID, p.ID))
+
+		tx, err := tc.Services.Transaction.GetByID(tc.Context, mt.ID, method.TransactionID)
+		require.NoError(t, err)
+
+		tx, err = tc.Services.Transaction.Receive(tc.Context, mt.ID, tx.ID, transaction.ReceiveTransaction{
+			Status:          transaction.StatusInProgress,
+			SenderAddress:   "0x123-sender",
+			TransactionHash: txHash,
+			FactAmount:      tx.Amount,
+			MetaData:        tx.MetaData,
+		})
+		require.NoError(t, err)
+
+		tc.Fakes.SetupGetTransactionReceipt(eth.Blockchain, txHash, tx.IsTest, makeReceipt(tx, 100, 12, "0xblock-a"), nil)
+		require.NoError(t, tc.Services.Processing.BatchCheckIncomingTransactions(tc.Context, []int64{tx.ID}))
+
+		tx, err = tc.Services.Transaction.GetByID(tc.Context, mt.ID, tx.ID)
+		require.NoError(t, err)
+		require.Equal(t, transaction.StatusCompleted, tx.Status)
+
+		blockNumber, blockHash := tx.Block()
+		require.Equal(t, int64(100), blockNumber)
+		require.Equal(t, "0xblock-a", blockHash)
+
+		return tx
+	}
+
+	// Given shortcut for imitating sweep of the inbound wallet
+	sweep := func(t *testing.T, tx *transaction.Transaction) {
+		balance, err := tc.Services.Wallet.GetWalletsBalance(tc.Context, *tx.RecipientWalletID, eth.Ticker, tx.NetworkID())
+		require.NoError(t, err)
+
+		_, err = tc.Services.Wallet.UpdateBalanceByID(tc.Context, balance.ID, wallet.UpdateBalanceByIDQuery{
+			Operation: wallet.OperationDecrement,
+			Amount:    balance.Amount,
+		})
+		require.NoError(t, err)
+	}
+
+	for _, testCase := range []struct {
+		name    string
+		checks  int
+		before  func(t *testing.T, tx *transaction.Transaction)
+		receipt func(tx *transaction.Transaction) (*blockchain.TransactionReceipt, error)
+		assert  func(t *testing.T, tx *transaction.Transaction)
+	}{
+		{
+			name: "transaction is not deep enough",
+			receipt: func(tx *transaction.Transaction) (*blockchain.TransactionReceipt, error) {
+				return makeReceipt(tx, 100, 20, "0xblock-a"), nil
+			},
+			assert: func(t *testing.T, tx *transaction.Transaction) {
+				assert.Equal(t, transaction.StatusCompleted, tx.Status)
+				assert.Empty(t, tx.MetaData[transaction.MetaReorgVerified])
+				assert.Empty(t, tc.Fakes.GetBusCalls())
+			},
+		},
+		{
+			name: "transaction is verified",
+			receipt: func(tx *transaction.Transaction) (*blockchain.TransactionReceipt, error) {
+				return makeReceipt(tx, 100, 50, "0xblock-a"), nil
+			},
+			assert: func(t *testing.T, tx *transaction.Transaction) {
+				assert.Equal(t, transaction.StatusCompleted, tx.Status)
+				assert.Equal(t, "true", tx.MetaData[transaction.MetaReorgVerified])
+			},
+		},
+		{
+			name: "transaction moved to another block",
+			receipt: func(tx *transaction.Transaction) (*blockchain.TransactionReceipt, error) {
+				return makeReceipt(tx, 101, 20, "0xblock-b"), nil
+			},
+			assert: func(t *testing.T, tx *transaction.Transaction) {
+				assert.Equal(t, transaction.StatusCompleted, tx.Status)
+
+				blockNumber, blockHash := tx.Block()
+				assert.Equal(t, int64(101), blockNumber)
+				assert.Equal(t, "0xblock-b", blockHash)
+			},
+		},
+		{
+			name:   "transaction is missing only once",
+			checks: 1,
+			receipt: func(tx *transaction.Transaction) (*blockchain.TransactionReceipt, error) {
+				return nil, blockchain.ErrTxNotFound
+			},
+			assert: func(t *testing.T, tx *transaction.Transaction) {
+				assert.Equal(t, transaction.StatusCompleted, tx.Status)
+				assert.Equal(t, "1", tx.MetaData[transaction.MetaReorgMisses])
+				assert.Empty(t, tc.Fakes.GetBusCalls())
+			},
+		},
+		{
+			name:   "transaction disappeared after inbound wallet was swept",
+			checks: 2,
+			before: sweep,
+			receipt: func(tx *transaction.Transaction) (*blockchain.TransactionReceipt, error) {
+				return nil, blockchain.ErrTxNotFound
+			},
+			assert: func(t *testing.T, tx *transaction.Transaction) {
+				assert.Equal(t, transaction.StatusOrphaned, tx.Status)
+				assert.Equal(t, tx.FactAmount.String(), tx.MetaData[transaction.MetaReorgWalletShortfall])
+				assert.Empty(t, tx.MetaData[transaction.MetaReorgMerchantShortfall])
+
+				// balance doesn't go below zero
+				walletBalance, err := tc.Services.Wallet.GetWalletsBalance(tc.Context, *tx.RecipientWalletID, eth.Ticker, tx.NetworkID())
+				require.NoError(t, err)
+				assert.True(t, walletBalance.Amount.IsZero())
+
+				merchantBalance, err := tc.Services.Wallet.GetMerchantBalance(tc.Context, mt.ID, eth.Ticker, tx.NetworkID())
+				require.NoError(t, err)
+				assert.True(t, merchantBalance.Amount.IsZero())
+			},
+		},
+		{
+			name:   "transaction disappeared",
+			checks: 2,
+			receipt: func(tx *transaction.Transaction) (*blockchain.TransactionReceipt, error) {
+				return nil, blockchain.ErrTxNotFound
+			},
+			assert: func(t *testing.T, tx *transaction.Transaction) {
+				assert.Equal(t, transaction.StatusOrphaned, tx.Status)
+
+				pt, err := tc.Services.Payment.GetByID(tc.Context, mt.ID, tx.EntityID)
+				require.NoError(t, err)
+				assert.Equal(t, payment.StatusFailed, pt.Status)
+
+				// balances are reverted
+				walletBalance, err := tc.Services.Wallet.GetWalletsBalance(tc.Context, *tx.RecipientWalletID, eth.Ticker, tx.NetworkID())
+				require.NoError(t, err)
+				assert.True(t, walletBalance.Amount.IsZero())
+
+				merchantBalance, err := tc.Services.Wallet.GetMerchantBalance(tc.Context, mt.ID, eth.Ticker, tx.NetworkID())
+				require.NoError(t, err)
+				assert.True(t, merchantBalance.Amount.IsZero())
+
+				// merchant is notified
+				calls := tc.Fakes.GetBusCalls()
+				require.Len(t, calls, 1)
+				assert.Equal(t, bus.TopicPaymentStatusUpdate, calls[0].A)
+			},
+		},
+		{
+			name: "payment of orphaned transaction is still successful",
+			before: func(t *testing.T, tx *transaction.Transaction) {
+				// imitate failed payment update right after orphaning
+				require.NoError(t, tc.Services.Transaction.Orphan(tc.Context, tx, "test"))
+
+				orphaned, err := tc.Services.Transaction.ListOrphanedWithPaymentStatus(tc.Context, payment.StatusSuccess.String(), 200)
+				require.NoError(t, err)
+				require.Len(t, orphaned, 1)
+				require.Equal(t, tx.ID, orphaned[0].ID)
+			},
+			receipt: func(tx *transaction.Transaction) (*blockchain.TransactionReceipt, error) {
+				return nil, blockchain.ErrTxNotFound
+			},
+			assert: func(t *testing.T, tx *transaction.Transaction) {
+				assert.Equal(t, transaction.StatusOrphaned, tx.Status)
+
+				pt, err := tc.Services.Payment.GetByID(tc.Context, mt.ID, tx.EntityID)
+				require.NoError(t, err)
+				assert.Equal(t, payment.StatusFailed, pt.Status)
+
+				orphaned, err := tc.Services.Transaction.ListOrphanedWithPaymentStatus(tc.Context, payment.StatusSuccess.String(), 200)
+				require.NoError(t, err)
+				assert.Empty(t, orphaned)
+
+				calls := tc.Fakes.GetBusCalls()
+				require.Len(t, calls, 1)
+				assert.Equal(t, bus.TopicPaymentStatusUpdate, calls[0].A)
+			},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			defer tc.Clear.Wallets(t)
+
+			// ARRANGE
+			// Given a completed transaction
+			tx := completedTX(t, "0x123-hash-"+uuid.NewString())
+
+			// And optional preconditions
+			if testCase.before != nil {
+				testCase.before(t, tx)
+			}
+
+			// And mocked receipt
+			receipt, err := testCase.receipt(tx)
+			tc.Fakes.SetupGetTransactionReceipt(eth.Blockchain, *tx.HashID, tx.IsTest, receipt, err)
+
+			// And cleared bus calls
+			tc.Fakes.Bus.Clear()
+
+			// ACT
+			// Check transaction as many times as scheduler would
+			for i := 0; i < lo.Max([]int{testCase.checks, 1}); i++ {
+				err = tc.Services.Processing.BatchCheckReorganizations(tc.Context, []int64{tx.ID})
+				require.NoError(t, err)
+			}
+
+			// ASSERT
+
+			tx, err = tc.Services.Transaction.GetByID(tc.Context, mt.ID, tx.ID)
+			require.NoError(t, err)
+
+			testCase.assert(t, tx)
+		})
+	}
+}
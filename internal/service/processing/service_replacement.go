@@ -0,0 +1,223 @@
+package processing
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/transaction"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"golang.org/x/sync/errgroup"
+)
+
+// outboundTypes types of transactions that are signed by KMS wallets and consume their nonces.
+var outboundTypes = []transaction.Type{
+	transaction.TypeInternal,
+	transaction.TypeWithdrawal,
+	transaction.TypeGasFunding,
+	transaction.TypeColdSweep,
+}
+
+func isOutboundType(t transaction.Type) bool {
+	return lo.Contains(outboundTypes, t)
+}
+
+// BatchBumpStuckTransactions re-broadcasts outbound (internal, withdrawal, gas funding & cold sweep) transactions that are pending
+// longer than Config.StuckTransactionTimeout. Replacement transaction has the same nonce and a higher fee,
+// so only one of the attempts can be included into the blockchain.
+func (s *Service) BatchBumpStuckTransactions(ctx context.Context, transactionIDs []int64) error {
+	var (
+		group     errgroup.Group
+		bumped    int64
+		failedTXs []int64
+		mu        sync.Mutex
+	)
+
+	group.SetLimit(8)
+
+	for i := range transactionIDs {
+		txID := transactionIDs[i]
+		group.Go(func() error {
+			ok, err := s.bumpStuckTransaction(ctx, txID)
+			if err != nil {
+				mu.Lock()
+				failedTXs = append(failedTXs, txID)
+				mu.Unlock()
+
+				return err
+			}
+
+			if ok {
+				atomic.AddInt64(&bumped, 1)
+			}
+
+			return nil
+		})
+	}
+
+	err := group.Wait()
+
+	evt := s.logger.Info()
+	if err != nil {
+		evt = s.logger.Error().Err(err)
+	}
+
+	evt.Int64("bumped_transactions_count", bumped).
+		Ints64("failed_transaction_ids", failedTXs).
+		Ints64("transaction_ids", transactionIDs).
+		Msg("Checked stuck outbound transactions")
+
+	return err
+}
+
+// bumpStuckTransaction replaces transaction with a higher fee. Returns true if replacement was broadcasted.
+func (s *Service) bumpStuckTransaction(ctx context.Context, txID int64) (bool, error) {
+	tx, err := s.transactions.GetByID(ctx, transaction.MerchantIDWildcard, txID)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to get transaction")
+	}
+
+	switch {
+	case !isOutboundType(tx.Type):
+		return false, errors.New("invalid transaction type")
+	case tx.SenderWalletID == nil:
+		return false, errors.New("empty sender wallet id")
+	case tx.IsFinalized(), tx.HashID == nil:
+		return false, nil
+	}
+
+	// transactions created before nonce tracking can't be replaced
+	nonce, ok := tx.Nonce()
+	if !ok {
+		return false, nil
+	}
+
+	if time.Since(tx.BroadcastedAt()) < s.config.StuckTransactionTimeout {
+		return false, nil
+	}
+
+	if s.config.MaxFeeBumps > 0 && tx.FeeBumps() >= s.config.MaxFeeBumps {
+		s.logger.Warn().
+			Int64("transaction_id", tx.ID).
+			Int64("fee_bumps", tx.FeeBumps()).
+			Str("transaction_hash", *tx.HashID).
+			Msg("stuck transaction reached max fee bumps")
+
+		return false, nil
+	}
+
+	// one of the attempts might be already included into the block, so only confirmations are awaited.
+	_, err = s.getOutboundReceipt(ctx, tx)
+
+	switch {
+	case err == nil:
+		return false, nil
+	case !errors.Is(err, blockchain.ErrTxNotFound):
+		return false, errors.Wrap(err, "unable to get transaction receipt")
+	}
+
+	sender, err := s.wallets.GetByID(ctx, *tx.SenderWalletID)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to get sender wallet")
+	}
+
+	baseCurrency, err := s.blockchain.GetNativeCoin(tx.Currency.Blockchain)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to get base currency")
+	}
+
+	currentFee, err := s.blockchain.CalculateFee(ctx, baseCurrency, tx.Currency, tx.IsTest)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to calculate fee")
+	}
+
+	fee, err := blockchain.ReplacementFee(
+		currentFee,
+		tx.MetaData[transaction.MetaGasPrice],
+		tx.MetaData[transaction.MetaPriorityFee],
+	)
+
+	switch {
+	case errors.Is(err, blockchain.ErrReplacementUnsupported):
+		return false, nil
+	case err != nil:
+		return false, errors.Wrap(err, "unable to calculate replacement fee")
+	}
+
+	txRaw, err := s.wallets.CreateReplacementTransaction(
+		ctx,
+		sender,
+		tx.RecipientAddress,
+		tx.Currency,
+		tx.Amount,
+		fee,
+		nonce,
+		tx.IsTest,
+	)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to create replacement transaction")
+	}
+
+	prevHash := *tx.HashID
+
+	txHash, err := s.blockchain.BroadcastTransaction(ctx, tx.Currency.Blockchain, txRaw, tx.IsTest)
+	if err != nil {
+		return false, errors.Wrapf(err, "unable to broadcast replacement transaction to %s", tx.Currency.Blockchain)
+	}
+
+	if err := s.transactions.ReplaceTransactionHash(ctx, tx, txHash, fee); err != nil {
+		return false, errors.Wrap(err, "unable to update transaction hash")
+	}
+
+	s.logger.Info().
+		Int64("transaction_id", tx.ID).
+		Int64("nonce", nonce).
+		Int64("fee_bumps", tx.FeeBumps()).
+		Str("replaced_transaction_hash", prevHash).
+		Str("transaction_hash", txHash).
+		Msg("replaced stuck transaction with a higher fee")
+
+	return true, nil
+}
+
+// getOutboundReceipt returns receipt of outbound transaction. If transaction was replaced,
+// any of the attempts can be included into the blockchain, so previous hashes are checked as well.
+// When previous attempt is found, transaction hash is updated accordingly.
+func (s *Service) getOutboundReceipt(ctx context.Context, tx *transaction.Transaction) (*blockchain.TransactionReceipt, error) {
+	attempts := tx.HashAttempts()
+	if len(attempts) == 0 {
+		return nil, errors.New("empty transaction hash")
+	}
+
+	receipt, err := s.blockchain.GetTransactionReceipt(ctx, tx.Currency.Blockchain, attempts[0], tx.IsTest)
+	if err == nil || len(attempts) == 1 {
+		return receipt, err
+	}
+
+	for _, hash := range attempts[1:] {
+		prevReceipt, errPrev := s.blockchain.GetTransactionReceipt(ctx, tx.Currency.Blockchain, hash, tx.IsTest)
+		if errPrev != nil {
+			// replaced attempts are usually dropped from the mempool
+			continue
+		}
+
+		if errUpdate := s.transactions.UpdateTransactionHash(ctx, tx.MerchantID, tx.ID, hash); errUpdate != nil {
+			return nil, errors.Wrap(errUpdate, "unable to update transaction hash")
+		}
+
+		s.logger.Info().
+			Int64("transaction_id", tx.ID).
+			Str("transaction_hash", hash).
+			Str("replacement_transaction_hash", attempts[0]).
+			Msg("previous attempt of replaced transaction was included into the blockchain")
+
+		tx.HashID = &hash
+
+		return prevReceipt, nil
+	}
+
+	return nil, err
+}
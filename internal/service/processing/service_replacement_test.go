@@ -0,0 +1,177 @@
+package processing_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/transaction"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/oxygenpay/oxygen/internal/test"
+	kmswallet "github.com/oxygenpay/oxygen/pkg/api-kms/v1/client/wallet"
+	kmsmodel "github.com/oxygenpay/oxygen/pkg/api-kms/v1/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:funlen
+func TestService_BatchBumpStuckTransactions(t *testing.T) {
+	tc := test.NewIntegrationTest(t)
+	ctx := tc.Context
+
+	eth := tc.Must.GetCurrency(t, "ETH")
+
+	// Mock tx fees
+	tc.Fakes.SetupAllFees(t, tc.Services.Blockchain)
+
+	ethNetworkFee := money.MustCryptoFromRaw(eth.Ticker, "1000", eth.Decimals)
+
+	// Given shortcut for creating internal transfer that was broadcasted at specified time
+	createTransfer := func(t *testing.T, broadcastedAt time.Time) (*transaction.Transaction, *wallet.Wallet, *wallet.Wallet) {
+		withEth1 := test.WithBalanceFromCurrency(eth, "500_000_000", false)
+		wtIn, balanceIn := tc.Must.CreateWalletWithBalance(t, eth.Ticker, wallet.TypeInbound, withEth1)
+
+		withEth2 := test.WithBalanceFromCurrency(eth, "0", false)
+		wtOut, _ := tc.Must.CreateWalletWithBalance(t, eth.Ticker, wallet.TypeOutbound, withEth2)
+
+		amount := money.MustCryptoFromRaw(eth.Ticker, "300_000_000", eth.Decimals)
+		usdAmount, err := money.FiatFromFloat64(money.USD, 1)
+		require.NoError(t, err)
+
+		nonce, err := tc.Services.Wallet.IncrementPendingTransaction(ctx, wtIn.ID, false)
+		require.NoError(t, err)
+
+		tx, err := tc.Services.Transaction.Create(ctx, 0, transaction.CreateTransaction{
+			Type:            transaction.TypeInternal,
+			SenderWallet:    wtIn,
+			RecipientWallet: wtOut,
+			Currency:        eth,
+			Amount:          amount,
+			USDAmount:       usdAmount,
+		})
+		require.NoError(t, err)
+
+		_, err = tc.Services.Wallet.UpdateBalanceByID(ctx, balanceIn.ID, wallet.UpdateBalanceByIDQuery{
+			Operation: wallet.OperationDecrement,
+			Amount:    amount,
+		})
+		require.NoError(t, err)
+
+		txHash := fmt.Sprintf("0x0123-abc-tx-%d", tx.ID)
+		require.NoError(t, tc.Services.Transaction.UpdateTransactionHash(ctx, transaction.SystemMerchantID, tx.ID, txHash))
+
+		fee, err := tc.Services.Blockchain.CalculateFee(ctx, eth, eth, false)
+		require.NoError(t, err)
+
+		meta := transaction.MetaData{}.WithBroadcast(int64(nonce), fee, broadcastedAt)
+		require.NoError(t, tc.Services.Transaction.UpdateMetaData(ctx, tx, meta))
+
+		tx, err = tc.Services.Transaction.GetByID(ctx, 0, tx.ID)
+		require.NoError(t, err)
+
+		return tx, wtIn, wtOut
+	}
+
+	t.Run("Skips recently broadcasted transaction", func(t *testing.T) {
+		// ARRANGE
+		tc.Clear.Wallets(t)
+
+		// Given recently broadcasted transaction
+		tx, _, _ := createTransfer(t, time.Now())
+
+		// ACT
+		err := tc.Services.Processing.BatchBumpStuckTransactions(ctx, []int64{tx.ID})
+
+		// ASSERT
+		require.NoError(t, err)
+
+		fresh, err := tc.Services.Transaction.GetByID(ctx, 0, tx.ID)
+		require.NoError(t, err)
+		assert.Equal(t, *tx.HashID, *fresh.HashID)
+		assert.Equal(t, int64(0), fresh.FeeBumps())
+	})
+
+	t.Run("Replaces stuck transaction and confirms previous attempt", func(t *testing.T) {
+		// ARRANGE
+		tc.Clear.Wallets(t)
+
+		// Given transaction that is pending for an hour
+		tx, wtIn, wtOut := createTransfer(t, time.Now().Add(-time.Hour))
+		prevHash := *tx.HashID
+
+		nonce, ok := tx.Nonce()
+		require.True(t, ok)
+
+		// That is not included into the blockchain yet
+		tc.Fakes.SetupGetTransactionReceipt(eth.Blockchain, prevHash, false, nil, blockchain.ErrTxNotFound)
+
+		// And mocked replacement with the same nonce and bumped fee
+		const (
+			replacementRaw  = "0x654321"
+			replacementHash = "0xaaaaaa"
+		)
+
+		tc.Providers.KMS.
+			On("CreateEthereumTransaction", mock.MatchedBy(func(p *kmswallet.CreateEthereumTransactionParams) bool {
+				return p.WalletID == wtIn.UUID.String() &&
+					*p.Data.Nonce == nonce &&
+					p.Data.MaxFeePerGas == "59467746938" &&
+					p.Data.MaxPriorityPerGas == "133647421"
+			})).
+			Return(&kmswallet.CreateEthereumTransactionCreated{
+				Payload: &kmsmodel.EthereumTransaction{RawTransaction: replacementRaw},
+			}, nil)
+
+		tc.Fakes.SetupBroadcastTransaction(eth.Blockchain, replacementRaw, false, replacementHash, nil)
+
+		// ACT
+		err := tc.Services.Processing.BatchBumpStuckTransactions(ctx, []int64{tx.ID})
+
+		// ASSERT
+		require.NoError(t, err)
+
+		tx, err = tc.Services.Transaction.GetByID(ctx, 0, tx.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, replacementHash, *tx.HashID)
+		assert.Equal(t, []string{replacementHash, prevHash}, tx.HashAttempts())
+		assert.Equal(t, int64(1), tx.FeeBumps())
+		assert.Equal(t, "59467746938", tx.MetaData[transaction.MetaGasPrice])
+		assert.Equal(t, "133647421", tx.MetaData[transaction.MetaPriorityFee])
+
+		// pending transactions counter is not changed
+		wt, err := tc.Services.Wallet.GetByID(ctx, wtIn.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), wt.PendingMainnetTransactions)
+
+		// ARRANGE
+		// Given previous attempt that was included into the blockchain after all
+		tc.Fakes.SetupGetTransactionReceipt(eth.Blockchain, replacementHash, false, nil, blockchain.ErrTxNotFound)
+		tc.Fakes.SetupGetTransactionReceipt(eth.Blockchain, prevHash, false, &blockchain.TransactionReceipt{
+			Blockchain:    eth.Blockchain,
+			Sender:        wtIn.Address,
+			Recipient:     wtOut.Address,
+			Hash:          prevHash,
+			NetworkFee:    ethNetworkFee,
+			Success:       true,
+			Confirmations: 5,
+			IsConfirmed:   true,
+		}, nil)
+
+		// ACT
+		err = tc.Services.Processing.BatchCheckInternalTransfers(ctx, []int64{tx.ID})
+
+		// ASSERT
+		require.NoError(t, err)
+
+		tx, err = tc.Services.Transaction.GetByID(ctx, 0, tx.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, transaction.StatusCompleted, tx.Status)
+		assert.Equal(t, prevHash, *tx.HashID)
+		assert.Equal(t, ethNetworkFee, *tx.NetworkFee)
+	})
+}
@@ -0,0 +1,223 @@
+package processing
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/transaction"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/pkg/errors"
+)
+
+// SweepPolicy restricts internal transfers from inbound wallets to favorable network conditions.
+// Zero values disable corresponding restriction.
+type SweepPolicy struct {
+	// MaxGasPriceGwei max acceptable effective gas price (base fee + priority fee). Applies only to EVM blockchains.
+	MaxGasPriceGwei float64 `yaml:"max_gas_price_gwei"`
+
+	// MaxFeeRatio max acceptable ratio of network fee to swept amount. 0.05 is 5%.
+	MaxFeeRatio float64 `yaml:"max_fee_ratio"`
+
+	// Deadline max duration the oldest unswept deposit can stay on the balance.
+	// After that balance is swept regardless of network conditions.
+	Deadline time.Duration `yaml:"deadline"`
+}
+
+func (p SweepPolicy) isEmpty() bool {
+	return p.MaxGasPriceGwei <= 0 && p.MaxFeeRatio <= 0
+}
+
+// SweepPolicyResult represents result of applying sweep policies to inbound balances.
+type SweepPolicyResult struct {
+	// ReadyBalances balances that should be swept during current run.
+	ReadyBalances []*wallet.Balance
+
+	// ForcedBalanceIDs ready balances which policy deadline is reached, so network conditions were not checked.
+	ForcedBalanceIDs []int64
+
+	// SkippedBalances balances which sweeping is postponed.
+	SkippedBalances []SkippedSweep
+
+	UnhandledErrors []error
+}
+
+type SkippedSweep struct {
+	Balance *wallet.Balance
+	Reason  string
+}
+
+const weiInGwei = 1_000_000_000
+
+// ApplySweepPolicies filters inbound balances according to blockchain's SweepPolicy.
+// Balances that fail to be evaluated are skipped and reported as unhandled errors.
+func (s *Service) ApplySweepPolicies(ctx context.Context, inboundBalances []*wallet.Balance) (*SweepPolicyResult, error) {
+	result := &SweepPolicyResult{}
+
+	for _, b := range inboundBalances {
+		currency, err := s.blockchain.GetCurrencyByTicker(b.Currency)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to get currency %q", b.Currency)
+		}
+
+		policy := s.config.SweepPolicy(currency.Blockchain)
+		if policy.isEmpty() {
+			result.ReadyBalances = append(result.ReadyBalances, b)
+			continue
+		}
+
+		reason, forced, err := s.evaluateSweepPolicy(ctx, policy, currency, b)
+		if err != nil {
+			s.logger.Error().Err(err).
+				Int64("balance_id", b.ID).
+				Str("currency", b.Currency).
+				Msg("unable to apply sweep policy")
+
+			result.UnhandledErrors = append(result.UnhandledErrors, errors.Wrapf(err, "balance #%d", b.ID))
+			continue
+		}
+
+		switch {
+		case reason != "":
+			result.SkippedBalances = append(result.SkippedBalances, SkippedSweep{Balance: b, Reason: reason})
+		case forced:
+			result.ReadyBalances = append(result.ReadyBalances, b)
+			result.ForcedBalanceIDs = append(result.ForcedBalanceIDs, b.ID)
+		default:
+			result.ReadyBalances = append(result.ReadyBalances, b)
+		}
+	}
+
+	return result, nil
+}
+
+// evaluateSweepPolicy returns the reason of postponing the sweep or an empty string if balance should be swept.
+// forced is true when balance is swept only because policy deadline is reached.
+func (s *Service) evaluateSweepPolicy(
+	ctx context.Context,
+	policy SweepPolicy,
+	currency money.CryptoCurrency,
+	b *wallet.Balance,
+) (string, bool, error) {
+	w, err := s.wallets.GetByID(ctx, b.EntityID)
+	if err != nil {
+		return "", false, errors.Wrap(err, "unable to get wallet")
+	}
+
+	if policy.Deadline > 0 {
+		since, err := s.oldestUnsweptDepositTime(ctx, w, b)
+		if err != nil {
+			return "", false, err
+		}
+
+		if time.Since(since) >= policy.Deadline {
+			return "", true, nil
+		}
+	}
+
+	baseCurrency, err := s.blockchain.GetNativeCoin(currency.Blockchain)
+	if err != nil {
+		return "", false, errors.Wrap(err, "unable to get native coin")
+	}
+
+	isTest := currency.TestNetworkID == b.NetworkID
+
+	fee, err := s.blockchain.CalculateSenderFee(ctx, baseCurrency, currency, w.Address, isTest)
+	if err != nil {
+		return "", false, errors.Wrap(err, "unable to calculate network fee")
+	}
+
+	if gasPrice, ok := fee.EffectiveGasPrice(); ok && policy.MaxGasPriceGwei > 0 {
+		gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(gasPrice), big.NewFloat(weiInGwei)).Float64()
+		if gwei > policy.MaxGasPriceGwei {
+			return fmt.Sprintf("gas price %.2f gwei exceeds %.2f gwei", gwei, policy.MaxGasPriceGwei), false, nil
+		}
+	}
+
+	if policy.MaxFeeRatio > 0 {
+		ratio, err := s.sweepFeeRatio(ctx, fee, baseCurrency, b.Amount)
+		if err != nil {
+			return "", false, err
+		}
+
+		if ratio > policy.MaxFeeRatio {
+			return fmt.Sprintf("network fee is %.2f%% of amount, max is %.2f%%", ratio*100, policy.MaxFeeRatio*100), false, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// oldestUnsweptDepositTime returns time of the oldest deposit that was credited to the balance
+// after its latest sweep. Falls back to the latest sweep (internal transfer) time or
+// balance creation time if deposits can't be distinguished.
+func (s *Service) oldestUnsweptDepositTime(ctx context.Context, w *wallet.Wallet, b *wallet.Balance) (time.Time, error) {
+	txs, err := s.transactions.ListByFilter(ctx, transaction.Filter{
+		SenderWalletID: w.ID,
+		NetworkID:      b.NetworkID,
+		Currency:       b.Currency,
+		Types:          []transaction.Type{transaction.TypeInternal},
+	}, 1)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "unable to list internal transactions")
+	}
+
+	lastSweep := b.CreatedAt
+	if len(txs) > 0 && txs[0].CreatedAt.After(b.CreatedAt) {
+		lastSweep = txs[0].CreatedAt
+	}
+
+	// balance is decremented right after internal transaction is created, so let's look a bit earlier
+	oldest, ok, err := s.wallets.OldestUnspentIncrement(ctx, b, lastSweep.Add(-time.Minute))
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "unable to get oldest unswept deposit")
+	}
+
+	if !ok {
+		return lastSweep, nil
+	}
+
+	return oldest, nil
+}
+
+// sweepFeeRatio returns USD ratio of network fee to swept amount.
+func (s *Service) sweepFeeRatio(
+	ctx context.Context,
+	fee blockchain.Fee,
+	baseCurrency money.CryptoCurrency,
+	amount money.Money,
+) (float64, error) {
+	feeAmount, err := fee.TotalCost(baseCurrency)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to get network fee total cost")
+	}
+
+	feeUSD, err := s.blockchain.CryptoToFiat(ctx, feeAmount, money.USD)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to convert network fee to USD")
+	}
+
+	amountUSD, err := s.blockchain.CryptoToFiat(ctx, amount, money.USD)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to convert balance to USD")
+	}
+
+	feeFloat, err := feeUSD.To.FiatToFloat64()
+	if err != nil {
+		return 0, err
+	}
+
+	amountFloat, err := amountUSD.To.FiatToFloat64()
+	if err != nil {
+		return 0, err
+	}
+
+	if amountFloat <= 0 {
+		return 0, errors.New("balance USD amount is zero")
+	}
+
+	return feeFloat / amountFloat, nil
+}
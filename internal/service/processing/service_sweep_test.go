@@ -0,0 +1,159 @@
+package processing_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/oxygenpay/oxygen/internal/test"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_ApplySweepPolicies(t *testing.T) {
+	tc := test.NewIntegrationTest(t)
+	ctx := tc.Context
+
+	eth := tc.Must.GetCurrency(t, "ETH")
+	bnb := tc.Must.GetCurrency(t, "BNB")
+
+	tc.Providers.TatumMock.SetupRates("ETH", money.USD, 1800)
+	tc.Providers.TatumMock.SetupRates("BNB", money.USD, 300)
+
+	// Given BSC sweep policy (see test.processingConfig): max 50 gwei, max 5% fee, 72h deadline
+	setupBSCFee := func(gasPrice, totalCostWEI string) {
+		tc.Fakes.SetupCalculateFee(bnb, bnb, false, blockchain.NewFee(bnb, time.Now(), false, blockchain.BSCFee{
+			GasUnits:     21000,
+			GasPrice:     gasPrice,
+			TotalCostWEI: totalCostWEI,
+		}))
+	}
+
+	setupBSCFeeWithPriority := func(gasPrice, priorityFee, totalCostWEI string) {
+		tc.Fakes.SetupCalculateFee(bnb, bnb, false, blockchain.NewFee(bnb, time.Now(), false, blockchain.BSCFee{
+			GasUnits:     21000,
+			GasPrice:     gasPrice,
+			PriorityFee:  priorityFee,
+			TotalCostWEI: totalCostWEI,
+		}))
+	}
+
+	for _, tt := range []struct {
+		name     string
+		currency money.CryptoCurrency
+		amount   string
+		age      time.Duration
+		deposit  string
+		setupFee func()
+
+		expectReady  bool
+		expectForced bool
+		expectReason string
+	}{
+		{
+			name:        "blockchain without policy",
+			currency:    eth,
+			amount:      "1000",
+			expectReady: true,
+		},
+		{
+			name:     "favorable network conditions",
+			currency: bnb,
+			// 1 BNB = $300
+			amount: "1_000_000_000_000_000_000",
+			// 5 gwei, $0.03 fee
+			setupFee:    func() { setupBSCFee("5000000000", "100000000000000") },
+			expectReady: true,
+		},
+		{
+			name:         "gas price is too high",
+			currency:     bnb,
+			amount:       "1_000_000_000_000_000_000",
+			setupFee:     func() { setupBSCFee("115243093692", "2420104967532000") },
+			expectReason: "gas price 115.24 gwei exceeds 50.00 gwei",
+		},
+		{
+			name:     "priority fee makes gas price too high",
+			currency: bnb,
+			amount:   "1_000_000_000_000_000_000",
+			// 45 gwei base + 10 gwei priority
+			setupFee:     func() { setupBSCFeeWithPriority("45000000000", "10000000000", "1155000000000000") },
+			expectReason: "gas price 55.00 gwei exceeds 50.00 gwei",
+		},
+		{
+			name:     "fee is too high compared to amount",
+			currency: bnb,
+			// 0.001 BNB = $0.3
+			amount:       "1_000_000_000_000_000",
+			setupFee:     func() { setupBSCFee("5000000000", "100000000000000") },
+			expectReason: "network fee is 10.00% of amount, max is 5.00%",
+		},
+		{
+			name:         "deadline is reached",
+			currency:     bnb,
+			amount:       "1_000_000_000_000_000",
+			age:          100 * time.Hour,
+			setupFee:     func() { setupBSCFee("115243093692", "2420104967532000") },
+			expectReady:  true,
+			expectForced: true,
+		},
+		{
+			name:         "deadline counts from the oldest unswept deposit",
+			currency:     bnb,
+			amount:       "1_000_000_000_000_000",
+			age:          100 * time.Hour,
+			deposit:      "1_000_000_000_000_000",
+			setupFee:     func() { setupBSCFee("115243093692", "2420104967532000") },
+			expectReason: "gas price 115.24 gwei exceeds 50.00 gwei",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tc.Clear.Wallets(t)
+
+			// ARRANGE
+			// Given inbound wallet with balance
+			_, b := tc.Must.CreateWalletWithBalance(t, tt.currency.Blockchain.String(), wallet.TypeInbound, withBalance(tt.currency, tt.amount, false))
+			b.CreatedAt = b.CreatedAt.Add(-tt.age)
+
+			// And optional recent deposit
+			if tt.deposit != "" {
+				_, err := tc.Services.Wallet.UpdateBalanceByID(ctx, b.ID, wallet.UpdateBalanceByIDQuery{
+					Operation: wallet.OperationIncrement,
+					Amount:    lo.Must(tt.currency.MakeAmount(tt.deposit)),
+				})
+				require.NoError(t, err)
+			}
+
+			// And network fee
+			if tt.setupFee != nil {
+				tt.setupFee()
+			}
+
+			// ACT
+			result, err := tc.Services.Processing.ApplySweepPolicies(ctx, []*wallet.Balance{b})
+
+			// ASSERT
+			require.NoError(t, err)
+			assert.Empty(t, result.UnhandledErrors)
+
+			if tt.expectReady {
+				assert.Equal(t, []*wallet.Balance{b}, result.ReadyBalances)
+				assert.Empty(t, result.SkippedBalances)
+			} else {
+				assert.Empty(t, result.ReadyBalances)
+				require.Len(t, result.SkippedBalances, 1)
+				assert.Equal(t, b.ID, result.SkippedBalances[0].Balance.ID)
+				assert.Equal(t, tt.expectReason, result.SkippedBalances[0].Reason)
+			}
+
+			if tt.expectForced {
+				assert.Equal(t, []int64{b.ID}, result.ForcedBalanceIDs)
+			} else {
+				assert.Empty(t, result.ForcedBalanceIDs)
+			}
+		})
+	}
+}
@@ -94,6 +94,32 @@ func (s *Service) ProcessIncomingWebhook(ctx context.Context, walletID uuid.UUID
 		NetworkID:     networkID,
 	}
 
+	return s.ProcessIncomingTransfer(ctx, wt, input)
+}
+
+// ProcessIncomingTransfer ingests incoming blockchain transfer to our wallet regardless
+// of its source (provider's webhook, block scanner, etc...). Processing is idempotent:
+// already known transaction hashes are skipped.
+//
+// Known hashes include our own transfers (gas funding, internal transfers, withdrawals), so they're
+// never matched with customer's pending payment on the same wallet.
+func (s *Service) ProcessIncomingTransfer(ctx context.Context, wt *wallet.Wallet, input Input) error {
+	tx, err := s.transactions.GetByHash(ctx, input.NetworkID, input.TransactionID)
+
+	switch {
+	case err == nil:
+		s.logger.Info().
+			Int64("wallet_id", wt.ID).
+			Int64("transaction_id", tx.ID).
+			Str("blockchain_tx_hash_id", input.TransactionID).
+			Str("network_id", input.NetworkID).
+			Msg("skipping incoming transfer: transaction hash is already known")
+
+		return nil
+	case !errors.Is(err, transaction.ErrNotFound):
+		return errors.Wrap(err, "unable to get transaction by hash")
+	}
+
 	processors := []webhookProcessor{
 		s.processTronAccountActivation,
 		s.processExpectedWebhook,
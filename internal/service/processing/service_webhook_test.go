@@ -4,9 +4,12 @@ import (
 	"testing"
 
 	"github.com/oxygenpay/oxygen/internal/service/processing"
+	"github.com/oxygenpay/oxygen/internal/service/transaction"
 	"github.com/oxygenpay/oxygen/internal/service/wallet"
 	"github.com/oxygenpay/oxygen/internal/test"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestUnknownNetwork(t *testing.T) {
@@ -27,3 +30,60 @@ func TestUnknownNetwork(t *testing.T) {
 	err := tc.Services.Processing.ProcessIncomingWebhook(tc.Context, wt.UUID, "333", wh)
 	assert.ErrorContains(t, err, "unknown ETH network id \"333\"")
 }
+
+func TestService_ProcessIncomingTransfer(t *testing.T) {
+	tc := test.NewIntegrationTest(t)
+
+	// Given a merchant
+	mt, _ := tc.Must.CreateMerchant(t, 1)
+
+	// And ETH currency
+	eth := tc.Must.GetCurrency(t, "ETH")
+	amount := lo.Must(eth.MakeAmount("1_000_000_000"))
+
+	// And inbound & gas wallets
+	inbound := tc.Must.CreateWallet(t, "ETH", "0x123-inbound", "0x-pub-key", wallet.TypeInbound)
+	gas := tc.Must.CreateWallet(t, "ETH", "0x123-gas", "0x-pub-key", wallet.TypeGas)
+
+	// And pending ETH payment on the inbound wallet
+	expected := tc.Must.CreateTransaction(t, mt.ID, func(p *transaction.CreateTransaction) {
+		p.Currency = eth
+		p.Amount = amount
+		p.ServiceFee = lo.Must(eth.MakeAmount("0"))
+		p.RecipientWallet = inbound
+		p.RecipientAddress = inbound.Address
+	})
+
+	// And gas funding of the same inbound wallet (e.g. for sweeping unswept USDT)
+	gasFunding := tc.Must.CreateTransaction(t, transaction.SystemMerchantID, func(p *transaction.CreateTransaction) {
+		p.Type = transaction.TypeGasFunding
+		p.EntityID = 0
+		p.Currency = eth
+		p.Amount = amount
+		p.ServiceFee = lo.Must(eth.MakeAmount("0"))
+		p.SenderWallet = gas
+		p.SenderAddress = gas.Address
+		p.RecipientWallet = inbound
+		p.RecipientAddress = inbound.Address
+		p.TransactionHash = "0x-gas-funding-hash"
+	})
+
+	// ACT
+	// Gas funding is received by the inbound wallet
+	err := tc.Services.Processing.ProcessIncomingTransfer(tc.Context, inbound, processing.Input{
+		Currency:      eth,
+		Amount:        amount,
+		SenderAddress: gas.Address,
+		TransactionID: *gasFunding.HashID,
+		NetworkID:     eth.NetworkID,
+	})
+
+	// ASSERT
+	require.NoError(t, err)
+
+	// payment is still pending
+	fresh, err := tc.Services.Transaction.GetByID(tc.Context, mt.ID, expected.ID)
+	require.NoError(t, err)
+	assert.Equal(t, transaction.StatusPending, fresh.Status)
+	assert.Nil(t, fresh.HashID)
+}
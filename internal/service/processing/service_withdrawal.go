@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/oxygenpay/oxygen/internal/money"
 	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/exchange"
 	"github.com/oxygenpay/oxygen/internal/service/merchant"
 	"github.com/oxygenpay/oxygen/internal/service/payment"
 	"github.com/oxygenpay/oxygen/internal/service/transaction"
@@ -223,19 +225,13 @@ func (s *Service) createWithdrawal(ctx context.Context, params withdrawalInput)
 	isTest := currency.NetworkID != params.MerchantBalance.NetworkID
 	out.IsTest = isTest
 
-	// 1. Calculate withdrawal fee in USD
-	withdrawalFeeUSD, err := s.blockchain.CalculateWithdrawalFeeUSD(ctx, baseCurrency, currency, isTest)
-	if err != nil {
-		return out, errors.Wrapf(err, "unable to get currency withdrawal fee in USD")
-	}
-
-	withdrawalFeeCrypto, err := s.blockchain.FiatToCrypto(ctx, withdrawalFeeUSD, currency)
+	// 1. Calculate withdrawal fee or use the one locked by merchant's quote
+	serviceFee, err := s.withdrawalServiceFee(ctx, params.Withdrawal, baseCurrency, currency, isTest)
 	if err != nil {
-		return out, errors.Wrapf(err, "unable to get currency withdrawal fee in crypto")
+		return out, err
 	}
 
 	amount := params.Withdrawal.Price
-	serviceFee := withdrawalFeeCrypto.To
 	out.ServiceFee = serviceFee
 
 	// 2. Ensure that merchant balance & outbound wallet have enough funds
@@ -255,7 +251,7 @@ func (s *Service) createWithdrawal(ctx context.Context, params withdrawalInput)
 		return out, errors.Wrap(err, "unable to calculate network fee")
 	}
 
-	txRaw, err := s.wallets.CreateSignedTransaction(
+	txRaw, nonce, err := s.wallets.CreateSignedTransaction(
 		ctx,
 		params.Wallet,
 		params.MerchantAddress.Address,
@@ -285,6 +281,7 @@ func (s *Service) createWithdrawal(ctx context.Context, params withdrawalInput)
 		Currency:         currency,
 		Amount:           amount,
 		USDAmount:        conv.To,
+		USDRateID:        conv.RateID,
 		ServiceFee:       serviceFee,
 		IsTest:           isTest,
 	})
@@ -342,6 +339,14 @@ func (s *Service) createWithdrawal(ctx context.Context, params withdrawalInput)
 			Msg("unable to update database tx hash id")
 	}
 
+	// nonce & fee are required for replacing the transaction if it gets stuck
+	broadcast := transaction.MetaData{}.WithBroadcast(nonce, txNetworkFee, time.Now())
+	if errMeta := s.transactions.UpdateMetaData(ctx, tx, broadcast); errMeta != nil {
+		s.logger.Error().Err(errMeta).
+			Int64("transaction_id", tx.ID).Str("transaction_hash_id", transactionHashID).
+			Msg("unable to update database tx broadcast metadata")
+	}
+
 	// 10. Update payment's status
 	_, err = s.payments.Update(
 		ctx,
@@ -424,7 +429,7 @@ func (s *Service) checkWithdrawalTransaction(ctx context.Context, txID int64) er
 		return errors.New("empty sender wallet id")
 	}
 
-	receipt, err := s.blockchain.GetTransactionReceipt(ctx, tx.Currency.Blockchain, *tx.HashID, tx.IsTest)
+	receipt, err := s.getOutboundReceipt(ctx, tx)
 	if err != nil {
 		return errors.Wrap(err, "unable to get transaction receipt")
 	}
@@ -572,3 +577,33 @@ func validateWithdrawal(pt *payment.Payment) error {
 
 	return nil
 }
+
+func (s *Service) withdrawalServiceFee(
+	ctx context.Context,
+	withdrawal *payment.Payment,
+	baseCurrency, currency money.CryptoCurrency,
+	isTest bool,
+) (money.Money, error) {
+	quote, err := s.payments.GetWithdrawalFeeQuote(ctx, withdrawal)
+
+	switch {
+	case errors.Is(err, exchange.ErrQuoteNotFound):
+		// withdrawal was created without a quote
+	case err != nil:
+		return money.Money{}, errors.Wrap(err, "unable to get withdrawal fee quote")
+	case quote.To.Ticker() == currency.Ticker:
+		return quote.To, nil
+	}
+
+	withdrawalFeeUSD, err := s.blockchain.CalculateWithdrawalFeeUSD(ctx, baseCurrency, currency, isTest)
+	if err != nil {
+		return money.Money{}, errors.Wrapf(err, "unable to get currency withdrawal fee in USD")
+	}
+
+	withdrawalFeeCrypto, err := s.blockchain.FiatToCrypto(ctx, withdrawalFeeUSD, currency)
+	if err != nil {
+		return money.Money{}, errors.Wrapf(err, "unable to get currency withdrawal fee in crypto")
+	}
+
+	return withdrawalFeeCrypto.To, nil
+}
@@ -2,6 +2,8 @@ package transaction
 
 import (
 	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgtype"
@@ -32,6 +34,9 @@ type Transaction struct {
 	Amount    money.Money
 	USDAmount money.Money
 
+	// USDRateID historical exchange rate used for USDAmount calculation (if known).
+	USDRateID *int64
+
 	FactAmount *money.Money
 
 	ServiceFee money.Money
@@ -51,6 +56,73 @@ func (tx *Transaction) IsInProgress() bool {
 	return tx.Status == StatusInProgress || tx.Status == StatusInProgressInvalid
 }
 
+// Confirmations returns current and required amount of blockchain confirmations
+// recorded while the transaction was in progress. Zeros are returned if unknown.
+func (tx *Transaction) Confirmations() (current, required int64) {
+	current, _ = strconv.ParseInt(tx.MetaData[MetaConfirmations], 10, 64)
+	required, _ = strconv.ParseInt(tx.MetaData[MetaRequiredConfirmations], 10, 64)
+
+	return current, required
+}
+
+// Block returns number & hash of the block that included the transaction at the moment of confirmation.
+func (tx *Transaction) Block() (number int64, hash string) {
+	number, _ = strconv.ParseInt(tx.MetaData[MetaBlockNumber], 10, 64)
+
+	return number, tx.MetaData[MetaBlockHash]
+}
+
+// Nonce returns nonce of outbound transaction. False is returned if nonce wasn't recorded.
+func (tx *Transaction) Nonce() (int64, bool) {
+	nonce, err := strconv.ParseInt(tx.MetaData[MetaNonce], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return nonce, true
+}
+
+// BroadcastedAt returns time of the latest broadcast of outbound transaction.
+func (tx *Transaction) BroadcastedAt() time.Time {
+	at, err := time.Parse(time.RFC3339, tx.MetaData[MetaBroadcastedAt])
+	if err != nil {
+		return tx.CreatedAt
+	}
+
+	return at
+}
+
+// FeeBumps returns how many times transaction was replaced with a higher fee.
+func (tx *Transaction) FeeBumps() int64 {
+	bumps, _ := strconv.ParseInt(tx.MetaData[MetaFeeBumps], 10, 64)
+
+	return bumps
+}
+
+// HashAttempts returns hashes of all broadcasted attempts starting from the latest one.
+// Each attempt shares the same nonce, so only one of them can be included into the blockchain.
+func (tx *Transaction) HashAttempts() []string {
+	var hashes []string
+	if tx.HashID != nil {
+		hashes = append(hashes, *tx.HashID)
+	}
+
+	replaced := replacedHashes(tx.MetaData)
+	for i := len(replaced) - 1; i >= 0; i-- {
+		hashes = append(hashes, replaced[i])
+	}
+
+	return hashes
+}
+
+func replacedHashes(m MetaData) []string {
+	if m[MetaReplacedHashes] == "" {
+		return nil
+	}
+
+	return strings.Split(m[MetaReplacedHashes], ",")
+}
+
 func (tx *Transaction) NetworkID() string {
 	return tx.Currency.ChooseNetwork(tx.IsTest)
 }
@@ -82,8 +154,58 @@ const (
 	MetaTransactionID     = "transactionId"
 	MetaRecipientWalletID = "recipientWalletId"
 	MetaMerchantID        = "merchantId"
+
+	MetaConfirmations         wallet.MetaDataKey = "confirmations"
+	MetaRequiredConfirmations wallet.MetaDataKey = "requiredConfirmations"
+
+	MetaBlockNumber   wallet.MetaDataKey = "blockNumber"
+	MetaBlockHash     wallet.MetaDataKey = "blockHash"
+	MetaReorgVerified wallet.MetaDataKey = "reorgVerified"
+	MetaReorgMisses   wallet.MetaDataKey = "reorgMisses"
+
+	MetaReorgWalletShortfall   wallet.MetaDataKey = "reorgWalletShortfall"
+	MetaReorgMerchantShortfall wallet.MetaDataKey = "reorgMerchantShortfall"
+
+	MetaNonce          wallet.MetaDataKey = "nonce"
+	MetaGasPrice       wallet.MetaDataKey = "gasPrice"
+	MetaPriorityFee    wallet.MetaDataKey = "priorityFee"
+	MetaBroadcastedAt  wallet.MetaDataKey = "broadcastedAt"
+	MetaReplacedHashes wallet.MetaDataKey = "replacedHashes"
+	MetaFeeBumps       wallet.MetaDataKey = "feeBumps"
 )
 
+// WithBroadcast returns a copy of metadata with nonce & fee of broadcasted outbound transaction.
+// Gas prices are recorded only for EVM blockchains.
+func (m MetaData) WithBroadcast(nonce int64, fee blockchain.Fee, at time.Time) MetaData {
+	result := make(MetaData, len(m)+4)
+	for k, v := range m {
+		result[k] = v
+	}
+
+	result[MetaNonce] = strconv.FormatInt(nonce, 10)
+	result[MetaBroadcastedAt] = at.UTC().Format(time.RFC3339)
+
+	if gasPrice, priorityFee, err := fee.GasPrices(); err == nil {
+		result[MetaGasPrice] = gasPrice
+		result[MetaPriorityFee] = priorityFee
+	}
+
+	return result
+}
+
+// WithConfirmations returns a copy of metadata with confirmations progress.
+func (m MetaData) WithConfirmations(confirmations, required int64) MetaData {
+	result := make(MetaData, len(m)+2)
+	for k, v := range m {
+		result[k] = v
+	}
+
+	result[MetaConfirmations] = strconv.FormatInt(confirmations, 10)
+	result[MetaRequiredConfirmations] = strconv.FormatInt(required, 10)
+
+	return result
+}
+
 func (m MetaData) toJSONB() pgtype.JSONB {
 	if len(m) == 0 {
 		return pgtype.JSONB{Status: pgtype.Null}
@@ -120,6 +242,10 @@ const (
 	// StatusFailed transaction was confirmed in blockchain as failed (reverted)
 	// but gas was consumed
 	StatusFailed Status = "failed"
+
+	// StatusOrphaned transaction was completed but then disappeared from blockchain
+	// due to chain reorganization. Balance changes are reverted.
+	StatusOrphaned Status = "orphaned"
 )
 
 var finalizedTransactionStatuses = map[Status]struct{}{
@@ -127,6 +253,7 @@ var finalizedTransactionStatuses = map[Status]struct{}{
 	StatusCompletedInvalid: {},
 	StatusCancelled:        {},
 	StatusFailed:           {},
+	StatusOrphaned:         {},
 }
 
 type Type string
@@ -141,11 +268,19 @@ const (
 	// TypeWithdrawal is for moving assets from outbound wallets to merchant's address
 	TypeWithdrawal Type = "withdrawal"
 
+	// TypeGasFunding is for sending native coin from gas wallet to inbound wallet,
+	// so the latter can pay network fees for moving tokens to outbound wallet.
+	TypeGasFunding Type = "gas_funding"
+
+	// TypeColdSweep is for moving excess assets from outbound wallets to cold address that is not managed by KMS.
+	TypeColdSweep Type = "cold_sweep"
+
 	// TypeVirtual is for moving assets within OxygenPay w/o reflecting it on blockchain
 	// (e.g. merchant to merchant, system to merchant, ...)
 	TypeVirtual Type = "virtual"
 )
 
 func (t Type) valid() bool {
-	return t == TypeIncoming || t == TypeInternal || t == TypeWithdrawal || t == TypeVirtual
+	return t == TypeIncoming || t == TypeInternal || t == TypeWithdrawal || t == TypeVirtual || t == TypeGasFunding ||
+		t == TypeColdSweep
 }
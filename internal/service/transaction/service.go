@@ -76,6 +76,9 @@ type CreateTransaction struct {
 	USDAmount  money.Money
 	ServiceFee money.Money
 
+	// USDRateID historical exchange rate used for USDAmount calculation. Optional.
+	USDRateID int64
+
 	IsTest bool
 
 	isIncomingUnexpected bool
@@ -116,7 +119,7 @@ func (c *CreateTransaction) validate() error {
 		if c.isIncomingUnexpected && c.TransactionHash == "" {
 			return errors.New("empty transaction hash")
 		}
-	case TypeInternal:
+	case TypeInternal, TypeGasFunding:
 		if c.EntityID != 0 {
 			return errors.New("entity id should be 0 if tx is internal")
 		}
@@ -128,6 +131,18 @@ func (c *CreateTransaction) validate() error {
 		if c.RecipientWallet == nil {
 			return errors.New("empty recipient wallet")
 		}
+	case TypeColdSweep:
+		if c.EntityID != 0 {
+			return errors.New("entity id should be 0 if tx is cold sweep")
+		}
+
+		if c.SenderWallet == nil {
+			return errors.New("empty sender wallet")
+		}
+
+		if c.RecipientAddress == "" {
+			return errors.New("empty recipient address")
+		}
 	case TypeWithdrawal:
 		if c.EntityID == 0 {
 			return errors.New("invalid entity id")
@@ -240,8 +255,9 @@ func (s *Service) Create(
 			ServiceFee: repository.MoneyToNumeric(params.ServiceFee),
 			UsdAmount:  repository.MoneyToNumeric(params.USDAmount),
 
-			Metadata: metaData,
-			IsTest:   params.IsTest,
+			Metadata:  metaData,
+			IsTest:    params.IsTest,
+			UsdRateID: sql.NullInt64{Int64: params.USDRateID, Valid: params.USDRateID > 0},
 		}
 
 		entry, errCreate := q.CreateTransaction(ctx, create)
@@ -343,6 +359,7 @@ type Filter struct {
 	Types             []Type
 	Statuses          []Status
 	HashIsEmpty       bool
+	SenderWalletID    int64
 }
 
 func (f Filter) toRepo(limit int32) repository.GetTransactionsByFilterParams {
@@ -364,6 +381,9 @@ func (f Filter) toRepo(limit int32) repository.GetTransactionsByFilterParams {
 
 		FilterEmptyHash: f.HashIsEmpty,
 
+		FilterBySenderWalletID: f.SenderWalletID != 0,
+		SenderWalletID:         repository.Int64ToNullable(f.SenderWalletID),
+
 		Limit: limit,
 	}
 }
@@ -486,6 +506,8 @@ func (s *Service) entryToTransaction(tx repository.Transaction) (*Transaction, e
 		ServiceFee: serviceFee,
 		NetworkFee: networkFee,
 
+		USDRateID: repository.NullableInt64ToPointer(tx.UsdRateID),
+
 		MetaData: metaData,
 		IsTest:   tx.IsTest,
 	}
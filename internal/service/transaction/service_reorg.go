@@ -0,0 +1,229 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+	"github.com/oxygenpay/oxygen/internal/db/repository"
+	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/pkg/errors"
+)
+
+// ListForReorgCheck returns completed incoming transactions that are not deep enough
+// in the blockchain to be considered irreversible. Results are paginated by id (afterID),
+// so transactions that are not verified yet don't block newer ones.
+func (s *Service) ListForReorgCheck(ctx context.Context, afterID int64, limit int32) ([]*Transaction, error) {
+	results, err := s.store.ListTransactionsForReorgCheck(ctx, repository.ListTransactionsForReorgCheckParams{
+		Type:     string(TypeIncoming),
+		Limit:    limit,
+		Statuses: []string{string(StatusCompleted), string(StatusCompletedInvalid)},
+		AfterID:  afterID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]*Transaction, len(results))
+	for i := range results {
+		tx, err := s.entryToTransaction(results[i])
+		if err != nil {
+			return nil, err
+		}
+
+		txs[i] = tx
+	}
+
+	return txs, nil
+}
+
+// ListOrphanedWithPaymentStatus returns orphaned incoming transactions which payments have specified status.
+// Used to find payments that were not marked as failed (e.g. payment update failed right after orphaning).
+func (s *Service) ListOrphanedWithPaymentStatus(ctx context.Context, paymentStatus string, limit int32) ([]*Transaction, error) {
+	results, err := s.store.ListOrphanedTransactionsWithPaymentStatus(ctx, repository.ListOrphanedTransactionsWithPaymentStatusParams{
+		Type:          string(TypeIncoming),
+		Status:        string(StatusOrphaned),
+		Limit:         limit,
+		PaymentStatus: paymentStatus,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]*Transaction, len(results))
+	for i := range results {
+		tx, err := s.entryToTransaction(results[i])
+		if err != nil {
+			return nil, err
+		}
+
+		txs[i] = tx
+	}
+
+	return txs, nil
+}
+
+// Orphan marks completed incoming transaction as orphaned after chain reorganization
+// and reverts related wallet & merchant balance increments. Each decrement is written to balance audit log.
+//
+// Funds of the transaction might be already swept to outbound wallet or withdrawn by the merchant.
+// Balances are never decremented below zero: the part that can't be reverted is recorded
+// to MetaReorgWalletShortfall / MetaReorgMerchantShortfall for manual review.
+func (s *Service) Orphan(ctx context.Context, tx *Transaction, reason string) error {
+	switch {
+	case tx.Type != TypeIncoming:
+		return errors.New("only incoming transactions can be orphaned")
+	case tx.Status != StatusCompleted && tx.Status != StatusCompletedInvalid:
+		return errors.Errorf("unable to orphan transaction with status %q", tx.Status)
+	case tx.FactAmount == nil || tx.RecipientWalletID == nil:
+		return errors.New("transaction has no fact amount or recipient")
+	}
+
+	var txHash string
+	if tx.HashID != nil {
+		txHash = *tx.HashID
+	}
+
+	meta := make(MetaData, len(tx.MetaData)+3)
+	for k, v := range tx.MetaData {
+		meta[k] = v
+	}
+
+	meta[MetaErrorReason] = reason
+
+	comment := fmt.Sprintf("reverting incoming tx %s: %s", txHash, reason)
+	auditMeta := wallet.MetaData{
+		MetaRecipientWalletID: strconv.FormatInt(*tx.RecipientWalletID, 10),
+		MetaMerchantID:        strconv.FormatInt(tx.MerchantID, 10),
+		MetaTransactionID:     strconv.FormatInt(tx.ID, 10),
+	}
+
+	err := s.store.RunTransaction(ctx, func(ctx context.Context, q repository.Querier) error {
+		decrementWallet := wallet.UpdateBalanceQuery{
+			EntityID:   *tx.RecipientWalletID,
+			EntityType: wallet.EntityTypeWallet,
+			Operation:  wallet.OperationDecrement,
+			Currency:   tx.Currency,
+			Amount:     *tx.FactAmount,
+			Comment:    comment,
+			MetaData:   auditMeta,
+			IsTest:     tx.IsTest,
+		}
+
+		shortfall, err := revertBalance(ctx, q, decrementWallet)
+		if err != nil {
+			return errors.Wrap(err, "unable to decrement wallet balance")
+		}
+
+		if !shortfall.IsZero() {
+			meta[MetaReorgWalletShortfall] = shortfall.String()
+		}
+
+		// merchant's balance was not incremented for underpayments and unexpected transactions
+		if tx.Status != StatusCompletedInvalid && tx.MerchantID != SystemMerchantID {
+			amount, err := merchantIncomingAmount(tx)
+			if err != nil {
+				return err
+			}
+
+			decrementMerchant := wallet.UpdateBalanceQuery{
+				EntityID:   tx.MerchantID,
+				EntityType: wallet.EntityTypeMerchant,
+				Operation:  wallet.OperationDecrement,
+				Currency:   tx.Currency,
+				Amount:     amount,
+				Comment:    comment,
+				MetaData:   auditMeta,
+				IsTest:     tx.IsTest,
+			}
+
+			shortfall, err := revertBalance(ctx, q, decrementMerchant)
+			if err != nil {
+				return errors.Wrap(err, "unable to decrement merchant balance")
+			}
+
+			if !shortfall.IsZero() {
+				meta[MetaReorgMerchantShortfall] = shortfall.String()
+			}
+		}
+
+		err = q.CancelTransaction(ctx, repository.CancelTransactionParams{
+			ID:         tx.ID,
+			Status:     string(StatusOrphaned),
+			UpdatedAt:  time.Now(),
+			Metadata:   meta.toJSONB(),
+			NetworkFee: pgtype.Numeric{Status: pgtype.Null},
+		})
+		if err != nil {
+			return errors.Wrap(err, "unable to update transaction status")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	tx.Status = StatusOrphaned
+	tx.MetaData = meta
+
+	return nil
+}
+
+// revertBalance decrements balance by query's amount but not below zero.
+// Returns the amount that wasn't reverted because balance is already spent.
+func revertBalance(ctx context.Context, q repository.Querier, query wallet.UpdateBalanceQuery) (money.Money, error) {
+	var available money.Money
+
+	balance, err := q.GetBalanceByFilterWithLock(ctx, repository.GetBalanceByFilterWithLockParams{
+		EntityID:   query.EntityID,
+		EntityType: string(query.EntityType),
+		NetworkID:  query.Currency.ChooseNetwork(query.IsTest),
+		Currency:   query.Currency.Ticker,
+	})
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		available, err = money.CryptoFromRaw(query.Amount.Ticker(), "0", query.Amount.Decimals())
+	case err != nil:
+		return money.Money{}, errors.Wrap(err, "unable to get balance")
+	default:
+		available, err = repository.NumericToMoney(balance.Amount, money.Crypto, balance.Currency, int64(balance.Decimals))
+	}
+
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	shortfall, err := money.CryptoFromRaw(query.Amount.Ticker(), "0", query.Amount.Decimals())
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	if available.LessThan(query.Amount) {
+		if available.IsNegative() {
+			available = shortfall
+		}
+
+		if shortfall, err = query.Amount.Sub(available); err != nil {
+			return money.Money{}, err
+		}
+
+		query.Amount = available
+	}
+
+	if query.Amount.IsZero() {
+		return shortfall, nil
+	}
+
+	if _, err := wallet.UpdateBalance(ctx, q, query); err != nil {
+		return money.Money{}, err
+	}
+
+	return shortfall, nil
+}
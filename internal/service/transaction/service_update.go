@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgtype"
 	pgx "github.com/jackc/pgx/v4"
 	"github.com/oxygenpay/oxygen/internal/db/repository"
 	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
 	"github.com/oxygenpay/oxygen/internal/service/wallet"
 	"github.com/pkg/errors"
 )
@@ -81,6 +83,38 @@ func (s *Service) Receive(
 	return result, nil
 }
 
+// UpdateConfirmations records blockchain confirmations progress of in-progress transaction
+// and reflects changes in *Transaction argument.
+func (s *Service) UpdateConfirmations(ctx context.Context, tx *Transaction, confirmations, required int64) error {
+	return s.UpdateMetaData(ctx, tx, MetaData{}.WithConfirmations(confirmations, required))
+}
+
+// UpdateMetaData merges provided metadata into transaction's metadata
+// and reflects changes in *Transaction argument.
+func (s *Service) UpdateMetaData(ctx context.Context, tx *Transaction, metaData MetaData) error {
+	meta := make(MetaData, len(tx.MetaData)+len(metaData))
+	for k, v := range tx.MetaData {
+		meta[k] = v
+	}
+	for k, v := range metaData {
+		meta[k] = v
+	}
+
+	err := s.store.UpdateTransactionMetadata(ctx, repository.UpdateTransactionMetadataParams{
+		Metadata:   meta.toJSONB(),
+		UpdatedAt:  time.Now(),
+		ID:         tx.ID,
+		MerchantID: tx.MerchantID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to update transaction metadata")
+	}
+
+	tx.MetaData = meta
+
+	return nil
+}
+
 // confirm mark tx as confirmed and updates related balances.
 func (s *Service) receive(ctx context.Context, q repository.Querier, merchantID, txID int64, params ReceiveTransaction) (*Transaction, error) {
 	// 1. Get transaction
@@ -199,6 +233,53 @@ func (s *Service) UpdateTransactionHash(ctx context.Context, merchantID, txID in
 	})
 }
 
+// ReplaceTransactionHash sets hash of the replacement transaction (same nonce, higher fee) and keeps
+// previous hash in the metadata, so all broadcasted attempts are tracked under the same transaction.
+// Reflects changes in *Transaction argument.
+func (s *Service) ReplaceTransactionHash(ctx context.Context, tx *Transaction, txHash string, fee blockchain.Fee) error {
+	if tx.HashID == nil {
+		return errors.Wrap(ErrInvalidUpdateParams, "transaction hash is empty")
+	}
+
+	nonce, ok := tx.Nonce()
+	if !ok {
+		return errors.Wrap(ErrInvalidUpdateParams, "transaction nonce is unknown")
+	}
+
+	now := time.Now()
+
+	meta := tx.MetaData.WithBroadcast(nonce, fee, now)
+	meta[MetaReplacedHashes] = strings.Join(append(replacedHashes(tx.MetaData), *tx.HashID), ",")
+	meta[MetaFeeBumps] = strconv.FormatInt(tx.FeeBumps()+1, 10)
+
+	err := s.store.RunTransaction(ctx, func(ctx context.Context, q repository.Querier) error {
+		err := q.SetTransactionHash(ctx, repository.SetTransactionHashParams{
+			ID:              tx.ID,
+			MerchantID:      tx.MerchantID,
+			UpdatedAt:       now,
+			TransactionHash: repository.StringToNullable(txHash),
+		})
+		if err != nil {
+			return errors.Wrap(err, "unable to update transaction hash")
+		}
+
+		return q.UpdateTransactionMetadata(ctx, repository.UpdateTransactionMetadataParams{
+			Metadata:   meta.toJSONB(),
+			UpdatedAt:  now,
+			ID:         tx.ID,
+			MerchantID: tx.MerchantID,
+		})
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to replace transaction hash")
+	}
+
+	tx.HashID = &txHash
+	tx.MetaData = meta
+
+	return nil
+}
+
 // confirm mark tx as confirmed and updates related balances.
 func (s *Service) confirm(ctx context.Context, q repository.Querier, merchantID, txID int64, params ConfirmTransaction) (*Transaction, error) {
 	// 1. Get transaction
@@ -331,16 +412,9 @@ func (s *Service) updateBalancesAfterTxConfirmation(
 			return nil
 		}
 
-		// If customer paid more than required, restrict
-		// merchant's balance increment by initial tx amount
-		gainedAmount := *tx.FactAmount
-		if tx.FactAmount.GreaterThan(tx.Amount) {
-			gainedAmount = tx.Amount
-		}
-
-		gainedAmountMinusFee, err := gainedAmount.Sub(tx.ServiceFee)
+		gainedAmountMinusFee, err := merchantIncomingAmount(tx)
 		if err != nil {
-			return errors.Wrap(err, "unable to subtract serviceFee")
+			return err
 		}
 
 		updateMerchantBalance := wallet.UpdateBalanceQuery{
@@ -364,7 +438,9 @@ func (s *Service) updateBalancesAfterTxConfirmation(
 		return nil
 	}
 
-	if tx.Type == TypeInternal {
+	// gas funding is accounted as internal transfer: gas wallet's coins are moved to inbound wallet
+	// and later spent as a network fee of inbound -> outbound token transfer.
+	if tx.Type == TypeInternal || tx.Type == TypeGasFunding {
 		if tx.SenderWalletID == nil {
 			return errors.New("sender wallet id is nil")
 		}
@@ -403,7 +479,8 @@ func (s *Service) updateBalancesAfterTxConfirmation(
 		return nil
 	}
 
-	if tx.Type == TypeWithdrawal {
+	// cold sweep amount is already decremented from outbound wallet, only network fee is left
+	if tx.Type == TypeWithdrawal || tx.Type == TypeColdSweep {
 		if tx.SenderWalletID == nil {
 			return errors.New("sender wallet id is nil")
 		}
@@ -426,7 +503,8 @@ func (s *Service) updateBalancesAfterTxConfirmation(
 				MetaTransactionID: strconv.FormatInt(tx.ID, 10),
 			},
 			Comment: fmt.Sprintf(
-				"decrementing balance as a fee to withdrawal tx %s (%s)",
+				"decrementing balance as a fee to %s tx %s (%s)",
+				tx.Type,
 				params.TransactionHash,
 				tx.Currency.Ticker,
 			),
@@ -476,6 +554,22 @@ func (s *Service) updateBalancesAfterTxConfirmation(
 	return fmt.Errorf("unknown transaction type %q", tx.Type)
 }
 
+// merchantIncomingAmount returns amount that merchant gains from incoming transaction.
+// If customer paid more than required, merchant's balance increment is restricted by initial tx amount.
+func merchantIncomingAmount(tx *Transaction) (money.Money, error) {
+	gainedAmount := *tx.FactAmount
+	if tx.FactAmount.GreaterThan(tx.Amount) {
+		gainedAmount = tx.Amount
+	}
+
+	gainedAmountMinusFee, err := gainedAmount.Sub(tx.ServiceFee)
+	if err != nil {
+		return money.Money{}, errors.Wrap(err, "unable to subtract serviceFee")
+	}
+
+	return gainedAmountMinusFee, nil
+}
+
 func (s *Service) Cancel(ctx context.Context, tx *Transaction, status Status, reason string, setNetworkFee *money.Money) error {
 	networkFee := pgtype.Numeric{Status: pgtype.Null}
 
@@ -27,10 +27,17 @@ var (
 const (
 	TypeInbound  Type = "inbound"
 	TypeOutbound Type = "outbound"
+
+	// TypeGas wallet funds inbound wallets with native coin so they can pay network fees for token transfers.
+	TypeGas Type = "gas"
+
+	// TypeStaking TRON wallet which TRX are staked for energy that is delegated to inbound & outbound wallets.
+	TypeStaking Type = "staking"
 )
 
 type BlockchainService interface {
 	blockchain.Convertor
+	blockchain.NonceResolver
 }
 
 type Service struct {
@@ -89,7 +96,7 @@ func (s *Service) Create(ctx context.Context, bc kmswallet.Blockchain, walletTyp
 		return nil, ErrInvalidBlockchain
 	}
 
-	if walletType != TypeOutbound && walletType != TypeInbound {
+	if walletType != TypeOutbound && walletType != TypeInbound && walletType != TypeGas && walletType != TypeStaking {
 		return nil, ErrInvalidType
 	}
 
@@ -149,9 +156,26 @@ func (s *Service) BulkCreateWallets(ctx context.Context, bc kmswallet.Blockchain
 // EnsureOutboundWallet finds or creates outbound wallet for specified blockchain.
 // Outbound wallets are used for funds withdrawal.
 func (s *Service) EnsureOutboundWallet(ctx context.Context, bc kmswallet.Blockchain) (*Wallet, bool, error) {
+	return s.ensureSystemWallet(ctx, bc, TypeOutbound)
+}
+
+// EnsureGasWallet finds or creates gas wallet for specified blockchain.
+// Gas wallets are used for paying network fees of inbound wallets' token transfers.
+func (s *Service) EnsureGasWallet(ctx context.Context, bc kmswallet.Blockchain) (*Wallet, bool, error) {
+	return s.ensureSystemWallet(ctx, bc, TypeGas)
+}
+
+// EnsureStakingWallet finds or creates staking wallet for specified blockchain.
+// Staking wallets are used for obtaining energy for TRON token transfers.
+func (s *Service) EnsureStakingWallet(ctx context.Context, bc kmswallet.Blockchain) (*Wallet, bool, error) {
+	return s.ensureSystemWallet(ctx, bc, TypeStaking)
+}
+
+// ensureSystemWallet finds or creates the only wallet of specified type for the blockchain.
+func (s *Service) ensureSystemWallet(ctx context.Context, bc kmswallet.Blockchain, walletType Type) (*Wallet, bool, error) {
 	params := repository.PaginateWalletsByIDParams{
 		Blockchain:         bc.String(),
-		Type:               repository.StringToNullable(string(TypeOutbound)),
+		Type:               repository.StringToNullable(string(walletType)),
 		FilterByType:       true,
 		FilterByBlockchain: true,
 		Limit:              1,
@@ -167,7 +191,7 @@ func (s *Service) EnsureOutboundWallet(ctx context.Context, bc kmswallet.Blockch
 		return entryToWallet(wallets[0]), false, nil
 	}
 
-	wallet, err := s.Create(ctx, bc, TypeOutbound)
+	wallet, err := s.Create(ctx, bc, walletType)
 	if err != nil {
 		return nil, false, errors.Wrap(err, "unable to create wallet")
 	}
@@ -201,6 +225,23 @@ func (s *Service) GetByUUID(ctx context.Context, id uuid.UUID) (*Wallet, error)
 	return entryToWallet(w), nil
 }
 
+// GetByAddress returns wallet by its blockchain address (case-insensitive).
+func (s *Service) GetByAddress(ctx context.Context, bc kmswallet.Blockchain, address string) (*Wallet, error) {
+	w, err := s.store.GetWalletByAddress(ctx, repository.GetWalletByAddressParams{
+		Blockchain: string(bc),
+		Address:    address,
+	})
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, ErrNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return entryToWallet(w), nil
+}
+
 func (s *Service) List(ctx context.Context, pagination Pagination) ([]*Wallet, *int64, error) {
 	results, err := s.store.PaginateWalletsByID(ctx, repository.PaginateWalletsByIDParams{
 		ID:                 pagination.Start,
@@ -227,6 +268,8 @@ func (s *Service) List(ctx context.Context, pagination Pagination) ([]*Wallet, *
 		Limit:              1,
 		FilterByBlockchain: pagination.FilterByBlockchain != "",
 		Blockchain:         string(pagination.FilterByBlockchain),
+		FilterByType:       pagination.FilterByType != "",
+		Type:               repository.StringToNullable(string(pagination.FilterByType)),
 	})
 
 	switch {
@@ -261,6 +261,92 @@ func (s *Service) loadUSDBalances(ctx context.Context, balances []*Balance) erro
 	return nil
 }
 
+// AmountAt restores balance amount at the given moment by reverting
+// all changes from the audit log that happened after it.
+func (s *Service) AmountAt(ctx context.Context, b *Balance, at time.Time) (money.Money, error) {
+	zero, err := money.New(b.Amount.Type(), b.Amount.Ticker(), "0", b.Amount.Decimals())
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	if at.Before(b.CreatedAt) {
+		return zero, nil
+	}
+
+	entries, err := s.store.ListBalanceAuditLogSince(ctx, repository.ListBalanceAuditLogSinceParams{
+		BalanceID: b.ID,
+		CreatedAt: at,
+	})
+	if err != nil {
+		return money.Money{}, errors.Wrap(err, "unable to list balance audit log")
+	}
+
+	amount := b.Amount
+
+	for _, entry := range entries {
+		metaData := make(MetaData)
+		if err := json.Unmarshal(entry.Metadata.Bytes, &metaData); err != nil {
+			return money.Money{}, errors.Wrapf(err, "unable to parse audit log entry %d", entry.ID)
+		}
+
+		change, err := money.New(b.Amount.Type(), b.Amount.Ticker(), metaData[MetaAmountRaw], b.Amount.Decimals())
+		if err != nil {
+			return money.Money{}, errors.Wrapf(err, "invalid amount in audit log entry %d", entry.ID)
+		}
+
+		switch BalanceOperation(metaData[MetaOperation]) {
+		case OperationIncrement:
+			amount, err = amount.Sub(change)
+		case OperationDecrement:
+			amount, err = amount.Add(change)
+		default:
+			err = errors.Errorf("unknown operation %q", metaData[MetaOperation])
+		}
+
+		if err != nil {
+			return money.Money{}, errors.Wrapf(err, "unable to revert audit log entry %d", entry.ID)
+		}
+	}
+
+	return amount, nil
+}
+
+// OldestUnspentIncrement returns time of the first balance increment that happened after
+// the latest decrement since the given moment, i.e. the oldest deposit that is still on the balance.
+// Returns false if balance wasn't incremented after its latest decrement.
+func (s *Service) OldestUnspentIncrement(ctx context.Context, b *Balance, since time.Time) (time.Time, bool, error) {
+	entries, err := s.store.ListBalanceAuditLogSince(ctx, repository.ListBalanceAuditLogSinceParams{
+		BalanceID: b.ID,
+		CreatedAt: since,
+	})
+	if err != nil {
+		return time.Time{}, false, errors.Wrap(err, "unable to list balance audit log")
+	}
+
+	var (
+		oldest time.Time
+		found  bool
+	)
+
+	for _, entry := range entries {
+		metaData := make(MetaData)
+		if err := json.Unmarshal(entry.Metadata.Bytes, &metaData); err != nil {
+			return time.Time{}, false, errors.Wrapf(err, "unable to parse audit log entry %d", entry.ID)
+		}
+
+		switch BalanceOperation(metaData[MetaOperation]) {
+		case OperationIncrement:
+			if !found {
+				oldest, found = entry.CreatedAt, true
+			}
+		case OperationDecrement:
+			oldest, found = time.Time{}, false
+		}
+	}
+
+	return oldest, found, nil
+}
+
 func (s *Service) GetMerchantBalanceByUUID(ctx context.Context, merchantID int64, balanceID uuid.UUID) (*Balance, error) {
 	return s.GetBalanceByUUID(ctx, EntityTypeMerchant, merchantID, balanceID)
 }
@@ -0,0 +1,159 @@
+package wallet
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oxygenpay/oxygen/internal/db/repository"
+	kmswallet "github.com/oxygenpay/oxygen/internal/kms/wallet"
+	kmsclient "github.com/oxygenpay/oxygen/pkg/api-kms/v1/client/wallet"
+	kmsmodel "github.com/oxygenpay/oxygen/pkg/api-kms/v1/model"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrAlreadyExists  = errors.New("wallet already exists")
+	ErrImportRejected = errors.New("wallet import rejected by KMS")
+)
+
+// Import imports externally generated wallet into KMS and registers it with provided type.
+// KMS checks that the key matches params.Address. Imported EVM wallet may already have on-chain history,
+// so its confirmed transaction counters are set to current mainnet & testnet nonces.
+func (s *Service) Import(ctx context.Context, params kmswallet.ImportParams, walletType Type) (*Wallet, error) {
+	if !params.Blockchain.IsValid() {
+		return nil, ErrInvalidBlockchain
+	}
+
+	if walletType != TypeOutbound && walletType != TypeInbound && walletType != TypeGas && walletType != TypeStaking {
+		return nil, ErrInvalidType
+	}
+
+	_, err := s.GetByAddress(ctx, params.Blockchain, params.Address)
+	switch {
+	case err == nil:
+		return nil, ErrAlreadyExists
+	case !errors.Is(err, ErrNotFound):
+		return nil, errors.Wrap(err, "unable to check existing wallet")
+	}
+
+	mainnetNonce, testnetNonce, err := s.resolveImportNonces(ctx, params.Blockchain, params.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.kms.ImportWallet(&kmsclient.ImportWalletParams{
+		Context: ctx,
+		Data: &kmsmodel.ImportWalletRequest{
+			Blockchain:         kmsmodel.Blockchain(params.Blockchain),
+			Address:            params.Address,
+			PrivateKey:         params.PrivateKey,
+			Mnemonic:           params.Mnemonic,
+			MnemonicPassphrase: params.MnemonicPassphrase,
+			DerivationPath:     params.DerivationPath,
+		},
+	})
+
+	var badRequest *kmsclient.ImportWalletBadRequest
+
+	switch {
+	case errors.As(err, &badRequest):
+		return nil, errors.Wrap(ErrImportRejected, kmsErrorMessage(badRequest.Payload))
+	case err != nil:
+		return nil, errors.Wrap(err, "unable to import wallet to KMS")
+	}
+
+	var entry repository.Wallet
+
+	err = s.store.RunTransaction(ctx, func(ctx context.Context, q repository.Querier) error {
+		entry, err = q.CreateWallet(ctx, repository.CreateWalletParams{
+			CreatedAt:  time.Now(),
+			Uuid:       uuid.MustParse(res.Payload.ID),
+			Address:    res.Payload.Address,
+			Blockchain: string(res.Payload.Blockchain),
+			Type:       repository.StringToNullable(string(walletType)),
+		})
+		if err != nil {
+			return err
+		}
+
+		if mainnetNonce == 0 && testnetNonce == 0 {
+			return nil
+		}
+
+		err = q.UpdateWalletMainnetTransactionCounters(ctx, repository.UpdateWalletMainnetTransactionCountersParams{
+			ID:                           entry.ID,
+			ConfirmedMainnetTransactions: mainnetNonce,
+		})
+		if err != nil {
+			return err
+		}
+
+		entry.ConfirmedMainnetTransactions = mainnetNonce
+
+		err = q.UpdateWalletTestnetTransactionCounters(ctx, repository.UpdateWalletTestnetTransactionCountersParams{
+			ID:                           entry.ID,
+			ConfirmedTestnetTransactions: testnetNonce,
+		})
+		if err != nil {
+			return err
+		}
+
+		entry.ConfirmedTestnetTransactions = testnetNonce
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to persist wallet")
+	}
+
+	s.logger.Info().
+		Int64("wallet_id", entry.ID).
+		Str("blockchain", entry.Blockchain).
+		Str("wallet_type", string(walletType)).
+		Int64("mainnet_nonce", entry.ConfirmedMainnetTransactions).
+		Int64("testnet_nonce", entry.ConfirmedTestnetTransactions).
+		Msg("imported wallet")
+
+	return entryToWallet(entry), nil
+}
+
+// resolveImportNonces returns pending on-chain nonces of EVM address (0 for other blockchains).
+// Pending nonce includes address's transactions that are still in the mempool, so they are not reused.
+func (s *Service) resolveImportNonces(
+	ctx context.Context,
+	chain kmswallet.Blockchain,
+	address string,
+) (mainnet, testnet int64, err error) {
+	switch chain {
+	case kmswallet.ETH, kmswallet.MATIC, kmswallet.BSC:
+	default:
+		return 0, 0, nil
+	}
+
+	blockchain := chain.ToMoneyBlockchain()
+
+	mainnetNonce, err := s.blockchain.GetNonce(ctx, blockchain, address, false)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "unable to get mainnet nonce of imported wallet")
+	}
+
+	testnetNonce, err := s.blockchain.GetNonce(ctx, blockchain, address, true)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "unable to get testnet nonce of imported wallet")
+	}
+
+	return int64(mainnetNonce.Pending), int64(testnetNonce.Pending), nil
+}
+
+func kmsErrorMessage(res *kmsmodel.ErrorResponse) string {
+	switch {
+	case res == nil:
+		return "unknown error"
+	case len(res.Errors) > 0 && res.Errors[0].Message != "":
+		return res.Errors[0].Message
+	default:
+		return res.Message
+	}
+}
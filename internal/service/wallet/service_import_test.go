@@ -0,0 +1,95 @@
+package wallet_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	kmswallet "github.com/oxygenpay/oxygen/internal/kms/wallet"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/wallet"
+	"github.com/oxygenpay/oxygen/internal/test"
+	kmsclient "github.com/oxygenpay/oxygen/pkg/api-kms/v1/client/wallet"
+	kmsmodel "github.com/oxygenpay/oxygen/pkg/api-kms/v1/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Import(t *testing.T) {
+	tc := test.NewIntegrationTest(t)
+
+	mockImport := func(blockchain kmswallet.Blockchain, address string) {
+		tc.Providers.KMS.
+			On("ImportWallet", mock.Anything).
+			Return(&kmsclient.ImportWalletCreated{Payload: &kmsmodel.Wallet{
+				ID:         uuid.New().String(),
+				Address:    address,
+				Blockchain: kmsmodel.Blockchain(blockchain),
+			}}, nil).
+			Once()
+	}
+
+	t.Run("Sets transaction counters of EVM wallet to on-chain nonces", func(t *testing.T) {
+		// ARRANGE
+		// Given treasury wallet with on-chain history
+		const address = "0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5"
+
+		tc.Fakes.SetupGetNonce(kmswallet.ETH.ToMoneyBlockchain(), address, false, blockchain.Nonce{Latest: 41, Pending: 42}, nil)
+		tc.Fakes.SetupGetNonce(kmswallet.ETH.ToMoneyBlockchain(), address, true, blockchain.Nonce{Latest: 3, Pending: 3}, nil)
+		mockImport(kmswallet.ETH, address)
+
+		// ACT
+		w, err := tc.Services.Wallet.Import(
+			tc.Context,
+			kmswallet.ImportParams{Blockchain: kmswallet.ETH, Address: address, PrivateKey: "0xabc"},
+			wallet.TypeOutbound,
+		)
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), w.ConfirmedMainnetTransactions)
+		assert.Equal(t, int64(3), w.ConfirmedTestnetTransactions)
+
+		fresh, err := tc.Services.Wallet.GetByID(tc.Context, w.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), fresh.ConfirmedMainnetTransactions)
+		assert.Equal(t, int64(0), fresh.PendingMainnetTransactions)
+		assert.Equal(t, int64(3), fresh.ConfirmedTestnetTransactions)
+
+		// Check that next withdrawal uses next nonce
+		nonce, err := tc.Services.Wallet.IncrementPendingTransaction(tc.Context, w.ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, 42, nonce)
+	})
+
+	t.Run("Doesn't resolve nonce of non-EVM wallet", func(t *testing.T) {
+		// ARRANGE
+		const address = "TJRabPrwbZy45sbavfcjinPJC18kjpRTv8"
+		mockImport(kmswallet.TRON, address)
+
+		// ACT
+		w, err := tc.Services.Wallet.Import(
+			tc.Context,
+			kmswallet.ImportParams{Blockchain: kmswallet.TRON, Address: address, PrivateKey: "0xabc"},
+			wallet.TypeOutbound,
+		)
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Zero(t, w.ConfirmedMainnetTransactions)
+		assert.Zero(t, w.ConfirmedTestnetTransactions)
+	})
+
+	t.Run("Fails if nonce can't be resolved", func(t *testing.T) {
+		// ACT
+		// GetNonce is not mocked for this address
+		_, err := tc.Services.Wallet.Import(
+			tc.Context,
+			kmswallet.ImportParams{Blockchain: kmswallet.BSC, Address: "0x00000000219ab540356cbb839cbe05303d7705fa"},
+			wallet.TypeOutbound,
+		)
+
+		// ASSERT
+		assert.Error(t, err)
+	})
+}
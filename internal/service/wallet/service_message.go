@@ -0,0 +1,56 @@
+package wallet
+
+import (
+	"context"
+	"time"
+
+	kms "github.com/oxygenpay/oxygen/internal/kms/wallet"
+	kmsclient "github.com/oxygenpay/oxygen/pkg/api-kms/v1/client/wallet"
+	kmsmodel "github.com/oxygenpay/oxygen/pkg/api-kms/v1/model"
+	"github.com/pkg/errors"
+)
+
+var ErrMessageRejected = errors.New("message signing rejected by KMS")
+
+// SignMessage asks KMS to sign proof of address ownership for the challenge. KMS builds the message itself,
+// checks that the wallet is allowed to sign messages and records each signature in its audit log.
+func (s *Service) SignMessage(ctx context.Context, w *Wallet, params kms.SignMessageParams) (kms.SignedMessage, error) {
+	res, err := s.kms.SignMessage(&kmsclient.SignMessageParams{
+		Context:  ctx,
+		WalletID: w.UUID.String(),
+		Data: &kmsmodel.SignMessageRequest{
+			Type:      string(params.Type),
+			Challenge: params.Challenge,
+		},
+	})
+
+	var (
+		badRequest *kmsclient.SignMessageBadRequest
+		forbidden  *kmsclient.SignMessageForbidden
+	)
+
+	switch {
+	case errors.As(err, &badRequest):
+		return kms.SignedMessage{}, errors.Wrap(ErrMessageRejected, kmsErrorMessage(badRequest.Payload))
+	case errors.As(err, &forbidden):
+		return kms.SignedMessage{}, errors.Wrap(ErrMessageRejected, kmsErrorMessage(forbidden.Payload))
+	case err != nil:
+		return kms.SignedMessage{}, errors.Wrap(err, "unable to sign message")
+	}
+
+	s.logger.Info().
+		Int64("wallet_id", w.ID).
+		Str("blockchain", w.Blockchain.String()).
+		Str("message_type", string(params.Type)).
+		Str("challenge", params.Challenge).
+		Str("message_hash", res.Payload.Hash).
+		Msg("signed message")
+
+	return kms.SignedMessage{
+		Address:   res.Payload.Address,
+		Message:   res.Payload.Message,
+		Timestamp: time.Unix(res.Payload.Timestamp, 0).UTC(),
+		Hash:      res.Payload.Hash,
+		Signature: res.Payload.Signature,
+	}, nil
+}
@@ -8,10 +8,20 @@ import (
 )
 
 var (
-	ErrTxConfirm  = errors.New("nothing to confirm")
-	ErrTxRollback = errors.New("nothing to rollback")
+	ErrTxConfirm         = errors.New("nothing to confirm")
+	ErrTxRollback        = errors.New("nothing to rollback")
+	ErrTxCountersChanged = errors.New("transaction counters were changed")
 )
 
+// TransactionCounters returns amount of confirmed & pending transactions. Their sum is the nonce for the next tx.
+func (w *Wallet) TransactionCounters(isTest bool) (confirmed, pending int64) {
+	if isTest {
+		return w.ConfirmedTestnetTransactions, w.PendingTestnetTransactions
+	}
+
+	return w.ConfirmedMainnetTransactions, w.PendingMainnetTransactions
+}
+
 // IncrementPendingTransaction updates Wallet's nonce parameter and returns nonce for next tx.
 func (s *Service) IncrementPendingTransaction(ctx context.Context, walletID int64, isTest bool) (int, error) {
 	var nonce int
@@ -105,3 +115,45 @@ func (s *Service) DecrementPendingTransaction(ctx context.Context, walletID int6
 		})
 	})
 }
+
+// SetTransactionCounters overrides wallet's transaction counters. Used for nonce reconciliation with the blockchain.
+// expectedNonce guards against concurrent updates: if wallet's nonce differs, ErrTxCountersChanged is returned.
+func (s *Service) SetTransactionCounters(
+	ctx context.Context,
+	walletID int64,
+	isTest bool,
+	expectedNonce, confirmed, pending int64,
+) error {
+	if confirmed < 0 || pending < 0 {
+		return errors.New("transaction counters should not be negative")
+	}
+
+	return s.store.RunTransaction(ctx, func(ctx context.Context, q repository.Querier) error {
+		w, err := q.GetWalletForUpdateByID(ctx, walletID)
+		if err != nil {
+			return errors.Wrap(err, "unable to get wallet for update")
+		}
+
+		if isTest {
+			if w.ConfirmedTestnetTransactions+w.PendingTestnetTransactions != expectedNonce {
+				return ErrTxCountersChanged
+			}
+
+			return q.UpdateWalletTestnetTransactionCounters(ctx, repository.UpdateWalletTestnetTransactionCountersParams{
+				ID:                           walletID,
+				ConfirmedTestnetTransactions: confirmed,
+				PendingTestnetTransactions:   pending,
+			})
+		}
+
+		if w.ConfirmedMainnetTransactions+w.PendingMainnetTransactions != expectedNonce {
+			return ErrTxCountersChanged
+		}
+
+		return q.UpdateWalletMainnetTransactionCounters(ctx, repository.UpdateWalletMainnetTransactionCountersParams{
+			ID:                           walletID,
+			ConfirmedMainnetTransactions: confirmed,
+			PendingMainnetTransactions:   pending,
+		})
+	})
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/pkg/errors"
 )
 
+// CreateSignedTransaction signs transaction using the next wallet's nonce.
+// Returns raw transaction and the nonce that was used.
 func (s *Service) CreateSignedTransaction(
 	ctx context.Context,
 	sender *Wallet,
@@ -22,10 +24,10 @@ func (s *Service) CreateSignedTransaction(
 	amount money.Money,
 	fee blockchain.Fee,
 	isTest bool,
-) (string, error) {
+) (string, int64, error) {
 	nonce, err := s.IncrementPendingTransaction(ctx, sender.ID, isTest)
 	if err != nil {
-		return "", errors.Wrap(err, "unable to increment pending transactions counter")
+		return "", 0, errors.Wrap(err, "unable to increment pending transactions counter")
 	}
 
 	txRaw, errCreate := s.createSignedTransaction(
@@ -41,11 +43,30 @@ func (s *Service) CreateSignedTransaction(
 
 	if errCreate != nil {
 		if err := s.DecrementPendingTransaction(ctx, sender.ID, isTest); err != nil {
-			return "", errors.Wrap(err, "unable to decrement pending transactions counter")
+			return "", 0, errors.Wrap(err, "unable to decrement pending transactions counter")
 		}
 	}
 
-	return txRaw, errCreate
+	return txRaw, int64(nonce), errCreate
+}
+
+// CreateReplacementTransaction signs transaction with already used nonce. It's used for replacing
+// stuck transaction with the one that has higher fee, so pending transactions counter stays the same.
+func (s *Service) CreateReplacementTransaction(
+	ctx context.Context,
+	sender *Wallet,
+	recipient string,
+	currency money.CryptoCurrency,
+	amount money.Money,
+	fee blockchain.Fee,
+	nonce int64,
+	isTest bool,
+) (string, error) {
+	if currency.Blockchain == kms.TRON.ToMoneyBlockchain() {
+		return "", errors.Wrap(blockchain.ErrReplacementUnsupported, currency.Ticker)
+	}
+
+	return s.createSignedTransaction(ctx, sender, recipient, currency, amount, fee, nonce, isTest)
 }
 
 //nolint:gocyclo
@@ -194,3 +215,41 @@ func (s *Service) createSignedTransaction(
 
 	return "", errors.New("unsupported currency " + currency.Ticker)
 }
+
+// CreateTronResourceTransaction signs TRON staking transaction (freeze, delegate or undelegate).
+// Amount is in SUN, receiver is ignored for freeze operation.
+func (s *Service) CreateTronResourceTransaction(
+	ctx context.Context,
+	sender *Wallet,
+	operation kms.TronResourceOperation,
+	amount int64,
+	receiver string,
+	isTest bool,
+) (string, error) {
+	if sender.Blockchain != kms.TRON {
+		return "", errors.Wrap(ErrInvalidBlockchain, "TRON wallet is expected")
+	}
+
+	res, err := s.kms.CreateTronResourceTransaction(&kmsclient.CreateTronResourceTransactionParams{
+		Context:  ctx,
+		WalletID: sender.UUID.String(),
+		Data: &kmsmodel.CreateTronResourceTransactionRequest{
+			Amount:    amount,
+			IsTest:    isTest,
+			Operation: string(operation),
+			Receiver:  receiver,
+			Resource:  kmsmodel.CreateTronResourceTransactionRequestResourceENERGY,
+		},
+	})
+
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to create TRON %s transaction", operation)
+	}
+
+	resAsBytes, err := json.Marshal(res.Payload)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to marshal TRON transaction")
+	}
+
+	return string(resAsBytes), nil
+}
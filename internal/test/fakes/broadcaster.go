@@ -20,6 +20,7 @@ type Broadcaster struct {
 	mu         sync.RWMutex
 	broadcasts map[string]lo.Tuple2[string, error]
 	receipts   map[string]lo.Tuple2[*blockchain.TransactionReceipt, error]
+	nonces     map[string]lo.Tuple2[blockchain.Nonce, error]
 }
 
 func newBroadcaster(t *testing.T) *Broadcaster {
@@ -27,6 +28,7 @@ func newBroadcaster(t *testing.T) *Broadcaster {
 		t:          t,
 		broadcasts: make(map[string]lo.Tuple2[string, error]),
 		receipts:   map[string]lo.Tuple2[*blockchain.TransactionReceipt, error]{},
+		nonces:     map[string]lo.Tuple2[blockchain.Nonce, error]{},
 	}
 }
 
@@ -62,6 +64,37 @@ func (m *Broadcaster) GetTransactionReceipt(
 	return res.A, res.B
 }
 
+func (m *Broadcaster) GetNonce(
+	_ context.Context, chain money.Blockchain, address string, isTest bool,
+) (blockchain.Nonce, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key := m.receiptKey(chain, address, isTest)
+
+	res, exists := m.nonces[key]
+	if !exists {
+		return blockchain.Nonce{}, errors.New("unexpected call of (*BroadcasterMock).GetNonce with args " + key)
+	}
+
+	return res.A, res.B
+}
+
+// RequiredConfirmations returns zero so tests rely on receipt's IsConfirmed flag.
+func (m *Broadcaster) RequiredConfirmations(_ money.Blockchain, _ money.Money) int64 {
+	return 0
+}
+
+// CrossCheckTransactionReceipt treats all receipts as consistent.
+func (m *Broadcaster) CrossCheckTransactionReceipt(_ context.Context, _ *blockchain.TransactionReceipt, _ money.Money) error {
+	return nil
+}
+
+// CrossCheckTransactionMissing treats missing transactions as confirmed by all endpoints.
+func (m *Broadcaster) CrossCheckTransactionMissing(_ context.Context, _ money.Blockchain, _ string, _ bool) error {
+	return nil
+}
+
 func (m *Broadcaster) SetupBroadcastTransaction(
 	chain money.Blockchain,
 	rawTransaction string,
@@ -94,6 +127,19 @@ func (m *Broadcaster) SetupGetTransactionReceipt(
 	m.receipts[m.receiptKey(chain, txID, isTest)] = lo.T2(receipt, err)
 }
 
+func (m *Broadcaster) SetupGetNonce(
+	chain money.Blockchain,
+	address string,
+	isTest bool,
+	nonce blockchain.Nonce,
+	err error,
+) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nonces[m.receiptKey(chain, address, isTest)] = lo.T2(nonce, err)
+}
+
 func (m *Broadcaster) broadcastKey(chain money.Blockchain, raw string, isTest bool) string {
 	return fmt.Sprintf("%s/%s/%t", chain.String(), raw, isTest)
 }
@@ -12,6 +12,7 @@ import (
 type Fakes struct {
 	*Broadcaster
 	*FeeCalculator
+	*ResourceResolver
 	*ConvertorProxy
 	*blockchain.CurrencyResolver
 	*Bus
@@ -22,6 +23,7 @@ func New(t *testing.T, blockchainService *blockchain.Service) *Fakes {
 	return &Fakes{
 		Broadcaster:      newBroadcaster(t),
 		FeeCalculator:    newFeeCalculator(t),
+		ResourceResolver: newResourceResolver(t),
 		ConvertorProxy:   newConvertorProxy(blockchainService),
 		CurrencyResolver: blockchainService.CurrencyResolver,
 		Bus:              &Bus{},
@@ -18,6 +18,7 @@ type FeeCalculator struct {
 	t              *testing.T
 	mu             sync.RWMutex
 	fees           map[string]blockchain.Fee
+	senderFees     map[string]blockchain.Fee
 	withdrawalFees map[string]money.Money
 }
 
@@ -25,6 +26,7 @@ func newFeeCalculator(t *testing.T) *FeeCalculator {
 	return &FeeCalculator{
 		t:              t,
 		fees:           make(map[string]blockchain.Fee),
+		senderFees:     make(map[string]blockchain.Fee),
 		withdrawalFees: make(map[string]money.Money),
 	}
 }
@@ -45,6 +47,24 @@ func (m *FeeCalculator) CalculateFee(
 	return blockchain.Fee{}, errors.New("unexpected call of (*FeeCalculatorMock).CalculateFee for " + key)
 }
 
+// CalculateSenderFee returns fee set up for specific sender. Falls back to CalculateFee otherwise.
+func (m *FeeCalculator) CalculateSenderFee(
+	ctx context.Context,
+	baseCurrency, currency money.CryptoCurrency,
+	sender string,
+	isTest bool,
+) (blockchain.Fee, error) {
+	m.mu.RLock()
+	fee, ok := m.senderFees[m.senderKey(baseCurrency, currency, sender, isTest)]
+	m.mu.RUnlock()
+
+	if ok {
+		return fee, nil
+	}
+
+	return m.CalculateFee(ctx, baseCurrency, currency, isTest)
+}
+
 func (m *FeeCalculator) CalculateWithdrawalFeeUSD(
 	_ context.Context,
 	baseCurrency, currency money.CryptoCurrency,
@@ -72,6 +92,18 @@ func (m *FeeCalculator) SetupCalculateFee(
 	m.fees[m.key(baseCurrency, currency, isTest)] = fee
 }
 
+func (m *FeeCalculator) SetupCalculateSenderFee(
+	baseCurrency, currency money.CryptoCurrency,
+	sender string,
+	isTest bool,
+	fee blockchain.Fee,
+) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.senderFees[m.senderKey(baseCurrency, currency, sender, isTest)] = fee
+}
+
 func (m *FeeCalculator) SetupCalculateWithdrawalFeeUSD(
 	baseCurrency, currency money.CryptoCurrency,
 	isTest bool,
@@ -178,6 +210,10 @@ func (m *FeeCalculator) SetupAllFees(t *testing.T, service *blockchain.Service)
 		FeeLimitSun: 3500000,
 		FeeLimitTRX: "0.35",
 		FeeLimitUSD: "0.02",
+
+		EstimatedSun: 3500000,
+		EstimatedTRX: "0.35",
+		EstimatedUSD: "0.02",
 	}
 
 	m.SetupCalculateFee(tron, tron, false, blockchain.NewFee(tron, now, false, tronFee))
@@ -195,3 +231,7 @@ func (m *FeeCalculator) SetupAllFees(t *testing.T, service *blockchain.Service)
 func (m *FeeCalculator) key(baseCurrency, currency money.CryptoCurrency, isTest bool) string {
 	return fmt.Sprintf("%s/%s/test:%t", baseCurrency.Ticker, currency.Ticker, isTest)
 }
+
+func (m *FeeCalculator) senderKey(baseCurrency, currency money.CryptoCurrency, sender string, isTest bool) string {
+	return m.key(baseCurrency, currency, isTest) + "/" + sender
+}
@@ -0,0 +1,74 @@
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
+	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+type ResourceResolver struct {
+	t         *testing.T
+	mu        sync.RWMutex
+	resources map[string]lo.Tuple2[trongrid.AccountResources, error]
+	staking   map[string]lo.Tuple2[blockchain.TronStaking, error]
+}
+
+func newResourceResolver(t *testing.T) *ResourceResolver {
+	return &ResourceResolver{
+		t:         t,
+		resources: map[string]lo.Tuple2[trongrid.AccountResources, error]{},
+		staking:   map[string]lo.Tuple2[blockchain.TronStaking, error]{},
+	}
+}
+
+func (m *ResourceResolver) GetTronResources(_ context.Context, address string, isTest bool) (trongrid.AccountResources, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key := m.key(address, isTest)
+
+	res, exists := m.resources[key]
+	if !exists {
+		return trongrid.AccountResources{}, errors.New("unexpected call of (*ResourceResolverMock).GetTronResources with args " + key)
+	}
+
+	return res.A, res.B
+}
+
+func (m *ResourceResolver) GetTronStaking(_ context.Context, address string, isTest bool) (blockchain.TronStaking, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key := m.key(address, isTest)
+
+	res, exists := m.staking[key]
+	if !exists {
+		return blockchain.TronStaking{}, errors.New("unexpected call of (*ResourceResolverMock).GetTronStaking with args " + key)
+	}
+
+	return res.A, res.B
+}
+
+func (m *ResourceResolver) SetupGetTronResources(address string, isTest bool, res trongrid.AccountResources, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.resources[m.key(address, isTest)] = lo.T2(res, err)
+}
+
+func (m *ResourceResolver) SetupGetTronStaking(address string, isTest bool, staking blockchain.TronStaking, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.staking[m.key(address, isTest)] = lo.T2(staking, err)
+}
+
+func (m *ResourceResolver) key(address string, isTest bool) string {
+	return fmt.Sprintf("%s/%t", address, isTest)
+}
@@ -15,6 +15,14 @@ type Trongrid struct {
 	mu                    sync.Mutex
 	txRequests            map[trongrid.TransactionRequest]trongrid.Transaction
 	contractCallsRequests map[trongrid.ContractCallRequest]trongrid.Transaction
+	freezeRequests        map[trongrid.FreezeBalanceRequest]trongrid.Transaction
+	delegateRequests      map[delegateRequest]trongrid.Transaction
+}
+
+// delegateRequest delegate / undelegate request
+type delegateRequest struct {
+	trongrid.DelegateResourceRequest
+	undelegate bool
 }
 
 // contractCallParameter every TRC-20 call generates unique hex-encoded ABI string.
@@ -26,6 +34,8 @@ func NewTrongrid(logger *zerolog.Logger) (*trongrid.Provider, *Trongrid) {
 		mu:                    sync.Mutex{},
 		txRequests:            make(map[trongrid.TransactionRequest]trongrid.Transaction),
 		contractCallsRequests: make(map[trongrid.ContractCallRequest]trongrid.Transaction),
+		freezeRequests:        make(map[trongrid.FreezeBalanceRequest]trongrid.Transaction),
+		delegateRequests:      make(map[delegateRequest]trongrid.Transaction),
 	}
 
 	e := echo.New()
@@ -33,6 +43,9 @@ func NewTrongrid(logger *zerolog.Logger) (*trongrid.Provider, *Trongrid) {
 
 	e.POST("/wallet/createtransaction", mock.createTransaction)
 	e.POST("/wallet/triggersmartcontract", mock.triggerSmartContract)
+	e.POST("/wallet/freezebalancev2", mock.freezeBalance)
+	e.POST("/wallet/delegateresource", mock.delegateResource(false))
+	e.POST("/wallet/undelegateresource", mock.delegateResource(true))
 
 	srv := httptest.NewServer(e)
 
@@ -61,6 +74,20 @@ func (m *Trongrid) SetupTriggerSmartContract(req trongrid.ContractCallRequest, r
 	m.contractCallsRequests[req] = res
 }
 
+func (m *Trongrid) SetupFreezeBalance(req trongrid.FreezeBalanceRequest, res trongrid.Transaction) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.freezeRequests[req] = res
+}
+
+func (m *Trongrid) SetupDelegateResource(req trongrid.DelegateResourceRequest, undelegate bool, res trongrid.Transaction) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.delegateRequests[delegateRequest{DelegateResourceRequest: req, undelegate: undelegate}] = res
+}
+
 func (m *Trongrid) createTransaction(c echo.Context) error {
 	var req trongrid.TransactionRequest
 	if err := c.Bind(&req); err != nil {
@@ -106,3 +133,39 @@ func (m *Trongrid) triggerSmartContract(c echo.Context) error {
 		Transaction: tx,
 	})
 }
+
+func (m *Trongrid) freezeBalance(c echo.Context) error {
+	var req trongrid.FreezeBalanceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusOK, trongrid.Transaction{Error: "INVALID REQUEST"})
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	response, exists := m.freezeRequests[req]
+	if !exists {
+		response = trongrid.Transaction{Error: "UNEXPECTED CALL"}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (m *Trongrid) delegateResource(undelegate bool) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req trongrid.DelegateResourceRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusOK, trongrid.Transaction{Error: "INVALID REQUEST"})
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		response, exists := m.delegateRequests[delegateRequest{DelegateResourceRequest: req, undelegate: undelegate}]
+		if !exists {
+			response = trongrid.Transaction{Error: "UNEXPECTED CALL"}
+		}
+
+		return c.JSON(http.StatusOK, response)
+	}
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/oxygenpay/oxygen/internal/lock"
 	"github.com/oxygenpay/oxygen/internal/log"
 	"github.com/oxygenpay/oxygen/internal/money"
+	"github.com/oxygenpay/oxygen/internal/provider/notify"
+	"github.com/oxygenpay/oxygen/internal/provider/rates"
 	tatumprovider "github.com/oxygenpay/oxygen/internal/provider/tatum"
 	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
 	httpServer "github.com/oxygenpay/oxygen/internal/server/http"
@@ -25,6 +27,7 @@ import (
 	"github.com/oxygenpay/oxygen/internal/server/http/paymentapi"
 	"github.com/oxygenpay/oxygen/internal/server/http/webhook"
 	"github.com/oxygenpay/oxygen/internal/service/blockchain"
+	"github.com/oxygenpay/oxygen/internal/service/exchange"
 	"github.com/oxygenpay/oxygen/internal/service/merchant"
 	"github.com/oxygenpay/oxygen/internal/service/payment"
 	"github.com/oxygenpay/oxygen/internal/service/processing"
@@ -67,6 +70,7 @@ type Services struct {
 	Merchants        *merchant.Service
 	Users            *user.Service
 	Wallet           *wallet.Service
+	Exchange         *exchange.Service
 	Payment          *payment.Service
 	Transaction      *transaction.Service
 	Blockchain       *blockchain.Service
@@ -89,6 +93,20 @@ var processingConfig = processing.Config{
 	PaymentFrontendBasePath: "https://pay.o2pay.co",
 	PaymentFrontendSubPath:  "/",
 	DefaultServiceFee:       0.015, // 1.5%
+	ReorgCheckDepth:         50,
+	ReorgOrphanChecks:       2,
+	StuckTransactionTimeout: 15 * time.Minute,
+	MaxFeeBumps:             3,
+
+	TronOutboundEnergyTransfers: 2,
+
+	SweepPolicies: map[string]processing.SweepPolicy{
+		"BSC": {MaxGasPriceGwei: 50, MaxFeeRatio: 0.05, Deadline: 72 * time.Hour},
+	},
+
+	ColdWallets: map[string]processing.ColdWallet{
+		"ETH_USDT": {Address: "0x690b9a9e9aa1c9db991c7721a92d351db4fac990", HotTarget: "1000"},
+	},
 }
 
 func NewIntegrationTest(t *testing.T) *IntegrationTest {
@@ -124,10 +142,12 @@ func NewIntegrationTest(t *testing.T) *IntegrationTest {
 	}
 
 	blockchainService := blockchain.New(
+		blockchain.Config{},
 		currencies,
 		blockchain.Providers{
 			Tatum:    tatumProvider,
 			Trongrid: trongridProvider,
+			Rates:    exchange.NewRecorder(rates.NewTatum(tatumProvider), repo),
 		},
 		false,
 		&logger,
@@ -140,7 +160,8 @@ func NewIntegrationTest(t *testing.T) *IntegrationTest {
 	authTokenManager := auth.NewTokenAuth(repo, &logger)
 	merchantsService := merchant.New(repo, blockchainService, &logger)
 	usersService := user.New(storage, globalFaker.Bus, kv, &logger)
-	walletsService := wallet.New(kmsWalletsClient, globalFaker.ConvertorProxy, storage, &logger)
+	walletsService := wallet.New(kmsWalletsClient, globalFaker, storage, &logger)
+	exchangeService := exchange.New(repo, walletsService, &logger)
 	transactionsService := transaction.New(storage, globalFaker.CurrencyResolver, walletsService, &logger)
 
 	paymentsService := payment.New(
@@ -149,6 +170,7 @@ func NewIntegrationTest(t *testing.T) *IntegrationTest {
 		transactionsService,
 		merchantsService,
 		walletsService,
+		exchangeService,
 		globalFaker,
 		globalFaker,
 		&logger,
@@ -162,6 +184,7 @@ func NewIntegrationTest(t *testing.T) *IntegrationTest {
 		transactionsService,
 		globalFaker,
 		tatumProvider,
+		notify.NewRegistry(),
 		globalFaker.Bus,
 		locker,
 		&logger,
@@ -178,6 +201,7 @@ func NewIntegrationTest(t *testing.T) *IntegrationTest {
 		authTokenManager,
 		paymentsService,
 		walletsService,
+		exchangeService,
 		globalFaker,
 		globalFaker.Bus,
 		&logger,
@@ -225,7 +249,7 @@ func NewIntegrationTest(t *testing.T) *IntegrationTest {
 		httpServer.WithMerchantAPI(merchantAPIHandler, authTokenManager),
 		httpServer.WithPaymentAPI(paymentAPIHandler, webConfig),
 		httpServer.WithWebhookAPI(webhookHandler),
-		kmsapi.SetupRoutes(kmsapi.New(kms.Service, &logger)),
+		kmsapi.SetupRoutes(kmsapi.New(kms.Service, kms.AuditLog, nil, &logger)),
 	)
 
 	tc := &IntegrationTest{
@@ -241,6 +265,7 @@ func NewIntegrationTest(t *testing.T) *IntegrationTest {
 			Merchants:        merchantsService,
 			Users:            usersService,
 			Wallet:           walletsService,
+			Exchange:         exchangeService,
 			Payment:          paymentsService,
 			Processing:       processingService,
 			Transaction:      transactionsService,
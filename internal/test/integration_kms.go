@@ -4,29 +4,27 @@ import (
 	cryptorand "crypto/rand"
 	"testing"
 
-	"github.com/oxygenpay/oxygen/internal/db/connection/bolt"
+	"github.com/oxygenpay/oxygen/internal/kms/audit"
+	"github.com/oxygenpay/oxygen/internal/kms/encryption"
+	"github.com/oxygenpay/oxygen/internal/kms/storage"
 	"github.com/oxygenpay/oxygen/internal/kms/wallet"
 	"github.com/oxygenpay/oxygen/internal/provider/trongrid"
 	"github.com/rs/zerolog"
-	"go.etcd.io/bbolt"
 )
 
 type KMS struct {
-	Bolt       *bbolt.DB
+	Store      storage.Store
 	Repository *wallet.Repository
 	Service    *wallet.Service
+	AuditLog   *audit.Log
 }
 
 func setupKMS(t *testing.T, trongridProvider *trongrid.Provider, logger *zerolog.Logger) *KMS {
 	dataSource := t.TempDir() + "/kms.test.db"
 
-	conn, err := bolt.Open(bolt.Config{DataSource: dataSource}, logger)
+	store, err := storage.Open(storage.Config{Type: storage.Bolt, Path: dataSource}, logger)
 	if err != nil {
-		t.Fatalf("unable to connect to bolt db: %s", err)
-	}
-
-	if err := conn.LoadBuckets(); err != nil {
-		t.Fatalf("unable to load kms bolt db buckets: %s", err)
+		t.Fatalf("unable to open kms store: %s", err)
 	}
 
 	walletGenerator :=
@@ -41,11 +39,37 @@ func setupKMS(t *testing.T, trongridProvider *trongrid.Provider, logger *zerolog
 				Trongrid:     trongridProvider,
 			})
 
-	repo := wallet.NewRepository(conn.DB())
+	masterKey := make([]byte, 32)
+	if _, err := cryptorand.Read(masterKey); err != nil {
+		t.Fatalf("unable to generate kms master key: %s", err)
+	}
+
+	keyring, err := encryption.NewKeyring(masterKey)
+	if err != nil {
+		t.Fatalf("unable to create kms keyring: %s", err)
+	}
+
+	repo := wallet.NewRepository(store, keyring)
+	if err := repo.VerifyKeyring(); err != nil {
+		t.Fatalf("unable to verify kms keyring: %s", err)
+	}
+
+	seed, err := wallet.GenerateHDSeed(cryptorand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate kms HD seed: %s", err)
+	}
+
+	keychain, err := wallet.NewHDKeychain(seed)
+	if err != nil {
+		t.Fatalf("unable to create kms HD keychain: %s", err)
+	}
+
+	auditLog := audit.New(store, []byte("audit-test-key"))
 
 	return &KMS{
-		Bolt:       conn.DB(),
+		Store:      store,
 		Repository: repo,
-		Service:    wallet.New(repo, walletGenerator, logger),
+		Service:    wallet.New(repo, walletGenerator, keychain, nil, auditLog, logger),
+		AuditLog:   auditLog,
 	}
 }
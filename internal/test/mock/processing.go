@@ -22,11 +22,14 @@ type ProcessingProxyMock struct {
 	service                    *processing.Service
 	mu                         sync.RWMutex
 	incomingCheckCalls         map[string]error
+	gasFundingCalls            map[string]lo.Tuple2[*processing.GasFundingResult, error]
 	internalTransferCalls      map[string]lo.Tuple2[*processing.TransferResult, error]
 	internalTransferCheckCalls map[string]error
 	withdrawalTransferCalls    map[string]lo.Tuple2[*processing.TransferResult, error]
 	withdrawalCheckCalls       map[string]error
 	expirationCheckCalls       map[string]error
+	reorgCheckCalls            map[string]error
+	stuckCheckCalls            map[string]error
 }
 
 func NewProcessingProxyMock(t *testing.T, service *processing.Service) *ProcessingProxyMock {
@@ -34,11 +37,14 @@ func NewProcessingProxyMock(t *testing.T, service *processing.Service) *Processi
 		t:                          t,
 		service:                    service,
 		incomingCheckCalls:         map[string]error{},
+		gasFundingCalls:            map[string]lo.Tuple2[*processing.GasFundingResult, error]{},
 		internalTransferCalls:      map[string]lo.Tuple2[*processing.TransferResult, error]{},
 		internalTransferCheckCalls: map[string]error{},
 		withdrawalTransferCalls:    map[string]lo.Tuple2[*processing.TransferResult, error]{},
 		withdrawalCheckCalls:       map[string]error{},
 		expirationCheckCalls:       map[string]error{},
+		reorgCheckCalls:            map[string]error{},
+		stuckCheckCalls:            map[string]error{},
 	}
 }
 
@@ -56,6 +62,37 @@ func (m *ProcessingProxyMock) BatchCheckIncomingTransactions(_ context.Context,
 	return err
 }
 
+func (m *ProcessingProxyMock) BatchCheckReorganizations(_ context.Context, transactionIDs []int64) error {
+	key := idsKey(transactionIDs)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	err, exists := m.reorgCheckCalls[key]
+	if !exists {
+		return fmt.Errorf("unexpected call (*ProcessingProxyMock).BatchCheckReorganizations for %q", key)
+	}
+
+	return err
+}
+
+func (m *ProcessingProxyMock) BatchFundGas(
+	_ context.Context,
+	balances []*wallet.Balance,
+) (*processing.GasFundingResult, error) {
+	key := m.transferKey(balances)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res, exists := m.gasFundingCalls[key]
+	if !exists {
+		return nil, fmt.Errorf("unexpected call (*ProcessingProxyMock).BatchFundGas for %q", key)
+	}
+
+	return res.A, res.B
+}
+
 func (m *ProcessingProxyMock) BatchCreateInternalTransfers(
 	_ context.Context,
 	balances []*wallet.Balance,
@@ -118,6 +155,20 @@ func (m *ProcessingProxyMock) BatchCheckWithdrawals(_ context.Context, transacti
 	return err
 }
 
+func (m *ProcessingProxyMock) BatchBumpStuckTransactions(_ context.Context, transactionIDs []int64) error {
+	key := idsKey(transactionIDs)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	err, exists := m.stuckCheckCalls[key]
+	if !exists {
+		return fmt.Errorf("unexpected call (*ProcessingProxyMock).BatchBumpStuckTransactions for %q", key)
+	}
+
+	return err
+}
+
 func (m *ProcessingProxyMock) BatchExpirePayments(_ context.Context, paymentIDs []int64) error {
 	key := idsKey(paymentIDs)
 
@@ -132,6 +183,23 @@ func (m *ProcessingProxyMock) BatchExpirePayments(_ context.Context, paymentIDs
 	return err
 }
 
+// SetupBatchFundGas mocks gas funding. If result is nil, all balances are considered as ready.
+func (m *ProcessingProxyMock) SetupBatchFundGas(
+	balances []*wallet.Balance,
+	result *processing.GasFundingResult,
+	err error,
+) {
+	key := m.transferKey(balances)
+	if result == nil {
+		result = &processing.GasFundingResult{ReadyBalances: balances}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.gasFundingCalls[key] = lo.T2(result, err)
+}
+
 func (m *ProcessingProxyMock) SetupBatchCreateInternalTransfers(
 	balances []*wallet.Balance,
 	result *processing.TransferResult,
@@ -157,6 +225,15 @@ func (m *ProcessingProxyMock) SetupBatchCheckIncomingTransactions(transactionIDs
 	m.incomingCheckCalls[key] = err
 }
 
+func (m *ProcessingProxyMock) SetupBatchCheckReorganizations(transactionIDs []int64, err error) {
+	key := idsKey(transactionIDs)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.reorgCheckCalls[key] = err
+}
+
 func (m *ProcessingProxyMock) SetupBatchCheckInternalTransfers(transactionIDs []int64, err error) {
 	key := idsKey(transactionIDs)
 
@@ -191,6 +268,15 @@ func (m *ProcessingProxyMock) SetupBatchCheckWithdrawals(transactionIDs []int64,
 	m.withdrawalCheckCalls[key] = err
 }
 
+func (m *ProcessingProxyMock) SetupBatchBumpStuckTransactions(transactionIDs []int64, err error) {
+	key := idsKey(transactionIDs)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stuckCheckCalls[key] = err
+}
+
 func (m *ProcessingProxyMock) SetupBatchExpirePayments(paymentsIDs []int64, err error) {
 	key := idsKey(paymentsIDs)
 
@@ -204,6 +290,37 @@ func (m *ProcessingProxyMock) EnsureOutboundWallet(ctx context.Context, chain mo
 	return m.service.EnsureOutboundWallet(ctx, chain)
 }
 
+func (m *ProcessingProxyMock) ReconcileNonce(
+	ctx context.Context,
+	walletID int64,
+	isTest, fillGaps bool,
+) (*processing.NonceReconciliation, error) {
+	return m.service.ReconcileNonce(ctx, walletID, isTest, fillGaps)
+}
+
+func (m *ProcessingProxyMock) ReconcileNonceForward(
+	ctx context.Context,
+	walletID int64,
+	isTest bool,
+) (*processing.NonceReconciliation, error) {
+	return m.service.ReconcileNonceForward(ctx, walletID, isTest)
+}
+
+func (m *ProcessingProxyMock) ApplySweepPolicies(
+	ctx context.Context,
+	balances []*wallet.Balance,
+) (*processing.SweepPolicyResult, error) {
+	return m.service.ApplySweepPolicies(ctx, balances)
+}
+
+func (m *ProcessingProxyMock) ManageTronEnergy(ctx context.Context, isTest bool) (*processing.TronEnergyResult, error) {
+	return m.service.ManageTronEnergy(ctx, isTest)
+}
+
+func (m *ProcessingProxyMock) SweepOutboundToCold(ctx context.Context) (*processing.ColdSweepResult, error) {
+	return m.service.SweepOutboundToCold(ctx)
+}
+
 const empty = "[ <empty> ]"
 
 func (m *ProcessingProxyMock) transferKey(balances []*wallet.Balance) string {
@@ -209,3 +209,14 @@ func (m *Must) GetBlockchainCoin(t *testing.T, chain money.Blockchain) money.Cry
 
 	return c
 }
+
+// ExpirePaymentQuotes moves expiration date of all payment's quotes to the past.
+func (m *Must) ExpirePaymentQuotes(t *testing.T, paymentID int64) {
+	_, err := m.tc.Database.connection.Exec(
+		m.tc.Context,
+		"update quotes set expires_at = $1 where payment_id = $2",
+		time.Now().Add(-time.Minute),
+		paymentID,
+	)
+	require.NoError(t, err)
+}
@@ -19,11 +19,20 @@ import (
 // swagger:model additionalPaymentInfo
 type AdditionalPaymentInfo struct {
 
+	// Current amount of blockchain confirmations of incoming transaction.
+	// For TRON it's amount of blocks on top of transaction's block (not a solidified flag)
+	// Example: 3
+	Confirmations int64 `json:"confirmations"`
+
 	// Customer's Email
 	// Example: user@gmail.com
 	// Required: true
 	CustomerEmail *string `json:"customerEmail"`
 
+	// Amount of blockchain confirmations required to complete the payment
+	// Example: 12
+	RequiredConfirmations int64 `json:"requiredConfirmations"`
+
 	// Customer's selected crypto currency
 	// Example: USDT (Ethereum)
 	// Required: true
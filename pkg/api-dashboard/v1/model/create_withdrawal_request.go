@@ -33,6 +33,10 @@ type CreateWithdrawalRequest struct {
 	// Example: 2402782A-711D-4ECD-8B73-FE561EBF5FEC
 	// Required: true
 	BalanceID string `json:"balanceId"`
+
+	// Optional withdrawal fee quote UUID. If provided, the quoted fee is charged
+	// Example: F7C2E0A5-4B0B-4A3E-9C9C-2D0C1B1E7F11
+	FeeQuoteID *string `json:"feeQuoteId,omitempty"`
 }
 
 // Validate validates this create withdrawal request
@@ -36,9 +36,18 @@ type WithdrawalFee struct {
 	// Example: 5.75
 	CurrencyFee string `json:"currencyFee"`
 
+	// Quote expiration date. Expired quote can't be used
+	// Example: 2023-04-06 20:01:39.358834 +0000 UTC
+	// Format: datetime
+	ExpiresAt strfmt.DateTime `json:"expiresAt"`
+
 	// Indicates whether currency is on testnet
 	IsTest bool `json:"isTest"`
 
+	// Quote UUID. Pass it as feeQuoteId when creating withdrawal to lock the fee
+	// Example: F7C2E0A5-4B0B-4A3E-9C9C-2D0C1B1E7F11
+	QuoteID string `json:"quoteId"`
+
 	// USD fee
 	// Example: 5.68
 	UsdFee string `json:"usdFee"`
@@ -52,6 +61,10 @@ func (m *WithdrawalFee) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateExpiresAt(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -70,6 +83,18 @@ func (m *WithdrawalFee) validateCalculatedAt(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *WithdrawalFee) validateExpiresAt(formats strfmt.Registry) error {
+	if swag.IsZero(m.ExpiresAt) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("expiresAt", "body", "datetime", m.ExpiresAt.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // ContextValidate validates this withdrawal fee based on context it is used
 func (m *WithdrawalFee) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	return nil
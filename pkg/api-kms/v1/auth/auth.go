@@ -0,0 +1,226 @@
+// Package auth implements HMAC-signed requests to KMS API. Client signs method, request URI,
+// timestamp, nonce & body hash with a shared secret; KMS verifies the signature and rejects
+// stale or replayed requests.
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/pkg/errors"
+)
+
+const (
+	HeaderTimestamp = "X-KMS-Timestamp"
+	HeaderNonce     = "X-KMS-Nonce"
+	HeaderSignature = "X-KMS-Signature"
+
+	// DefaultMaxSkew max difference between request's timestamp and KMS clock.
+	DefaultMaxSkew = time.Minute
+
+	nonceSize = 16
+)
+
+var (
+	ErrMissingSignature = errors.New("request is not signed")
+	ErrInvalidSignature = errors.New("invalid request signature")
+	ErrExpired          = errors.New("request timestamp is out of allowed window")
+	ErrReplay           = errors.New("request nonce was already used")
+)
+
+// Sign returns hex-encoded HMAC-SHA256 signature of the request.
+func Sign(secret, method, uri string, timestamp int64, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+
+	payload := strings.Join([]string{
+		strings.ToUpper(method),
+		uri,
+		strconv.FormatInt(timestamp, 10),
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RoundTripper signs outgoing requests.
+type RoundTripper struct {
+	secret string
+	next   http.RoundTripper
+}
+
+func NewRoundTripper(secret string, next http.RoundTripper) *RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &RoundTripper{secret: secret, next: next}
+}
+
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read request body")
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now().Unix()
+
+	// RoundTripper should not modify original request
+	signed := req.Clone(req.Context())
+	signed.Body = io.NopCloser(bytes.NewReader(body))
+	signed.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	signed.Header.Set(HeaderNonce, nonce)
+	signed.Header.Set(HeaderSignature, Sign(rt.secret, req.Method, req.URL.RequestURI(), timestamp, nonce, body))
+
+	return rt.next.RoundTrip(signed)
+}
+
+// NonceStore remembers used nonces. Remember returns ErrReplay if nonce was already used
+// by a request with the same timestamp. Nonces of requests outside allowed time window may be forgotten.
+type NonceStore interface {
+	Remember(timestamp int64, nonce string) error
+}
+
+// Verifier validates signed requests, so the same request can't be replayed within allowed time window.
+type Verifier struct {
+	secret  string
+	maxSkew time.Duration
+	nonces  NonceStore
+	now     func() time.Time
+}
+
+// NewVerifier creates Verifier. If nonces is nil, seen nonces are kept in memory, which means
+// that a request can be replayed against another KMS replica. Use a shared NonceStore for multiple replicas.
+func NewVerifier(secret string, maxSkew time.Duration, nonces NonceStore) *Verifier {
+	if maxSkew <= 0 {
+		maxSkew = DefaultMaxSkew
+	}
+
+	if nonces == nil {
+		nonces = NewMemoryNonceStore(maxSkew)
+	}
+
+	return &Verifier{
+		secret:  secret,
+		maxSkew: maxSkew,
+		nonces:  nonces,
+		now:     time.Now,
+	}
+}
+
+// KeyID returns public identifier of verifier's secret. Used to attribute requests without revealing the secret.
+func (v *Verifier) KeyID() string {
+	mac := hmac.New(sha256.New, []byte(v.secret))
+	mac.Write([]byte("kms-key-id"))
+
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// Verify validates request's signature. Body should be read by the caller.
+func (v *Verifier) Verify(req *http.Request, body []byte) error {
+	var (
+		rawTimestamp = req.Header.Get(HeaderTimestamp)
+		nonce        = req.Header.Get(HeaderNonce)
+		signature    = req.Header.Get(HeaderSignature)
+	)
+
+	if rawTimestamp == "" || nonce == "" || signature == "" {
+		return ErrMissingSignature
+	}
+
+	timestamp, err := strconv.ParseInt(rawTimestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	skew := v.now().Sub(time.Unix(timestamp, 0))
+	if skew > v.maxSkew || skew < -v.maxSkew {
+		return ErrExpired
+	}
+
+	expected := Sign(v.secret, req.Method, req.URL.RequestURI(), timestamp, nonce, body)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return ErrInvalidSignature
+	}
+
+	return v.nonces.Remember(timestamp, nonce)
+}
+
+// Stop stops nonces cleanup.
+func (v *Verifier) Stop() {
+	if s, ok := v.nonces.(interface{ Stop() }); ok {
+		s.Stop()
+	}
+}
+
+// MemoryNonceStore keeps seen nonces in memory for 2 * maxSkew. Suitable only for a single KMS replica.
+type MemoryNonceStore struct {
+	mu     sync.Mutex
+	nonces *ttlcache.Cache[string, struct{}]
+}
+
+func NewMemoryNonceStore(maxSkew time.Duration) *MemoryNonceStore {
+	nonces := ttlcache.New[string, struct{}](
+		ttlcache.WithTTL[string, struct{}](2*maxSkew),
+		ttlcache.WithDisableTouchOnHit[string, struct{}](),
+	)
+
+	go nonces.Start()
+
+	return &MemoryNonceStore{nonces: nonces}
+}
+
+func (s *MemoryNonceStore) Remember(_ int64, nonce string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.nonces.Get(nonce) != nil {
+		return ErrReplay
+	}
+
+	s.nonces.Set(nonce, struct{}{}, ttlcache.DefaultTTL)
+
+	return nil
+}
+
+// Stop stops nonces cleanup.
+func (s *MemoryNonceStore) Stop() {
+	s.nonces.Stop()
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	defer req.Body.Close()
+
+	return io.ReadAll(req.Body)
+}
+
+func newNonce() (string, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Wrap(err, "unable to generate nonce")
+	}
+
+	return hex.EncodeToString(nonce), nil
+}
@@ -0,0 +1,22 @@
+package client
+
+import (
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/auth"
+)
+
+// NewHTTPClientWithSecret creates a new KMS client that signs requests with the shared secret.
+// Requests are not signed if the secret is empty.
+func NewHTTPClientWithSecret(formats strfmt.Registry, cfg *TransportConfig, secret string) *KMSInternalAPI {
+	if cfg == nil {
+		cfg = DefaultTransportConfig()
+	}
+
+	transport := httptransport.New(cfg.Host, cfg.BasePath, cfg.Schemes)
+	if secret != "" {
+		transport.Transport = auth.NewRoundTripper(secret, transport.Transport)
+	}
+
+	return New(transport, formats)
+}
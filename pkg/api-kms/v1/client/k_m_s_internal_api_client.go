@@ -10,6 +10,8 @@ import (
 	httptransport "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
 
+	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/client/audit"
+	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/client/system"
 	"github.com/oxygenpay/oxygen/pkg/api-kms/v1/client/wallet"
 )
 
@@ -55,6 +57,8 @@ func New(transport runtime.ClientTransport, formats strfmt.Registry) *KMSInterna
 
 	cli := new(KMSInternalAPI)
 	cli.Transport = transport
+	cli.Audit = audit.New(transport, formats)
+	cli.System = system.New(transport, formats)
 	cli.Wallet = wallet.New(transport, formats)
 	return cli
 }
@@ -100,6 +104,10 @@ func (cfg *TransportConfig) WithSchemes(schemes []string) *TransportConfig {
 
 // KMSInternalAPI is a client for k m s internal API
 type KMSInternalAPI struct {
+	Audit audit.ClientService
+
+	System system.ClientService
+
 	Wallet wallet.ClientService
 
 	Transport runtime.ClientTransport
@@ -108,5 +116,7 @@ type KMSInternalAPI struct {
 // SetTransport changes the transport on the client and all its subresources
 func (c *KMSInternalAPI) SetTransport(transport runtime.ClientTransport) {
 	c.Transport = transport
+	c.Audit.SetTransport(transport)
+	c.System.SetTransport(transport)
 	c.Wallet.SetTransport(transport)
 }
@@ -36,6 +36,8 @@ type ClientService interface {
 
 	CreateMaticTransaction(params *CreateMaticTransactionParams, opts ...ClientOption) (*CreateMaticTransactionCreated, error)
 
+	CreateTronResourceTransaction(params *CreateTronResourceTransactionParams, opts ...ClientOption) (*CreateTronResourceTransactionCreated, error)
+
 	CreateTronTransaction(params *CreateTronTransactionParams, opts ...ClientOption) (*CreateTronTransactionCreated, error)
 
 	CreateWallet(params *CreateWalletParams, opts ...ClientOption) (*CreateWalletCreated, error)
@@ -44,6 +46,10 @@ type ClientService interface {
 
 	GetWallet(params *GetWalletParams, opts ...ClientOption) (*GetWalletOK, error)
 
+	ImportWallet(params *ImportWalletParams, opts ...ClientOption) (*ImportWalletCreated, error)
+
+	SignMessage(params *SignMessageParams, opts ...ClientOption) (*SignMessageOK, error)
+
 	SetTransport(transport runtime.ClientTransport)
 }
 
@@ -161,6 +167,46 @@ func (a *Client) CreateMaticTransaction(params *CreateMaticTransactionParams, op
 	panic(msg)
 }
 
+/*
+  CreateTronResourceTransaction creates tron resource transaction
+
+  Freezes TRX for energy / bandwidth or delegates obtained resources to another account
+*/
+func (a *Client) CreateTronResourceTransaction(params *CreateTronResourceTransactionParams, opts ...ClientOption) (*CreateTronResourceTransactionCreated, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewCreateTronResourceTransactionParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "createTronResourceTransaction",
+		Method:             "POST",
+		PathPattern:        "/wallet/{walletId}/transaction/tron/resource",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &CreateTronResourceTransactionReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*CreateTronResourceTransactionCreated)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for createTronResourceTransaction: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
 /*
   CreateTronTransaction creates tron transaction
 */
@@ -313,6 +359,86 @@ func (a *Client) GetWallet(params *GetWalletParams, opts ...ClientOption) (*GetW
 	panic(msg)
 }
 
+/*
+  ImportWallet imports wallet
+*/
+func (a *Client) ImportWallet(params *ImportWalletParams, opts ...ClientOption) (*ImportWalletCreated, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewImportWalletParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "importWallet",
+		Method:             "POST",
+		PathPattern:        "/wallet/import",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &ImportWalletReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*ImportWalletCreated)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for importWallet: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
+/*
+  SignMessage signs message
+
+  Signs proof of address ownership built by KMS from wallet's address, the challenge & current time.
+Allowed only for wallets listed in signing policy's message_signing_wallets.
+
+*/
+func (a *Client) SignMessage(params *SignMessageParams, opts ...ClientOption) (*SignMessageOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewSignMessageParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "signMessage",
+		Method:             "POST",
+		PathPattern:        "/wallet/{walletId}/message",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &SignMessageReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*SignMessageOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	// safeguard: normally, absent a default response, unknown success responses return an error above: so this is a codegen issue
+	msg := fmt.Sprintf("unexpected success response for signMessage: API contract not enforced by server. Client expected to get an error, but got: %T", result)
+	panic(msg)
+}
+
 // SetTransport changes the transport on the client
 func (a *Client) SetTransport(transport runtime.ClientTransport) {
 	a.transport = transport
@@ -113,6 +113,39 @@ func (_m *ClientService) CreateMaticTransaction(params *wallet.CreateMaticTransa
 	return r0, r1
 }
 
+// CreateTronResourceTransaction provides a mock function with given fields: params, opts
+func (_m *ClientService) CreateTronResourceTransaction(params *wallet.CreateTronResourceTransactionParams, opts ...wallet.ClientOption) (*wallet.CreateTronResourceTransactionCreated, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *wallet.CreateTronResourceTransactionCreated
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*wallet.CreateTronResourceTransactionParams, ...wallet.ClientOption) (*wallet.CreateTronResourceTransactionCreated, error)); ok {
+		return rf(params, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(*wallet.CreateTronResourceTransactionParams, ...wallet.ClientOption) *wallet.CreateTronResourceTransactionCreated); ok {
+		r0 = rf(params, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.CreateTronResourceTransactionCreated)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*wallet.CreateTronResourceTransactionParams, ...wallet.ClientOption) error); ok {
+		r1 = rf(params, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateTronTransaction provides a mock function with given fields: params, opts
 func (_m *ClientService) CreateTronTransaction(params *wallet.CreateTronTransactionParams, opts ...wallet.ClientOption) (*wallet.CreateTronTransactionCreated, error) {
 	_va := make([]interface{}, len(opts))
@@ -245,11 +278,77 @@ func (_m *ClientService) GetWallet(params *wallet.GetWalletParams, opts ...walle
 	return r0, r1
 }
 
+// ImportWallet provides a mock function with given fields: params, opts
+func (_m *ClientService) ImportWallet(params *wallet.ImportWalletParams, opts ...wallet.ClientOption) (*wallet.ImportWalletCreated, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *wallet.ImportWalletCreated
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*wallet.ImportWalletParams, ...wallet.ClientOption) (*wallet.ImportWalletCreated, error)); ok {
+		return rf(params, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(*wallet.ImportWalletParams, ...wallet.ClientOption) *wallet.ImportWalletCreated); ok {
+		r0 = rf(params, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.ImportWalletCreated)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*wallet.ImportWalletParams, ...wallet.ClientOption) error); ok {
+		r1 = rf(params, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetTransport provides a mock function with given fields: transport
 func (_m *ClientService) SetTransport(transport runtime.ClientTransport) {
 	_m.Called(transport)
 }
 
+// SignMessage provides a mock function with given fields: params, opts
+func (_m *ClientService) SignMessage(params *wallet.SignMessageParams, opts ...wallet.ClientOption) (*wallet.SignMessageOK, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *wallet.SignMessageOK
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*wallet.SignMessageParams, ...wallet.ClientOption) (*wallet.SignMessageOK, error)); ok {
+		return rf(params, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(*wallet.SignMessageParams, ...wallet.ClientOption) *wallet.SignMessageOK); ok {
+		r0 = rf(params, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.SignMessageOK)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*wallet.SignMessageParams, ...wallet.ClientOption) error); ok {
+		r1 = rf(params, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewClientService creates a new instance of ClientService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewClientService(t interface {
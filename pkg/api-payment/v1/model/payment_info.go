@@ -30,6 +30,11 @@ type PaymentInfo struct {
 	// Required: true
 	AmountFormatted string `json:"amountFormatted"`
 
+	// Current amount of blockchain confirmations of incoming transaction.
+	// For TRON it's amount of blocks on top of transaction's block (not a solidified flag)
+	// Example: 3
+	Confirmations int64 `json:"confirmations"`
+
 	// Expiration duration in minutes
 	// Example: 20
 	// Required: true
@@ -51,6 +56,10 @@ type PaymentInfo struct {
 	// Required: true
 	RecipientAddress string `json:"recipientAddress"`
 
+	// Amount of blockchain confirmations required to complete the payment
+	// Example: 12
+	RequiredConfirmations int64 `json:"requiredConfirmations"`
+
 	// Payment status
 	// Example: success
 	// Required: true
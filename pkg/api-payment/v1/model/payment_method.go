@@ -8,8 +8,10 @@ package model
 import (
 	"context"
 
+	"github.com/go-openapi/errors"
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
 )
 
 // PaymentMethod PaymentType
@@ -17,6 +19,10 @@ import (
 // swagger:model paymentMethod
 type PaymentMethod struct {
 
+	// Quoted amount in selected currency. Guaranteed until quote expiration
+	// Example: 0.0361
+	Amount string `json:"amount,omitempty"`
+
 	// blockchain
 	Blockchain string `json:"blockchain"`
 
@@ -35,12 +41,46 @@ type PaymentMethod struct {
 	// network Id
 	NetworkID string `json:"networkId"`
 
+	// Quote expiration date. Payment can't be locked with expired quote
+	// Example: 2023-04-06 20:01:39.358834 +0000 UTC
+	// Format: datetime
+	QuoteExpiresAt strfmt.DateTime `json:"quoteExpiresAt,omitempty"`
+
+	// Seconds left until quote expiration
+	// Example: 540
+	QuoteExpiresIn int64 `json:"quoteExpiresIn,omitempty"`
+
+	// Quote UUID
+	// Example: F7C2E0A5-4B0B-4A3E-9C9C-2D0C1B1E7F11
+	QuoteID string `json:"quoteId,omitempty"`
+
 	// ticker
 	Ticker string `json:"ticker"`
 }
 
 // Validate validates this payment method
 func (m *PaymentMethod) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateQuoteExpiresAt(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *PaymentMethod) validateQuoteExpiresAt(formats strfmt.Registry) error {
+	if swag.IsZero(m.QuoteExpiresAt) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("quoteExpiresAt", "body", "datetime", m.QuoteExpiresAt.String(), formats); err != nil {
+		return err
+	}
+
 	return nil
 }
 
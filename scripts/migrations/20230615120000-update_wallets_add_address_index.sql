@@ -0,0 +1,5 @@
+-- +migrate Up
+create index wallets_blockchain_address_index on wallets (blockchain, lower(address));
+
+-- +migrate Down
+drop index wallets_blockchain_address_index;
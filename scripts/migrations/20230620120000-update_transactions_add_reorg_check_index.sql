@@ -0,0 +1,6 @@
+-- +migrate Up
+create index transactions_reorg_check_index on transactions (id)
+where type = 'incoming' and metadata->>'blockNumber' is not null and metadata->>'reorgVerified' is null;
+
+-- +migrate Down
+drop index transactions_reorg_check_index;
@@ -0,0 +1,19 @@
+-- +migrate Up
+create table if not exists exchange_rates
+(
+    id            bigserial constraint exchange_rates_pkey primary key,
+    created_at    timestamp   not null,
+    from_currency varchar(16) not null,
+    to_currency   varchar(16) not null,
+    rate          numeric     not null,
+    source        varchar(64) not null
+);
+
+create index exchange_rates_pair_created_at on exchange_rates (from_currency, to_currency, created_at);
+
+alter table transactions add column usd_rate_id bigint;
+
+-- +migrate Down
+alter table transactions drop column usd_rate_id;
+
+drop table if exists exchange_rates;
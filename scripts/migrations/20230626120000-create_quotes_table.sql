@@ -0,0 +1,25 @@
+-- +migrate Up
+create table if not exists quotes
+(
+    id            bigserial constraint quotes_pkey primary key,
+    uuid          uuid        not null,
+    created_at    timestamp   not null,
+    expires_at    timestamp   not null,
+    merchant_id   bigint      not null,
+    type          varchar(32) not null,
+    from_currency varchar(16) not null,
+    from_amount   numeric     not null,
+    from_decimals int         not null,
+    to_currency   varchar(16) not null,
+    to_amount     numeric     not null,
+    to_decimals   int         not null,
+    rate          numeric     not null,
+    rate_id       bigint,
+    payment_id    bigint
+);
+
+create unique index quotes_uuid on quotes (uuid);
+create index quotes_payment_id on quotes (payment_id);
+
+-- +migrate Down
+drop table if exists quotes;
@@ -0,0 +1,5 @@
+-- +migrate Up
+create unique index gas_wallets_unique on wallets (type, blockchain) where type = 'gas';
+
+-- +migrate Down
+drop index gas_wallets_unique;
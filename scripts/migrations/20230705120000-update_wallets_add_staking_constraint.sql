@@ -0,0 +1,5 @@
+-- +migrate Up
+create unique index staking_wallets_unique on wallets (type, blockchain) where type = 'staking';
+
+-- +migrate Down
+drop index staking_wallets_unique;
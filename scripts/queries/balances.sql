@@ -44,3 +44,5 @@ returning *;
 -- name: InsertBalanceAuditLog :exec
 insert into balance_audit_log(created_at, balance_id, comment, metadata) values ($1,$2, $3, $4) returning *;
 
+-- name: ListBalanceAuditLogSince :many
+select * from balance_audit_log where balance_id = $1 and created_at > $2 order by id;
@@ -0,0 +1,19 @@
+-- name: CreateExchangeRate :one
+insert into exchange_rates (created_at, from_currency, to_currency, rate, source)
+values ($1, $2, $3, $4, $5)
+returning *;
+
+-- name: GetExchangeRateByID :one
+select * from exchange_rates where id = $1;
+
+-- name: GetExchangeRateAt :one
+select * from exchange_rates
+where from_currency = $1 and to_currency = $2 and created_at <= $3
+order by created_at desc
+limit 1;
+
+-- name: ListExchangeRates :many
+select * from exchange_rates
+where from_currency = $1 and to_currency = $2 and created_at >= $3 and created_at <= $4
+order by created_at
+limit $5;
@@ -0,0 +1,31 @@
+-- name: CreateQuote :one
+insert into quotes (
+uuid,
+created_at,
+expires_at,
+merchant_id,
+type,
+from_currency,
+from_amount,
+from_decimals,
+to_currency,
+to_amount,
+to_decimals,
+rate,
+rate_id,
+payment_id
+) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+returning *;
+
+-- name: GetQuoteByUUID :one
+select * from quotes where merchant_id = $1 and uuid = $2;
+
+-- name: GetLatestQuoteByPaymentID :one
+select * from quotes where payment_id = $1 and type = $2
+order by id desc
+limit 1;
+
+-- name: UpdateQuotePaymentID :one
+update quotes set payment_id = $2
+where id = $1 and payment_id is null
+returning *;
@@ -8,13 +8,14 @@ insert into transactions (
       transaction_hash,
       blockchain, network_id, currency_type, currency, decimals, network_decimals,
       amount, fact_amount, network_fee, service_fee, usd_amount,
-      metadata, is_test
+      metadata, is_test, usd_rate_id
 )
 values (
       $1, $2, $3, $4, $5, $6,
       $7, $8, $9, $10, $11, $12,
       $13, $14, $15, $16, $17, $18,
-      $19, $20, $21, $22, $23, $24
+      $19, $20, $21, $22, $23, $24,
+      $25
 ) returning *;
 
 -- name: GetTransactionByID :one
@@ -45,6 +46,9 @@ where id = $1;
 -- name: SetTransactionHash :exec
 update transactions set transaction_hash = $1, updated_at = $2 where id = $3 and merchant_id = $4;
 
+-- name: UpdateTransactionMetadata :exec
+update transactions set metadata = $1, updated_at = $2 where id = $3 and merchant_id = $4;
+
 -- name: GetTransactionsByFilter :many
 select * from transactions
 where (CASE WHEN @filter_by_recipient_wallet_id::boolean THEN recipient_wallet_id = $1 ELSE true END)
@@ -53,9 +57,29 @@ and (CASE WHEN @filter_by_currency::boolean THEN currency = $3 ELSE true END)
 and (CASE WHEN @filter_by_types::boolean THEN type = any(sqlc.arg(types)::varchar[]) ELSE true END)
 and (CASE WHEN @filter_by_statuses::boolean THEN status = any(sqlc.arg(statuses)::varchar[]) ELSE true END)
 and (CASE WHEN @filter_empty_hash::boolean THEN transaction_hash is null ELSE true END)
+and (CASE WHEN @filter_by_sender_wallet_id::boolean THEN sender_wallet_id = sqlc.arg(sender_wallet_id) ELSE true END)
 order by id desc
 limit $4;
 
+-- name: ListTransactionsForReorgCheck :many
+select * from transactions
+where type = $1
+and status = any(sqlc.arg(statuses)::varchar[])
+and metadata->>'blockNumber' is not null
+and metadata->>'reorgVerified' is null
+and id > sqlc.arg(after_id)
+order by id
+limit $2;
+
+-- name: ListOrphanedTransactionsWithPaymentStatus :many
+select transactions.* from transactions
+join payments on payments.id = transactions.entity_id and payments.merchant_id = transactions.merchant_id
+where transactions.type = $1
+and transactions.status = $2
+and payments.status = sqlc.arg(payment_status)
+order by transactions.id
+limit $3;
+
 -- name: UpdateTransaction :one
 update transactions set
 status = $3,
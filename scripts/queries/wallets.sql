@@ -21,6 +21,12 @@ FROM wallets
 WHERE uuid = $1
 LIMIT 1;
 
+-- name: GetWalletByAddress :one
+SELECT *
+FROM wallets
+WHERE blockchain = $1 AND lower(address) = lower(@address::text)
+LIMIT 1;
+
 -- name: PaginateWalletsByID :many
 SELECT *
 FROM wallets
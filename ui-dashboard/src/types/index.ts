@@ -132,6 +132,8 @@ interface AdditionalPaymentInfo {
     customerEmail: string;
     selectedCurrency: string;
     serviceFee: string;
+    confirmations: number;
+    requiredConfirmations: number;
 }
 
 interface AdditionalWithdrawalInfo {
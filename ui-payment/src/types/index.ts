@@ -35,6 +35,8 @@ interface PaymentInfo {
     successUrl?: string;
     expiresAt: string;
     expirationDurationMin: number;
+    confirmations: number;
+    requiredConfirmations: number;
     successAction?: PaymentAction;
     successMessage?: string;
     paymentLink: string;